@@ -0,0 +1,157 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AuthProvider string
+
+const (
+	// AuthProviderInternal authenticates the clients with the passwords stored in
+	// system_auth.
+	AuthProviderInternal = AuthProvider("Internal")
+
+	// AuthProviderLDAP authenticates the clients against an LDAP server.
+	AuthProviderLDAP = AuthProvider("LDAP")
+
+	DefaultLDAPUsernameAttribute = "cn"
+	DefaultLDAPGroupSyncPeriod   = 10 * time.Minute
+)
+
+// Auth configures the authentication and the authorization of the CQL clients. When it
+// is not set, the defaults of the image are used, which enable them.
+type Auth struct {
+	// Enabled selects the authenticator of Provider and the CassandraAuthorizer, and makes
+	// Stargate and Reaper authenticate. Disabling it selects the AllowAllAuthenticator and
+	// the AllowAllAuthorizer. Enabling it on existing datacenters first raises the
+	// replication of system_auth so that the roles are available in every datacenter.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// CacheValidityPeriodMillis is the validity period of the roles, permissions and
+	// credentials caches, i.e., roles_validity_in_ms, permissions_validity_in_ms and
+	// credentials_validity_in_ms.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CacheValidityPeriodMillis *int64 `json:"cacheValidityPeriodMillis,omitempty"`
+
+	// CacheUpdateIntervalMillis is the refresh interval of the roles, permissions and
+	// credentials caches, i.e., roles_update_interval_in_ms, permissions_update_interval_in_ms
+	// and credentials_update_interval_in_ms.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CacheUpdateIntervalMillis *int64 `json:"cacheUpdateIntervalMillis,omitempty"`
+
+	// Provider is what authenticates the clients. Internal, the default, checks the
+	// passwords stored in system_auth. LDAP checks the credentials against an LDAP server
+	// with the cassandra-ldap plugin of Instaclustr, which the server image must include.
+	// The roles that have a password in system_auth, such as the superuser and the roles of
+	// Stargate and Reaper, keep authenticating with it.
+	// +kubebuilder:validation:Enum=Internal;LDAP
+	// +kubebuilder:default=Internal
+	// +optional
+	Provider AuthProvider `json:"provider,omitempty"`
+
+	// LDAP configures the LDAP provider.
+	// +optional
+	LDAP *LDAPConfig `json:"ldap,omitempty"`
+}
+
+// LDAPConfig configures the authentication of the clients against an LDAP server, and
+// the Cassandra roles that are granted to the members of LDAP groups.
+type LDAPConfig struct {
+	// ServerURL is the URL of the LDAP server, e.g., ldaps://ldap.example.com:636.
+	// +kubebuilder:validation:Pattern=`^ldaps?://`
+	ServerURL string `json:"serverURL"`
+
+	// BindSecretRef references the Secret that holds the DN and the password that
+	// Cassandra and the operator bind with, in its username and password keys. The Secret
+	// is replicated to the Kubernetes clusters of the datacenters, and changing it
+	// triggers a rolling restart.
+	BindSecretRef corev1.LocalObjectReference `json:"bindSecretRef"`
+
+	// SearchBase is the DN of the entry under which the users are searched.
+	SearchBase string `json:"searchBase"`
+
+	// UsernameAttribute is the attribute of the user entries that holds the name that the
+	// clients log in with. Defaults to cn.
+	// +optional
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+
+	// GroupMappings grant Cassandra roles to the members of LDAP groups.
+	// +optional
+	GroupMappings []LDAPGroupMapping `json:"groupMappings,omitempty"`
+
+	// GroupSyncPeriod is how often the members of the groups of GroupMappings are read
+	// from the LDAP server. Defaults to 10 minutes.
+	// +optional
+	GroupSyncPeriod *metav1.Duration `json:"groupSyncPeriod,omitempty"`
+}
+
+// LDAPGroupMapping grants Cassandra roles to the members of an LDAP group. The mapped
+// roles are created if they do not exist, and the operator grants them to a role for each
+// member, named after its UsernameAttribute. The permissions of the mapped roles are not
+// managed.
+type LDAPGroupMapping struct {
+	// Group is the DN of the LDAP group. Its members are read from its member and
+	// uniqueMember attributes.
+	Group string `json:"group"`
+
+	// Roles are the Cassandra roles that are granted to the members of Group. The grants
+	// made by the group sync are revoked from the roles that are no longer members of a
+	// group mapped to them; the grants made otherwise, e.g., through CassandraRoles, are
+	// left as is.
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+}
+
+// IsEnabled returns true if authentication is enabled, which is the default.
+func (in *Auth) IsEnabled() bool {
+	return in == nil || in.Enabled == nil || *in.Enabled
+}
+
+func (in *Auth) GetProvider() AuthProvider {
+	if in == nil || in.Provider == "" {
+		return AuthProviderInternal
+	}
+	return in.Provider
+}
+
+// IsLDAPEnabled returns true if authentication is enabled with the LDAP provider.
+func (in *Auth) IsLDAPEnabled() bool {
+	return in.IsEnabled() && in.GetProvider() == AuthProviderLDAP && in.LDAP != nil
+}
+
+func (in *LDAPConfig) GetUsernameAttribute() string {
+	if in.UsernameAttribute == "" {
+		return DefaultLDAPUsernameAttribute
+	}
+	return in.UsernameAttribute
+}
+
+func (in *LDAPConfig) GetGroupSyncPeriod() time.Duration {
+	if in.GroupSyncPeriod == nil {
+		return DefaultLDAPGroupSyncPeriod
+	}
+	return in.GroupSyncPeriod.Duration
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the Cassandra object when its resource is deleted.
+	DeletionPolicyRetain = DeletionPolicy("Retain")
+
+	// DeletionPolicyDelete drops the Cassandra object when its resource is deleted.
+	DeletionPolicyDelete = DeletionPolicy("Delete")
+
+	DefaultKeyspaceMaxReplicationFactor = 3
+)
+
+// CassandraKeyspaceSpec defines the desired state of CassandraKeyspace. Replication and
+// DurableWrites are the only options of a keyspace in Cassandra; the tables of the keyspace
+// and their options are not managed.
+type CassandraKeyspaceSpec struct {
+	// Cluster references the K8ssandraCluster, in the same namespace, whose Cassandra
+	// cluster the keyspace is created in.
+	Cluster corev1.LocalObjectReference `json:"cluster"`
+
+	// Name is the name of the keyspace. Defaults to the name of the CassandraKeyspace, with
+	// its dashes replaced by underscores. It must be lowercase since Cassandra folds the
+	// unquoted keyspace names to lowercase.
+	// Cannot be changed.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]{1,48}$`
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Replication is the replication of the keyspace, with NetworkTopologyStrategy.
+	// +optional
+	Replication KeyspaceReplication `json:"replication,omitempty"`
+
+	// DurableWrites selects whether the writes to the keyspace go through the commit log.
+	// The default of Cassandra, true, is kept when it is not set.
+	// +optional
+	DurableWrites *bool `json:"durableWrites,omitempty"`
+
+	// DeletionPolicy is what happens to the keyspace when the CassandraKeyspace is
+	// deleted. Retain, the default, keeps the keyspace and its data. Delete drops it.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// KeyspaceReplication is the replication of a keyspace in each datacenter.
+type KeyspaceReplication struct {
+	// Datacenters maps the names of the datacenters to their replication factor. When it
+	// is empty, the replication follows the topology of the cluster: each ready
+	// datacenter gets as many replicas as it has nodes, up to MaxReplicationFactor, and
+	// the replication is updated as datacenters are added, removed or resized. Add the
+	// keyspace to the replicatedKeyspaces of the K8ssandraCluster for its data to be
+	// streamed to new datacenters.
+	// +optional
+	Datacenters map[string]int `json:"datacenters,omitempty"`
+
+	// MaxReplicationFactor is the highest replication factor of a datacenter when the
+	// replication follows the topology. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxReplicationFactor *int `json:"maxReplicationFactor,omitempty"`
+}
+
+// CassandraKeyspaceStatus defines the observed state of CassandraKeyspace.
+type CassandraKeyspaceStatus struct {
+	// ObservedGeneration is the generation of the CassandraKeyspace that was last
+	// reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Replication is the actual replication factor of the keyspace in each datacenter.
+	// +optional
+	Replication map[string]int `json:"replication,omitempty"`
+
+	// DurableWrites is the actual durable_writes of the keyspace. It is only reported when
+	// spec.durableWrites is set.
+	// +optional
+	DurableWrites *bool `json:"durableWrites,omitempty"`
+
+	// Error is the error encountered by the last reconciliation. It is empty if the last
+	// reconciliation succeeded.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.cluster.name`
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.error`
+
+// CassandraKeyspace is the Schema for the cassandrakeyspaces API
+type CassandraKeyspace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CassandraKeyspaceSpec   `json:"spec,omitempty"`
+	Status CassandraKeyspaceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CassandraKeyspaceList contains a list of CassandraKeyspace
+type CassandraKeyspaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CassandraKeyspace `json:"items"`
+}
+
+// KeyspaceName returns the name of the keyspace, which defaults to the name of the
+// resource with its dashes replaced by underscores.
+func (in *CassandraKeyspace) KeyspaceName() string {
+	if in.Spec.Name == "" {
+		return strings.ReplaceAll(in.Name, "-", "_")
+	}
+	return in.Spec.Name
+}
+
+func (in *CassandraKeyspace) GetDeletionPolicy() DeletionPolicy {
+	if in.Spec.DeletionPolicy == "" {
+		return DeletionPolicyRetain
+	}
+	return in.Spec.DeletionPolicy
+}
+
+// FollowsTopology returns true if the replication of the keyspace is computed from the
+// datacenters of the cluster.
+func (in *KeyspaceReplication) FollowsTopology() bool {
+	return len(in.Datacenters) == 0
+}
+
+func (in *KeyspaceReplication) GetMaxReplicationFactor() int {
+	if in.MaxReplicationFactor == nil {
+		return DefaultKeyspaceMaxReplicationFactor
+	}
+	return *in.MaxReplicationFactor
+}
+
+func init() {
+	SchemeBuilder.Register(&CassandraKeyspace{}, &CassandraKeyspaceList{})
+}
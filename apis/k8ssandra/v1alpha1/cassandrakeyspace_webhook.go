@@ -0,0 +1,109 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var (
+	keyspaceWebhookLog = logf.Log.WithName("cassandrakeyspace-webhook")
+
+	// keyspaceNameRegexp matches the names of the keyspaces managed through CassandraKeyspaces.
+	// Cassandra folds unquoted identifiers to lowercase, so mixed case names are rejected to
+	// not create a keyspace whose name differs from the one in the spec.
+	keyspaceNameRegexp = regexp.MustCompile(`^[a-z0-9_]{1,48}$`)
+)
+
+// SetupWebhookWithManager registers the validating webhook for CassandraKeyspace with mgr.
+func (in *CassandraKeyspace) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-k8ssandra-io-v1alpha1-cassandrakeyspace,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8ssandra.io,resources=cassandrakeyspaces,verbs=create;update,versions=v1alpha1,name=vcassandrakeyspace.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &CassandraKeyspace{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (in *CassandraKeyspace) ValidateCreate() error {
+	keyspaceWebhookLog.Info("validate create", "name", in.Name)
+
+	return in.toAggregateError(in.validateKeyspaceName())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (in *CassandraKeyspace) ValidateUpdate(old runtime.Object) error {
+	keyspaceWebhookLog.Info("validate update", "name", in.Name)
+
+	oldKeyspace, ok := old.(*CassandraKeyspace)
+	if !ok {
+		return fmt.Errorf("expected a CassandraKeyspace but got a %T", old)
+	}
+
+	if in.DeletionTimestamp != nil {
+		// Do not get in the way of the removal of the finalizer
+		return nil
+	}
+
+	allErrs := in.validateKeyspaceName()
+	if in.KeyspaceName() != oldKeyspace.KeyspaceName() {
+		// The keyspace would not be renamed, another one would be created instead
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "name"), "the keyspace name cannot be changed"))
+	}
+	return in.toAggregateError(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (in *CassandraKeyspace) ValidateDelete() error {
+	return nil
+}
+
+// validateKeyspaceName checks the name of the keyspace, which defaults to the name of the
+// resource.
+func (in *CassandraKeyspace) validateKeyspaceName() field.ErrorList {
+	var allErrs field.ErrorList
+	name := in.KeyspaceName()
+	path := field.NewPath("spec", "name")
+	if in.Spec.Name == "" {
+		path = field.NewPath("metadata", "name")
+	}
+
+	if !keyspaceNameRegexp.MatchString(name) {
+		allErrs = append(allErrs, field.Invalid(path, name,
+			"must be a valid keyspace name: at most 48 lowercase alphanumeric characters or underscores"))
+	} else if isSystemKeyspace(name) {
+		allErrs = append(allErrs, field.Invalid(path, name, "cannot be a system keyspace"))
+	}
+	return allErrs
+}
+
+func (in *CassandraKeyspace) toAggregateError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("CassandraKeyspace").GroupKind(), in.Name, allErrs)
+}
@@ -0,0 +1,113 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCassandraKeyspaceValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(ks *CassandraKeyspace)
+		invalid string
+	}{
+		{
+			name:   "name of the resource",
+			mutate: func(ks *CassandraKeyspace) {},
+		},
+		{
+			name: "name of the spec",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Spec.Name = "app_data"
+			},
+		},
+		{
+			name: "mixed case name",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Spec.Name = "AppData"
+			},
+			invalid: "spec.name",
+		},
+		{
+			name: "resource name that is not a keyspace name",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Name = "app.data"
+			},
+			invalid: "metadata.name",
+		},
+		{
+			name: "system keyspace",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Spec.Name = "system_auth"
+			},
+			invalid: "spec.name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := newTestKeyspace()
+			tt.mutate(ks)
+			assertValidationResult(t, ks.ValidateCreate(), tt.invalid)
+		})
+	}
+}
+
+func TestCassandraKeyspaceValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(ks *CassandraKeyspace)
+		invalid string
+	}{
+		{
+			name: "replication change",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Spec.Replication.Datacenters = map[string]int{"dc1": 3}
+			},
+		},
+		{
+			name: "name change",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Spec.Name = "other_data"
+			},
+			invalid: "spec.name",
+		},
+		{
+			name: "default name set explicitly",
+			mutate: func(ks *CassandraKeyspace) {
+				ks.Spec.Name = "app_data"
+			},
+		},
+		{
+			name: "name change of a deleted keyspace",
+			mutate: func(ks *CassandraKeyspace) {
+				now := metav1.Now()
+				ks.DeletionTimestamp = &now
+				ks.Spec.Name = "other_data"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldKs := newTestKeyspace()
+			ks := oldKs.DeepCopy()
+			tt.mutate(ks)
+			assertValidationResult(t, ks.ValidateUpdate(oldKs), tt.invalid)
+		})
+	}
+}
+
+func newTestKeyspace() *CassandraKeyspace {
+	return &CassandraKeyspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "app-data",
+		},
+		Spec: CassandraKeyspaceSpec{
+			Cluster: corev1.LocalObjectReference{Name: "test"},
+		},
+	}
+}
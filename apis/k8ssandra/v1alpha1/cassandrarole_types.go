@@ -0,0 +1,185 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Permission is a permission on the data resources of Cassandra.
+// +kubebuilder:validation:Enum=ALL;CREATE;ALTER;DROP;SELECT;MODIFY;AUTHORIZE
+type Permission string
+
+const (
+	PermissionAll       = Permission("ALL")
+	PermissionCreate    = Permission("CREATE")
+	PermissionAlter     = Permission("ALTER")
+	PermissionDrop      = Permission("DROP")
+	PermissionSelect    = Permission("SELECT")
+	PermissionModify    = Permission("MODIFY")
+	PermissionAuthorize = Permission("AUTHORIZE")
+)
+
+// CassandraRoleSpec defines the desired state of CassandraRole.
+type CassandraRoleSpec struct {
+	// Cluster references the K8ssandraCluster, in the same namespace, whose Cassandra
+	// cluster the role is created in.
+	Cluster corev1.LocalObjectReference `json:"cluster"`
+
+	// Name is the name of the role. Defaults to the name of the CassandraRole. Cannot be
+	// changed.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Login selects whether the role can log in. Defaults to true.
+	// +optional
+	Login *bool `json:"login,omitempty"`
+
+	// Superuser selects whether the role is a superuser.
+	// +optional
+	Superuser bool `json:"superuser,omitempty"`
+
+	// SecretName is the name of the Secret with the password of the role, under the
+	// password key. If the Secret does not exist, it is generated with a random password.
+	// The Secret is replicated to the namespaces and the Kubernetes contexts of all the
+	// datacenters. Defaults to the name of the CassandraRole followed by -credentials. It
+	// is ignored when the role cannot log in.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Roles are the roles that are granted to the role. The roles that were granted by the
+	// operator are revoked when they are removed from the list; the roles granted otherwise,
+	// e.g., through the group mappings of the LDAP provider, are left as is.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+
+	// Permissions are the permissions of the role on keyspaces and tables. The permissions
+	// that were granted by the operator are revoked when they are removed from the list;
+	// the permissions granted otherwise, e.g., by Cassandra to the creator of a keyspace or
+	// a table, are left as is.
+	// +optional
+	Permissions []RolePermissions `json:"permissions,omitempty"`
+
+	// DeletionPolicy is what happens to the role when the CassandraRole is deleted. Retain,
+	// the default, keeps the role. Delete drops it.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// RolePermissions are the permissions of a role on a keyspace, on a table, or on all the
+// keyspaces.
+type RolePermissions struct {
+	// Keyspace is the name of the keyspace. The permissions apply to all the keyspaces when
+	// it is empty.
+	// +optional
+	Keyspace string `json:"keyspace,omitempty"`
+
+	// Table is the name of a table of Keyspace. The permissions apply to the whole keyspace
+	// when it is empty.
+	// +optional
+	Table string `json:"table,omitempty"`
+
+	// Permissions are the granted permissions. ALL is every permission that applies to the
+	// resource; CREATE does not apply to tables.
+	// +kubebuilder:validation:MinItems=1
+	Permissions []Permission `json:"permissions"`
+}
+
+// CassandraRoleStatus defines the observed state of CassandraRole.
+type CassandraRoleStatus struct {
+	// ObservedGeneration is the generation of the CassandraRole that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SecretHash is the hash of the Secret whose password was last set on the role. The
+	// password is set again when the Secret changes.
+	// +optional
+	SecretHash string `json:"secretHash,omitempty"`
+
+	// GrantedRoles are the roles of Roles that were granted to the role by the operator.
+	// Only those are revoked when they are removed from Roles.
+	// +optional
+	GrantedRoles []string `json:"grantedRoles,omitempty"`
+
+	// GrantedPermissions are the permissions of Permissions that were granted to the role
+	// by the operator, by data resource, e.g., data/app. Only those are revoked when they
+	// are removed from Permissions.
+	// +optional
+	GrantedPermissions map[string][]string `json:"grantedPermissions,omitempty"`
+
+	// Error is the error encountered by the last reconciliation. It is empty if the last
+	// reconciliation succeeded.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.cluster.name`
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.error`
+
+// CassandraRole is the Schema for the cassandraroles API
+type CassandraRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CassandraRoleSpec   `json:"spec,omitempty"`
+	Status CassandraRoleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CassandraRoleList contains a list of CassandraRole
+type CassandraRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CassandraRole `json:"items"`
+}
+
+// RoleName returns the name of the role, which defaults to the name of the resource.
+func (in *CassandraRole) RoleName() string {
+	if in.Spec.Name == "" {
+		return in.Name
+	}
+	return in.Spec.Name
+}
+
+// SecretName returns the name of the Secret with the password of the role.
+func (in *CassandraRole) SecretName() string {
+	if in.Spec.SecretName == "" {
+		return in.Name + "-credentials"
+	}
+	return in.Spec.SecretName
+}
+
+func (in *CassandraRole) CanLogin() bool {
+	return in.Spec.Login == nil || *in.Spec.Login
+}
+
+func (in *CassandraRole) GetDeletionPolicy() DeletionPolicy {
+	if in.Spec.DeletionPolicy == "" {
+		return DeletionPolicyRetain
+	}
+	return in.Spec.DeletionPolicy
+}
+
+func init() {
+	SchemeBuilder.Register(&CassandraRole{}, &CassandraRoleList{})
+}
@@ -0,0 +1,810 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// CassandraYaml defines the cassandra.yaml properties of Cassandra 3.11 and 4.0. The
+// properties that do not exist in the server version of a datacenter, e.g., start_rpc with
+// 4.0, are not rendered in its configuration. They are reported by the
+// CassandraConfigValid condition of the K8ssandraCluster instead.
+//
+// The properties managed by the operator or by cass-operator, i.e., cluster_name,
+// seed_provider, partitioner, endpoint_snitch, the addresses, ports and directories, are
+// not exposed. Neither are the authentication options, which are set from Auth. The stores
+// and passwords of the encryption options are set by the operator when TLS is enabled.
+type CassandraYaml struct {
+	// Authenticator string `json:"authenticator,omitempty"`
+	//
+	// Authorizer string `json:"authorizer,omitempty"`
+	//
+	// RoleManager string `json:"role_manager,omitempty"`
+	//
+	// RoleValidityMillis *int64 `json:"roles_validity_in_ms,omitempty"`
+	//
+	// RoleUpdateIntervalMillis *int64 `json:"roles_update_interval_in_ms,omitempty"`
+	//
+	// PermissionValidityMillis *int64 `json:"permissions_validity_in_ms,omitempty"`
+
+	// The properties that only exist in some versions are tagged with the version that
+	// introduced them (since) or with the version that removed them (removed).
+
+	// Tokens
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NumTokens *int `json:"num_tokens,omitempty"`
+
+	// +optional
+	AllocateTokensForKeyspace *string `json:"allocate_tokens_for_keyspace,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AllocateTokensForLocalReplicationFactor *int `json:"allocate_tokens_for_local_replication_factor,omitempty" since:"4.0"`
+
+	// +optional
+	AutoBootstrap *bool `json:"auto_bootstrap,omitempty"`
+
+	// Hints and batchlog
+
+	// +optional
+	HintedHandoffEnabled *bool `json:"hinted_handoff_enabled,omitempty"`
+
+	// +optional
+	HintedHandoffDisabledDatacenters []string `json:"hinted_handoff_disabled_datacenters,omitempty"`
+
+	// +optional
+	MaxHintWindowMs *int `json:"max_hint_window_in_ms,omitempty"`
+
+	// +optional
+	HintedHandoffThrottleKb *int `json:"hinted_handoff_throttle_in_kb,omitempty"`
+
+	// +optional
+	MaxHintsDeliveryThreads *int `json:"max_hints_delivery_threads,omitempty"`
+
+	// +optional
+	HintsFlushPeriodMs *int `json:"hints_flush_period_in_ms,omitempty"`
+
+	// +optional
+	MaxHintsFileSizeMb *int `json:"max_hints_file_size_in_mb,omitempty"`
+
+	// +optional
+	HintsCompression *ParameterizedClass `json:"hints_compression,omitempty"`
+
+	// +optional
+	BatchlogReplayThrottleKb *int `json:"batchlog_replay_throttle_in_kb,omitempty"`
+
+	// Failure policies
+
+	// +kubebuilder:validation:Enum=die;stop_paranoid;stop;best_effort;ignore
+	// +optional
+	DiskFailurePolicy *string `json:"disk_failure_policy,omitempty"`
+
+	// +kubebuilder:validation:Enum=die;stop;stop_commit;ignore
+	// +optional
+	CommitFailurePolicy *string `json:"commit_failure_policy,omitempty"`
+
+	// Caches
+
+	// +optional
+	PreparedStatementsCacheSizeMb *int `json:"prepared_statements_cache_size_mb,omitempty"`
+
+	// +optional
+	ThriftPreparedStatementCacheSizeMb *int `json:"thrift_prepared_statements_cache_size_mb,omitempty" removed:"4.0"`
+
+	// +optional
+	KeyCacheSizeMb *int `json:"key_cache_size_in_mb,omitempty"`
+
+	// +optional
+	KeyCacheSavePeriod *int `json:"key_cache_save_period,omitempty"`
+
+	// +optional
+	KeyCacheKeysToSave *int `json:"key_cache_keys_to_save,omitempty"`
+
+	// +optional
+	KeyCacheMigrateDuringCompaction *bool `json:"key_cache_migrate_during_compaction,omitempty" since:"4.0"`
+
+	// +optional
+	RowCacheClassName *string `json:"row_cache_class_name,omitempty"`
+
+	// +optional
+	RowCacheSizeMb *int `json:"row_cache_size_in_mb,omitempty"`
+
+	// +optional
+	RowCacheSavePeriod *int `json:"row_cache_save_period,omitempty"`
+
+	// +optional
+	RowCacheKeysToSave *int `json:"row_cache_keys_to_save,omitempty"`
+
+	// +optional
+	CounterCacheSizeMb *int `json:"counter_cache_size_in_mb,omitempty"`
+
+	// +optional
+	CounterCacheSavePeriod *int `json:"counter_cache_save_period,omitempty"`
+
+	// +optional
+	CounterCacheKeysToSave *int `json:"counter_cache_keys_to_save,omitempty"`
+
+	// +optional
+	CacheLoadTimeoutSeconds *int `json:"cache_load_timeout_seconds,omitempty"`
+
+	// +optional
+	FileCacheSizeMb *int `json:"file_cache_size_in_mb,omitempty"`
+
+	// +optional
+	FileCacheRoundUp *bool `json:"file_cache_round_up,omitempty"`
+
+	// +optional
+	BufferPoolUseHeapIfExhausted *bool `json:"buffer_pool_use_heap_if_exhausted,omitempty"`
+
+	// +kubebuilder:validation:Enum=ssd;spinning
+	// +optional
+	DiskOptimizationStrategy *string `json:"disk_optimization_strategy,omitempty"`
+
+	// +kubebuilder:validation:Enum=auto;mmap;mmap_index_only;standard
+	// +optional
+	DiskAccessMode *string `json:"disk_access_mode,omitempty"`
+
+	// Commit log
+
+	// +kubebuilder:validation:Enum=periodic;batch;group
+	// +optional
+	CommitLogSync *string `json:"commitlog_sync,omitempty"`
+
+	// +optional
+	CommitLogSyncBatchWindowMs *float64 `json:"commitlog_sync_batch_window_in_ms,omitempty"`
+
+	// +optional
+	CommitLogSyncGroupWindowMs *float64 `json:"commitlog_sync_group_window_in_ms,omitempty" since:"4.0"`
+
+	// +optional
+	CommitLogSyncPeriodMs *int `json:"commitlog_sync_period_in_ms,omitempty"`
+
+	// +optional
+	PeriodicCommitLogSyncLagBlockMs *int `json:"periodic_commitlog_sync_lag_block_in_ms,omitempty" since:"4.0"`
+
+	// +optional
+	CommitLogSegmentSizeMb *int `json:"commitlog_segment_size_in_mb,omitempty"`
+
+	// +optional
+	CommitLogTotalSpaceMb *int `json:"commitlog_total_space_in_mb,omitempty"`
+
+	// +optional
+	CommitLogCompression *ParameterizedClass `json:"commitlog_compression,omitempty"`
+
+	// +optional
+	CommitLogMaxCompressionBuffersInPool *int `json:"commitlog_max_compression_buffers_in_pool,omitempty"`
+
+	// +kubebuilder:validation:Enum=none;fast;table
+	// +optional
+	FlushCompression *string `json:"flush_compression,omitempty" since:"4.0"`
+
+	// Thread pools
+
+	// +optional
+	ConcurrentReads *int `json:"concurrent_reads,omitempty"`
+
+	// +optional
+	ConcurrentWrites *int `json:"concurrent_writes,omitempty"`
+
+	// +optional
+	ConcurrentCounterWrites *int `json:"concurrent_counter_writes,omitempty"`
+
+	// +optional
+	ConcurrentMaterializedViewWrites *int `json:"concurrent_materialized_view_writes,omitempty"`
+
+	// +optional
+	ConcurrentMaterializedViewBuilders *int `json:"concurrent_materialized_view_builders,omitempty" since:"4.0"`
+
+	// +optional
+	ConcurrentValidations *int `json:"concurrent_validations,omitempty" since:"4.0"`
+
+	// +optional
+	ConcurrentCompactors *int `json:"concurrent_compactors,omitempty"`
+
+	// Memtables
+
+	// +optional
+	MemtableHeapSpaceMb *int `json:"memtable_heap_space_in_mb,omitempty"`
+
+	// +optional
+	MemtableOffheapSpaceMb *int `json:"memtable_offheap_space_in_mb,omitempty"`
+
+	// +optional
+	MemtableCleanupThreshold *float64 `json:"memtable_cleanup_threshold,omitempty"`
+
+	// +kubebuilder:validation:Enum=unslabbed_heap_buffers;heap_buffers;offheap_buffers;offheap_objects
+	// +optional
+	MemtableAllocationType *string `json:"memtable_allocation_type,omitempty"`
+
+	// +optional
+	MemtableFlushWriters *int `json:"memtable_flush_writers,omitempty"`
+
+	// Change data capture
+
+	// +optional
+	CdcEnabled *bool `json:"cdc_enabled,omitempty"`
+
+	// +optional
+	CdcTotalSpaceMb *int `json:"cdc_total_space_in_mb,omitempty"`
+
+	// +optional
+	CdcFreeSpaceCheckIntervalMs *int `json:"cdc_free_space_check_interval_ms,omitempty"`
+
+	// Index summaries and SSTables
+
+	// +optional
+	IndexSummaryCapacityMb *int `json:"index_summary_capacity_in_mb,omitempty"`
+
+	// +optional
+	IndexSummaryResizeIntervalMinutes *int `json:"index_summary_resize_interval_in_minutes,omitempty"`
+
+	// +optional
+	TrickleFsync *bool `json:"trickle_fsync,omitempty"`
+
+	// +optional
+	TrickleFsyncIntervalKb *int `json:"trickle_fsync_interval_in_kb,omitempty"`
+
+	// +optional
+	ColumnIndexSizeKb *int `json:"column_index_size_in_kb,omitempty"`
+
+	// +optional
+	ColumnIndexCacheSizeKb *int `json:"column_index_cache_size_in_kb,omitempty"`
+
+	// +optional
+	SstablePreemptiveOpenIntervalMb *int `json:"sstable_preemptive_open_interval_in_mb,omitempty"`
+
+	// +optional
+	AutomaticSstableUpgrade *bool `json:"automatic_sstable_upgrade,omitempty" since:"4.0"`
+
+	// +optional
+	MaxConcurrentAutomaticSstableUpgrades *int `json:"max_concurrent_automatic_sstable_upgrades,omitempty" since:"4.0"`
+
+	// Native transport
+
+	// +optional
+	StartNativeTransport *bool `json:"start_native_transport,omitempty"`
+
+	// +optional
+	NativeTransportMaxThreads *int `json:"native_transport_max_threads,omitempty"`
+
+	// +optional
+	NativeTransportMaxFrameSizeMb *int `json:"native_transport_max_frame_size_in_mb,omitempty"`
+
+	// +optional
+	NativeTransportMaxConcurrentConnections *int64 `json:"native_transport_max_concurrent_connections,omitempty"`
+
+	// +optional
+	NativeTransportMaxConcurrentConnectionsPerIp *int64 `json:"native_transport_max_concurrent_connections_per_ip,omitempty"`
+
+	// +optional
+	NativeTransportMaxConcurrentRequestsInBytes *int64 `json:"native_transport_max_concurrent_requests_in_bytes,omitempty"`
+
+	// +optional
+	NativeTransportMaxConcurrentRequestsInBytesPerIp *int64 `json:"native_transport_max_concurrent_requests_in_bytes_per_ip,omitempty"`
+
+	// +optional
+	NativeTransportFlushInBatchesLegacy *bool `json:"native_transport_flush_in_batches_legacy,omitempty" since:"4.0"`
+
+	// +optional
+	NativeTransportAllowOlderProtocols *bool `json:"native_transport_allow_older_protocols,omitempty" since:"4.0"`
+
+	// +optional
+	NativeTransportIdleTimeoutMs *int64 `json:"native_transport_idle_timeout_in_ms,omitempty" since:"4.0"`
+
+	// +optional
+	NativeTransportReceiveQueueCapacityInBytes *int `json:"native_transport_receive_queue_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// Thrift
+
+	// +optional
+	StartRpc *bool `json:"start_rpc,omitempty" removed:"4.0"`
+
+	// +optional
+	RpcKeepalive *bool `json:"rpc_keepalive,omitempty" removed:"4.0"`
+
+	// +kubebuilder:validation:Enum=sync;hsha
+	// +optional
+	RpcServerType *string `json:"rpc_server_type,omitempty" removed:"4.0"`
+
+	// +optional
+	RpcMinThreads *int `json:"rpc_min_threads,omitempty" removed:"4.0"`
+
+	// +optional
+	RpcMaxThreads *int `json:"rpc_max_threads,omitempty" removed:"4.0"`
+
+	// +optional
+	RpcSendBuffSizeInBytes *int `json:"rpc_send_buff_size_in_bytes,omitempty" removed:"4.0"`
+
+	// +optional
+	RpcRecvBuffSizeInBytes *int `json:"rpc_recv_buff_size_in_bytes,omitempty" removed:"4.0"`
+
+	// +optional
+	ThriftFramedTransportSizeMb *int `json:"thrift_framed_transport_size_in_mb,omitempty" removed:"4.0"`
+
+	// +optional
+	RequestScheduler *string `json:"request_scheduler,omitempty" removed:"4.0"`
+
+	// +kubebuilder:validation:Enum=keyspace
+	// +optional
+	RequestSchedulerId *string `json:"request_scheduler_id,omitempty" removed:"4.0"`
+
+	// Internode messaging
+
+	// +optional
+	InternodeAuthenticator *string `json:"internode_authenticator,omitempty"`
+
+	// +optional
+	InternodeSendBuffSizeInBytes *int `json:"internode_send_buff_size_in_bytes,omitempty" removed:"4.0"`
+
+	// +optional
+	InternodeRecvBuffSizeInBytes *int `json:"internode_recv_buff_size_in_bytes,omitempty" removed:"4.0"`
+
+	// +optional
+	InternodeSocketSendBufferSizeInBytes *int `json:"internode_socket_send_buffer_size_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeSocketReceiveBufferSizeInBytes *int `json:"internode_socket_receive_buffer_size_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeApplicationSendQueueCapacityInBytes *int `json:"internode_application_send_queue_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeApplicationSendQueueReserveEndpointCapacityInBytes *int `json:"internode_application_send_queue_reserve_endpoint_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeApplicationSendQueueReserveGlobalCapacityInBytes *int `json:"internode_application_send_queue_reserve_global_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeApplicationReceiveQueueCapacityInBytes *int `json:"internode_application_receive_queue_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeApplicationReceiveQueueReserveEndpointCapacityInBytes *int `json:"internode_application_receive_queue_reserve_endpoint_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeApplicationReceiveQueueReserveGlobalCapacityInBytes *int `json:"internode_application_receive_queue_reserve_global_capacity_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeMaxMessageSizeInBytes *int `json:"internode_max_message_size_in_bytes,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeTcpConnectTimeoutMs *int `json:"internode_tcp_connect_timeout_in_ms,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeTcpUserTimeoutMs *int `json:"internode_tcp_user_timeout_in_ms,omitempty" since:"4.0"`
+
+	// +optional
+	InternodeStreamingTcpUserTimeoutMs *int `json:"internode_streaming_tcp_user_timeout_in_ms,omitempty" since:"4.0"`
+
+	// +kubebuilder:validation:Enum=all;dc;none
+	// +optional
+	InternodeCompression *string `json:"internode_compression,omitempty"`
+
+	// +optional
+	InterDcTcpNodelay *bool `json:"inter_dc_tcp_nodelay,omitempty"`
+
+	// +optional
+	OtcCoalescingStrategy *string `json:"otc_coalescing_strategy,omitempty"`
+
+	// +optional
+	OtcCoalescingWindowUs *int `json:"otc_coalescing_window_us,omitempty"`
+
+	// +optional
+	OtcCoalescingEnoughCoalescedMessages *int `json:"otc_coalescing_enough_coalesced_messages,omitempty"`
+
+	// +optional
+	OtcBacklogExpirationIntervalMs *int `json:"otc_backlog_expiration_interval_ms,omitempty"`
+
+	// +optional
+	MaxMutationSizeKb *int `json:"max_mutation_size_in_kb,omitempty"`
+
+	// +optional
+	MaxValueSizeMb *int `json:"max_value_size_in_mb,omitempty"`
+
+	// +optional
+	ConsecutiveMessageErrorsThreshold *int `json:"consecutive_message_errors_threshold,omitempty" since:"4.0"`
+
+	// +optional
+	BackPressureEnabled *bool `json:"back_pressure_enabled,omitempty"`
+
+	// +optional
+	BackPressureStrategy *ParameterizedClass `json:"back_pressure_strategy,omitempty"`
+
+	// Encryption
+
+	// +optional
+	ServerEncryptionOptions *ServerEncryptionOptions `json:"server_encryption_options,omitempty"`
+
+	// +optional
+	ClientEncryptionOptions *ClientEncryptionOptions `json:"client_encryption_options,omitempty"`
+
+	// Backups and snapshots
+
+	// +optional
+	IncrementalBackups *bool `json:"incremental_backups,omitempty"`
+
+	// +optional
+	SnapshotBeforeCompaction *bool `json:"snapshot_before_compaction,omitempty"`
+
+	// +optional
+	AutoSnapshot *bool `json:"auto_snapshot,omitempty"`
+
+	// Batches
+
+	// +optional
+	BatchSizeWarnThresholdKb *int `json:"batch_size_warn_threshold_in_kb,omitempty"`
+
+	// +optional
+	BatchSizeFailThresholdKb *int `json:"batch_size_fail_threshold_in_kb,omitempty"`
+
+	// +optional
+	UnloggedBatchAcrossPartitionsWarnThreshold *int `json:"unlogged_batch_across_partitions_warn_threshold,omitempty"`
+
+	// Compaction
+
+	// +optional
+	CompactionThroughputMbPerSec *int `json:"compaction_throughput_mb_per_sec,omitempty"`
+
+	// +optional
+	CompactionLargePartitionWarningThresholdMb *int `json:"compaction_large_partition_warning_threshold_mb,omitempty"`
+
+	// +optional
+	CompactionTombstoneWarningThreshold *int `json:"compaction_tombstone_warning_threshold,omitempty" since:"4.0"`
+
+	// Streaming
+
+	// +optional
+	StreamEntireSstables *bool `json:"stream_entire_sstables,omitempty" since:"4.0"`
+
+	// +optional
+	StreamThroughputOutboundMegabitsPerSec *int `json:"stream_throughput_outbound_megabits_per_sec,omitempty"`
+
+	// +optional
+	InterDcStreamThroughputOutboundMegabitsPerSec *int `json:"inter_dc_stream_throughput_outbound_megabits_per_sec,omitempty"`
+
+	// +optional
+	StreamingKeepAlivePeriodInSecs *int `json:"streaming_keep_alive_period_in_secs,omitempty"`
+
+	// +optional
+	StreamingConnectionsPerHost *int `json:"streaming_connections_per_host,omitempty"`
+
+	// +optional
+	StreamingSocketTimeoutMs *int `json:"streaming_socket_timeout_in_ms,omitempty" removed:"4.0"`
+
+	// Timeouts
+
+	// +optional
+	ReadRequestTimeoutMs *int64 `json:"read_request_timeout_in_ms,omitempty"`
+
+	// +optional
+	RangeRequestTimeoutMs *int64 `json:"range_request_timeout_in_ms,omitempty"`
+
+	// +optional
+	WriteRequestTimeoutMs *int64 `json:"write_request_timeout_in_ms,omitempty"`
+
+	// +optional
+	CounterWriteRequestTimeoutMs *int64 `json:"counter_write_request_timeout_in_ms,omitempty"`
+
+	// +optional
+	CasContentionTimeoutMs *int64 `json:"cas_contention_timeout_in_ms,omitempty"`
+
+	// +optional
+	TruncateRequestTimeoutMs *int64 `json:"truncate_request_timeout_in_ms,omitempty"`
+
+	// +optional
+	RequestTimeoutMs *int64 `json:"request_timeout_in_ms,omitempty"`
+
+	// +optional
+	SlowQueryLogTimeoutMs *int `json:"slow_query_log_timeout_in_ms,omitempty"`
+
+	// +optional
+	CrossNodeTimeout *bool `json:"cross_node_timeout,omitempty"`
+
+	// +optional
+	BlockForPeersTimeoutInSecs *int `json:"block_for_peers_timeout_in_secs,omitempty" since:"4.0"`
+
+	// +optional
+	BlockForPeersInRemoteDcs *bool `json:"block_for_peers_in_remote_dcs,omitempty" since:"4.0"`
+
+	// Failure detection and dynamic snitch
+
+	// +optional
+	PhiConvictThreshold *float64 `json:"phi_convict_threshold,omitempty"`
+
+	// +optional
+	DynamicSnitchUpdateIntervalMs *int `json:"dynamic_snitch_update_interval_in_ms,omitempty"`
+
+	// +optional
+	DynamicSnitchResetIntervalMs *int `json:"dynamic_snitch_reset_interval_in_ms,omitempty"`
+
+	// +optional
+	DynamicSnitchBadnessThreshold *float64 `json:"dynamic_snitch_badness_threshold,omitempty"`
+
+	// Tombstones
+
+	// +optional
+	TombstoneWarnThreshold *int `json:"tombstone_warn_threshold,omitempty"`
+
+	// +optional
+	TombstoneFailureThreshold *int `json:"tombstone_failure_threshold,omitempty"`
+
+	// +optional
+	ReplicaFilteringProtection *ReplicaFilteringProtectionOptions `json:"replica_filtering_protection,omitempty"`
+
+	// +kubebuilder:validation:Enum=disabled;warn;exception
+	// +optional
+	CorruptedTombstoneStrategy *string `json:"corrupted_tombstone_strategy,omitempty" since:"4.0"`
+
+	// +optional
+	InitialRangeTombstoneListAllocationSize *int `json:"initial_range_tombstone_list_allocation_size,omitempty" since:"4.0"`
+
+	// +optional
+	RangeTombstoneListGrowthFactor *float64 `json:"range_tombstone_list_growth_factor,omitempty" since:"4.0"`
+
+	// Repairs
+
+	// +optional
+	RepairSessionMaxTreeDepth *int `json:"repair_session_max_tree_depth,omitempty"`
+
+	// +optional
+	RepairSessionSpaceMb *int `json:"repair_session_space_in_mb,omitempty" since:"4.0"`
+
+	// +optional
+	RepairCommandPoolSize *int `json:"repair_command_pool_size,omitempty" since:"4.0"`
+
+	// +kubebuilder:validation:Enum=queue;reject
+	// +optional
+	RepairCommandPoolFullStrategy *string `json:"repair_command_pool_full_strategy,omitempty" since:"4.0"`
+
+	// +optional
+	UseOffheapMerkleTrees *bool `json:"use_offheap_merkle_trees,omitempty" since:"4.0"`
+
+	// +optional
+	RepairedDataTrackingForRangeReadsEnabled *bool `json:"repaired_data_tracking_for_range_reads_enabled,omitempty" since:"4.0"`
+
+	// +optional
+	RepairedDataTrackingForPartitionReadsEnabled *bool `json:"repaired_data_tracking_for_partition_reads_enabled,omitempty" since:"4.0"`
+
+	// +optional
+	ReportUnconfirmedRepairedDataMismatches *bool `json:"report_unconfirmed_repaired_data_mismatches,omitempty" since:"4.0"`
+
+	// +optional
+	SnapshotOnRepairedDataMismatch *bool `json:"snapshot_on_repaired_data_mismatch,omitempty" since:"4.0"`
+
+	// +optional
+	ValidationPreviewPurgeHeadStartInSec *int `json:"validation_preview_purge_head_start_in_sec,omitempty" since:"4.0"`
+
+	// +optional
+	AutoOptimiseIncRepairStreams *bool `json:"auto_optimise_inc_repair_streams,omitempty" since:"4.0"`
+
+	// +optional
+	AutoOptimiseFullRepairStreams *bool `json:"auto_optimise_full_repair_streams,omitempty" since:"4.0"`
+
+	// +optional
+	AutoOptimisePreviewRepairStreams *bool `json:"auto_optimise_preview_repair_streams,omitempty" since:"4.0"`
+
+	// Tracing and logging
+
+	// +optional
+	TracetypeQueryTtl *int `json:"tracetype_query_ttl,omitempty"`
+
+	// +optional
+	TracetypeRepairTtl *int `json:"tracetype_repair_ttl,omitempty"`
+
+	// +optional
+	GcLogThresholdMs *int `json:"gc_log_threshold_in_ms,omitempty"`
+
+	// +optional
+	GcWarnThresholdMs *int `json:"gc_warn_threshold_in_ms,omitempty"`
+
+	// +optional
+	AuditLoggingOptions *AuditLogOptions `json:"audit_logging_options,omitempty" since:"4.0"`
+
+	// +optional
+	FullQueryLoggingOptions *FullQueryLoggerOptions `json:"full_query_logging_options,omitempty" since:"4.0"`
+
+	// +optional
+	DiagnosticEventsEnabled *bool `json:"diagnostic_events_enabled,omitempty" since:"4.0"`
+
+	// +kubebuilder:validation:Enum=ANY;ONE;TWO;THREE;QUORUM;ALL;LOCAL_QUORUM;EACH_QUORUM;SERIAL;LOCAL_SERIAL;LOCAL_ONE
+	// +optional
+	IdealConsistencyLevel *string `json:"ideal_consistency_level,omitempty" since:"4.0"`
+
+	// User defined functions
+
+	// +optional
+	EnableUserDefinedFunctions *bool `json:"enable_user_defined_functions,omitempty"`
+
+	// +optional
+	EnableScriptedUserDefinedFunctions *bool `json:"enable_scripted_user_defined_functions,omitempty"`
+
+	// +optional
+	EnableUserDefinedFunctionsThreads *bool `json:"enable_user_defined_functions_threads,omitempty"`
+
+	// +optional
+	UserDefinedFunctionWarnTimeout *int64 `json:"user_defined_function_warn_timeout,omitempty"`
+
+	// +optional
+	UserDefinedFunctionFailTimeout *int64 `json:"user_defined_function_fail_timeout,omitempty"`
+
+	// +kubebuilder:validation:Enum=ignore;die;die_immediate
+	// +optional
+	UserFunctionTimeoutPolicy *string `json:"user_function_timeout_policy,omitempty"`
+
+	// Features
+
+	// +optional
+	EnableMaterializedViews *bool `json:"enable_materialized_views,omitempty"`
+
+	// +optional
+	EnableSasiIndexes *bool `json:"enable_sasi_indexes,omitempty"`
+
+	// +optional
+	EnableTransientReplication *bool `json:"enable_transient_replication,omitempty" since:"4.0"`
+
+	// +optional
+	EnableDropCompactStorage *bool `json:"enable_drop_compact_storage,omitempty"`
+
+	// +optional
+	CheckForDuplicateRowsDuringReads *bool `json:"check_for_duplicate_rows_during_reads,omitempty"`
+
+	// +optional
+	CheckForDuplicateRowsDuringCompaction *bool `json:"check_for_duplicate_rows_during_compaction,omitempty"`
+
+	// +optional
+	TableCountWarnThreshold *int `json:"table_count_warn_threshold,omitempty" since:"4.0"`
+
+	// +optional
+	KeyspaceCountWarnThreshold *int `json:"keyspace_count_warn_threshold,omitempty" since:"4.0"`
+
+	// +optional
+	WindowsTimerInterval *int `json:"windows_timer_interval,omitempty"`
+}
+
+// ParameterizedClass is a Java class name and the parameters used to instantiate it, as
+// used for instance by commitlog_compression.
+type ParameterizedClass struct {
+	ClassName string `json:"class_name"`
+
+	// +optional
+	Parameters []map[string]string `json:"parameters,omitempty"`
+}
+
+// EncryptionOptions are the options shared by server_encryption_options and
+// client_encryption_options.
+type EncryptionOptions struct {
+	// +optional
+	Keystore *string `json:"keystore,omitempty"`
+
+	// +optional
+	KeystorePassword *string `json:"keystore_password,omitempty"`
+
+	// +optional
+	Truststore *string `json:"truststore,omitempty"`
+
+	// +optional
+	TruststorePassword *string `json:"truststore_password,omitempty"`
+
+	// +optional
+	Protocol *string `json:"protocol,omitempty"`
+
+	// +optional
+	StoreType *string `json:"store_type,omitempty"`
+
+	// +optional
+	CipherSuites []string `json:"cipher_suites,omitempty"`
+
+	// +optional
+	RequireClientAuth *bool `json:"require_client_auth,omitempty"`
+}
+
+type ServerEncryptionOptions struct {
+	// +kubebuilder:validation:Enum=none;all;dc;rack
+	// +optional
+	InternodeEncryption *string `json:"internode_encryption,omitempty"`
+
+	// +optional
+	RequireEndpointVerification *bool `json:"require_endpoint_verification,omitempty"`
+
+	EncryptionOptions `json:",inline"`
+}
+
+type ClientEncryptionOptions struct {
+	Enabled bool `json:"enabled"`
+
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+
+	EncryptionOptions `json:",inline"`
+}
+
+type ReplicaFilteringProtectionOptions struct {
+	// +optional
+	CachedRowsWarnThreshold *int `json:"cached_rows_warn_threshold,omitempty"`
+
+	// +optional
+	CachedRowsFailThreshold *int `json:"cached_rows_fail_threshold,omitempty"`
+}
+
+type AuditLogOptions struct {
+	Enabled bool `json:"enabled"`
+
+	// +optional
+	Logger *ParameterizedClass `json:"logger,omitempty"`
+
+	// +optional
+	AuditLogsDir *string `json:"audit_logs_dir,omitempty"`
+
+	// +optional
+	IncludedKeyspaces *string `json:"included_keyspaces,omitempty"`
+
+	// +optional
+	ExcludedKeyspaces *string `json:"excluded_keyspaces,omitempty"`
+
+	// +optional
+	IncludedCategories *string `json:"included_categories,omitempty"`
+
+	// +optional
+	ExcludedCategories *string `json:"excluded_categories,omitempty"`
+
+	// +optional
+	IncludedUsers *string `json:"included_users,omitempty"`
+
+	// +optional
+	ExcludedUsers *string `json:"excluded_users,omitempty"`
+
+	// +kubebuilder:validation:Enum=MINUTELY;HOURLY;DAILY
+	// +optional
+	RollCycle *string `json:"roll_cycle,omitempty"`
+
+	// +optional
+	Block *bool `json:"block,omitempty"`
+
+	// +optional
+	MaxQueueWeight *int `json:"max_queue_weight,omitempty"`
+
+	// +optional
+	MaxLogSize *int64 `json:"max_log_size,omitempty"`
+
+	// +optional
+	ArchiveCommand *string `json:"archive_command,omitempty"`
+
+	// +optional
+	MaxArchiveRetries *int `json:"max_archive_retries,omitempty"`
+}
+
+type FullQueryLoggerOptions struct {
+	// +optional
+	LogDir *string `json:"log_dir,omitempty"`
+
+	// +optional
+	ArchiveCommand *string `json:"archive_command,omitempty"`
+
+	// +kubebuilder:validation:Enum=MINUTELY;HOURLY;DAILY
+	// +optional
+	RollCycle *string `json:"roll_cycle,omitempty"`
+
+	// +optional
+	Block *bool `json:"block,omitempty"`
+
+	// +optional
+	MaxQueueWeight *int `json:"max_queue_weight,omitempty"`
+
+	// +optional
+	MaxLogSize *int64 `json:"max_log_size,omitempty"`
+
+	// +optional
+	MaxArchiveRetries *int `json:"max_archive_retries,omitempty"`
+}
@@ -9,6 +9,33 @@ const (
 	// regardless of whether the replication of the system keyspaces changes.
 	SystemReplicationAnnotation = "k8ssandra.io/system-replication"
 
+	// RestartedAtAnnotation is set on the pod template of the Stargate deployments to
+	// restart them after a cluster-wide rolling restart. Its value is the time at which the
+	// rolling restart was requested.
+	RestartedAtAnnotation = "k8ssandra.io/restarted-at"
+
+	// StoppedReplicasAnnotation is set on the Stargate and Reaper deployments of a
+	// datacenter when they are scaled down to zero before the datacenter is stopped. Its
+	// value is the number of replicas to restore when the datacenter is resumed.
+	StoppedReplicasAnnotation = "k8ssandra.io/stopped-replicas"
+
+	// ConfigFilesHashAnnotation is set on the pod template of a CassandraDatacenter that
+	// uses ConfigFiles. Its value is a hash of the content of the files, so that changing
+	// them triggers a rolling restart.
+	ConfigFilesHashAnnotation = "k8ssandra.io/config-files-hash"
+
+	// EncryptionStoresHashAnnotation is set on the pod template of the Cassandra, Stargate
+	// and Reaper pods that mount encryption stores. Its value is a hash of the stores, so
+	// that renewing the certificates triggers a rolling restart. The K8ssandraCluster
+	// controller sets it on the Stargate and Reaper resources, whose controllers copy it to
+	// the pod template of their deployments.
+	EncryptionStoresHashAnnotation = "k8ssandra.io/encryption-stores-hash"
+
+	// LDAPBindSecretHashAnnotation is set on the pod template of a CassandraDatacenter that
+	// authenticates with LDAP. Its value is a hash of the bind Secret, so that changing the
+	// bind credentials triggers a rolling restart.
+	LDAPBindSecretHashAnnotation = "k8ssandra.io/ldap-bind-secret-hash"
+
 	NameLabel      = "app.kubernetes.io/name"
 	NameLabelValue = "k8ssandra-operator"
 
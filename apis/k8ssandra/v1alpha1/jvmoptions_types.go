@@ -0,0 +1,374 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	Java8  = 8
+	Java11 = 11
+
+	GarbageCollectorCMS = "CMS"
+	GarbageCollectorG1  = "G1GC"
+	GarbageCollectorZGC = "ZGC"
+
+	// DefaultJmxPort is the JMX port of Cassandra. Reaper connects to it.
+	DefaultJmxPort = 7199
+
+	// ServerDataMountPath is where cass-operator mounts the data volume of Cassandra.
+	ServerDataMountPath = "/var/lib/cassandra"
+)
+
+type JvmOptions struct {
+	// HeapSize is the initial and maximum size of the heap. When not set, it is computed
+	// from the memory limit, or request, of the Cassandra container.
+	// +optional
+	HeapSize *resource.Quantity `json:"heapSize,omitempty"`
+
+	// HeapNewGenSize is the size of the young generation. It can only be set with the CMS
+	// garbage collector, G1 and ZGC size the young generation themselves. When not set
+	// with CMS, it is computed from the CPU limit, or request, of the Cassandra container.
+	// +optional
+	HeapNewGenSize *resource.Quantity `json:"heapNewGenSize,omitempty"`
+
+	// +optional
+	AdditionalOptions []string `json:"additionalOptions,omitempty"`
+
+	// JavaVersion is the major version of the JVM that runs Cassandra. With Cassandra 4.0,
+	// it determines whether the options are rendered in jvm8-server.options or in
+	// jvm11-server.options. Cassandra 3.11 only supports Java 8. Defaults to 8 with
+	// Cassandra 3.11 and to 11 with Cassandra 4.0.
+	// +kubebuilder:validation:Enum=8;11
+	// +optional
+	JavaVersion *int `json:"javaVersion,omitempty"`
+
+	// GarbageCollector is the garbage collector of the JVM. CMS is only supported with
+	// Java 8 and ZGC with Java 11. When not set, the default of Cassandra for the Java
+	// version is used.
+	// +kubebuilder:validation:Enum=CMS;G1GC;ZGC
+	// +optional
+	GarbageCollector *string `json:"garbageCollector,omitempty"`
+
+	// Cms tunes the CMS garbage collector. It can only be set when garbageCollector is CMS.
+	// +optional
+	Cms *CmsOptions `json:"cms,omitempty"`
+
+	// G1 tunes the G1 garbage collector. It can only be set when garbageCollector is G1GC.
+	// +optional
+	G1 *G1Options `json:"g1,omitempty"`
+
+	// GcLogging configures the GC log of Cassandra, which is written to
+	// /var/log/cassandra/gc.log.
+	// +optional
+	GcLogging *GcLoggingOptions `json:"gcLogging,omitempty"`
+
+	// HeapDump enables heap dumps on OutOfMemoryError.
+	// +optional
+	HeapDump *HeapDumpOptions `json:"heapDump,omitempty"`
+
+	// +optional
+	Jmx *JmxOptions `json:"jmx,omitempty"`
+}
+
+type CmsOptions struct {
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SurvivorRatio *int `json:"survivorRatio,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	MaxTenuringThreshold *int `json:"maxTenuringThreshold,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	InitiatingOccupancyFraction *int `json:"initiatingOccupancyFraction,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WaitDurationMs *int `json:"waitDurationMs,omitempty"`
+}
+
+type G1Options struct {
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RSetUpdatingPauseTimePercent *int `json:"rSetUpdatingPauseTimePercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxGcPauseMs *int `json:"maxGcPauseMs,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	InitiatingHeapOccupancyPercent *int `json:"initiatingHeapOccupancyPercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ParallelGcThreads *int `json:"parallelGcThreads,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConcurrentGcThreads *int `json:"concurrentGcThreads,omitempty"`
+}
+
+type GcLoggingOptions struct {
+	// Enabled turns the GC log on or off. Cassandra enables it by default.
+	Enabled bool `json:"enabled"`
+
+	// FileCount is the number of rotated GC log files that are kept. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FileCount *int `json:"fileCount,omitempty"`
+
+	// FileSize is the size at which the GC log file is rotated. Defaults to 10Mi.
+	// +optional
+	FileSize *resource.Quantity `json:"fileSize,omitempty"`
+}
+
+type HeapDumpOptions struct {
+	// Path is the directory where heap dumps are written. It must be on a mounted volume,
+	// i.e., under /var/lib/cassandra or under the mountPath of one of the additional
+	// volumes of the datacenter, so that heap dumps survive the restart of the pod.
+	Path string `json:"path"`
+}
+
+type JmxOptions struct {
+	// Port is the port of the JMX server of Cassandra. It cannot be changed from the
+	// default of 7199 when Reaper is deployed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int `json:"port,omitempty"`
+}
+
+func (in *CassandraConfig) getJvmOptions() *JvmOptions {
+	if in == nil {
+		return nil
+	}
+	return in.JvmOptions
+}
+
+// GetJavaVersion returns the Java version of the options for the given Cassandra version.
+func (in *JvmOptions) GetJavaVersion(serverVersion string) int {
+	if in != nil && in.JavaVersion != nil {
+		return *in.JavaVersion
+	}
+	if version.Cassandra3_11.Contains(serverVersion) {
+		return Java8
+	}
+	return Java11
+}
+
+// GetJmxPort returns the JMX port of Cassandra.
+func (in *JvmOptions) GetJmxPort() int {
+	if in == nil || in.Jmx == nil || in.Jmx.Port == nil {
+		return DefaultJmxPort
+	}
+	return *in.Jmx.Port
+}
+
+// Validate checks that the options are consistent with each other and with serverVersion.
+// mountPaths are the paths where volumes are mounted in the Cassandra container, heap
+// dumps must be written under one of them.
+func (in *JvmOptions) Validate(serverVersion string, mountPaths []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if in == nil {
+		return allErrs
+	}
+
+	javaVersion := in.GetJavaVersion(serverVersion)
+	if javaVersion != Java8 && version.Cassandra3_11.Contains(serverVersion) {
+		allErrs = append(allErrs, field.Invalid(path.Child("javaVersion"), javaVersion,
+			fmt.Sprintf("Cassandra %s only supports Java 8", serverVersion)))
+	}
+
+	if in.GarbageCollector != nil {
+		gc := *in.GarbageCollector
+		switch {
+		case gc == GarbageCollectorCMS && javaVersion != Java8:
+			allErrs = append(allErrs, field.Invalid(path.Child("garbageCollector"), gc, "CMS is only supported with Java 8"))
+		case gc == GarbageCollectorZGC && javaVersion != Java11:
+			allErrs = append(allErrs, field.Invalid(path.Child("garbageCollector"), gc, "ZGC is only supported with Java 11"))
+		}
+		if in.HeapNewGenSize != nil && gc != GarbageCollectorCMS {
+			allErrs = append(allErrs, field.Forbidden(path.Child("heapNewGenSize"),
+				fmt.Sprintf("the young generation size cannot be set with %s", gc)))
+		}
+	}
+
+	if in.Cms != nil && (in.GarbageCollector == nil || *in.GarbageCollector != GarbageCollectorCMS) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("cms"), "can only be set when garbageCollector is CMS"))
+	}
+	if in.G1 != nil && (in.GarbageCollector == nil || *in.GarbageCollector != GarbageCollectorG1) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("g1"), "can only be set when garbageCollector is G1GC"))
+	}
+
+	if in.HeapDump != nil && !isOnMountedVolume(in.HeapDump.Path, mountPaths) {
+		allErrs = append(allErrs, field.Invalid(path.Child("heapDump", "path"), in.HeapDump.Path,
+			fmt.Sprintf("must be an absolute path on a mounted volume: %s", strings.Join(mountPaths, ", "))))
+	}
+
+	return allErrs
+}
+
+// VolumeMountPaths returns the paths where the volumes of storageConfigs are mounted in the
+// Cassandra container.
+func VolumeMountPaths(storageConfigs ...*cassdcapi.StorageConfig) []string {
+	mountPaths := []string{ServerDataMountPath}
+	for _, storageConfig := range storageConfigs {
+		if storageConfig == nil {
+			continue
+		}
+		for _, volume := range storageConfig.AdditionalVolumes {
+			mountPaths = append(mountPaths, volume.MountPath)
+		}
+	}
+	return mountPaths
+}
+
+// isOnMountedVolume returns true if path is an absolute path under one of mountPaths.
+func isOnMountedVolume(path string, mountPaths []string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, mountPath := range mountPaths {
+		mountPath = filepath.Clean(mountPath)
+		if path == mountPath || strings.HasPrefix(path, mountPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// CoalesceJvmOptions returns the JVM options of the datacenter of dcTemplate, i.e., its
+// options merged with the cluster-level options, after removing the cluster-level options
+// listed in dcTemplate.Unset. The templates are not modified.
+func CoalesceJvmOptions(clusterTemplate *CassandraClusterTemplate, dcTemplate *CassandraDatacenterTemplate) *JvmOptions {
+	clusterOptions := clusterTemplate.CassandraConfig.getJvmOptions().DeepCopy()
+	for _, path := range dcTemplate.Unset {
+		clusterOptions.Unset(path)
+	}
+	return MergeJvmOptions(clusterOptions, dcTemplate.CassandraConfig.getJvmOptions().DeepCopy())
+}
+
+// MergeJvmOptions merges the cluster-level options with the dc-level options field by
+// field, the dc-level options taking precedence, except for the GC settings: when the dc
+// selects another garbage collector than the cluster, the GC tunables and the young
+// generation size of the cluster are not inherited. The arguments may be modified or
+// returned, they should not be shared with other objects.
+func MergeJvmOptions(cluster, dc *JvmOptions) *JvmOptions {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	additionalOptions := mergeJvmAdditionalOptions(cluster.AdditionalOptions, dc.AdditionalOptions)
+	if dc.GarbageCollector != nil && (cluster.GarbageCollector == nil || *dc.GarbageCollector != *cluster.GarbageCollector) {
+		cluster.Cms = nil
+		cluster.G1 = nil
+		cluster.HeapNewGenSize = nil
+	}
+	if cluster.Cms != nil && dc.Cms != nil {
+		utils.MergeFields(cluster.Cms, dc.Cms)
+	}
+	if cluster.G1 != nil && dc.G1 != nil {
+		utils.MergeFields(cluster.G1, dc.G1)
+	}
+	utils.MergeFields(cluster, dc)
+	dc.AdditionalOptions = additionalOptions
+	return dc
+}
+
+// Unset removes the option identified by path, one of the JVM options paths supported by
+// CassandraDatacenterTemplate.Unset. Other paths are ignored.
+func (in *JvmOptions) Unset(path string) {
+	if in == nil {
+		return
+	}
+	switch {
+	case path == UnsetHeapSize:
+		in.HeapSize = nil
+	case path == UnsetHeapNewGenSize:
+		in.HeapNewGenSize = nil
+	case path == UnsetAdditionalJvmOptions:
+		in.AdditionalOptions = nil
+	case strings.HasPrefix(path, UnsetAdditionalJvmOptions+"."):
+		name := strings.TrimPrefix(path, UnsetAdditionalJvmOptions+".")
+		filtered := make([]string, 0, len(in.AdditionalOptions))
+		for _, option := range in.AdditionalOptions {
+			if option != name && jvmOptionName(option) != name {
+				filtered = append(filtered, option)
+			}
+		}
+		in.AdditionalOptions = filtered
+	}
+}
+
+// mergeJvmAdditionalOptions returns the cluster options followed by the dc options. A
+// cluster option is dropped when the dc sets the same option, e.g., -Dcassandra.ring_delay_ms=10000
+// at the dc-level replaces -Dcassandra.ring_delay_ms=30000 at the cluster-level.
+func mergeJvmAdditionalOptions(cluster, dc []string) []string {
+	if len(cluster) == 0 {
+		return dc
+	}
+	if len(dc) == 0 {
+		return cluster
+	}
+	dcOptions := make(map[string]bool, len(dc))
+	for _, option := range dc {
+		dcOptions[jvmOptionName(option)] = true
+	}
+	merged := make([]string, 0, len(cluster)+len(dc))
+	for _, option := range cluster {
+		if !dcOptions[jvmOptionName(option)] {
+			merged = append(merged, option)
+		}
+	}
+	return append(merged, dc...)
+}
+
+// jvmOptionName returns the part of a JVM option that identifies it regardless of its
+// value, e.g., -Dcassandra.ring_delay_ms for -Dcassandra.ring_delay_ms=10000, -XX:UseG1GC
+// for -XX:+UseG1GC, or -Xss for -Xss256k.
+func jvmOptionName(option string) string {
+	switch {
+	case strings.HasPrefix(option, "-XX:+"), strings.HasPrefix(option, "-XX:-"):
+		return "-XX:" + option[len("-XX:+"):]
+	case strings.HasPrefix(option, "-Xss"), strings.HasPrefix(option, "-Xms"),
+		strings.HasPrefix(option, "-Xmx"), strings.HasPrefix(option, "-Xmn"):
+		return option[:len("-Xss")]
+	}
+	if i := strings.Index(option, "="); i >= 0 {
+		return option[:i]
+	}
+	return option
+}
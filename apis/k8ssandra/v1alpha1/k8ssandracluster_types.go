@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"reflect"
+	"strings"
+
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
 	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
@@ -44,6 +47,31 @@ type K8ssandraClusterSpec struct {
 	// If this is non-nil, Reaper will be deployed on every Cassandra datacenter in this K8ssandraCluster.
 	// +optional
 	Reaper *reaperapi.ReaperClusterTemplate `json:"reaper,omitempty"`
+
+	// RollingRestart triggers a rolling restart of all the Cassandra nodes of the cluster,
+	// one datacenter at a time. A new rolling restart is started each time
+	// rollingRestart.requestedAt changes.
+	// +optional
+	RollingRestart *RollingRestartRequest `json:"rollingRestart,omitempty"`
+
+	// Stopped, when true, stops all the datacenters of the cluster, unless overridden at the
+	// datacenter level. The Stargate and Reaper deployments of a datacenter are scaled down
+	// to zero before the datacenter is stopped, and scaled back up once it is running again.
+	// The PersistentVolumeClaims of the Cassandra pods are kept.
+	// +optional
+	Stopped bool `json:"stopped,omitempty"`
+}
+
+// RollingRestartRequest describes a cluster-wide rolling restart.
+type RollingRestartRequest struct {
+	// RequestedAt identifies the rolling restart. Setting it to a new value, typically the
+	// current time, starts a new rolling restart.
+	RequestedAt metav1.Time `json:"requestedAt"`
+
+	// RestartStargate, when true, also restarts the Stargate deployments once all the
+	// datacenters have been restarted.
+	// +optional
+	RestartStargate bool `json:"restartStargate,omitempty"`
 }
 
 // K8ssandraClusterStatus defines the observed state of K8ssandraCluster
@@ -51,6 +79,11 @@ type K8ssandraClusterStatus struct {
 	// +optional
 	Conditions []K8ssandraClusterCondition `json:"conditions,omitempty"`
 
+	// ObservedGeneration is the generation of the K8ssandraCluster that was last
+	// reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Datacenters maps the CassandraDatacenter name to a K8ssandraStatus. The
 	// naming is a bit confusing but the mapping makes sense because we have a
 	// CassandraDatacenter and then define other components like Stargate and Reaper
@@ -58,6 +91,55 @@ type K8ssandraClusterStatus struct {
 	//
 	// TODO Figure out how to inline this field
 	Datacenters map[string]K8ssandraStatus `json:"datacenters,omitempty"`
+
+	// RollingRestart is the observed state of the last rolling restart requested through
+	// spec.rollingRestart.
+	// +optional
+	RollingRestart *RollingRestartStatus `json:"rollingRestart,omitempty"`
+
+	// Upgrade is the observed state of the last upgrade of the Cassandra version of the
+	// cluster.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+
+	// SystemRepair is the observed state of the last repair of the system keyspaces after
+	// their replication factor was raised.
+	// +optional
+	SystemRepair *RepairStatus `json:"systemRepair,omitempty"`
+
+	// LDAPGrantedRoles maps the roles of the group mappings of the LDAP provider to the
+	// members they were granted to by the group sync. Only those grants are revoked when
+	// the members leave the groups; the grants made otherwise, e.g., through CassandraRoles,
+	// are left as is.
+	// +optional
+	LDAPGrantedRoles map[string][]string `json:"ldapGrantedRoles,omitempty"`
+}
+
+// RollingRestartStatus is the observed state of a cluster-wide rolling restart.
+type RollingRestartStatus struct {
+	// RequestedAt is the value of spec.rollingRestart.requestedAt for this rolling restart.
+	RequestedAt metav1.Time `json:"requestedAt"`
+
+	// StartTime is the time at which the rolling restart started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time at which all the datacenters had been restarted. It is
+	// nil while the rolling restart is in progress.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Datacenter is the datacenter currently being restarted.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// DatacenterStartTime is the time at which the restart of Datacenter was requested.
+	// +optional
+	DatacenterStartTime *metav1.Time `json:"datacenterStartTime,omitempty"`
+
+	// RestartedDatacenters is the list of datacenters that have been restarted.
+	// +optional
+	RestartedDatacenters []string `json:"restartedDatacenters,omitempty"`
 }
 
 type K8ssandraClusterConditionType string
@@ -68,6 +150,63 @@ const (
 	// their readiness condition change back and forth. Once set, this condition however
 	// does not change.
 	CassandraInitialized = "CassandraInitialized"
+
+	// DatacenterDecommissioning is set to true while a datacenter that has been removed
+	// from the spec is being decommissioned. It is set back to false once the
+	// decommission has completed and the CassandraDatacenter has been deleted.
+	DatacenterDecommissioning = "DatacenterDecommissioning"
+
+	// ClusterReady is set to true when Cassandra, Stargate and Reaper are ready in all the
+	// datacenters of the cluster.
+	ClusterReady = "Ready"
+
+	// ClusterProgressing is set to true while changes are being applied to the cluster,
+	// i.e., when the last reconciliation did not run to completion. Its reason is the step
+	// of the reconciliation that is in progress.
+	ClusterProgressing = "Progressing"
+
+	// ClusterDegraded is set to true when the last reconciliation failed, when a
+	// datacenter reported an error, when Reaper failed to register the cluster, or when
+	// the Kubernetes cluster of a datacenter could not be reached.
+	ClusterDegraded = "Degraded"
+
+	// CassandraConfigValid is set to false when the cassandra.yaml properties of a
+	// datacenter include properties that do not exist in its Cassandra version. These
+	// properties are not rendered in the configuration of the datacenter.
+	CassandraConfigValid = "CassandraConfigValid"
+)
+
+// Reasons of the Progressing condition.
+const (
+	ReasonReconcilingSecrets           = "ReconcilingSecrets"
+	ReasonDecommissioningDatacenter    = "DecommissioningDatacenter"
+	ReasonReconcilingDatacenters       = "ReconcilingDatacenters"
+	ReasonRollingRestart               = "RollingRestart"
+	ReasonReconcilingSchema            = "ReconcilingSchema"
+	ReasonReconcilingStargateAndReaper = "ReconcilingStargateAndReaper"
+	ReasonReconcilingRoles             = "ReconcilingRoles"
+	ReasonReconciled                   = "Reconciled"
+)
+
+// Reasons of the Ready condition.
+const (
+	ReasonDatacentersReady    = "DatacentersReady"
+	ReasonDatacentersNotReady = "DatacentersNotReady"
+	ReasonDatacentersStopped  = "DatacentersStopped"
+)
+
+// Reasons of the Degraded condition.
+const (
+	ReasonReconcileFailed    = "ReconcileFailed"
+	ReasonContextUnreachable = "ContextUnreachable"
+	ReasonDatacenterFailed   = "DatacenterFailed"
+	ReasonNoErrors           = "NoErrors"
+)
+
+// Reasons of the CassandraConfigValid condition.
+const (
+	ReasonUnsupportedProperties = "UnsupportedProperties"
+	ReasonValidConfig           = "ValidConfig"
 )
 
 type K8ssandraClusterCondition struct {
@@ -77,6 +216,14 @@ type K8ssandraClusterCondition struct {
 	// LastTransitionTime is the last time the condition transited from one status to another.
 	// +optional
 	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a CamelCase identifier of the cause of the last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable explanation of the current status.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // K8ssandraStatus defines the observed of a k8ssandra instance
@@ -84,10 +231,340 @@ type K8ssandraStatus struct {
 	Cassandra *cassdcapi.CassandraDatacenterStatus `json:"cassandra,omitempty"`
 	Stargate  *stargateapi.StargateStatus          `json:"stargate,omitempty"`
 	Reaper    *reaperapi.ReaperStatus              `json:"reaper,omitempty"`
+
+	// DecommissionProgress tracks the decommission of a datacenter that has been removed
+	// from the spec. It is empty for datacenters that are not being decommissioned.
+	// +optional
+	DecommissionProgress DecommissionProgress `json:"decommissionProgress,omitempty"`
+
+	// Rebuild tracks the rebuild of a datacenter that has been added to an existing
+	// cluster. It is nil for datacenters that were created along with the cluster.
+	// +optional
+	Rebuild *RebuildStatus `json:"rebuild,omitempty"`
+
+	// Error is the error encountered by the last reconciliation of the datacenter. It is
+	// empty if the last reconciliation succeeded.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Summary is an overview of the state of the datacenter.
+	// +optional
+	Summary *DatacenterSummary `json:"summary,omitempty"`
+
+	// Tuning holds the settings that were derived from the resources of the Cassandra
+	// container because they were not set explicitly in the config of the datacenter.
+	// +optional
+	Tuning *TuningStatus `json:"tuning,omitempty"`
+
+	// Config reports the changes of the Cassandra configuration that are not persisted in
+	// the CassandraDatacenter yet.
+	// +optional
+	Config *ConfigStatus `json:"config,omitempty"`
 }
 
+// DatacenterComponent is one of the components deployed in each datacenter.
+type DatacenterComponent string
+
+const (
+	ComponentCassandra DatacenterComponent = "Cassandra"
+	ComponentStargate  DatacenterComponent = "Stargate"
+	ComponentReaper    DatacenterComponent = "Reaper"
+)
+
+// DatacenterSummary is an overview of the state of a datacenter.
+type DatacenterSummary struct {
+	// K8sContext is the Kubernetes context in which the datacenter is deployed.
+	// +optional
+	K8sContext string `json:"k8sContext,omitempty"`
+
+	// Unreachable is true if the Kubernetes cluster of K8sContext could not be reached
+	// during the last reconciliation.
+	// +optional
+	Unreachable bool `json:"unreachable,omitempty"`
+
+	// Stopped is true if the datacenter is stopped.
+	// +optional
+	Stopped bool `json:"stopped,omitempty"`
+
+	// Nodes is the desired number of Cassandra nodes.
+	Nodes int32 `json:"nodes"`
+
+	// ReadyNodes is the number of Cassandra pods that are ready.
+	ReadyNodes int32 `json:"readyNodes"`
+
+	// Ready is true when Cassandra, as well as Stargate and Reaper if they are deployed,
+	// are ready in the datacenter.
+	Ready bool `json:"ready"`
+
+	// BlockedBy is the first component that is not ready, checked in the order Cassandra,
+	// Stargate, Reaper. It is empty when the datacenter is ready.
+	// +optional
+	BlockedBy DatacenterComponent `json:"blockedBy,omitempty"`
+}
+
+// TuningStatus holds the settings of a datacenter that were computed from the CPU and
+// memory of the Cassandra container. A field is nil when the setting was set explicitly
+// or when the resources needed to compute it are not set.
+type TuningStatus struct {
+	// +optional
+	HeapSize *resource.Quantity `json:"heapSize,omitempty"`
+
+	// +optional
+	HeapNewGenSize *resource.Quantity `json:"heapNewGenSize,omitempty"`
+
+	// +optional
+	ConcurrentReads *int `json:"concurrentReads,omitempty"`
+
+	// +optional
+	ConcurrentWrites *int `json:"concurrentWrites,omitempty"`
+
+	// +optional
+	ConcurrentCompactors *int `json:"concurrentCompactors,omitempty"`
+
+	// +optional
+	MemtableFlushWriters *int `json:"memtableFlushWriters,omitempty"`
+}
+
+// ConfigStatus reports how the changes of the Cassandra configuration of a datacenter are
+// rolled out. Changes of properties that can be modified at runtime are applied to the
+// running nodes through the management API, the other changes require a rolling restart.
+type ConfigStatus struct {
+	// AppliedLive maps the properties that were applied to the running nodes without
+	// restarting them to their value. They are persisted in the CassandraDatacenter by the
+	// next update that restarts its nodes, e.g., a change of their resources. In the
+	// meantime they are applied again to the nodes that restart.
+	// +optional
+	AppliedLive map[string]string `json:"appliedLive,omitempty"`
+
+	// LastLiveUpdate is the last time the properties of AppliedLive were applied.
+	// +optional
+	LastLiveUpdate *metav1.Time `json:"lastLiveUpdate,omitempty"`
+
+	// PendingRestart lists the properties that changed and that take effect once the
+	// nodes have been restarted.
+	// +optional
+	PendingRestart []string `json:"pendingRestart,omitempty"`
+}
+
+// RolloutPolicy controls the order in which the datacenters of a cluster are reconciled.
+type RolloutPolicy string
+
+const (
+	// RolloutSerial reconciles the datacenters one at a time, in the order in which they
+	// are declared. Each datacenter must be ready before the next one is reconciled.
+	RolloutSerial RolloutPolicy = "Serial"
+
+	// RolloutParallel reconciles all the datacenters at the same time.
+	RolloutParallel RolloutPolicy = "Parallel"
+
+	// RolloutFirstDcThenParallel waits for the first datacenter to be ready before
+	// reconciling the other ones in parallel when the cluster is created. Once the cluster
+	// is initialized, it behaves like RolloutParallel.
+	RolloutFirstDcThenParallel RolloutPolicy = "FirstDcThenParallel"
+)
+
+// RebuildProgress is the current step of the rebuild of a new datacenter.
+type RebuildProgress string
+
+const (
+	// RebuildPending means that the datacenter has been created but is not ready yet.
+	RebuildPending RebuildProgress = "Pending"
+
+	// RebuildUpdatingReplication means that the datacenter is being added to the
+	// replication settings of the keyspaces listed in ReplicatedKeyspaces.
+	RebuildUpdatingReplication RebuildProgress = "UpdatingReplication"
+
+	// RebuildRunning means that the nodes of the datacenter are being rebuilt one at a
+	// time.
+	RebuildRunning RebuildProgress = "Rebuilding"
+
+	// RebuildFailed means that the last rebuild attempt failed. It will be retried.
+	RebuildFailed RebuildProgress = "Failed"
+
+	// RebuildCompleted means that all the nodes of the datacenter have been rebuilt.
+	RebuildCompleted RebuildProgress = "Completed"
+)
+
+// RebuildStatus is the observed state of the rebuild of a datacenter that has been added
+// to an existing cluster.
+type RebuildStatus struct {
+	Progress RebuildProgress `json:"progress"`
+
+	// SourceDatacenter is the datacenter from which data is streamed.
+	// +optional
+	SourceDatacenter string `json:"sourceDatacenter,omitempty"`
+
+	// CurrentPod is the pod that is currently being rebuilt.
+	// +optional
+	CurrentPod string `json:"currentPod,omitempty"`
+
+	// JobId is the id of the management API job rebuilding CurrentPod.
+	// +optional
+	JobId string `json:"jobId,omitempty"`
+
+	// RebuiltPods is the list of pods that have been rebuilt.
+	// +optional
+	RebuiltPods []string `json:"rebuiltPods,omitempty"`
+
+	// LastError is the error reported by the last failed rebuild.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// RepairProgress is the current step of the repair of keyspaces.
+type RepairProgress string
+
+const (
+	// RepairPending means that the repair has been requested but has not started yet.
+	RepairPending RepairProgress = "Pending"
+
+	// RepairRunning means that the nodes are being repaired one at a time.
+	RepairRunning RepairProgress = "Running"
+
+	// RepairFailed means that the last repair attempt failed. It will be retried.
+	RepairFailed RepairProgress = "Failed"
+
+	// RepairCompleted means that all the nodes have been repaired.
+	RepairCompleted RepairProgress = "Completed"
+)
+
+// RepairStatus is the observed state of the repair of keyspaces whose replication factor
+// was raised. The nodes of Datacenters are repaired one at a time, one keyspace after the
+// other.
+type RepairStatus struct {
+	Progress RepairProgress `json:"progress"`
+
+	// Keyspaces are the keyspaces to repair.
+	Keyspaces []string `json:"keyspaces"`
+
+	// Datacenters are the datacenters whose nodes are repaired.
+	Datacenters []string `json:"datacenters"`
+
+	// CurrentPod is the pod that is currently being repaired.
+	// +optional
+	CurrentPod string `json:"currentPod,omitempty"`
+
+	// CurrentKeyspace is the keyspace that is currently being repaired on CurrentPod.
+	// +optional
+	CurrentKeyspace string `json:"currentKeyspace,omitempty"`
+
+	// JobId is the id of the management API job repairing CurrentKeyspace on CurrentPod.
+	// +optional
+	JobId string `json:"jobId,omitempty"`
+
+	// RepairedPods is the list of pods on which all the keyspaces have been repaired.
+	// +optional
+	RepairedPods []string `json:"repairedPods,omitempty"`
+
+	// LastError is the error reported by the last failed repair.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// UpgradeProgress is the current step of the upgrade of the Cassandra version of a
+// cluster.
+type UpgradeProgress string
+
+const (
+	// UpgradePreChecks means that the operator is checking that all the nodes are up and
+	// agree on the schema before starting the upgrade.
+	UpgradePreChecks UpgradeProgress = "PreChecks"
+
+	// UpgradeSnapshotting means that a snapshot of all the keyspaces is being taken on
+	// every node.
+	UpgradeSnapshotting UpgradeProgress = "Snapshotting"
+
+	// UpgradeUpgradingDatacenter means that the nodes of Datacenter are being restarted
+	// with the new version.
+	UpgradeUpgradingDatacenter UpgradeProgress = "UpgradingDatacenter"
+
+	// UpgradeUpgradingSSTables means that upgradesstables is being run on the nodes of
+	// Datacenter, one at a time.
+	UpgradeUpgradingSSTables UpgradeProgress = "UpgradingSSTables"
+
+	// UpgradeCompleted means that all the datacenters run the desired version.
+	UpgradeCompleted UpgradeProgress = "Completed"
+)
+
+// UpgradeStatus is the observed state of the upgrade of the Cassandra version of a
+// cluster. If a step fails, the upgrade stays at that step and LastError is set; the step
+// is retried later and the datacenters that have not been upgraded yet keep running the
+// previous version in the meantime.
+type UpgradeStatus struct {
+	Progress UpgradeProgress `json:"progress"`
+
+	// StartTime is the time at which the upgrade started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time at which all the datacenters had been upgraded. It is nil
+	// while the upgrade is in progress.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// SnapshotName is the name of the snapshot taken before the upgrade.
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// SnapshottedPods is the list of pods on which the snapshot has been taken.
+	// +optional
+	SnapshottedPods []string `json:"snapshottedPods,omitempty"`
+
+	// Datacenter is the datacenter currently being upgraded.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// DatacenterStartTime is the time at which the new version was applied to Datacenter.
+	// +optional
+	DatacenterStartTime *metav1.Time `json:"datacenterStartTime,omitempty"`
+
+	// CurrentPod is the pod of Datacenter on which upgradesstables is running.
+	// +optional
+	CurrentPod string `json:"currentPod,omitempty"`
+
+	// JobId is the id of the management API job running upgradesstables on CurrentPod.
+	// +optional
+	JobId string `json:"jobId,omitempty"`
+
+	// UpgradedPods is the list of pods of Datacenter whose sstables have been upgraded.
+	// +optional
+	UpgradedPods []string `json:"upgradedPods,omitempty"`
+
+	// UpgradedDatacenters is the list of datacenters that have been upgraded.
+	// +optional
+	UpgradedDatacenters []string `json:"upgradedDatacenters,omitempty"`
+
+	// LastError is the error that stopped the upgrade. It is cleared once the failed step
+	// succeeds.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// DecommissionProgress is the current step of a datacenter decommission.
+type DecommissionProgress string
+
+const (
+	// DecommUpdatingReplication means that the datacenter is being removed from the
+	// replication settings of all keyspaces.
+	DecommUpdatingReplication DecommissionProgress = "UpdatingReplication"
+
+	// DecommDecommissioning means that the nodes of the datacenter are being
+	// decommissioned one at a time.
+	DecommDecommissioning DecommissionProgress = "Decommissioning"
+
+	// DecommDeleting means that the nodes have left the cluster and the
+	// CassandraDatacenter and its Stargate and Reaper objects are being deleted.
+	DecommDeleting DecommissionProgress = "Deleting"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Progressing",type=string,JSONPath=`.status.conditions[?(@.type=="Progressing")].status`
+// +kubebuilder:printcolumn:name="Degraded",type=string,JSONPath=`.status.conditions[?(@.type=="Degraded")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Message",type=string,priority=1,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:path=k8ssandraclusters,shortName=k8c;k8cs
 
 // K8ssandraCluster is the Schema for the k8ssandraclusters API
@@ -135,6 +612,15 @@ func (in *K8ssandraCluster) HasReapers() bool {
 	return false
 }
 
+// IsDatacenterStopped returns true if the datacenter described by dcTemplate should be
+// stopped, taking into account the datacenter-level override of the stopped setting.
+func (in *K8ssandraCluster) IsDatacenterStopped(dcTemplate *CassandraDatacenterTemplate) bool {
+	if dcTemplate.Stopped != nil {
+		return *dcTemplate.Stopped
+	}
+	return in.Spec.Stopped
+}
+
 // +kubebuilder:object:root=true
 
 // K8ssandraClusterList contains a list of K8ssandraCluster
@@ -160,13 +646,21 @@ type CassandraClusterTemplate struct {
 	ServerImage string `json:"serverImage,omitempty"`
 
 	// ServerVersion is the Cassandra version.
-	// +kubebuilder:validation:Pattern=(3\.11\.\d+)|(4\.0\.\d+)
+	// +kubebuilder:validation:Pattern=(3\.11\.\d+)|(4\.0\.\d+)|(4\.1\.\d+)
 	ServerVersion string `json:"serverVersion,omitempty"`
 
 	// Resources is the cpu and memory resources for the cassandra container.
 	// +optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// ResourceTuning enables the computation of the heap size, the young generation size,
+	// concurrent_reads, concurrent_writes, concurrent_compactors and memtable_flush_writers
+	// from the resources of the cassandra container, for the settings that are not set
+	// explicitly. It is disabled by default, as the computed settings change, and restart
+	// the nodes, when the resources change.
+	// +optional
+	ResourceTuning *bool `json:"resourceTuning,omitempty"`
+
 	// SystemLoggerResources is the cpu and memory resources for the server-system-logger
 	// container.
 	// +optional
@@ -177,6 +671,10 @@ type CassandraClusterTemplate struct {
 	// +optional
 	CassandraConfig *CassandraConfig `json:"config,omitempty"`
 
+	// ConfigFiles references ConfigMaps that hold configuration files of Cassandra.
+	// +optional
+	ConfigFiles *ConfigFiles `json:"configFiles,omitempty"`
+
 	// StorageConfig is the persistent storage requirements for each Cassandra pod. This
 	// includes everything under /var/lib/cassandra, namely the commit log and data
 	// directories.
@@ -187,10 +685,27 @@ type CassandraClusterTemplate struct {
 	// +optional
 	Networking *cassdcapi.NetworkingConfig `json:"networking,omitempty"`
 
+	// TLS configures the encryption of the client and internode connections.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Auth configures the authentication and the authorization of the CQL clients.
+	// +optional
+	Auth *Auth `json:"auth,omitempty"`
+
 	// Racks is a list of named racks. Note that racks are used to create node affinity. //
 	// +optional
 	Racks []cassdcapi.Rack `json:"racks,omitempty"`
 
+	// Tolerations are applied to the Cassandra pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeAffinityLabels pins the Cassandra pods to the nodes that have these labels. The
+	// nodeAffinityLabels of the racks are added to them.
+	// +optional
+	NodeAffinityLabels map[string]string `json:"nodeAffinityLabels,omitempty"`
+
 	// Datacenters a list of the DCs in the cluster.
 	// +optional
 	Datacenters []CassandraDatacenterTemplate `json:"datacenters,omitempty"`
@@ -199,6 +714,85 @@ type CassandraClusterTemplate struct {
 	// api heap.
 	// +optional
 	MgmtAPIHeap *resource.Quantity `json:"mgmtAPIHeap,omitempty"`
+
+	// ReplicatedKeyspaces is a list of user keyspaces whose replication is updated to
+	// include new datacenters when they are added to an existing cluster. New datacenters
+	// are given a replication factor of min(3, size). The replication of the system_auth,
+	// system_distributed and system_traces keyspaces is always updated, see
+	// SystemReplication.
+	// +optional
+	ReplicatedKeyspaces []string `json:"replicatedKeyspaces,omitempty"`
+
+	// SystemReplication configures the replication of the system_auth, system_distributed
+	// and system_traces keyspaces, and of additional keyspaces that are managed the same
+	// way. By default, each datacenter has a replication factor of min(3, size).
+	// +optional
+	SystemReplication *SystemReplicationPolicy `json:"systemReplication,omitempty"`
+
+	// RolloutPolicy controls whether the CassandraDatacenters are created and updated one
+	// at a time or in parallel. Defaults to FirstDcThenParallel, which bootstraps the first
+	// datacenter before the other ones.
+	// +kubebuilder:validation:Enum=Serial;Parallel;FirstDcThenParallel
+	// +kubebuilder:default=FirstDcThenParallel
+	// +optional
+	RolloutPolicy RolloutPolicy `json:"rolloutPolicy,omitempty"`
+
+	// Upgrade configures how a change of serverVersion is rolled out. Upgrades are always
+	// performed one datacenter at a time.
+	// +optional
+	Upgrade *UpgradePolicy `json:"upgrade,omitempty"`
+}
+
+// UpgradePolicy configures the upgrade of the Cassandra version of a cluster.
+type UpgradePolicy struct {
+	// SnapshotBeforeUpgrade, when true, takes a snapshot of all the keyspaces on every node
+	// of the cluster before the first datacenter is upgraded.
+	// +optional
+	SnapshotBeforeUpgrade bool `json:"snapshotBeforeUpgrade,omitempty"`
+}
+
+// SystemReplicationPolicy configures the replication of the system keyspaces. The nodes of
+// a datacenter are repaired after the replication factor of the datacenter is raised, so
+// that the new replicas have the data. Datacenters that are added to the cluster are
+// rebuilt instead.
+type SystemReplicationPolicy struct {
+	// ReplicationFactor is the replication factor of each datacenter. It is capped by the
+	// size of the datacenter. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicationFactor *int `json:"replicationFactor,omitempty"`
+
+	// Datacenters overrides the replication factor of some datacenters, by name. An
+	// override must be at least 1 and cannot exceed the size of its datacenter.
+	// +optional
+	Datacenters map[string]int `json:"datacenters,omitempty"`
+
+	// AdditionalKeyspaces are keyspaces that get the same replication as the system
+	// keyspaces, e.g., dse_security. Keyspaces that do not exist are ignored.
+	// +optional
+	AdditionalKeyspaces []string `json:"additionalKeyspaces,omitempty"`
+
+	// GrowWithDatacenterSize selects whether the replication factor of a datacenter is
+	// raised when the datacenter is scaled up, e.g., from 1 to 3 nodes. When false, the
+	// replication factor of a datacenter is only computed when it is added to the
+	// replication. Defaults to true.
+	// +optional
+	GrowWithDatacenterSize *bool `json:"growWithDatacenterSize,omitempty"`
+}
+
+// GetReplicationFactor returns the replication factor of the datacenters that have no
+// override, before it is capped by their size.
+func (in *SystemReplicationPolicy) GetReplicationFactor() int {
+	if in == nil || in.ReplicationFactor == nil {
+		return 3
+	}
+	return *in.ReplicationFactor
+}
+
+// GrowsWithDatacenterSize returns true if the replication factor of a datacenter is raised
+// when the datacenter is scaled up.
+func (in *SystemReplicationPolicy) GrowsWithDatacenterSize() bool {
+	return in == nil || in.GrowWithDatacenterSize == nil || *in.GrowWithDatacenterSize
 }
 
 // +kubebuilder:pruning:PreserveUnknownFields
@@ -216,26 +810,50 @@ type CassandraDatacenterTemplate struct {
 	Size int32 `json:"size"`
 
 	// ServerVersion is the Cassandra version.
-	// +kubebuilder:validation:Pattern=(3\.11\.\d+)|(4\.0\.\d+)
+	// +kubebuilder:validation:Pattern=(3\.11\.\d+)|(4\.0\.\d+)|(4\.1\.\d+)
 	// +optional
 	ServerVersion string `json:"serverVersion,omitempty"`
 
 	// CassandraConfig is configuration settings that are applied to cassandra.yaml and
-	// jvm-options for 3.11.x or jvm-server-options for 4.x.
+	// jvm-options for 3.11.x or jvm-server-options for 4.x. They are merged with the
+	// cluster-level settings, the datacenter values taking precedence.
 	CassandraConfig *CassandraConfig `json:"config,omitempty"`
 
+	// ConfigFiles references ConfigMaps that hold configuration files of Cassandra. They
+	// are merged file by file with the cluster-level ones, the datacenter values taking
+	// precedence.
+	// +optional
+	ConfigFiles *ConfigFiles `json:"configFiles,omitempty"`
+
 	// Resources is the cpu and memory resources for the cassandra container.
 	// +optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// ResourceTuning enables the computation of settings from the resources of the
+	// cassandra container, see CassandraClusterTemplate.ResourceTuning. It overrides the
+	// cluster-level setting.
+	// +optional
+	ResourceTuning *bool `json:"resourceTuning,omitempty"`
+
 	// SystemLoggerResources is the cpu and memory resources for the server-system-logger
 	// container.
 	// +optional
 	SystemLoggerResources *corev1.ResourceRequirements `json:"systemLoggerResources,omitempty"`
 
+	// Racks is a list of named racks. When set, it replaces the cluster-level racks.
 	// +optional
 	Racks []cassdcapi.Rack `json:"racks,omitempty"`
 
+	// Tolerations are applied to the Cassandra pods. When set, they replace the
+	// cluster-level tolerations.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeAffinityLabels pins the Cassandra pods to the nodes that have these labels. They
+	// are merged with the cluster-level labels, the datacenter values taking precedence.
+	// +optional
+	NodeAffinityLabels map[string]string `json:"nodeAffinityLabels,omitempty"`
+
 	// Networking enables host networking and configures a NodePort ports.
 	// +optional
 	Networking *cassdcapi.NetworkingConfig `json:"networking,omitempty"`
@@ -259,6 +877,71 @@ type CassandraDatacenterTemplate struct {
 	// api heap.
 	// +optional
 	MgmtAPIHeap *resource.Quantity `json:"mgmtAPIHeap,omitempty"`
+
+	// Stopped overrides the cluster-level stopped setting for this datacenter.
+	// +optional
+	Stopped *bool `json:"stopped,omitempty"`
+
+	// Unset lists cluster-level settings that this datacenter does not inherit. The
+	// config, resources, networking and storageConfig settings of the datacenter are merged
+	// field by field with those of the cluster, the datacenter values taking precedence.
+	// A listed setting is removed from the cluster-level values before they are merged,
+	// so that the datacenter uses its own value or the default one. The supported paths
+	// are:
+	//
+	//   config.cassandraYaml.<property>, e.g. config.cassandraYaml.num_tokens
+	//   config.jvmOptions.heapSize
+	//   config.jvmOptions.heapNewGenSize
+	//   config.jvmOptions.additionalOptions
+	//   config.jvmOptions.additionalOptions.<option>, e.g. config.jvmOptions.additionalOptions.-Dcassandra.ring_delay_ms
+	//   resources.limits.<resource>, e.g. resources.limits.cpu
+	//   resources.requests.<resource>
+	//   networking.nodePort
+	//   networking.hostNetwork
+	//   storageConfig.additionalVolumes.<name>
+	// +optional
+	Unset []string `json:"unset,omitempty"`
+}
+
+// Paths, and prefixes of paths, supported by CassandraDatacenterTemplate.Unset.
+const (
+	UnsetCassandraYamlPrefix     = "config.cassandraYaml."
+	UnsetHeapSize                = "config.jvmOptions.heapSize"
+	UnsetHeapNewGenSize          = "config.jvmOptions.heapNewGenSize"
+	UnsetAdditionalJvmOptions    = "config.jvmOptions.additionalOptions"
+	UnsetResourceLimitsPrefix    = "resources.limits."
+	UnsetResourceRequestsPrefix  = "resources.requests."
+	UnsetNodePort                = "networking.nodePort"
+	UnsetHostNetwork             = "networking.hostNetwork"
+	UnsetAdditionalVolumesPrefix = "storageConfig.additionalVolumes."
+)
+
+// IsValidUnsetPath returns true if path is supported by CassandraDatacenterTemplate.Unset.
+func IsValidUnsetPath(path string) bool {
+	switch path {
+	case UnsetHeapSize, UnsetHeapNewGenSize, UnsetAdditionalJvmOptions, UnsetNodePort, UnsetHostNetwork:
+		return true
+	}
+	for _, prefix := range []string{UnsetAdditionalJvmOptions + ".", UnsetResourceLimitsPrefix, UnsetResourceRequestsPrefix, UnsetAdditionalVolumesPrefix} {
+		if strings.HasPrefix(path, prefix) && len(path) > len(prefix) {
+			return true
+		}
+	}
+	if strings.HasPrefix(path, UnsetCassandraYamlPrefix) {
+		property := strings.TrimPrefix(path, UnsetCassandraYamlPrefix)
+		yamlType := reflect.TypeOf(CassandraYaml{})
+		for i := 0; i < yamlType.NumField(); i++ {
+			if CassandraYamlPropertyName(yamlType.Field(i)) == property {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CassandraYamlPropertyName returns the name in cassandra.yaml of a field of CassandraYaml.
+func CassandraYamlPropertyName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
 }
 
 type EmbeddedObjectMeta struct {
@@ -281,102 +964,47 @@ type CassandraConfig struct {
 
 	// +optional
 	JvmOptions *JvmOptions `json:"jvmOptions,omitempty"`
-}
 
-type Auth struct {
+	// TraceProbability is the probability that a request is traced, between 0 and 1.
+	// It is not a cassandra.yaml property, it is applied to the running nodes through the
+	// management API, and again to the nodes that restart. Defaults to 0.
 	// +optional
-	Enabled bool `json:"enabled,omitempty"`
-
-	// +optional
-	CacheValidityPeriodMillis *int64 `json:"cacheValidityPeriodMillis,omitempty"`
-
-	// +optional
-	CacheUpdateIntervalMillis *int64 `json:"cacheUpdateIntervalMillis,omitempty"`
-
-	// +optional
-	SuperUserSecretName string `json:"superUserSecretName,omitempty"`
+	TraceProbability *float64 `json:"traceProbability,omitempty"`
 }
 
-type CassandraYaml struct {
-	// Authenticator string `json:"authenticator,omitempty"`
-	//
-	// Authorizer string `json:"authorizer,omitempty"`
-	//
-	// RoleManager string `json:"role_manager,omitempty"`
-	//
-	// RoleValidityMillis *int64 `json:"roles_validity_in_ms,omitempty"`
-	//
-	// RoleUpdateIntervalMillis *int64 `json:"roles_update_interval_in_ms,omitempty"`
-	//
-	// PermissionValidityMillis *int64 `json:"permissions_validity_in_ms,omitempty"`
-
-	// +optional
-	NumTokens *int `json:"num_tokens,omitempty"`
-
-	// +optional
-	AllocateTokensForLocalReplicationFactor *int `json:"allocate_tokens_for_local_replication_factor,omitempty"`
-
-	// +optional
-	ConcurrentReads *int `json:"concurrent_reads,omitempty"`
-
-	// +optional
-	ConcurrentWrites *int `json:"concurrent_writes,omitempty"`
-
+// ConfigFiles references ConfigMaps that hold configuration files of Cassandra. The
+// ConfigMaps must be in the namespace of the K8ssandraCluster, they are copied to the
+// namespace of each datacenter in its k8sContext. A change of their content triggers a
+// rolling restart of the datacenters that use them.
+type ConfigFiles struct {
+	// Logback is the logback.xml file. It replaces the default one.
 	// +optional
-	ConcurrentCounterWrites *int `json:"concurrent_counter_writes,omitempty"`
+	Logback *ConfigFileSource `json:"logback,omitempty"`
 
+	// CassandraEnv is appended to cassandra-env.sh.
 	// +optional
-	AutoSnapshot *bool `json:"auto_snapshot,omitempty"`
+	CassandraEnv *ConfigFileSource `json:"cassandraEnv,omitempty"`
 
+	// RackDc is appended to cassandra-rackdc.properties. The dc and rack properties are
+	// set by cass-operator and must not be set in this file.
 	// +optional
-	MemtableFlushWriters *int `json:"memtable_flush_writers,omitempty"`
+	RackDc *ConfigFileSource `json:"rackDc,omitempty"`
 
+	// CommitLogArchiving is the commitlog_archiving.properties file. It replaces the
+	// default one.
 	// +optional
-	CommitLogSegmentSizeMb *int `json:"commitlog_segment_size_in_mb,omitempty"`
-
-	// +optional
-	ConcurrentCompactors *int `json:"concurrent_compactors,omitempty"`
-
-	// +optional
-	CompactionThroughputMbPerSec *int `json:"compaction_throughput_mb_per_sec,omitempty"`
-
-	// +optional
-	SstablePreemptiveOpenIntervalMb *int `json:"sstable_preemptive_open_interval_in_mb,omitempty"`
-
-	// +optional
-	KeyCacheSizeMb *int `json:"key_cache_size_in_mb,omitempty"`
-
-	// +optional
-	ThriftPreparedStatementCacheSizeMb *int `json:"thrift_prepared_statements_cache_size_mb,omitempty"`
-
-	// +optional
-	PreparedStatementsCacheSizeMb *int `json:"prepared_statements_cache_size_mb,omitempty"`
-
-	// +optional
-	StartRpc *bool `json:"start_rpc,omitempty"`
-
-	// +optional
-	SlowQueryLogTimeoutMs *int `json:"slow_query_log_timeout_in_ms,omitempty"`
-
-	// +optional
-	CounterCacheSizeMb *int `json:"counter_cache_size_in_mb,omitempty"`
-
-	// +optional
-	FileCacheSizeMb *int `json:"file_cache_size_in_mb,omitempty"`
-
-	// +optional
-	RowCacheSizeMb *int `json:"row_cache_size_in_mb,omitempty"`
+	CommitLogArchiving *ConfigFileSource `json:"commitLogArchiving,omitempty"`
 }
 
-type JvmOptions struct {
-	// +optional
-	HeapSize *resource.Quantity `json:"heapSize,omitempty"`
-
-	// +optional
-	HeapNewGenSize *resource.Quantity `json:"heapNewGenSize,omitempty"`
+// ConfigFileSource references a key of a ConfigMap.
+type ConfigFileSource struct {
+	// ConfigMapRef is the ConfigMap that holds the file.
+	ConfigMapRef corev1.LocalObjectReference `json:"configMapRef"`
 
+	// Key is the key of the file in the ConfigMap. Defaults to the name of the file, e.g.,
+	// logback.xml.
 	// +optional
-	AdditionalOptions []string `json:"additionalOptions,omitempty"`
+	Key string `json:"key,omitempty"`
 }
 
 func (s *K8ssandraClusterStatus) GetConditionStatus(conditionType K8ssandraClusterConditionType) corev1.ConditionStatus {
@@ -11,6 +11,7 @@ import (
 func TestK8ssandraCluster(t *testing.T) {
 	t.Run("HasStargates", testK8ssandraClusterHasStargates)
 	t.Run("HasReapers", testK8ssandraClusterHasReapers)
+	t.Run("IsDatacenterStopped", testK8ssandraClusterIsDatacenterStopped)
 }
 
 func testK8ssandraClusterHasStargates(t *testing.T) {
@@ -100,3 +101,34 @@ func testK8ssandraClusterHasReapers(t *testing.T) {
 		assert.True(t, kc.HasReapers())
 	})
 }
+
+func testK8ssandraClusterIsDatacenterStopped(t *testing.T) {
+	stopped := true
+	running := false
+	kc := K8ssandraCluster{
+		Spec: K8ssandraClusterSpec{
+			Cassandra: &CassandraClusterTemplate{
+				Cluster: "cluster1",
+				Datacenters: []CassandraDatacenterTemplate{
+					{Size: 3},
+					{Size: 3, Stopped: &stopped},
+					{Size: 3, Stopped: &running},
+				},
+			},
+		},
+	}
+	dcs := kc.Spec.Cassandra.Datacenters
+
+	t.Run("cluster running", func(t *testing.T) {
+		assert.False(t, kc.IsDatacenterStopped(&dcs[0]))
+		assert.True(t, kc.IsDatacenterStopped(&dcs[1]))
+		assert.False(t, kc.IsDatacenterStopped(&dcs[2]))
+	})
+	t.Run("cluster stopped", func(t *testing.T) {
+		kc := kc.DeepCopy()
+		kc.Spec.Stopped = true
+		assert.True(t, kc.IsDatacenterStopped(&dcs[0]))
+		assert.True(t, kc.IsDatacenterStopped(&dcs[1]))
+		assert.False(t, kc.IsDatacenterStopped(&dcs[2]))
+	})
+}
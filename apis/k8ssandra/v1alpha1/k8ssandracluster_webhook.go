@@ -0,0 +1,456 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var (
+	webhookLog = logf.Log.WithName("k8ssandracluster-webhook")
+
+	// clientCache is used to check that the k8sContext of each datacenter refers to a
+	// known ClientConfig. It is nil when the webhook runs outside of the control plane, in
+	// which case the check is skipped.
+	clientCache *clientcache.ClientCache
+
+	// cqlIdentifierRegexp matches unquoted CQL identifiers that can be used as keyspace names.
+	cqlIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]{1,48}$`)
+)
+
+// SetupWebhookWithManager registers the validating webhook for K8ssandraCluster with mgr.
+func (in *K8ssandraCluster) SetupWebhookWithManager(mgr ctrl.Manager, cCache *clientcache.ClientCache) error {
+	clientCache = cCache
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-k8ssandra-io-v1alpha1-k8ssandracluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8ssandra.io,resources=k8ssandraclusters,verbs=create;update,versions=v1alpha1,name=vk8ssandracluster.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &K8ssandraCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (in *K8ssandraCluster) ValidateCreate() error {
+	webhookLog.Info("validate create", "name", in.Name)
+
+	return in.toAggregateError(in.validateK8ssandraCluster(nil))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (in *K8ssandraCluster) ValidateUpdate(old runtime.Object) error {
+	webhookLog.Info("validate update", "name", in.Name)
+
+	oldCluster, ok := old.(*K8ssandraCluster)
+	if !ok {
+		return fmt.Errorf("expected a K8ssandraCluster but got a %T", old)
+	}
+
+	if in.DeletionTimestamp != nil {
+		// Do not get in the way of the removal of the finalizer
+		return nil
+	}
+
+	allErrs := in.validateK8ssandraCluster(oldCluster)
+	allErrs = append(allErrs, in.validateImmutableFields(oldCluster)...)
+	return in.toAggregateError(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (in *K8ssandraCluster) ValidateDelete() error {
+	return nil
+}
+
+func (in *K8ssandraCluster) toAggregateError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("K8ssandraCluster").GroupKind(), in.Name, allErrs)
+}
+
+// validateK8ssandraCluster validates the spec of in. oldCluster is the current version of
+// the object for updates and nil for creates. It is used to only check the k8sContext of
+// datacenters that are new or whose k8sContext changed, so that the operator can still
+// update an existing K8ssandraCluster if one of its ClientConfigs goes missing.
+func (in *K8ssandraCluster) validateK8ssandraCluster(oldCluster *K8ssandraCluster) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if in.Spec.Cassandra == nil {
+		return allErrs
+	}
+
+	cassandraPath := field.NewPath("spec", "cassandra")
+	dcNames := make(map[string]bool, len(in.Spec.Cassandra.Datacenters))
+
+	if in.Spec.Cassandra.ServerVersion != "" {
+		allErrs = append(allErrs, validateServerVersion(in.Spec.Cassandra.ServerVersion, cassandraPath.Child("serverVersion"))...)
+	}
+
+	clusterJvmOptions := in.Spec.Cassandra.CassandraConfig.getJvmOptions()
+	allErrs = append(allErrs, clusterJvmOptions.Validate(in.Spec.Cassandra.ServerVersion,
+		VolumeMountPaths(in.Spec.Cassandra.StorageConfig), cassandraPath.Child("config", "jvmOptions"))...)
+
+	if in.Spec.Cassandra.TLS != nil {
+		allErrs = append(allErrs, validateTLS(in.Spec.Cassandra.TLS, cassandraPath.Child("tls"))...)
+	}
+
+	if in.Spec.Cassandra.Auth != nil {
+		allErrs = append(allErrs, validateAuth(in.Spec.Cassandra.Auth, cassandraPath.Child("auth"))...)
+	}
+
+	for i, dcTemplate := range in.Spec.Cassandra.Datacenters {
+		dcPath := cassandraPath.Child("datacenters").Index(i)
+
+		if dcNames[dcTemplate.Meta.Name] {
+			allErrs = append(allErrs, field.Duplicate(dcPath.Child("metadata", "name"), dcTemplate.Meta.Name))
+		}
+		dcNames[dcTemplate.Meta.Name] = true
+
+		if clientCache != nil && dcTemplate.K8sContext != "" && !oldCluster.hasDatacenterInContext(dcTemplate.Meta.Name, dcTemplate.K8sContext) {
+			if _, err := clientCache.GetRemoteClient(dcTemplate.K8sContext); err != nil {
+				allErrs = append(allErrs, field.Invalid(dcPath.Child("k8sContext"), dcTemplate.K8sContext,
+					"no ClientConfig found for this context"))
+			}
+		}
+
+		storageConfig := dcTemplate.StorageConfig
+		if storageConfig == nil {
+			storageConfig = in.Spec.Cassandra.StorageConfig
+		}
+		if storageConfig == nil || storageConfig.CassandraDataVolumeClaimSpec == nil {
+			allErrs = append(allErrs, field.Required(dcPath.Child("storageConfig"),
+				"storageConfig.cassandraDataVolumeClaimSpec must be set either at the cluster level or at the datacenter level"))
+		}
+
+		stargateTemplate := dcTemplate.Stargate.Coalesce(in.Spec.Stargate)
+		if stargateTemplate != nil {
+			racks := dcTemplate.Racks
+			if len(racks) == 0 {
+				racks = in.Spec.Cassandra.Racks
+			}
+			allErrs = append(allErrs, validateStargate(stargateTemplate, racks, dcPath.Child("stargate"))...)
+		}
+
+		if dcTemplate.Reaper != nil {
+			allErrs = append(allErrs, validateReaperTemplate(dcTemplate.Reaper, dcPath.Child("reaper"))...)
+		}
+
+		serverVersion := dcTemplate.ServerVersion
+		if serverVersion == "" {
+			serverVersion = in.Spec.Cassandra.ServerVersion
+		} else {
+			allErrs = append(allErrs, validateServerVersion(serverVersion, dcPath.Child("serverVersion"))...)
+		}
+		// The datacenter is created with the cluster-level options merged with its own
+		// options, which may conflict even though each of them is valid
+		dcJvmOptions := CoalesceJvmOptions(in.Spec.Cassandra, &dcTemplate)
+		allErrs = append(allErrs, dcJvmOptions.Validate(serverVersion,
+			VolumeMountPaths(in.Spec.Cassandra.StorageConfig, dcTemplate.StorageConfig), dcPath.Child("config", "jvmOptions"))...)
+
+		if jmxPort := dcJvmOptions.GetJmxPort(); jmxPort != DefaultJmxPort && (in.Spec.Reaper != nil || dcTemplate.Reaper != nil) {
+			allErrs = append(allErrs, field.Forbidden(dcPath.Child("config", "jvmOptions", "jmx", "port"),
+				fmt.Sprintf("the JMX port must be %d when Reaper is deployed", DefaultJmxPort)))
+		}
+
+		for j, path := range dcTemplate.Unset {
+			if !IsValidUnsetPath(path) {
+				allErrs = append(allErrs, field.Invalid(dcPath.Child("unset").Index(j), path, "unsupported path"))
+			}
+		}
+	}
+
+	if in.Spec.Cassandra.SystemReplication != nil {
+		allErrs = append(allErrs, validateSystemReplication(in.Spec.Cassandra.SystemReplication,
+			in.Spec.Cassandra.Datacenters, cassandraPath.Child("systemReplication"))...)
+	}
+
+	if in.Spec.Reaper != nil {
+		allErrs = append(allErrs, validateReaper(in.Spec.Reaper, field.NewPath("spec", "reaper"))...)
+	}
+
+	return allErrs
+}
+
+// hasDatacenterInContext returns true if the receiver declares a datacenter named dcName in
+// k8sContext.
+func (in *K8ssandraCluster) hasDatacenterInContext(dcName, k8sContext string) bool {
+	if in == nil || in.Spec.Cassandra == nil {
+		return false
+	}
+	for _, dcTemplate := range in.Spec.Cassandra.Datacenters {
+		if dcTemplate.Meta.Name == dcName && dcTemplate.K8sContext == k8sContext {
+			return true
+		}
+	}
+	return false
+}
+
+// validateImmutableFields checks that the fields that cannot be changed once the
+// CassandraDatacenters have been created are unchanged.
+func (in *K8ssandraCluster) validateImmutableFields(oldCluster *K8ssandraCluster) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if in.Spec.Cassandra == nil || oldCluster.Spec.Cassandra == nil {
+		return allErrs
+	}
+
+	cassandraPath := field.NewPath("spec", "cassandra")
+	newCassandra := in.Spec.Cassandra
+	oldCassandra := oldCluster.Spec.Cassandra
+
+	if newCassandra.Cluster != oldCassandra.Cluster {
+		allErrs = append(allErrs, field.Forbidden(cassandraPath.Child("cluster"), "cluster name cannot be changed"))
+	}
+
+	if newCassandra.SuperuserSecretName != oldCassandra.SuperuserSecretName {
+		allErrs = append(allErrs, field.Forbidden(cassandraPath.Child("superuserSecret"), "superuserSecret cannot be changed"))
+	}
+
+	oldDcs := make(map[string]CassandraDatacenterTemplate, len(oldCassandra.Datacenters))
+	for _, dcTemplate := range oldCassandra.Datacenters {
+		oldDcs[dcTemplate.Meta.Name] = dcTemplate
+	}
+
+	added, kept := 0, 0
+	for i, dcTemplate := range newCassandra.Datacenters {
+		dcPath := cassandraPath.Child("datacenters").Index(i)
+		oldDc, found := oldDcs[dcTemplate.Meta.Name]
+		if !found {
+			added++
+			continue
+		}
+		kept++
+
+		if dcTemplate.K8sContext != oldDc.K8sContext {
+			allErrs = append(allErrs, field.Forbidden(dcPath.Child("k8sContext"), "k8sContext of an existing datacenter cannot be changed"))
+		}
+		if dcTemplate.Meta.Namespace != oldDc.Meta.Namespace {
+			allErrs = append(allErrs, field.Forbidden(dcPath.Child("metadata", "namespace"), "namespace of an existing datacenter cannot be changed"))
+		}
+	}
+
+	// Datacenters can be added or removed, but not both at the same time. Otherwise there
+	// is no telling a rename apart from the replacement of a datacenter.
+	if removed := len(oldCassandra.Datacenters) - kept; added > 0 && removed > 0 {
+		allErrs = append(allErrs, field.Forbidden(cassandraPath.Child("datacenters"),
+			"datacenters cannot be renamed, and cannot be added and removed in the same update"))
+	}
+
+	return allErrs
+}
+
+// validateServerVersion checks that serverVersion is a Cassandra version that the operator
+// supports.
+func validateServerVersion(serverVersion string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if err := version.Validate(serverVersion); err != nil {
+		allErrs = append(allErrs, field.Invalid(path, serverVersion, err.Error()))
+	}
+	return allErrs
+}
+
+// validateTLS checks that the options of tls are supported by its certificate provider.
+func validateTLS(tls *TLSConfig, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if tls.GetProvider() == CertificateProviderInternal {
+		if tls.GetKeystoreFormat() == encryption.StoreTypePKCS12 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("keystoreFormat"),
+				"PKCS12 requires the CertManager provider"))
+		}
+		if tls.IssuerRef != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("issuerRef"),
+				"issuerRef requires the CertManager provider"))
+		}
+	}
+
+	if tls.GetCertificateDuration() <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("certificateDuration"),
+			tls.GetCertificateDuration().String(), "must be positive"))
+	} else if tls.GetRenewBefore() <= 0 || tls.GetRenewBefore() >= tls.GetCertificateDuration() {
+		allErrs = append(allErrs, field.Invalid(path.Child("renewBefore"),
+			tls.GetRenewBefore().String(), "must be positive and shorter than the certificate duration"))
+	}
+
+	return allErrs
+}
+
+// validateAuth checks that the LDAP options are only set with the LDAP provider, and that
+// the LDAP provider is configured.
+func validateAuth(auth *Auth, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if auth.GetProvider() != AuthProviderLDAP {
+		if auth.LDAP != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("ldap"), "ldap requires the LDAP provider"))
+		}
+		return allErrs
+	}
+
+	if !auth.IsEnabled() {
+		allErrs = append(allErrs, field.Forbidden(path.Child("provider"), "the LDAP provider requires authentication to be enabled"))
+	}
+	if auth.LDAP == nil {
+		allErrs = append(allErrs, field.Required(path.Child("ldap"), "the LDAP provider requires ldap"))
+	} else if auth.LDAP.GetGroupSyncPeriod() <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("ldap", "groupSyncPeriod"),
+			auth.LDAP.GetGroupSyncPeriod().String(), "must be positive"))
+	}
+
+	return allErrs
+}
+
+// validateSystemReplication checks that the replication factor overrides of the system
+// keyspaces name datacenters of the cluster and fit in their size.
+func validateSystemReplication(policy *SystemReplicationPolicy, datacenters []CassandraDatacenterTemplate, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	dcSizes := make(map[string]int32, len(datacenters))
+	for _, dcTemplate := range datacenters {
+		dcSizes[dcTemplate.Meta.Name] = dcTemplate.Size
+	}
+
+	dcNames := make([]string, 0, len(policy.Datacenters))
+	for dcName := range policy.Datacenters {
+		dcNames = append(dcNames, dcName)
+	}
+	sort.Strings(dcNames)
+
+	for _, dcName := range dcNames {
+		rf := policy.Datacenters[dcName]
+		dcPath := path.Child("datacenters").Key(dcName)
+		size, found := dcSizes[dcName]
+		switch {
+		case !found:
+			allErrs = append(allErrs, field.NotFound(dcPath, dcName))
+		case rf < 1:
+			allErrs = append(allErrs, field.Invalid(dcPath, rf, "the replication factor must be at least 1"))
+		case rf > int(size):
+			allErrs = append(allErrs, field.Invalid(dcPath, rf,
+				fmt.Sprintf("the replication factor cannot exceed the size of the datacenter (%d)", size)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateStargate checks that the Stargate template of a datacenter can be deployed
+// given the racks of that datacenter.
+func validateStargate(template *stargateapi.StargateDatacenterTemplate, racks []cassdcapi.Rack, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateStargateHeap(&template.StargateTemplate, path)...)
+
+	rackNames := make(map[string]bool, len(racks))
+	for _, rack := range racks {
+		rackNames[rack.Name] = true
+	}
+	if len(rackNames) == 0 {
+		// cass-operator creates a single rack named "default" when none is specified
+		rackNames["default"] = true
+	}
+
+	for i, rackTemplate := range template.Racks {
+		rackPath := path.Child("racks").Index(i)
+		if !rackNames[rackTemplate.Name] {
+			allErrs = append(allErrs, field.NotFound(rackPath.Child("name"), rackTemplate.Name))
+		}
+		allErrs = append(allErrs, validateStargateHeap(&rackTemplate.StargateTemplate, rackPath)...)
+	}
+
+	return allErrs
+}
+
+// validateStargateHeap checks that the Stargate heap fits in the memory limit of the
+// Stargate pods.
+func validateStargateHeap(template *stargateapi.StargateTemplate, path *field.Path) field.ErrorList {
+	if template.HeapSize == nil || template.Resources == nil {
+		return nil
+	}
+	limit, found := template.Resources.Limits[corev1.ResourceMemory]
+	if !found || template.HeapSize.Cmp(limit) <= 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path.Child("heapSize"), template.HeapSize.String(),
+		fmt.Sprintf("heap size must not exceed the memory limit of %s", limit.String()))}
+}
+
+func validateReaper(template *reaperapi.ReaperClusterTemplate, path *field.Path) field.ErrorList {
+	allErrs := validateReaperTemplate(&template.ReaperDatacenterTemplate, path)
+
+	if template.Keyspace != "" {
+		if !cqlIdentifierRegexp.MatchString(template.Keyspace) {
+			allErrs = append(allErrs, field.Invalid(path.Child("keyspace"), template.Keyspace,
+				"must be a valid keyspace name: at most 48 alphanumeric characters or underscores"))
+		} else if isSystemKeyspace(template.Keyspace) {
+			allErrs = append(allErrs, field.Invalid(path.Child("keyspace"), template.Keyspace,
+				"cannot be a system keyspace"))
+		}
+	}
+
+	for _, secretRef := range []struct {
+		name  string
+		value string
+	}{
+		{"cassandraUserSecretRef", template.CassandraUserSecretRef},
+		{"jmxUserSecretRef", template.JmxUserSecretRef},
+	} {
+		if secretRef.value == "" {
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(secretRef.value) {
+			allErrs = append(allErrs, field.Invalid(path.Child(secretRef.name), secretRef.value, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateReaperTemplate(template *reaperapi.ReaperDatacenterTemplate, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if template.ServiceAccountName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(template.ServiceAccountName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("serviceAccountName"), template.ServiceAccountName, msg))
+		}
+	}
+	return allErrs
+}
+
+func isSystemKeyspace(keyspace string) bool {
+	switch keyspace {
+	case "system", "system_auth", "system_distributed", "system_schema", "system_traces", "system_views", "system_virtual_schema":
+		return true
+	}
+	return false
+}
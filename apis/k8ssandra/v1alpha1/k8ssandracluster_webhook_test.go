@@ -0,0 +1,567 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateK8ssandraCluster(t *testing.T) {
+	t.Run("ValidateCreate", testValidateCreate)
+	t.Run("ValidateUpdate", testValidateUpdate)
+}
+
+func testValidateCreate(t *testing.T) {
+	setupTestClientCache(t)
+
+	tests := []struct {
+		name    string
+		mutate  func(kc *K8ssandraCluster)
+		invalid string
+	}{
+		{
+			name:   "valid",
+			mutate: func(kc *K8ssandraCluster) {},
+		},
+		{
+			name: "duplicate dc name",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Meta.Name = "dc1"
+			},
+			invalid: "spec.cassandra.datacenters[1].metadata.name",
+		},
+		{
+			name: "unknown k8sContext",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].K8sContext = "unknown"
+			},
+			invalid: "spec.cassandra.datacenters[1].k8sContext",
+		},
+		{
+			name: "storage config at cluster level",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.StorageConfig = kc.Spec.Cassandra.Datacenters[0].StorageConfig
+				kc.Spec.Cassandra.Datacenters[0].StorageConfig = nil
+			},
+		},
+		{
+			name: "missing storage config",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[0].StorageConfig = nil
+			},
+			invalid: "spec.cassandra.datacenters[0].storageConfig",
+		},
+		{
+			name: "stargate rack not found",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[0].Stargate = &stargateapi.StargateDatacenterTemplate{
+					StargateClusterTemplate: stargateapi.StargateClusterTemplate{Size: 1},
+					Racks:                   []stargateapi.StargateRackTemplate{{Name: "rack1"}},
+				}
+			},
+			invalid: "spec.cassandra.datacenters[0].stargate.racks[0].name",
+		},
+		{
+			name: "stargate heap exceeds memory limit",
+			mutate: func(kc *K8ssandraCluster) {
+				heapSize := resource.MustParse("2Gi")
+				kc.Spec.Stargate = &stargateapi.StargateClusterTemplate{
+					Size: 1,
+					StargateTemplate: stargateapi.StargateTemplate{
+						HeapSize: &heapSize,
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						},
+					},
+				}
+			},
+			invalid: "spec.cassandra.datacenters[0].stargate.heapSize",
+		},
+		{
+			name: "reaper system keyspace",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Reaper = &reaperapi.ReaperClusterTemplate{Keyspace: "system_auth"}
+			},
+			invalid: "spec.reaper.keyspace",
+		},
+		{
+			name: "reaper invalid keyspace",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Reaper = &reaperapi.ReaperClusterTemplate{Keyspace: "reaper-db"}
+			},
+			invalid: "spec.reaper.keyspace",
+		},
+		{
+			name: "reaper invalid service account",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Reaper = &reaperapi.ReaperDatacenterTemplate{ServiceAccountName: "Reaper_SA"}
+			},
+			invalid: "spec.cassandra.datacenters[1].reaper.serviceAccountName",
+		},
+		{
+			name: "unset cluster settings",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Unset = []string{
+					"config.cassandraYaml.num_tokens",
+					"config.jvmOptions.additionalOptions.-XX:+UseG1GC",
+					"resources.limits.cpu",
+					"networking.hostNetwork",
+				}
+			},
+		},
+		{
+			name: "unset unknown cassandra.yaml property",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Unset = []string{"config.jvmOptions.heapSize", "config.cassandraYaml.unknown"}
+			},
+			invalid: "spec.cassandra.datacenters[1].unset[1]",
+		},
+		{
+			name: "unset unsupported path",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Unset = []string{"serverImage"}
+			},
+			invalid: "spec.cassandra.datacenters[1].unset[0]",
+		},
+		{
+			name: "cassandra 4.1",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.1.0"
+			},
+		},
+		{
+			name: "unsupported cluster version",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "3.0.25"
+			},
+			invalid: "spec.cassandra.serverVersion",
+		},
+		{
+			name: "unsupported datacenter version",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.Datacenters[1].ServerVersion = "4.2.0"
+			},
+			invalid: "spec.cassandra.datacenters[1].serverVersion",
+		},
+		{
+			name: "g1 tunables",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{
+						GarbageCollector: stringPtr(GarbageCollectorG1),
+						G1:               &G1Options{MaxGcPauseMs: intPtr(300)},
+					},
+				}
+			},
+		},
+		{
+			name: "cms with java 11",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{GarbageCollector: stringPtr(GarbageCollectorCMS)},
+				}
+			},
+			invalid: "spec.cassandra.config.jvmOptions.garbageCollector",
+		},
+		{
+			name: "java 11 with cassandra 3.11",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.Datacenters[1].ServerVersion = "3.11.11"
+				kc.Spec.Cassandra.Datacenters[1].CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{JavaVersion: intPtr(Java11)},
+				}
+			},
+			invalid: "spec.cassandra.datacenters[1].config.jvmOptions.javaVersion",
+		},
+		{
+			name: "new gen size with g1",
+			mutate: func(kc *K8ssandraCluster) {
+				newGenSize := resource.MustParse("512Mi")
+				kc.Spec.Cassandra.ServerVersion = "3.11.11"
+				kc.Spec.Cassandra.Datacenters[0].CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{
+						GarbageCollector: stringPtr(GarbageCollectorG1),
+						HeapNewGenSize:   &newGenSize,
+					},
+				}
+			},
+			invalid: "spec.cassandra.datacenters[0].config.jvmOptions.heapNewGenSize",
+		},
+		{
+			name: "heap from the cluster and zgc from the datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				heapSize := resource.MustParse("8Gi")
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{HeapSize: &heapSize, JavaVersion: intPtr(Java8)},
+				}
+				kc.Spec.Cassandra.Datacenters[1].CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{GarbageCollector: stringPtr(GarbageCollectorZGC)},
+				}
+			},
+			invalid: "spec.cassandra.datacenters[1].config.jvmOptions.garbageCollector",
+		},
+		{
+			name: "g1 from the cluster and new gen size from the datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				newGenSize := resource.MustParse("512Mi")
+				kc.Spec.Cassandra.ServerVersion = "3.11.11"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{GarbageCollector: stringPtr(GarbageCollectorG1)},
+				}
+				kc.Spec.Cassandra.Datacenters[0].CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{HeapNewGenSize: &newGenSize},
+				}
+			},
+			invalid: "spec.cassandra.datacenters[0].config.jvmOptions.heapNewGenSize",
+		},
+		{
+			name: "g1 from the cluster and g1 tunables from the datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{GarbageCollector: stringPtr(GarbageCollectorG1)},
+				}
+				kc.Spec.Cassandra.Datacenters[0].CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{G1: &G1Options{MaxGcPauseMs: intPtr(300)}},
+				}
+			},
+		},
+		{
+			name: "cms tunables without cms",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "3.11.11"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{Cms: &CmsOptions{SurvivorRatio: intPtr(8)}},
+				}
+			},
+			invalid: "spec.cassandra.config.jvmOptions.cms",
+		},
+		{
+			name: "heap dump on an additional volume",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.StorageConfig = &cassdcapi.StorageConfig{
+					AdditionalVolumes: cassdcapi.AdditionalVolumesSlice{{Name: "dumps", MountPath: "/dumps"}},
+				}
+				kc.Spec.Cassandra.Datacenters[0].CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{HeapDump: &HeapDumpOptions{Path: "/dumps/cassandra"}},
+				}
+			},
+		},
+		{
+			name: "heap dump not on a mounted volume",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{HeapDump: &HeapDumpOptions{Path: "/tmp"}},
+				}
+			},
+			invalid: "spec.cassandra.config.jvmOptions.heapDump.path",
+		},
+		{
+			name: "jmx port with reaper",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.ServerVersion = "4.0.1"
+				kc.Spec.Cassandra.CassandraConfig = &CassandraConfig{
+					JvmOptions: &JvmOptions{Jmx: &JmxOptions{Port: intPtr(7299)}},
+				}
+				kc.Spec.Reaper = &reaperapi.ReaperClusterTemplate{}
+			},
+			invalid: "spec.cassandra.datacenters[0].config.jvmOptions.jmx.port",
+		},
+		{
+			name: "pkcs12 stores with cert-manager",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.TLS = &TLSConfig{
+					ClientEncryption: &ClientEncryption{},
+					Provider:         CertificateProviderCertManager,
+					IssuerRef:        &IssuerReference{Name: "issuer"},
+					KeystoreFormat:   encryption.StoreTypePKCS12,
+				}
+			},
+		},
+		{
+			name: "pkcs12 stores with the internal provider",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.TLS = &TLSConfig{InternodeEncryption: "all", KeystoreFormat: encryption.StoreTypePKCS12}
+			},
+			invalid: "spec.cassandra.tls.keystoreFormat",
+		},
+		{
+			name: "issuer with the internal provider",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.TLS = &TLSConfig{InternodeEncryption: "all", IssuerRef: &IssuerReference{Name: "issuer"}}
+			},
+			invalid: "spec.cassandra.tls.issuerRef",
+		},
+		{
+			name: "renew before longer than the certificate duration",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.TLS = &TLSConfig{
+					InternodeEncryption: "all",
+					CertificateDuration: &metav1.Duration{Duration: 24 * time.Hour},
+				}
+			},
+			invalid: "spec.cassandra.tls.renewBefore",
+		},
+		{
+			name: "ldap provider",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Auth = &Auth{Provider: AuthProviderLDAP, LDAP: newTestLDAPConfig()}
+			},
+		},
+		{
+			name: "ldap provider without ldap",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Auth = &Auth{Provider: AuthProviderLDAP}
+			},
+			invalid: "spec.cassandra.auth.ldap",
+		},
+		{
+			name: "ldap provider with authentication disabled",
+			mutate: func(kc *K8ssandraCluster) {
+				disabled := false
+				kc.Spec.Cassandra.Auth = &Auth{Enabled: &disabled, Provider: AuthProviderLDAP, LDAP: newTestLDAPConfig()}
+			},
+			invalid: "spec.cassandra.auth.provider",
+		},
+		{
+			name: "ldap with the internal provider",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Auth = &Auth{LDAP: newTestLDAPConfig()}
+			},
+			invalid: "spec.cassandra.auth.ldap",
+		},
+		{
+			name: "zero ldap group sync period",
+			mutate: func(kc *K8ssandraCluster) {
+				ldap := newTestLDAPConfig()
+				ldap.GroupSyncPeriod = &metav1.Duration{}
+				kc.Spec.Cassandra.Auth = &Auth{Provider: AuthProviderLDAP, LDAP: ldap}
+			},
+			invalid: "spec.cassandra.auth.ldap.groupSyncPeriod",
+		},
+		{
+			name: "system replication override",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.SystemReplication = &SystemReplicationPolicy{Datacenters: map[string]int{"dc1": 1, "dc2": 3}}
+			},
+		},
+		{
+			name: "system replication override of an unknown datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.SystemReplication = &SystemReplicationPolicy{Datacenters: map[string]int{"dc3": 3}}
+			},
+			invalid: "spec.cassandra.systemReplication.datacenters[dc3]",
+		},
+		{
+			name: "zero system replication override",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.SystemReplication = &SystemReplicationPolicy{Datacenters: map[string]int{"dc1": 0}}
+			},
+			invalid: "spec.cassandra.systemReplication.datacenters[dc1]",
+		},
+		{
+			name: "system replication override larger than the datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.SystemReplication = &SystemReplicationPolicy{Datacenters: map[string]int{"dc2": 5}}
+			},
+			invalid: "spec.cassandra.systemReplication.datacenters[dc2]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := newTestCluster()
+			tt.mutate(kc)
+			assertValidationResult(t, kc.ValidateCreate(), tt.invalid)
+		})
+	}
+}
+
+func testValidateUpdate(t *testing.T) {
+	setupTestClientCache(t)
+
+	tests := []struct {
+		name    string
+		mutate  func(kc *K8ssandraCluster)
+		invalid string
+	}{
+		{
+			name: "scale datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[0].Size = 6
+			},
+		},
+		{
+			name: "scale datacenter below its system replication override",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.SystemReplication = &SystemReplicationPolicy{Datacenters: map[string]int{"dc1": 3}}
+				kc.Spec.Cassandra.Datacenters[0].Size = 1
+			},
+			invalid: "spec.cassandra.systemReplication.datacenters[dc1]",
+		},
+		{
+			name: "change cluster name",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Cluster = "renamed"
+			},
+			invalid: "spec.cassandra.cluster",
+		},
+		{
+			name: "change superuser secret",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.SuperuserSecretName = "other-superuser"
+			},
+			invalid: "spec.cassandra.superuserSecret",
+		},
+		{
+			name: "change k8sContext",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].K8sContext = "cluster-0"
+			},
+			invalid: "spec.cassandra.datacenters[1].k8sContext",
+		},
+		{
+			name: "change namespace",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Meta.Namespace = "other-namespace"
+			},
+			invalid: "spec.cassandra.datacenters[1].metadata.namespace",
+		},
+		{
+			name: "rename datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters[1].Meta.Name = "dc3"
+			},
+			invalid: "spec.cassandra.datacenters",
+		},
+		{
+			name: "add datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				dc3 := kc.Spec.Cassandra.Datacenters[1].DeepCopy()
+				dc3.Meta.Name = "dc3"
+				kc.Spec.Cassandra.Datacenters = append(kc.Spec.Cassandra.Datacenters, *dc3)
+			},
+		},
+		{
+			name: "remove datacenter",
+			mutate: func(kc *K8ssandraCluster) {
+				kc.Spec.Cassandra.Datacenters = kc.Spec.Cassandra.Datacenters[:1]
+			},
+		},
+		{
+			name: "existing datacenter with unknown k8sContext",
+			mutate: func(kc *K8ssandraCluster) {
+				delete(clientCache.GetRemoteClients(), "cluster-1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestClientCache(t)
+			oldCluster := newTestCluster()
+			kc := oldCluster.DeepCopy()
+			tt.mutate(kc)
+			assertValidationResult(t, kc.ValidateUpdate(oldCluster), tt.invalid)
+		})
+	}
+
+	t.Run("being deleted", func(t *testing.T) {
+		oldCluster := newTestCluster()
+		kc := oldCluster.DeepCopy()
+		kc.DeletionTimestamp = &metav1.Time{}
+		kc.Spec.Cassandra.Cluster = "renamed"
+		assert.NoError(t, kc.ValidateUpdate(oldCluster))
+	})
+}
+
+// setupTestClientCache sets the package clientCache to one that knows about the
+// cluster-0 and cluster-1 contexts, and restores the previous value when t completes.
+func setupTestClientCache(t *testing.T) {
+	previous := clientCache
+	t.Cleanup(func() {
+		clientCache = previous
+	})
+
+	scheme := runtime.NewScheme()
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clientCache = clientcache.New(c, c, scheme)
+	clientCache.AddClient("cluster-0", c)
+	clientCache.AddClient("cluster-1", c)
+}
+
+func assertValidationResult(t *testing.T, err error, invalidField string) {
+	if invalidField == "" {
+		assert.NoError(t, err)
+		return
+	}
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), invalidField)
+	}
+}
+
+func newTestCluster() *K8ssandraCluster {
+	storageConfig := &cassdcapi.StorageConfig{
+		CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{},
+	}
+	return &K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: K8ssandraClusterSpec{
+			Cassandra: &CassandraClusterTemplate{
+				Cluster:             "test",
+				SuperuserSecretName: "test-superuser",
+				Datacenters: []CassandraDatacenterTemplate{
+					{
+						Meta:          EmbeddedObjectMeta{Name: "dc1"},
+						K8sContext:    "cluster-0",
+						Size:          3,
+						StorageConfig: storageConfig,
+					},
+					{
+						Meta:          EmbeddedObjectMeta{Name: "dc2"},
+						K8sContext:    "cluster-1",
+						Size:          3,
+						StorageConfig: storageConfig,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestLDAPConfig() *LDAPConfig {
+	return &LDAPConfig{
+		ServerURL:     "ldap://ldap.example.com:389",
+		BindSecretRef: corev1.LocalObjectReference{Name: "ldap-bind"},
+		SearchBase:    "ou=people,dc=example,dc=com",
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
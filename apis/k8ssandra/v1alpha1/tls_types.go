@@ -0,0 +1,157 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CertificateProvider string
+
+const (
+	// CertificateProviderInternal issues the certificates with a CA that is generated by
+	// the operator and stored in a Secret.
+	CertificateProviderInternal = CertificateProvider("Internal")
+
+	// CertificateProviderCertManager issues the certificates with cert-manager.
+	CertificateProviderCertManager = CertificateProvider("CertManager")
+
+	InternodeEncryptionNone = "none"
+
+	DefaultCertificateDuration = 365 * 24 * time.Hour
+	DefaultRenewBefore         = 30 * 24 * time.Hour
+	DefaultCADuration          = 10 * 365 * 24 * time.Hour
+)
+
+// TLSConfig configures the encryption of the connections to and between the Cassandra
+// nodes. Each datacenter is given a certificate, which is stored along with the CA
+// certificates in a keystore and a truststore. The stores are replicated to the
+// Kubernetes clusters of the datacenters and mounted in the Cassandra, Stargate and
+// Reaper pods. Renewing a certificate triggers a rolling restart of the pods that use it.
+type TLSConfig struct {
+	// ClientEncryption enables the encryption of the CQL connections.
+	// +optional
+	ClientEncryption *ClientEncryption `json:"clientEncryption,omitempty"`
+
+	// InternodeEncryption selects the internode connections that are encrypted: none,
+	// all, dc for the connections between datacenters, or rack for the connections
+	// between racks. Stargate is given the stores when it is not none.
+	// +kubebuilder:validation:Enum=none;all;dc;rack
+	// +kubebuilder:default=none
+	// +optional
+	InternodeEncryption string `json:"internodeEncryption,omitempty"`
+
+	// Provider is what issues the certificates. Internal, the default, issues them with the
+	// CA whose PEM-encoded certificate and private key are in the tls.crt and tls.key keys of
+	// the <name>-ca Secret, where name is the name of the K8ssandraCluster. The Secret is
+	// generated if it does not exist. CertManager creates cert-manager Certificates, which
+	// requires cert-manager to be installed in the Kubernetes cluster of the
+	// K8ssandraCluster.
+	// +kubebuilder:validation:Enum=Internal;CertManager
+	// +kubebuilder:default=Internal
+	// +optional
+	Provider CertificateProvider `json:"provider,omitempty"`
+
+	// IssuerRef references the cert-manager issuer of the certificates with the
+	// CertManager provider. A self-signed CA issuer is created when it is not set.
+	// +optional
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+
+	// KeystoreFormat is the format of the keystores and truststores. PKCS12 requires the
+	// CertManager provider.
+	// +kubebuilder:validation:Enum=JKS;PKCS12
+	// +kubebuilder:default=JKS
+	// +optional
+	KeystoreFormat encryption.StoreType `json:"keystoreFormat,omitempty"`
+
+	// CertificateDuration is the validity of the certificates of the datacenters. Defaults
+	// to one year.
+	// +optional
+	CertificateDuration *metav1.Duration `json:"certificateDuration,omitempty"`
+
+	// RenewBefore is how long before their expiration the certificates are renewed.
+	// Defaults to 30 days.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+type ClientEncryption struct {
+	// Optional allows the clients to connect without TLS to the encrypted port.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+
+	// RequireClientAuth requires the clients to present a certificate signed by the CA.
+	// Reaper presents the certificate of its datacenter.
+	// +optional
+	RequireClientAuth bool `json:"requireClientAuth,omitempty"`
+}
+
+// IssuerReference references a cert-manager Issuer or ClusterIssuer.
+type IssuerReference struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// IsClientEncryptionEnabled returns true if the CQL connections are encrypted.
+func (in *TLSConfig) IsClientEncryptionEnabled() bool {
+	return in != nil && in.ClientEncryption != nil
+}
+
+// IsInternodeEncryptionEnabled returns true if some internode connections are encrypted.
+func (in *TLSConfig) IsInternodeEncryptionEnabled() bool {
+	return in != nil && in.InternodeEncryption != "" && in.InternodeEncryption != InternodeEncryptionNone
+}
+
+// IsEnabled returns true if some connections are encrypted.
+func (in *TLSConfig) IsEnabled() bool {
+	return in.IsClientEncryptionEnabled() || in.IsInternodeEncryptionEnabled()
+}
+
+func (in *TLSConfig) GetProvider() CertificateProvider {
+	if in.Provider == "" {
+		return CertificateProviderInternal
+	}
+	return in.Provider
+}
+
+func (in *TLSConfig) GetKeystoreFormat() encryption.StoreType {
+	if in.KeystoreFormat == "" {
+		return encryption.StoreTypeJKS
+	}
+	return in.KeystoreFormat
+}
+
+func (in *TLSConfig) GetCertificateDuration() time.Duration {
+	if in.CertificateDuration == nil {
+		return DefaultCertificateDuration
+	}
+	return in.CertificateDuration.Duration
+}
+
+func (in *TLSConfig) GetRenewBefore() time.Duration {
+	if in.RenewBefore == nil {
+		return DefaultRenewBefore
+	}
+	return in.RenewBefore.Duration
+}
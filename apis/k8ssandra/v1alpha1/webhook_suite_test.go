@@ -0,0 +1,139 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+const (
+	timeout  = time.Second * 10
+	interval = time.Millisecond * 250
+)
+
+// TestWebhook runs the validating webhook against an envtest API server to verify that
+// invalid K8ssandraClusters are rejected at admission time.
+func TestWebhook(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "..", "config", "webhook")},
+		},
+	}
+
+	cfg, err := testEnv.Start()
+	require.NoError(err, "failed to start test environment")
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("failed to stop test environment: %s", err)
+		}
+	}()
+
+	scheme := runtime.NewScheme()
+	require.NoError(clientgoscheme.AddToScheme(scheme))
+	require.NoError(AddToScheme(scheme))
+	require.NoError(admissionv1.AddToScheme(scheme))
+
+	testClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	require.NoError(err, "failed to create test client")
+
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Host:               webhookInstallOptions.LocalServingHost,
+		Port:               webhookInstallOptions.LocalServingPort,
+		CertDir:            webhookInstallOptions.LocalServingCertDir,
+		LeaderElection:     false,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(err, "failed to create manager")
+
+	cCache := clientcache.New(testClient, testClient, scheme)
+	cCache.AddClient("cluster-0", testClient)
+	require.NoError((&K8ssandraCluster{}).SetupWebhookWithManager(mgr, cCache), "failed to set up webhook")
+	require.NoError((&CassandraKeyspace{}).SetupWebhookWithManager(mgr), "failed to set up webhook")
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("failed to start manager: %s", err)
+		}
+	}()
+
+	dialer := &net.Dialer{Timeout: time.Second}
+	addrPort := fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort)
+	require.Eventually(func() bool {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addrPort, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return false
+		}
+		return conn.Close() == nil
+	}, timeout, interval, "timed out waiting for the webhook server")
+
+	kc := &K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: K8ssandraClusterSpec{
+			Cassandra: &CassandraClusterTemplate{
+				Cluster: "test",
+				Datacenters: []CassandraDatacenterTemplate{
+					{
+						Meta:          EmbeddedObjectMeta{Name: "dc1"},
+						K8sContext:    "cluster-0",
+						Size:          3,
+						ServerVersion: "4.0.1",
+						StorageConfig: &cassdcapi.StorageConfig{
+							CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Log("check that a K8ssandraCluster with an unknown k8sContext is rejected")
+	invalid := kc.DeepCopy()
+	invalid.Spec.Cassandra.Datacenters[0].K8sContext = "unknown"
+	require.Error(testClient.Create(ctx, invalid), "K8ssandraCluster with an unknown k8sContext should be rejected")
+
+	t.Log("check that a K8ssandraCluster without storage config is rejected")
+	invalid = kc.DeepCopy()
+	invalid.Spec.Cassandra.Datacenters[0].StorageConfig = nil
+	require.Error(testClient.Create(ctx, invalid), "K8ssandraCluster without storage config should be rejected")
+
+	t.Log("check that a valid K8ssandraCluster is accepted")
+	require.NoError(testClient.Create(ctx, kc), "failed to create K8ssandraCluster")
+
+	t.Log("check that the cluster name cannot be changed")
+	update := kc.DeepCopy()
+	update.Spec.Cassandra.Cluster = "renamed"
+	require.Error(testClient.Update(ctx, update), "cluster name change should be rejected")
+
+	t.Log("check that the datacenter can be scaled")
+	update = kc.DeepCopy()
+	update.Spec.Cassandra.Datacenters[0].Size = 6
+	require.NoError(testClient.Update(ctx, update), "failed to scale datacenter")
+}
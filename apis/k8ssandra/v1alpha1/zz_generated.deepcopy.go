@@ -25,12 +25,103 @@ import (
 	reaperv1alpha1 "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
 	stargatev1alpha1 "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogOptions) DeepCopyInto(out *AuditLogOptions) {
+	*out = *in
+	if in.Logger != nil {
+		in, out := &in.Logger, &out.Logger
+		*out = new(ParameterizedClass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLogsDir != nil {
+		in, out := &in.AuditLogsDir, &out.AuditLogsDir
+		*out = new(string)
+		**out = **in
+	}
+	if in.IncludedKeyspaces != nil {
+		in, out := &in.IncludedKeyspaces, &out.IncludedKeyspaces
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExcludedKeyspaces != nil {
+		in, out := &in.ExcludedKeyspaces, &out.ExcludedKeyspaces
+		*out = new(string)
+		**out = **in
+	}
+	if in.IncludedCategories != nil {
+		in, out := &in.IncludedCategories, &out.IncludedCategories
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExcludedCategories != nil {
+		in, out := &in.ExcludedCategories, &out.ExcludedCategories
+		*out = new(string)
+		**out = **in
+	}
+	if in.IncludedUsers != nil {
+		in, out := &in.IncludedUsers, &out.IncludedUsers
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExcludedUsers != nil {
+		in, out := &in.ExcludedUsers, &out.ExcludedUsers
+		*out = new(string)
+		**out = **in
+	}
+	if in.RollCycle != nil {
+		in, out := &in.RollCycle, &out.RollCycle
+		*out = new(string)
+		**out = **in
+	}
+	if in.Block != nil {
+		in, out := &in.Block, &out.Block
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxQueueWeight != nil {
+		in, out := &in.MaxQueueWeight, &out.MaxQueueWeight
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxLogSize != nil {
+		in, out := &in.MaxLogSize, &out.MaxLogSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ArchiveCommand != nil {
+		in, out := &in.ArchiveCommand, &out.ArchiveCommand
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxArchiveRetries != nil {
+		in, out := &in.MaxArchiveRetries, &out.MaxArchiveRetries
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogOptions.
+func (in *AuditLogOptions) DeepCopy() *AuditLogOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Auth) DeepCopyInto(out *Auth) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.CacheValidityPeriodMillis != nil {
 		in, out := &in.CacheValidityPeriodMillis, &out.CacheValidityPeriodMillis
 		*out = new(int64)
@@ -41,6 +132,11 @@ func (in *Auth) DeepCopyInto(out *Auth) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
@@ -61,6 +157,11 @@ func (in *CassandraClusterTemplate) DeepCopyInto(out *CassandraClusterTemplate)
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResourceTuning != nil {
+		in, out := &in.ResourceTuning, &out.ResourceTuning
+		*out = new(bool)
+		**out = **in
+	}
 	if in.SystemLoggerResources != nil {
 		in, out := &in.SystemLoggerResources, &out.SystemLoggerResources
 		*out = new(v1.ResourceRequirements)
@@ -71,6 +172,11 @@ func (in *CassandraClusterTemplate) DeepCopyInto(out *CassandraClusterTemplate)
 		*out = new(CassandraConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigFiles != nil {
+		in, out := &in.ConfigFiles, &out.ConfigFiles
+		*out = new(ConfigFiles)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageConfig != nil {
 		in, out := &in.StorageConfig, &out.StorageConfig
 		*out = new(v1beta1.StorageConfig)
@@ -81,6 +187,16 @@ func (in *CassandraClusterTemplate) DeepCopyInto(out *CassandraClusterTemplate)
 		*out = new(v1beta1.NetworkingConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(Auth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Racks != nil {
 		in, out := &in.Racks, &out.Racks
 		*out = make([]v1beta1.Rack, len(*in))
@@ -88,6 +204,20 @@ func (in *CassandraClusterTemplate) DeepCopyInto(out *CassandraClusterTemplate)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeAffinityLabels != nil {
+		in, out := &in.NodeAffinityLabels, &out.NodeAffinityLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Datacenters != nil {
 		in, out := &in.Datacenters, &out.Datacenters
 		*out = make([]CassandraDatacenterTemplate, len(*in))
@@ -100,6 +230,21 @@ func (in *CassandraClusterTemplate) DeepCopyInto(out *CassandraClusterTemplate)
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.ReplicatedKeyspaces != nil {
+		in, out := &in.ReplicatedKeyspaces, &out.ReplicatedKeyspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SystemReplication != nil {
+		in, out := &in.SystemReplication, &out.SystemReplication
+		*out = new(SystemReplicationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradePolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraClusterTemplate.
@@ -125,6 +270,11 @@ func (in *CassandraConfig) DeepCopyInto(out *CassandraConfig) {
 		*out = new(JvmOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TraceProbability != nil {
+		in, out := &in.TraceProbability, &out.TraceProbability
+		*out = new(float64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraConfig.
@@ -146,11 +296,21 @@ func (in *CassandraDatacenterTemplate) DeepCopyInto(out *CassandraDatacenterTemp
 		*out = new(CassandraConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigFiles != nil {
+		in, out := &in.ConfigFiles, &out.ConfigFiles
+		*out = new(ConfigFiles)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResourceTuning != nil {
+		in, out := &in.ResourceTuning, &out.ResourceTuning
+		*out = new(bool)
+		**out = **in
+	}
 	if in.SystemLoggerResources != nil {
 		in, out := &in.SystemLoggerResources, &out.SystemLoggerResources
 		*out = new(v1.ResourceRequirements)
@@ -163,6 +323,20 @@ func (in *CassandraDatacenterTemplate) DeepCopyInto(out *CassandraDatacenterTemp
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeAffinityLabels != nil {
+		in, out := &in.NodeAffinityLabels, &out.NodeAffinityLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Networking != nil {
 		in, out := &in.Networking, &out.Networking
 		*out = new(v1beta1.NetworkingConfig)
@@ -188,6 +362,16 @@ func (in *CassandraDatacenterTemplate) DeepCopyInto(out *CassandraDatacenterTemp
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.Stopped != nil {
+		in, out := &in.Stopped, &out.Stopped
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Unset != nil {
+		in, out := &in.Unset, &out.Unset
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraDatacenterTemplate.
@@ -201,246 +385,166 @@ func (in *CassandraDatacenterTemplate) DeepCopy() *CassandraDatacenterTemplate {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CassandraYaml) DeepCopyInto(out *CassandraYaml) {
+func (in *CassandraKeyspace) DeepCopyInto(out *CassandraKeyspace) {
 	*out = *in
-	if in.NumTokens != nil {
-		in, out := &in.NumTokens, &out.NumTokens
-		*out = new(int)
-		**out = **in
-	}
-	if in.AllocateTokensForLocalReplicationFactor != nil {
-		in, out := &in.AllocateTokensForLocalReplicationFactor, &out.AllocateTokensForLocalReplicationFactor
-		*out = new(int)
-		**out = **in
-	}
-	if in.ConcurrentReads != nil {
-		in, out := &in.ConcurrentReads, &out.ConcurrentReads
-		*out = new(int)
-		**out = **in
-	}
-	if in.ConcurrentWrites != nil {
-		in, out := &in.ConcurrentWrites, &out.ConcurrentWrites
-		*out = new(int)
-		**out = **in
-	}
-	if in.ConcurrentCounterWrites != nil {
-		in, out := &in.ConcurrentCounterWrites, &out.ConcurrentCounterWrites
-		*out = new(int)
-		**out = **in
-	}
-	if in.AutoSnapshot != nil {
-		in, out := &in.AutoSnapshot, &out.AutoSnapshot
-		*out = new(bool)
-		**out = **in
-	}
-	if in.MemtableFlushWriters != nil {
-		in, out := &in.MemtableFlushWriters, &out.MemtableFlushWriters
-		*out = new(int)
-		**out = **in
-	}
-	if in.CommitLogSegmentSizeMb != nil {
-		in, out := &in.CommitLogSegmentSizeMb, &out.CommitLogSegmentSizeMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.ConcurrentCompactors != nil {
-		in, out := &in.ConcurrentCompactors, &out.ConcurrentCompactors
-		*out = new(int)
-		**out = **in
-	}
-	if in.CompactionThroughputMbPerSec != nil {
-		in, out := &in.CompactionThroughputMbPerSec, &out.CompactionThroughputMbPerSec
-		*out = new(int)
-		**out = **in
-	}
-	if in.SstablePreemptiveOpenIntervalMb != nil {
-		in, out := &in.SstablePreemptiveOpenIntervalMb, &out.SstablePreemptiveOpenIntervalMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.KeyCacheSizeMb != nil {
-		in, out := &in.KeyCacheSizeMb, &out.KeyCacheSizeMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.ThriftPreparedStatementCacheSizeMb != nil {
-		in, out := &in.ThriftPreparedStatementCacheSizeMb, &out.ThriftPreparedStatementCacheSizeMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.PreparedStatementsCacheSizeMb != nil {
-		in, out := &in.PreparedStatementsCacheSizeMb, &out.PreparedStatementsCacheSizeMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.StartRpc != nil {
-		in, out := &in.StartRpc, &out.StartRpc
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SlowQueryLogTimeoutMs != nil {
-		in, out := &in.SlowQueryLogTimeoutMs, &out.SlowQueryLogTimeoutMs
-		*out = new(int)
-		**out = **in
-	}
-	if in.CounterCacheSizeMb != nil {
-		in, out := &in.CounterCacheSizeMb, &out.CounterCacheSizeMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.FileCacheSizeMb != nil {
-		in, out := &in.FileCacheSizeMb, &out.FileCacheSizeMb
-		*out = new(int)
-		**out = **in
-	}
-	if in.RowCacheSizeMb != nil {
-		in, out := &in.RowCacheSizeMb, &out.RowCacheSizeMb
-		*out = new(int)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraYaml.
-func (in *CassandraYaml) DeepCopy() *CassandraYaml {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraKeyspace.
+func (in *CassandraKeyspace) DeepCopy() *CassandraKeyspace {
 	if in == nil {
 		return nil
 	}
-	out := new(CassandraYaml)
+	out := new(CassandraKeyspace)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CassandraKeyspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmbeddedObjectMeta) DeepCopyInto(out *EmbeddedObjectMeta) {
+func (in *CassandraKeyspaceList) DeepCopyInto(out *CassandraKeyspaceList) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CassandraKeyspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedObjectMeta.
-func (in *EmbeddedObjectMeta) DeepCopy() *EmbeddedObjectMeta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraKeyspaceList.
+func (in *CassandraKeyspaceList) DeepCopy() *CassandraKeyspaceList {
 	if in == nil {
 		return nil
 	}
-	out := new(EmbeddedObjectMeta)
+	out := new(CassandraKeyspaceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CassandraKeyspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JvmOptions) DeepCopyInto(out *JvmOptions) {
+func (in *CassandraKeyspaceSpec) DeepCopyInto(out *CassandraKeyspaceSpec) {
 	*out = *in
-	if in.HeapSize != nil {
-		in, out := &in.HeapSize, &out.HeapSize
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.HeapNewGenSize != nil {
-		in, out := &in.HeapNewGenSize, &out.HeapNewGenSize
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.AdditionalOptions != nil {
-		in, out := &in.AdditionalOptions, &out.AdditionalOptions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.Cluster = in.Cluster
+	in.Replication.DeepCopyInto(&out.Replication)
+	if in.DurableWrites != nil {
+		in, out := &in.DurableWrites, &out.DurableWrites
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JvmOptions.
-func (in *JvmOptions) DeepCopy() *JvmOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraKeyspaceSpec.
+func (in *CassandraKeyspaceSpec) DeepCopy() *CassandraKeyspaceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(JvmOptions)
+	out := new(CassandraKeyspaceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K8ssandraCluster) DeepCopyInto(out *K8ssandraCluster) {
+func (in *CassandraKeyspaceStatus) DeepCopyInto(out *CassandraKeyspaceStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DurableWrites != nil {
+		in, out := &in.DurableWrites, &out.DurableWrites
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraCluster.
-func (in *K8ssandraCluster) DeepCopy() *K8ssandraCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraKeyspaceStatus.
+func (in *CassandraKeyspaceStatus) DeepCopy() *CassandraKeyspaceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(K8ssandraCluster)
+	out := new(CassandraKeyspaceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *K8ssandraCluster) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K8ssandraClusterCondition) DeepCopyInto(out *K8ssandraClusterCondition) {
+func (in *CassandraRole) DeepCopyInto(out *CassandraRole) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterCondition.
-func (in *K8ssandraClusterCondition) DeepCopy() *K8ssandraClusterCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraRole.
+func (in *CassandraRole) DeepCopy() *CassandraRole {
 	if in == nil {
 		return nil
 	}
-	out := new(K8ssandraClusterCondition)
+	out := new(CassandraRole)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CassandraRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K8ssandraClusterList) DeepCopyInto(out *K8ssandraClusterList) {
+func (in *CassandraRoleList) DeepCopyInto(out *CassandraRoleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]K8ssandraCluster, len(*in))
+		*out = make([]CassandraRole, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterList.
-func (in *K8ssandraClusterList) DeepCopy() *K8ssandraClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraRoleList.
+func (in *CassandraRoleList) DeepCopy() *CassandraRoleList {
 	if in == nil {
 		return nil
 	}
-	out := new(K8ssandraClusterList)
+	out := new(CassandraRoleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *K8ssandraClusterList) DeepCopyObject() runtime.Object {
+func (in *CassandraRoleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -448,90 +552,2180 @@ func (in *K8ssandraClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K8ssandraClusterSpec) DeepCopyInto(out *K8ssandraClusterSpec) {
+func (in *CassandraRoleSpec) DeepCopyInto(out *CassandraRoleSpec) {
 	*out = *in
-	if in.Cassandra != nil {
-		in, out := &in.Cassandra, &out.Cassandra
-		*out = new(CassandraClusterTemplate)
-		(*in).DeepCopyInto(*out)
+	out.Cluster = in.Cluster
+	if in.Login != nil {
+		in, out := &in.Login, &out.Login
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Stargate != nil {
-		in, out := &in.Stargate, &out.Stargate
-		*out = new(stargatev1alpha1.StargateClusterTemplate)
-		(*in).DeepCopyInto(*out)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Reaper != nil {
-		in, out := &in.Reaper, &out.Reaper
-		*out = new(reaperv1alpha1.ReaperClusterTemplate)
-		(*in).DeepCopyInto(*out)
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]RolePermissions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterSpec.
-func (in *K8ssandraClusterSpec) DeepCopy() *K8ssandraClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraRoleSpec.
+func (in *CassandraRoleSpec) DeepCopy() *CassandraRoleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(K8ssandraClusterSpec)
+	out := new(CassandraRoleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K8ssandraClusterStatus) DeepCopyInto(out *K8ssandraClusterStatus) {
+func (in *CassandraRoleStatus) DeepCopyInto(out *CassandraRoleStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]K8ssandraClusterCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.GrantedRoles != nil {
+		in, out := &in.GrantedRoles, &out.GrantedRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Datacenters != nil {
-		in, out := &in.Datacenters, &out.Datacenters
-		*out = make(map[string]K8ssandraStatus, len(*in))
+	if in.GrantedPermissions != nil {
+		in, out := &in.GrantedPermissions, &out.GrantedPermissions
+		*out = make(map[string][]string, len(*in))
 		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterStatus.
-func (in *K8ssandraClusterStatus) DeepCopy() *K8ssandraClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraRoleStatus.
+func (in *CassandraRoleStatus) DeepCopy() *CassandraRoleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(K8ssandraClusterStatus)
+	out := new(CassandraRoleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *K8ssandraStatus) DeepCopyInto(out *K8ssandraStatus) {
+func (in *CassandraYaml) DeepCopyInto(out *CassandraYaml) {
 	*out = *in
-	if in.Cassandra != nil {
-		in, out := &in.Cassandra, &out.Cassandra
-		*out = new(v1beta1.CassandraDatacenterStatus)
-		(*in).DeepCopyInto(*out)
+	if in.NumTokens != nil {
+		in, out := &in.NumTokens, &out.NumTokens
+		*out = new(int)
+		**out = **in
 	}
-	if in.Stargate != nil {
-		in, out := &in.Stargate, &out.Stargate
-		*out = new(stargatev1alpha1.StargateStatus)
+	if in.AllocateTokensForKeyspace != nil {
+		in, out := &in.AllocateTokensForKeyspace, &out.AllocateTokensForKeyspace
+		*out = new(string)
+		**out = **in
+	}
+	if in.AllocateTokensForLocalReplicationFactor != nil {
+		in, out := &in.AllocateTokensForLocalReplicationFactor, &out.AllocateTokensForLocalReplicationFactor
+		*out = new(int)
+		**out = **in
+	}
+	if in.AutoBootstrap != nil {
+		in, out := &in.AutoBootstrap, &out.AutoBootstrap
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HintedHandoffEnabled != nil {
+		in, out := &in.HintedHandoffEnabled, &out.HintedHandoffEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HintedHandoffDisabledDatacenters != nil {
+		in, out := &in.HintedHandoffDisabledDatacenters, &out.HintedHandoffDisabledDatacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxHintWindowMs != nil {
+		in, out := &in.MaxHintWindowMs, &out.MaxHintWindowMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.HintedHandoffThrottleKb != nil {
+		in, out := &in.HintedHandoffThrottleKb, &out.HintedHandoffThrottleKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxHintsDeliveryThreads != nil {
+		in, out := &in.MaxHintsDeliveryThreads, &out.MaxHintsDeliveryThreads
+		*out = new(int)
+		**out = **in
+	}
+	if in.HintsFlushPeriodMs != nil {
+		in, out := &in.HintsFlushPeriodMs, &out.HintsFlushPeriodMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxHintsFileSizeMb != nil {
+		in, out := &in.MaxHintsFileSizeMb, &out.MaxHintsFileSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.HintsCompression != nil {
+		in, out := &in.HintsCompression, &out.HintsCompression
+		*out = new(ParameterizedClass)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Reaper != nil {
-		in, out := &in.Reaper, &out.Reaper
-		*out = new(reaperv1alpha1.ReaperStatus)
+	if in.BatchlogReplayThrottleKb != nil {
+		in, out := &in.BatchlogReplayThrottleKb, &out.BatchlogReplayThrottleKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.DiskFailurePolicy != nil {
+		in, out := &in.DiskFailurePolicy, &out.DiskFailurePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.CommitFailurePolicy != nil {
+		in, out := &in.CommitFailurePolicy, &out.CommitFailurePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.PreparedStatementsCacheSizeMb != nil {
+		in, out := &in.PreparedStatementsCacheSizeMb, &out.PreparedStatementsCacheSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.ThriftPreparedStatementCacheSizeMb != nil {
+		in, out := &in.ThriftPreparedStatementCacheSizeMb, &out.ThriftPreparedStatementCacheSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeyCacheSizeMb != nil {
+		in, out := &in.KeyCacheSizeMb, &out.KeyCacheSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeyCacheSavePeriod != nil {
+		in, out := &in.KeyCacheSavePeriod, &out.KeyCacheSavePeriod
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeyCacheKeysToSave != nil {
+		in, out := &in.KeyCacheKeysToSave, &out.KeyCacheKeysToSave
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeyCacheMigrateDuringCompaction != nil {
+		in, out := &in.KeyCacheMigrateDuringCompaction, &out.KeyCacheMigrateDuringCompaction
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RowCacheClassName != nil {
+		in, out := &in.RowCacheClassName, &out.RowCacheClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.RowCacheSizeMb != nil {
+		in, out := &in.RowCacheSizeMb, &out.RowCacheSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.RowCacheSavePeriod != nil {
+		in, out := &in.RowCacheSavePeriod, &out.RowCacheSavePeriod
+		*out = new(int)
+		**out = **in
+	}
+	if in.RowCacheKeysToSave != nil {
+		in, out := &in.RowCacheKeysToSave, &out.RowCacheKeysToSave
+		*out = new(int)
+		**out = **in
+	}
+	if in.CounterCacheSizeMb != nil {
+		in, out := &in.CounterCacheSizeMb, &out.CounterCacheSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.CounterCacheSavePeriod != nil {
+		in, out := &in.CounterCacheSavePeriod, &out.CounterCacheSavePeriod
+		*out = new(int)
+		**out = **in
+	}
+	if in.CounterCacheKeysToSave != nil {
+		in, out := &in.CounterCacheKeysToSave, &out.CounterCacheKeysToSave
+		*out = new(int)
+		**out = **in
+	}
+	if in.CacheLoadTimeoutSeconds != nil {
+		in, out := &in.CacheLoadTimeoutSeconds, &out.CacheLoadTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.FileCacheSizeMb != nil {
+		in, out := &in.FileCacheSizeMb, &out.FileCacheSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.FileCacheRoundUp != nil {
+		in, out := &in.FileCacheRoundUp, &out.FileCacheRoundUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BufferPoolUseHeapIfExhausted != nil {
+		in, out := &in.BufferPoolUseHeapIfExhausted, &out.BufferPoolUseHeapIfExhausted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DiskOptimizationStrategy != nil {
+		in, out := &in.DiskOptimizationStrategy, &out.DiskOptimizationStrategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.DiskAccessMode != nil {
+		in, out := &in.DiskAccessMode, &out.DiskAccessMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.CommitLogSync != nil {
+		in, out := &in.CommitLogSync, &out.CommitLogSync
+		*out = new(string)
+		**out = **in
+	}
+	if in.CommitLogSyncBatchWindowMs != nil {
+		in, out := &in.CommitLogSyncBatchWindowMs, &out.CommitLogSyncBatchWindowMs
+		*out = new(float64)
+		**out = **in
+	}
+	if in.CommitLogSyncGroupWindowMs != nil {
+		in, out := &in.CommitLogSyncGroupWindowMs, &out.CommitLogSyncGroupWindowMs
+		*out = new(float64)
+		**out = **in
+	}
+	if in.CommitLogSyncPeriodMs != nil {
+		in, out := &in.CommitLogSyncPeriodMs, &out.CommitLogSyncPeriodMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.PeriodicCommitLogSyncLagBlockMs != nil {
+		in, out := &in.PeriodicCommitLogSyncLagBlockMs, &out.PeriodicCommitLogSyncLagBlockMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.CommitLogSegmentSizeMb != nil {
+		in, out := &in.CommitLogSegmentSizeMb, &out.CommitLogSegmentSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.CommitLogTotalSpaceMb != nil {
+		in, out := &in.CommitLogTotalSpaceMb, &out.CommitLogTotalSpaceMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.CommitLogCompression != nil {
+		in, out := &in.CommitLogCompression, &out.CommitLogCompression
+		*out = new(ParameterizedClass)
 		(*in).DeepCopyInto(*out)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraStatus.
-func (in *K8ssandraStatus) DeepCopy() *K8ssandraStatus {
-	if in == nil {
-		return nil
+	if in.CommitLogMaxCompressionBuffersInPool != nil {
+		in, out := &in.CommitLogMaxCompressionBuffersInPool, &out.CommitLogMaxCompressionBuffersInPool
+		*out = new(int)
+		**out = **in
 	}
-	out := new(K8ssandraStatus)
+	if in.FlushCompression != nil {
+		in, out := &in.FlushCompression, &out.FlushCompression
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConcurrentReads != nil {
+		in, out := &in.ConcurrentReads, &out.ConcurrentReads
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentWrites != nil {
+		in, out := &in.ConcurrentWrites, &out.ConcurrentWrites
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentCounterWrites != nil {
+		in, out := &in.ConcurrentCounterWrites, &out.ConcurrentCounterWrites
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentMaterializedViewWrites != nil {
+		in, out := &in.ConcurrentMaterializedViewWrites, &out.ConcurrentMaterializedViewWrites
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentMaterializedViewBuilders != nil {
+		in, out := &in.ConcurrentMaterializedViewBuilders, &out.ConcurrentMaterializedViewBuilders
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentValidations != nil {
+		in, out := &in.ConcurrentValidations, &out.ConcurrentValidations
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentCompactors != nil {
+		in, out := &in.ConcurrentCompactors, &out.ConcurrentCompactors
+		*out = new(int)
+		**out = **in
+	}
+	if in.MemtableHeapSpaceMb != nil {
+		in, out := &in.MemtableHeapSpaceMb, &out.MemtableHeapSpaceMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.MemtableOffheapSpaceMb != nil {
+		in, out := &in.MemtableOffheapSpaceMb, &out.MemtableOffheapSpaceMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.MemtableCleanupThreshold != nil {
+		in, out := &in.MemtableCleanupThreshold, &out.MemtableCleanupThreshold
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MemtableAllocationType != nil {
+		in, out := &in.MemtableAllocationType, &out.MemtableAllocationType
+		*out = new(string)
+		**out = **in
+	}
+	if in.MemtableFlushWriters != nil {
+		in, out := &in.MemtableFlushWriters, &out.MemtableFlushWriters
+		*out = new(int)
+		**out = **in
+	}
+	if in.CdcEnabled != nil {
+		in, out := &in.CdcEnabled, &out.CdcEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CdcTotalSpaceMb != nil {
+		in, out := &in.CdcTotalSpaceMb, &out.CdcTotalSpaceMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.CdcFreeSpaceCheckIntervalMs != nil {
+		in, out := &in.CdcFreeSpaceCheckIntervalMs, &out.CdcFreeSpaceCheckIntervalMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.IndexSummaryCapacityMb != nil {
+		in, out := &in.IndexSummaryCapacityMb, &out.IndexSummaryCapacityMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.IndexSummaryResizeIntervalMinutes != nil {
+		in, out := &in.IndexSummaryResizeIntervalMinutes, &out.IndexSummaryResizeIntervalMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.TrickleFsync != nil {
+		in, out := &in.TrickleFsync, &out.TrickleFsync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TrickleFsyncIntervalKb != nil {
+		in, out := &in.TrickleFsyncIntervalKb, &out.TrickleFsyncIntervalKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.ColumnIndexSizeKb != nil {
+		in, out := &in.ColumnIndexSizeKb, &out.ColumnIndexSizeKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.ColumnIndexCacheSizeKb != nil {
+		in, out := &in.ColumnIndexCacheSizeKb, &out.ColumnIndexCacheSizeKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.SstablePreemptiveOpenIntervalMb != nil {
+		in, out := &in.SstablePreemptiveOpenIntervalMb, &out.SstablePreemptiveOpenIntervalMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.AutomaticSstableUpgrade != nil {
+		in, out := &in.AutomaticSstableUpgrade, &out.AutomaticSstableUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxConcurrentAutomaticSstableUpgrades != nil {
+		in, out := &in.MaxConcurrentAutomaticSstableUpgrades, &out.MaxConcurrentAutomaticSstableUpgrades
+		*out = new(int)
+		**out = **in
+	}
+	if in.StartNativeTransport != nil {
+		in, out := &in.StartNativeTransport, &out.StartNativeTransport
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NativeTransportMaxThreads != nil {
+		in, out := &in.NativeTransportMaxThreads, &out.NativeTransportMaxThreads
+		*out = new(int)
+		**out = **in
+	}
+	if in.NativeTransportMaxFrameSizeMb != nil {
+		in, out := &in.NativeTransportMaxFrameSizeMb, &out.NativeTransportMaxFrameSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.NativeTransportMaxConcurrentConnections != nil {
+		in, out := &in.NativeTransportMaxConcurrentConnections, &out.NativeTransportMaxConcurrentConnections
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NativeTransportMaxConcurrentConnectionsPerIp != nil {
+		in, out := &in.NativeTransportMaxConcurrentConnectionsPerIp, &out.NativeTransportMaxConcurrentConnectionsPerIp
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NativeTransportMaxConcurrentRequestsInBytes != nil {
+		in, out := &in.NativeTransportMaxConcurrentRequestsInBytes, &out.NativeTransportMaxConcurrentRequestsInBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NativeTransportMaxConcurrentRequestsInBytesPerIp != nil {
+		in, out := &in.NativeTransportMaxConcurrentRequestsInBytesPerIp, &out.NativeTransportMaxConcurrentRequestsInBytesPerIp
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NativeTransportFlushInBatchesLegacy != nil {
+		in, out := &in.NativeTransportFlushInBatchesLegacy, &out.NativeTransportFlushInBatchesLegacy
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NativeTransportAllowOlderProtocols != nil {
+		in, out := &in.NativeTransportAllowOlderProtocols, &out.NativeTransportAllowOlderProtocols
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NativeTransportIdleTimeoutMs != nil {
+		in, out := &in.NativeTransportIdleTimeoutMs, &out.NativeTransportIdleTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NativeTransportReceiveQueueCapacityInBytes != nil {
+		in, out := &in.NativeTransportReceiveQueueCapacityInBytes, &out.NativeTransportReceiveQueueCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.StartRpc != nil {
+		in, out := &in.StartRpc, &out.StartRpc
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RpcKeepalive != nil {
+		in, out := &in.RpcKeepalive, &out.RpcKeepalive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RpcServerType != nil {
+		in, out := &in.RpcServerType, &out.RpcServerType
+		*out = new(string)
+		**out = **in
+	}
+	if in.RpcMinThreads != nil {
+		in, out := &in.RpcMinThreads, &out.RpcMinThreads
+		*out = new(int)
+		**out = **in
+	}
+	if in.RpcMaxThreads != nil {
+		in, out := &in.RpcMaxThreads, &out.RpcMaxThreads
+		*out = new(int)
+		**out = **in
+	}
+	if in.RpcSendBuffSizeInBytes != nil {
+		in, out := &in.RpcSendBuffSizeInBytes, &out.RpcSendBuffSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.RpcRecvBuffSizeInBytes != nil {
+		in, out := &in.RpcRecvBuffSizeInBytes, &out.RpcRecvBuffSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.ThriftFramedTransportSizeMb != nil {
+		in, out := &in.ThriftFramedTransportSizeMb, &out.ThriftFramedTransportSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.RequestScheduler != nil {
+		in, out := &in.RequestScheduler, &out.RequestScheduler
+		*out = new(string)
+		**out = **in
+	}
+	if in.RequestSchedulerId != nil {
+		in, out := &in.RequestSchedulerId, &out.RequestSchedulerId
+		*out = new(string)
+		**out = **in
+	}
+	if in.InternodeAuthenticator != nil {
+		in, out := &in.InternodeAuthenticator, &out.InternodeAuthenticator
+		*out = new(string)
+		**out = **in
+	}
+	if in.InternodeSendBuffSizeInBytes != nil {
+		in, out := &in.InternodeSendBuffSizeInBytes, &out.InternodeSendBuffSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeRecvBuffSizeInBytes != nil {
+		in, out := &in.InternodeRecvBuffSizeInBytes, &out.InternodeRecvBuffSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeSocketSendBufferSizeInBytes != nil {
+		in, out := &in.InternodeSocketSendBufferSizeInBytes, &out.InternodeSocketSendBufferSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeSocketReceiveBufferSizeInBytes != nil {
+		in, out := &in.InternodeSocketReceiveBufferSizeInBytes, &out.InternodeSocketReceiveBufferSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeApplicationSendQueueCapacityInBytes != nil {
+		in, out := &in.InternodeApplicationSendQueueCapacityInBytes, &out.InternodeApplicationSendQueueCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeApplicationSendQueueReserveEndpointCapacityInBytes != nil {
+		in, out := &in.InternodeApplicationSendQueueReserveEndpointCapacityInBytes, &out.InternodeApplicationSendQueueReserveEndpointCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeApplicationSendQueueReserveGlobalCapacityInBytes != nil {
+		in, out := &in.InternodeApplicationSendQueueReserveGlobalCapacityInBytes, &out.InternodeApplicationSendQueueReserveGlobalCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeApplicationReceiveQueueCapacityInBytes != nil {
+		in, out := &in.InternodeApplicationReceiveQueueCapacityInBytes, &out.InternodeApplicationReceiveQueueCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeApplicationReceiveQueueReserveEndpointCapacityInBytes != nil {
+		in, out := &in.InternodeApplicationReceiveQueueReserveEndpointCapacityInBytes, &out.InternodeApplicationReceiveQueueReserveEndpointCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeApplicationReceiveQueueReserveGlobalCapacityInBytes != nil {
+		in, out := &in.InternodeApplicationReceiveQueueReserveGlobalCapacityInBytes, &out.InternodeApplicationReceiveQueueReserveGlobalCapacityInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeMaxMessageSizeInBytes != nil {
+		in, out := &in.InternodeMaxMessageSizeInBytes, &out.InternodeMaxMessageSizeInBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeTcpConnectTimeoutMs != nil {
+		in, out := &in.InternodeTcpConnectTimeoutMs, &out.InternodeTcpConnectTimeoutMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeTcpUserTimeoutMs != nil {
+		in, out := &in.InternodeTcpUserTimeoutMs, &out.InternodeTcpUserTimeoutMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeStreamingTcpUserTimeoutMs != nil {
+		in, out := &in.InternodeStreamingTcpUserTimeoutMs, &out.InternodeStreamingTcpUserTimeoutMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.InternodeCompression != nil {
+		in, out := &in.InternodeCompression, &out.InternodeCompression
+		*out = new(string)
+		**out = **in
+	}
+	if in.InterDcTcpNodelay != nil {
+		in, out := &in.InterDcTcpNodelay, &out.InterDcTcpNodelay
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OtcCoalescingStrategy != nil {
+		in, out := &in.OtcCoalescingStrategy, &out.OtcCoalescingStrategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.OtcCoalescingWindowUs != nil {
+		in, out := &in.OtcCoalescingWindowUs, &out.OtcCoalescingWindowUs
+		*out = new(int)
+		**out = **in
+	}
+	if in.OtcCoalescingEnoughCoalescedMessages != nil {
+		in, out := &in.OtcCoalescingEnoughCoalescedMessages, &out.OtcCoalescingEnoughCoalescedMessages
+		*out = new(int)
+		**out = **in
+	}
+	if in.OtcBacklogExpirationIntervalMs != nil {
+		in, out := &in.OtcBacklogExpirationIntervalMs, &out.OtcBacklogExpirationIntervalMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxMutationSizeKb != nil {
+		in, out := &in.MaxMutationSizeKb, &out.MaxMutationSizeKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxValueSizeMb != nil {
+		in, out := &in.MaxValueSizeMb, &out.MaxValueSizeMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConsecutiveMessageErrorsThreshold != nil {
+		in, out := &in.ConsecutiveMessageErrorsThreshold, &out.ConsecutiveMessageErrorsThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.BackPressureEnabled != nil {
+		in, out := &in.BackPressureEnabled, &out.BackPressureEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BackPressureStrategy != nil {
+		in, out := &in.BackPressureStrategy, &out.BackPressureStrategy
+		*out = new(ParameterizedClass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerEncryptionOptions != nil {
+		in, out := &in.ServerEncryptionOptions, &out.ServerEncryptionOptions
+		*out = new(ServerEncryptionOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientEncryptionOptions != nil {
+		in, out := &in.ClientEncryptionOptions, &out.ClientEncryptionOptions
+		*out = new(ClientEncryptionOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IncrementalBackups != nil {
+		in, out := &in.IncrementalBackups, &out.IncrementalBackups
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SnapshotBeforeCompaction != nil {
+		in, out := &in.SnapshotBeforeCompaction, &out.SnapshotBeforeCompaction
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoSnapshot != nil {
+		in, out := &in.AutoSnapshot, &out.AutoSnapshot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BatchSizeWarnThresholdKb != nil {
+		in, out := &in.BatchSizeWarnThresholdKb, &out.BatchSizeWarnThresholdKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.BatchSizeFailThresholdKb != nil {
+		in, out := &in.BatchSizeFailThresholdKb, &out.BatchSizeFailThresholdKb
+		*out = new(int)
+		**out = **in
+	}
+	if in.UnloggedBatchAcrossPartitionsWarnThreshold != nil {
+		in, out := &in.UnloggedBatchAcrossPartitionsWarnThreshold, &out.UnloggedBatchAcrossPartitionsWarnThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.CompactionThroughputMbPerSec != nil {
+		in, out := &in.CompactionThroughputMbPerSec, &out.CompactionThroughputMbPerSec
+		*out = new(int)
+		**out = **in
+	}
+	if in.CompactionLargePartitionWarningThresholdMb != nil {
+		in, out := &in.CompactionLargePartitionWarningThresholdMb, &out.CompactionLargePartitionWarningThresholdMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.CompactionTombstoneWarningThreshold != nil {
+		in, out := &in.CompactionTombstoneWarningThreshold, &out.CompactionTombstoneWarningThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.StreamEntireSstables != nil {
+		in, out := &in.StreamEntireSstables, &out.StreamEntireSstables
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StreamThroughputOutboundMegabitsPerSec != nil {
+		in, out := &in.StreamThroughputOutboundMegabitsPerSec, &out.StreamThroughputOutboundMegabitsPerSec
+		*out = new(int)
+		**out = **in
+	}
+	if in.InterDcStreamThroughputOutboundMegabitsPerSec != nil {
+		in, out := &in.InterDcStreamThroughputOutboundMegabitsPerSec, &out.InterDcStreamThroughputOutboundMegabitsPerSec
+		*out = new(int)
+		**out = **in
+	}
+	if in.StreamingKeepAlivePeriodInSecs != nil {
+		in, out := &in.StreamingKeepAlivePeriodInSecs, &out.StreamingKeepAlivePeriodInSecs
+		*out = new(int)
+		**out = **in
+	}
+	if in.StreamingConnectionsPerHost != nil {
+		in, out := &in.StreamingConnectionsPerHost, &out.StreamingConnectionsPerHost
+		*out = new(int)
+		**out = **in
+	}
+	if in.StreamingSocketTimeoutMs != nil {
+		in, out := &in.StreamingSocketTimeoutMs, &out.StreamingSocketTimeoutMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.ReadRequestTimeoutMs != nil {
+		in, out := &in.ReadRequestTimeoutMs, &out.ReadRequestTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RangeRequestTimeoutMs != nil {
+		in, out := &in.RangeRequestTimeoutMs, &out.RangeRequestTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WriteRequestTimeoutMs != nil {
+		in, out := &in.WriteRequestTimeoutMs, &out.WriteRequestTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CounterWriteRequestTimeoutMs != nil {
+		in, out := &in.CounterWriteRequestTimeoutMs, &out.CounterWriteRequestTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CasContentionTimeoutMs != nil {
+		in, out := &in.CasContentionTimeoutMs, &out.CasContentionTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TruncateRequestTimeoutMs != nil {
+		in, out := &in.TruncateRequestTimeoutMs, &out.TruncateRequestTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RequestTimeoutMs != nil {
+		in, out := &in.RequestTimeoutMs, &out.RequestTimeoutMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SlowQueryLogTimeoutMs != nil {
+		in, out := &in.SlowQueryLogTimeoutMs, &out.SlowQueryLogTimeoutMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.CrossNodeTimeout != nil {
+		in, out := &in.CrossNodeTimeout, &out.CrossNodeTimeout
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BlockForPeersTimeoutInSecs != nil {
+		in, out := &in.BlockForPeersTimeoutInSecs, &out.BlockForPeersTimeoutInSecs
+		*out = new(int)
+		**out = **in
+	}
+	if in.BlockForPeersInRemoteDcs != nil {
+		in, out := &in.BlockForPeersInRemoteDcs, &out.BlockForPeersInRemoteDcs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PhiConvictThreshold != nil {
+		in, out := &in.PhiConvictThreshold, &out.PhiConvictThreshold
+		*out = new(float64)
+		**out = **in
+	}
+	if in.DynamicSnitchUpdateIntervalMs != nil {
+		in, out := &in.DynamicSnitchUpdateIntervalMs, &out.DynamicSnitchUpdateIntervalMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.DynamicSnitchResetIntervalMs != nil {
+		in, out := &in.DynamicSnitchResetIntervalMs, &out.DynamicSnitchResetIntervalMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.DynamicSnitchBadnessThreshold != nil {
+		in, out := &in.DynamicSnitchBadnessThreshold, &out.DynamicSnitchBadnessThreshold
+		*out = new(float64)
+		**out = **in
+	}
+	if in.TombstoneWarnThreshold != nil {
+		in, out := &in.TombstoneWarnThreshold, &out.TombstoneWarnThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.TombstoneFailureThreshold != nil {
+		in, out := &in.TombstoneFailureThreshold, &out.TombstoneFailureThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.ReplicaFilteringProtection != nil {
+		in, out := &in.ReplicaFilteringProtection, &out.ReplicaFilteringProtection
+		*out = new(ReplicaFilteringProtectionOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CorruptedTombstoneStrategy != nil {
+		in, out := &in.CorruptedTombstoneStrategy, &out.CorruptedTombstoneStrategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.InitialRangeTombstoneListAllocationSize != nil {
+		in, out := &in.InitialRangeTombstoneListAllocationSize, &out.InitialRangeTombstoneListAllocationSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.RangeTombstoneListGrowthFactor != nil {
+		in, out := &in.RangeTombstoneListGrowthFactor, &out.RangeTombstoneListGrowthFactor
+		*out = new(float64)
+		**out = **in
+	}
+	if in.RepairSessionMaxTreeDepth != nil {
+		in, out := &in.RepairSessionMaxTreeDepth, &out.RepairSessionMaxTreeDepth
+		*out = new(int)
+		**out = **in
+	}
+	if in.RepairSessionSpaceMb != nil {
+		in, out := &in.RepairSessionSpaceMb, &out.RepairSessionSpaceMb
+		*out = new(int)
+		**out = **in
+	}
+	if in.RepairCommandPoolSize != nil {
+		in, out := &in.RepairCommandPoolSize, &out.RepairCommandPoolSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.RepairCommandPoolFullStrategy != nil {
+		in, out := &in.RepairCommandPoolFullStrategy, &out.RepairCommandPoolFullStrategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.UseOffheapMerkleTrees != nil {
+		in, out := &in.UseOffheapMerkleTrees, &out.UseOffheapMerkleTrees
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RepairedDataTrackingForRangeReadsEnabled != nil {
+		in, out := &in.RepairedDataTrackingForRangeReadsEnabled, &out.RepairedDataTrackingForRangeReadsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RepairedDataTrackingForPartitionReadsEnabled != nil {
+		in, out := &in.RepairedDataTrackingForPartitionReadsEnabled, &out.RepairedDataTrackingForPartitionReadsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReportUnconfirmedRepairedDataMismatches != nil {
+		in, out := &in.ReportUnconfirmedRepairedDataMismatches, &out.ReportUnconfirmedRepairedDataMismatches
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SnapshotOnRepairedDataMismatch != nil {
+		in, out := &in.SnapshotOnRepairedDataMismatch, &out.SnapshotOnRepairedDataMismatch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ValidationPreviewPurgeHeadStartInSec != nil {
+		in, out := &in.ValidationPreviewPurgeHeadStartInSec, &out.ValidationPreviewPurgeHeadStartInSec
+		*out = new(int)
+		**out = **in
+	}
+	if in.AutoOptimiseIncRepairStreams != nil {
+		in, out := &in.AutoOptimiseIncRepairStreams, &out.AutoOptimiseIncRepairStreams
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoOptimiseFullRepairStreams != nil {
+		in, out := &in.AutoOptimiseFullRepairStreams, &out.AutoOptimiseFullRepairStreams
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoOptimisePreviewRepairStreams != nil {
+		in, out := &in.AutoOptimisePreviewRepairStreams, &out.AutoOptimisePreviewRepairStreams
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TracetypeQueryTtl != nil {
+		in, out := &in.TracetypeQueryTtl, &out.TracetypeQueryTtl
+		*out = new(int)
+		**out = **in
+	}
+	if in.TracetypeRepairTtl != nil {
+		in, out := &in.TracetypeRepairTtl, &out.TracetypeRepairTtl
+		*out = new(int)
+		**out = **in
+	}
+	if in.GcLogThresholdMs != nil {
+		in, out := &in.GcLogThresholdMs, &out.GcLogThresholdMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.GcWarnThresholdMs != nil {
+		in, out := &in.GcWarnThresholdMs, &out.GcWarnThresholdMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.AuditLoggingOptions != nil {
+		in, out := &in.AuditLoggingOptions, &out.AuditLoggingOptions
+		*out = new(AuditLogOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FullQueryLoggingOptions != nil {
+		in, out := &in.FullQueryLoggingOptions, &out.FullQueryLoggingOptions
+		*out = new(FullQueryLoggerOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiagnosticEventsEnabled != nil {
+		in, out := &in.DiagnosticEventsEnabled, &out.DiagnosticEventsEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IdealConsistencyLevel != nil {
+		in, out := &in.IdealConsistencyLevel, &out.IdealConsistencyLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnableUserDefinedFunctions != nil {
+		in, out := &in.EnableUserDefinedFunctions, &out.EnableUserDefinedFunctions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableScriptedUserDefinedFunctions != nil {
+		in, out := &in.EnableScriptedUserDefinedFunctions, &out.EnableScriptedUserDefinedFunctions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableUserDefinedFunctionsThreads != nil {
+		in, out := &in.EnableUserDefinedFunctionsThreads, &out.EnableUserDefinedFunctionsThreads
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UserDefinedFunctionWarnTimeout != nil {
+		in, out := &in.UserDefinedFunctionWarnTimeout, &out.UserDefinedFunctionWarnTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UserDefinedFunctionFailTimeout != nil {
+		in, out := &in.UserDefinedFunctionFailTimeout, &out.UserDefinedFunctionFailTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UserFunctionTimeoutPolicy != nil {
+		in, out := &in.UserFunctionTimeoutPolicy, &out.UserFunctionTimeoutPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.EnableMaterializedViews != nil {
+		in, out := &in.EnableMaterializedViews, &out.EnableMaterializedViews
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableSasiIndexes != nil {
+		in, out := &in.EnableSasiIndexes, &out.EnableSasiIndexes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableTransientReplication != nil {
+		in, out := &in.EnableTransientReplication, &out.EnableTransientReplication
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableDropCompactStorage != nil {
+		in, out := &in.EnableDropCompactStorage, &out.EnableDropCompactStorage
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CheckForDuplicateRowsDuringReads != nil {
+		in, out := &in.CheckForDuplicateRowsDuringReads, &out.CheckForDuplicateRowsDuringReads
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CheckForDuplicateRowsDuringCompaction != nil {
+		in, out := &in.CheckForDuplicateRowsDuringCompaction, &out.CheckForDuplicateRowsDuringCompaction
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TableCountWarnThreshold != nil {
+		in, out := &in.TableCountWarnThreshold, &out.TableCountWarnThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.KeyspaceCountWarnThreshold != nil {
+		in, out := &in.KeyspaceCountWarnThreshold, &out.KeyspaceCountWarnThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.WindowsTimerInterval != nil {
+		in, out := &in.WindowsTimerInterval, &out.WindowsTimerInterval
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraYaml.
+func (in *CassandraYaml) DeepCopy() *CassandraYaml {
+	if in == nil {
+		return nil
+	}
+	out := new(CassandraYaml)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientEncryption) DeepCopyInto(out *ClientEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientEncryption.
+func (in *ClientEncryption) DeepCopy() *ClientEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientEncryptionOptions) DeepCopyInto(out *ClientEncryptionOptions) {
+	*out = *in
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	in.EncryptionOptions.DeepCopyInto(&out.EncryptionOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientEncryptionOptions.
+func (in *ClientEncryptionOptions) DeepCopy() *ClientEncryptionOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientEncryptionOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CmsOptions) DeepCopyInto(out *CmsOptions) {
+	*out = *in
+	if in.SurvivorRatio != nil {
+		in, out := &in.SurvivorRatio, &out.SurvivorRatio
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxTenuringThreshold != nil {
+		in, out := &in.MaxTenuringThreshold, &out.MaxTenuringThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.InitiatingOccupancyFraction != nil {
+		in, out := &in.InitiatingOccupancyFraction, &out.InitiatingOccupancyFraction
+		*out = new(int)
+		**out = **in
+	}
+	if in.WaitDurationMs != nil {
+		in, out := &in.WaitDurationMs, &out.WaitDurationMs
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CmsOptions.
+func (in *CmsOptions) DeepCopy() *CmsOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CmsOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigFileSource) DeepCopyInto(out *ConfigFileSource) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigFileSource.
+func (in *ConfigFileSource) DeepCopy() *ConfigFileSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigFileSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigFiles) DeepCopyInto(out *ConfigFiles) {
+	*out = *in
+	if in.Logback != nil {
+		in, out := &in.Logback, &out.Logback
+		*out = new(ConfigFileSource)
+		**out = **in
+	}
+	if in.CassandraEnv != nil {
+		in, out := &in.CassandraEnv, &out.CassandraEnv
+		*out = new(ConfigFileSource)
+		**out = **in
+	}
+	if in.RackDc != nil {
+		in, out := &in.RackDc, &out.RackDc
+		*out = new(ConfigFileSource)
+		**out = **in
+	}
+	if in.CommitLogArchiving != nil {
+		in, out := &in.CommitLogArchiving, &out.CommitLogArchiving
+		*out = new(ConfigFileSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigFiles.
+func (in *ConfigFiles) DeepCopy() *ConfigFiles {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigFiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
+	*out = *in
+	if in.AppliedLive != nil {
+		in, out := &in.AppliedLive, &out.AppliedLive
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastLiveUpdate != nil {
+		in, out := &in.LastLiveUpdate, &out.LastLiveUpdate
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingRestart != nil {
+		in, out := &in.PendingRestart, &out.PendingRestart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStatus.
+func (in *ConfigStatus) DeepCopy() *ConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatacenterSummary) DeepCopyInto(out *DatacenterSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatacenterSummary.
+func (in *DatacenterSummary) DeepCopy() *DatacenterSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(DatacenterSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedObjectMeta) DeepCopyInto(out *EmbeddedObjectMeta) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedObjectMeta.
+func (in *EmbeddedObjectMeta) DeepCopy() *EmbeddedObjectMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedObjectMeta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionOptions) DeepCopyInto(out *EncryptionOptions) {
+	*out = *in
+	if in.Keystore != nil {
+		in, out := &in.Keystore, &out.Keystore
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeystorePassword != nil {
+		in, out := &in.KeystorePassword, &out.KeystorePassword
+		*out = new(string)
+		**out = **in
+	}
+	if in.Truststore != nil {
+		in, out := &in.Truststore, &out.Truststore
+		*out = new(string)
+		**out = **in
+	}
+	if in.TruststorePassword != nil {
+		in, out := &in.TruststorePassword, &out.TruststorePassword
+		*out = new(string)
+		**out = **in
+	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.StoreType != nil {
+		in, out := &in.StoreType, &out.StoreType
+		*out = new(string)
+		**out = **in
+	}
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequireClientAuth != nil {
+		in, out := &in.RequireClientAuth, &out.RequireClientAuth
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionOptions.
+func (in *EncryptionOptions) DeepCopy() *EncryptionOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FullQueryLoggerOptions) DeepCopyInto(out *FullQueryLoggerOptions) {
+	*out = *in
+	if in.LogDir != nil {
+		in, out := &in.LogDir, &out.LogDir
+		*out = new(string)
+		**out = **in
+	}
+	if in.ArchiveCommand != nil {
+		in, out := &in.ArchiveCommand, &out.ArchiveCommand
+		*out = new(string)
+		**out = **in
+	}
+	if in.RollCycle != nil {
+		in, out := &in.RollCycle, &out.RollCycle
+		*out = new(string)
+		**out = **in
+	}
+	if in.Block != nil {
+		in, out := &in.Block, &out.Block
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxQueueWeight != nil {
+		in, out := &in.MaxQueueWeight, &out.MaxQueueWeight
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxLogSize != nil {
+		in, out := &in.MaxLogSize, &out.MaxLogSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxArchiveRetries != nil {
+		in, out := &in.MaxArchiveRetries, &out.MaxArchiveRetries
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FullQueryLoggerOptions.
+func (in *FullQueryLoggerOptions) DeepCopy() *FullQueryLoggerOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(FullQueryLoggerOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *G1Options) DeepCopyInto(out *G1Options) {
+	*out = *in
+	if in.RSetUpdatingPauseTimePercent != nil {
+		in, out := &in.RSetUpdatingPauseTimePercent, &out.RSetUpdatingPauseTimePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxGcPauseMs != nil {
+		in, out := &in.MaxGcPauseMs, &out.MaxGcPauseMs
+		*out = new(int)
+		**out = **in
+	}
+	if in.InitiatingHeapOccupancyPercent != nil {
+		in, out := &in.InitiatingHeapOccupancyPercent, &out.InitiatingHeapOccupancyPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.ParallelGcThreads != nil {
+		in, out := &in.ParallelGcThreads, &out.ParallelGcThreads
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentGcThreads != nil {
+		in, out := &in.ConcurrentGcThreads, &out.ConcurrentGcThreads
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new G1Options.
+func (in *G1Options) DeepCopy() *G1Options {
+	if in == nil {
+		return nil
+	}
+	out := new(G1Options)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GcLoggingOptions) DeepCopyInto(out *GcLoggingOptions) {
+	*out = *in
+	if in.FileCount != nil {
+		in, out := &in.FileCount, &out.FileCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.FileSize != nil {
+		in, out := &in.FileSize, &out.FileSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcLoggingOptions.
+func (in *GcLoggingOptions) DeepCopy() *GcLoggingOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(GcLoggingOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeapDumpOptions) DeepCopyInto(out *HeapDumpOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeapDumpOptions.
+func (in *HeapDumpOptions) DeepCopy() *HeapDumpOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(HeapDumpOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JmxOptions) DeepCopyInto(out *JmxOptions) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JmxOptions.
+func (in *JmxOptions) DeepCopy() *JmxOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(JmxOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JvmOptions) DeepCopyInto(out *JvmOptions) {
+	*out = *in
+	if in.HeapSize != nil {
+		in, out := &in.HeapSize, &out.HeapSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HeapNewGenSize != nil {
+		in, out := &in.HeapNewGenSize, &out.HeapNewGenSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.AdditionalOptions != nil {
+		in, out := &in.AdditionalOptions, &out.AdditionalOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JavaVersion != nil {
+		in, out := &in.JavaVersion, &out.JavaVersion
+		*out = new(int)
+		**out = **in
+	}
+	if in.GarbageCollector != nil {
+		in, out := &in.GarbageCollector, &out.GarbageCollector
+		*out = new(string)
+		**out = **in
+	}
+	if in.Cms != nil {
+		in, out := &in.Cms, &out.Cms
+		*out = new(CmsOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.G1 != nil {
+		in, out := &in.G1, &out.G1
+		*out = new(G1Options)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GcLogging != nil {
+		in, out := &in.GcLogging, &out.GcLogging
+		*out = new(GcLoggingOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeapDump != nil {
+		in, out := &in.HeapDump, &out.HeapDump
+		*out = new(HeapDumpOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Jmx != nil {
+		in, out := &in.Jmx, &out.Jmx
+		*out = new(JmxOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JvmOptions.
+func (in *JvmOptions) DeepCopy() *JvmOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(JvmOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K8ssandraCluster) DeepCopyInto(out *K8ssandraCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraCluster.
+func (in *K8ssandraCluster) DeepCopy() *K8ssandraCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(K8ssandraCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *K8ssandraCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K8ssandraClusterCondition) DeepCopyInto(out *K8ssandraClusterCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterCondition.
+func (in *K8ssandraClusterCondition) DeepCopy() *K8ssandraClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(K8ssandraClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K8ssandraClusterList) DeepCopyInto(out *K8ssandraClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]K8ssandraCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterList.
+func (in *K8ssandraClusterList) DeepCopy() *K8ssandraClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(K8ssandraClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *K8ssandraClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K8ssandraClusterSpec) DeepCopyInto(out *K8ssandraClusterSpec) {
+	*out = *in
+	if in.Cassandra != nil {
+		in, out := &in.Cassandra, &out.Cassandra
+		*out = new(CassandraClusterTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stargate != nil {
+		in, out := &in.Stargate, &out.Stargate
+		*out = new(stargatev1alpha1.StargateClusterTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reaper != nil {
+		in, out := &in.Reaper, &out.Reaper
+		*out = new(reaperv1alpha1.ReaperClusterTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollingRestart != nil {
+		in, out := &in.RollingRestart, &out.RollingRestart
+		*out = new(RollingRestartRequest)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterSpec.
+func (in *K8ssandraClusterSpec) DeepCopy() *K8ssandraClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(K8ssandraClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K8ssandraClusterStatus) DeepCopyInto(out *K8ssandraClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]K8ssandraClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make(map[string]K8ssandraStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RollingRestart != nil {
+		in, out := &in.RollingRestart, &out.RollingRestart
+		*out = new(RollingRestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SystemRepair != nil {
+		in, out := &in.SystemRepair, &out.SystemRepair
+		*out = new(RepairStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAPGrantedRoles != nil {
+		in, out := &in.LDAPGrantedRoles, &out.LDAPGrantedRoles
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraClusterStatus.
+func (in *K8ssandraClusterStatus) DeepCopy() *K8ssandraClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(K8ssandraClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *K8ssandraStatus) DeepCopyInto(out *K8ssandraStatus) {
+	*out = *in
+	if in.Cassandra != nil {
+		in, out := &in.Cassandra, &out.Cassandra
+		*out = new(v1beta1.CassandraDatacenterStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stargate != nil {
+		in, out := &in.Stargate, &out.Stargate
+		*out = new(stargatev1alpha1.StargateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reaper != nil {
+		in, out := &in.Reaper, &out.Reaper
+		*out = new(reaperv1alpha1.ReaperStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rebuild != nil {
+		in, out := &in.Rebuild, &out.Rebuild
+		*out = new(RebuildStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(DatacenterSummary)
+		**out = **in
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(TuningStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new K8ssandraStatus.
+func (in *K8ssandraStatus) DeepCopy() *K8ssandraStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(K8ssandraStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyspaceReplication) DeepCopyInto(out *KeyspaceReplication) {
+	*out = *in
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxReplicationFactor != nil {
+		in, out := &in.MaxReplicationFactor, &out.MaxReplicationFactor
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyspaceReplication.
+func (in *KeyspaceReplication) DeepCopy() *KeyspaceReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyspaceReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+	*out = *in
+	out.BindSecretRef = in.BindSecretRef
+	if in.GroupMappings != nil {
+		in, out := &in.GroupMappings, &out.GroupMappings
+		*out = make([]LDAPGroupMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GroupSyncPeriod != nil {
+		in, out := &in.GroupSyncPeriod, &out.GroupSyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
+func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPGroupMapping) DeepCopyInto(out *LDAPGroupMapping) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPGroupMapping.
+func (in *LDAPGroupMapping) DeepCopy() *LDAPGroupMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPGroupMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterizedClass) DeepCopyInto(out *ParameterizedClass) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterizedClass.
+func (in *ParameterizedClass) DeepCopy() *ParameterizedClass {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterizedClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebuildStatus) DeepCopyInto(out *RebuildStatus) {
+	*out = *in
+	if in.RebuiltPods != nil {
+		in, out := &in.RebuiltPods, &out.RebuiltPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebuildStatus.
+func (in *RebuildStatus) DeepCopy() *RebuildStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RebuildStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairStatus) DeepCopyInto(out *RepairStatus) {
+	*out = *in
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RepairedPods != nil {
+		in, out := &in.RepairedPods, &out.RepairedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairStatus.
+func (in *RepairStatus) DeepCopy() *RepairStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaFilteringProtectionOptions) DeepCopyInto(out *ReplicaFilteringProtectionOptions) {
+	*out = *in
+	if in.CachedRowsWarnThreshold != nil {
+		in, out := &in.CachedRowsWarnThreshold, &out.CachedRowsWarnThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.CachedRowsFailThreshold != nil {
+		in, out := &in.CachedRowsFailThreshold, &out.CachedRowsFailThreshold
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaFilteringProtectionOptions.
+func (in *ReplicaFilteringProtectionOptions) DeepCopy() *ReplicaFilteringProtectionOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaFilteringProtectionOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolePermissions) DeepCopyInto(out *RolePermissions) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]Permission, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolePermissions.
+func (in *RolePermissions) DeepCopy() *RolePermissions {
+	if in == nil {
+		return nil
+	}
+	out := new(RolePermissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingRestartRequest) DeepCopyInto(out *RollingRestartRequest) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingRestartRequest.
+func (in *RollingRestartRequest) DeepCopy() *RollingRestartRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingRestartRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingRestartStatus) DeepCopyInto(out *RollingRestartStatus) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DatacenterStartTime != nil {
+		in, out := &in.DatacenterStartTime, &out.DatacenterStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RestartedDatacenters != nil {
+		in, out := &in.RestartedDatacenters, &out.RestartedDatacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingRestartStatus.
+func (in *RollingRestartStatus) DeepCopy() *RollingRestartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingRestartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerEncryptionOptions) DeepCopyInto(out *ServerEncryptionOptions) {
+	*out = *in
+	if in.InternodeEncryption != nil {
+		in, out := &in.InternodeEncryption, &out.InternodeEncryption
+		*out = new(string)
+		**out = **in
+	}
+	if in.RequireEndpointVerification != nil {
+		in, out := &in.RequireEndpointVerification, &out.RequireEndpointVerification
+		*out = new(bool)
+		**out = **in
+	}
+	in.EncryptionOptions.DeepCopyInto(&out.EncryptionOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerEncryptionOptions.
+func (in *ServerEncryptionOptions) DeepCopy() *ServerEncryptionOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerEncryptionOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemReplicationPolicy) DeepCopyInto(out *SystemReplicationPolicy) {
+	*out = *in
+	if in.ReplicationFactor != nil {
+		in, out := &in.ReplicationFactor, &out.ReplicationFactor
+		*out = new(int)
+		**out = **in
+	}
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalKeyspaces != nil {
+		in, out := &in.AdditionalKeyspaces, &out.AdditionalKeyspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrowWithDatacenterSize != nil {
+		in, out := &in.GrowWithDatacenterSize, &out.GrowWithDatacenterSize
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemReplicationPolicy.
+func (in *SystemReplicationPolicy) DeepCopy() *SystemReplicationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemReplicationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	if in.ClientEncryption != nil {
+		in, out := &in.ClientEncryption, &out.ClientEncryption
+		*out = new(ClientEncryption)
+		**out = **in
+	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(IssuerReference)
+		**out = **in
+	}
+	if in.CertificateDuration != nil {
+		in, out := &in.CertificateDuration, &out.CertificateDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningStatus) DeepCopyInto(out *TuningStatus) {
+	*out = *in
+	if in.HeapSize != nil {
+		in, out := &in.HeapSize, &out.HeapSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HeapNewGenSize != nil {
+		in, out := &in.HeapNewGenSize, &out.HeapNewGenSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ConcurrentReads != nil {
+		in, out := &in.ConcurrentReads, &out.ConcurrentReads
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentWrites != nil {
+		in, out := &in.ConcurrentWrites, &out.ConcurrentWrites
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentCompactors != nil {
+		in, out := &in.ConcurrentCompactors, &out.ConcurrentCompactors
+		*out = new(int)
+		**out = **in
+	}
+	if in.MemtableFlushWriters != nil {
+		in, out := &in.MemtableFlushWriters, &out.MemtableFlushWriters
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningStatus.
+func (in *TuningStatus) DeepCopy() *TuningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SnapshottedPods != nil {
+		in, out := &in.SnapshottedPods, &out.SnapshottedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DatacenterStartTime != nil {
+		in, out := &in.DatacenterStartTime, &out.DatacenterStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpgradedPods != nil {
+		in, out := &in.UpgradedPods, &out.UpgradedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradedDatacenters != nil {
+		in, out := &in.UpgradedDatacenters, &out.UpgradedDatacenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -208,6 +209,13 @@ type ReaperSpec struct {
 	// +kubebuilder:default="LOCAL"
 	// +kubebuilder:validation:Enum:=LOCAL;ALL;EACH
 	DatacenterAvailability string `json:"datacenterAvailability,omitempty"`
+
+	// ClientEncryptionStores references the keystore and the truststore that Reaper uses to
+	// connect to Cassandra when client encryption is enabled. They are mounted in
+	// /etc/encryption. The keystore is only used when the nodes require client
+	// authentication.
+	// +optional
+	ClientEncryptionStores *encryption.Stores `json:"clientEncryptionStores,omitempty"`
 }
 
 // ReaperProgress is a word summarizing the state of a Reaper resource.
@@ -230,6 +238,10 @@ type ReaperConditionType string
 
 const (
 	ReaperReady ReaperConditionType = "Ready"
+
+	// ReaperClusterRegistered is set to true once the Cassandra cluster is registered with
+	// Reaper, and to false when the registration fails. Its message is then the error.
+	ReaperClusterRegistered ReaperConditionType = "ClusterRegistered"
 )
 
 type ReaperCondition struct {
@@ -239,6 +251,10 @@ type ReaperCondition struct {
 	// LastTransitionTime is the last time the condition transited from one status to another.
 	// +optional
 	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Message is a human readable explanation of the current status.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ReaperStatus defines the observed state of Reaper
@@ -295,6 +311,40 @@ func (in *ReaperStatus) SetNotReady() {
 	})
 }
 
+// SetClusterRegistered sets the ClusterRegistered condition. err is the error that made
+// the registration fail, or nil if the cluster is registered. The LastTransitionTime is
+// only updated when the status of the condition changes.
+func (in *ReaperStatus) SetClusterRegistered(err error) {
+	condition := ReaperCondition{Type: ReaperClusterRegistered, Status: corev1.ConditionTrue}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Message = err.Error()
+	}
+	for _, c := range in.Conditions {
+		if c.Type == ReaperClusterRegistered && c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+	}
+	if condition.LastTransitionTime == nil {
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	in.SetCondition(condition)
+}
+
+// ClusterRegistrationError returns the error of the last registration of the Cassandra
+// cluster with Reaper, or an empty string if it did not fail.
+func (in *ReaperStatus) ClusterRegistrationError() string {
+	if in != nil {
+		for _, condition := range in.Conditions {
+			if condition.Type == ReaperClusterRegistered && condition.Status == corev1.ConditionFalse {
+				return condition.Message
+			}
+		}
+	}
+	return ""
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="DC",type=string,JSONPath=`.spec.datacenterRef.name`
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
 	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -228,6 +229,11 @@ func (in *ReaperSpec) DeepCopyInto(out *ReaperSpec) {
 	*out = *in
 	in.ReaperClusterTemplate.DeepCopyInto(&out.ReaperClusterTemplate)
 	out.DatacenterRef = in.DatacenterRef
+	if in.ClientEncryptionStores != nil {
+		in, out := &in.ClientEncryptionStores, &out.ClientEncryptionStores
+		*out = new(encryption.Stores)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperSpec.
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -172,6 +173,31 @@ type StargateSpec struct {
 	// Stargate should be deployed.
 	// +kubebuilder:validation:Required
 	DatacenterRef corev1.LocalObjectReference `json:"datacenterRef"`
+
+	// ServerVersion is the Cassandra version of the cluster, which is used to choose the
+	// default Stargate image. While a cluster is being upgraded its datacenters do not all
+	// run the same version; this should then be the oldest version. If empty, the version
+	// of the datacenter referenced by DatacenterRef is used.
+	// +optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+
+	// EncryptionStores references the keystore and the truststore that Stargate uses when
+	// internode encryption is enabled. The server_encryption_options of the cassandra.yaml
+	// referenced by CassandraConfigMapRef must point to them; they are mounted in
+	// /etc/encryption.
+	// +optional
+	EncryptionStores *encryption.Stores `json:"encryptionStores,omitempty"`
+
+	// Auth enables the authentication of the clients of Stargate.
+	// +kubebuilder:default=true
+	// +optional
+	Auth *bool `json:"auth,omitempty"`
+}
+
+// IsAuthEnabled returns true if the clients of Stargate must authenticate, which is the
+// default.
+func (in *StargateSpec) IsAuthEnabled() bool {
+	return in.Auth == nil || *in.Auth
 }
 
 // StargateProgress is a word summarizing the state of a Stargate resource.
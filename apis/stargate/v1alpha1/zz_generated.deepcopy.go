@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
 	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -164,6 +165,16 @@ func (in *StargateSpec) DeepCopyInto(out *StargateSpec) {
 	*out = *in
 	in.StargateDatacenterTemplate.DeepCopyInto(&out.StargateDatacenterTemplate)
 	out.DatacenterRef = in.DatacenterRef
+	if in.EncryptionStores != nil {
+		in, out := &in.EncryptionStores, &out.EncryptionStores
+		*out = new(encryption.Stores)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StargateSpec.
@@ -0,0 +1,122 @@
+package k8ssandra
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// reconcileAuth orchestrates enabling authentication on existing datacenters. Before the
+// nodes of a datacenter restart with the PasswordAuthenticator, the replication of
+// system_auth is raised so that every datacenter has replicas of the roles, and the
+// Reaper of the datacenter is given its CQL credentials. Disabling authentication needs
+// no preparation: Stargate and Reaper stop authenticating once the datacenters have been
+// updated.
+//
+// This is called before the CassandraDatacenters are reconciled. The datacenters that are
+// not ready to enable authentication keep their current authenticator, authorizer and role
+// manager in dcs, while their other config changes are rolled out, so the returned result
+// should only be acted upon once the datacenters have been reconciled.
+func (r *K8ssandraClusterReconciler) reconcileAuth(ctx context.Context, kc *api.K8ssandraCluster, dcs []*dcReconciliation, logger logr.Logger) result.ReconcileResult {
+	actualDcs := make(map[string]*cassdcapi.CassandraDatacenter)
+	pending := make([]*dcReconciliation, 0)
+	for _, dc := range dcs {
+		actualDc := &cassdcapi.CassandraDatacenter{}
+		if err := dc.remoteClient.Get(ctx, utils.GetKey(dc.desiredDc), actualDc); err != nil {
+			if errors.IsNotFound(err) {
+				// New datacenters are created with the desired config.
+				continue
+			}
+			dc.logger.Error(err, "Failed to get datacenter")
+			return result.Error(err)
+		}
+		if enabling, err := authEnabledBy(actualDc, dc.desiredDc); err != nil {
+			dc.logger.Error(err, "Failed to parse the config of the datacenter")
+			return result.Error(err)
+		} else if enabling {
+			actualDcs[dc.desiredDc.Name] = actualDc
+			pending = append(pending, dc)
+		}
+	}
+
+	if len(pending) == 0 {
+		return result.Continue()
+	}
+
+	recResult := r.prepareAuth(ctx, kc, pending, actualDcs, logger)
+	if recResult.Completed() {
+		for _, dc := range pending {
+			dc.logger.Info("Holding back authentication until the cluster is prepared")
+			actualConfig := actualDcs[dc.desiredDc.Name].Spec.Config
+			config, err := cassandra.HoldBackAuth(actualConfig, dc.desiredDc.Spec.Config)
+			if err != nil {
+				// The datacenter is reconciled regardless of the result, keep its whole config
+				dc.logger.Error(err, "Failed to hold back authentication, holding back the whole config")
+				config = actualConfig
+			}
+			dc.desiredDc.Spec.Config = config
+		}
+	}
+	return recResult
+}
+
+// prepareAuth raises the replication of system_auth through the management API of a
+// ready datacenter, then updates the Reapers of the pending datacenters.
+func (r *K8ssandraClusterReconciler) prepareAuth(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	pending []*dcReconciliation,
+	actualDcs map[string]*cassdcapi.CassandraDatacenter,
+	logger logr.Logger,
+) result.ReconcileResult {
+	readyDc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	if readyDc == nil {
+		logger.Info("Waiting for a datacenter to be ready to update the replication of system_auth")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, readyDc, remoteClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		return result.Error(err)
+	}
+	if err := r.updateSystemKeyspaceReplication(kc, "system_auth", managementApi, logger); err != nil {
+		r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.AuthenticationBlocked,
+			"Failed to update the replication of system_auth before enabling authentication: %v", err)
+		return result.Error(err)
+	}
+
+	for _, dc := range pending {
+		if dc.desiredDc.Spec.Stopped {
+			continue
+		}
+		if recResult := r.reconcileReaper(ctx, kc, dc.dcTemplate, actualDcs[dc.desiredDc.Name], dc.logger, dc.remoteClient); recResult.Completed() {
+			return recResult
+		}
+	}
+	return result.Continue()
+}
+
+// authEnabledBy returns true if desiredDc enables authentication on actualDc.
+func authEnabledBy(actualDc, desiredDc *cassdcapi.CassandraDatacenter) (bool, error) {
+	actual, err := cassandra.IsAuthEnabled(actualDc.Spec.Config)
+	if err != nil {
+		return false, err
+	}
+	desired, err := cassandra.IsAuthEnabled(desiredDc.Spec.Config)
+	if err != nil {
+		return false, err
+	}
+	return desired && !actual, nil
+}
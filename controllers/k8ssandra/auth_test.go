@@ -0,0 +1,167 @@
+package k8ssandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAuthEnabledBy(t *testing.T) {
+	const (
+		defaults = `{"cassandra-yaml": {"num_tokens": 16}}`
+		enabled  = `{"cassandra-yaml": {"authenticator": "PasswordAuthenticator", "authorizer": "CassandraAuthorizer"}}`
+		disabled = `{"cassandra-yaml": {"authenticator": "AllowAllAuthenticator", "authorizer": "AllowAllAuthorizer"}}`
+	)
+	tests := []struct {
+		name     string
+		actual   string
+		desired  string
+		expected bool
+	}{
+		{"unchanged", defaults, defaults, false},
+		{"explicitly enabled", defaults, enabled, false},
+		{"disabling", enabled, disabled, false},
+		{"enabling", disabled, enabled, true},
+		{"enabling by default", disabled, defaults, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualDc := &cassdcapi.CassandraDatacenter{Spec: cassdcapi.CassandraDatacenterSpec{Config: []byte(tt.actual)}}
+			desiredDc := &cassdcapi.CassandraDatacenter{Spec: cassdcapi.CassandraDatacenterSpec{Config: []byte(tt.desired)}}
+			enabling, err := authEnabledBy(actualDc, desiredDc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, enabling)
+		})
+	}
+}
+
+func TestReconcileAuth(t *testing.T) {
+	const (
+		actualConfig  = `{"cassandra-yaml":{"authenticator":"AllowAllAuthenticator","authorizer":"AllowAllAuthorizer","concurrent_reads":32}}`
+		desiredConfig = `{"cassandra-yaml":{"authenticator":"PasswordAuthenticator","authorizer":"CassandraAuthorizer","concurrent_reads":64}}`
+		heldBack      = `{"cassandra-yaml":{"authenticator":"AllowAllAuthenticator","authorizer":"AllowAllAuthorizer","concurrent_reads":64}}`
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, reaperapi.AddToScheme(scheme))
+
+	tests := []struct {
+		name             string
+		ready            bool
+		alterErr         error
+		expectedConfig   string
+		expectedResult   string
+		expectedAlterRf  bool
+		expectedWarnings int
+	}{
+		{
+			name:            "cluster prepared",
+			ready:           true,
+			expectedConfig:  desiredConfig,
+			expectedResult:  "continue",
+			expectedAlterRf: true,
+		},
+		{
+			name:           "no ready datacenter",
+			expectedConfig: heldBack,
+			expectedResult: "requeue",
+		},
+		{
+			name:             "replication update failed",
+			ready:            true,
+			alterErr:         errors.New("failure"),
+			expectedConfig:   heldBack,
+			expectedResult:   "error",
+			expectedAlterRf:  true,
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &api.K8ssandraCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+				Spec: api.K8ssandraClusterSpec{
+					Cassandra: &api.CassandraClusterTemplate{
+						Cluster: "test",
+						Datacenters: []api.CassandraDatacenterTemplate{
+							{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+						},
+					},
+				},
+			}
+			actualDc := &cassdcapi.CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+				Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: "test", Size: 3, Config: []byte(actualConfig)},
+			}
+			if tt.ready {
+				actualDc.Status = *readyDatacenterStatus()
+			}
+			desiredDc := actualDc.DeepCopy()
+			desiredDc.Spec.Config = []byte(desiredConfig)
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(actualDc).Build()
+			managementApi := new(mocks.ManagementApiFacade)
+			managementApi.On("GetKeyspaceReplication", "system_auth").Return(map[string]string{
+				"class":              "org.apache.cassandra.locator.SimpleStrategy",
+				"replication_factor": "1",
+			}, nil)
+			managementApi.On("AlterKeyspace", "system_auth", mock.Anything).Return(tt.alterErr)
+			recorder := record.NewFakeRecorder(10)
+			r := &K8ssandraClusterReconciler{
+				ReconcilerConfig: config.InitConfig(),
+				ClientCache:      clientcache.New(c, c, scheme),
+				ManagementApi:    &mockManagementApiFactory{managementApi: managementApi},
+				Recorder:         recorder,
+			}
+
+			dc := &dcReconciliation{
+				dcTemplate:   kc.Spec.Cassandra.Datacenters[0],
+				desiredDc:    desiredDc,
+				remoteClient: c,
+				logger:       logr.Discard(),
+			}
+			recResult := r.reconcileAuth(context.Background(), kc, []*dcReconciliation{dc}, logr.Discard())
+
+			switch tt.expectedResult {
+			case "continue":
+				assert.False(t, recResult.Completed())
+			case "requeue":
+				require.True(t, recResult.Completed())
+				res, err := recResult.Output()
+				assert.NoError(t, err)
+				assert.True(t, res.Requeue)
+			case "error":
+				require.True(t, recResult.Completed())
+				_, err := recResult.Output()
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tt.expectedConfig, string(dc.desiredDc.Spec.Config))
+			if tt.expectedAlterRf {
+				managementApi.AssertCalled(t, "AlterKeyspace", "system_auth", map[string]int{"dc1": 3})
+			} else {
+				managementApi.AssertNotCalled(t, "AlterKeyspace", mock.Anything, mock.Anything)
+			}
+			assert.Len(t, recorder.Events, tt.expectedWarnings)
+		})
+	}
+}
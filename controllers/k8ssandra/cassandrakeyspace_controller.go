@@ -0,0 +1,340 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	cassandraKeyspaceFinalizer = "cassandrakeyspace.k8ssandra.io/finalizer"
+)
+
+// CassandraKeyspaceReconciler reconciles a CassandraKeyspace object
+type CassandraKeyspaceReconciler struct {
+	*config.ReconcilerConfig
+	client.Client
+	Scheme        *runtime.Scheme
+	ClientCache   *clientcache.ClientCache
+	ManagementApi cassandra.ManagementApiFactory
+	Cql           cassandra.CqlClientFactory
+	Recorder      record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=cassandrakeyspaces,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=cassandrakeyspaces/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=cassandrakeyspaces/finalizers,verbs=update
+// +kubebuilder:rbac:groups=cassandra.datastax.com,namespace="k8ssandra",resources=cassandradatacenters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=pods;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=events,verbs=create;patch
+
+func (r *CassandraKeyspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("CassandraKeyspace", req.NamespacedName)
+
+	ks := &api.CassandraKeyspace{}
+	if err := r.Get(ctx, req.NamespacedName, ks); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
+	}
+
+	ks = ks.DeepCopy()
+	patch := client.MergeFrom(ks.DeepCopy())
+	recResult := r.reconcile(ctx, ks, logger)
+	res, err := recResult.Output()
+	if ks.GetDeletionTimestamp() == nil {
+		ks.Status.ObservedGeneration = ks.Generation
+		if err != nil {
+			ks.Status.Error = err.Error()
+		} else {
+			ks.Status.Error = ""
+		}
+		if patchErr := r.Status().Patch(ctx, ks, patch); patchErr != nil {
+			logger.Error(patchErr, "Failed to update CassandraKeyspace status")
+		}
+	}
+	return res, err
+}
+
+func (r *CassandraKeyspaceReconciler) reconcile(ctx context.Context, ks *api.CassandraKeyspace, logger logr.Logger) result.ReconcileResult {
+	kc := &api.K8ssandraCluster{}
+	kcKey := types.NamespacedName{Namespace: ks.Namespace, Name: ks.Spec.Cluster.Name}
+	if err := r.Get(ctx, kcKey, kc); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to get K8ssandraCluster", "K8ssandraCluster", kcKey)
+			return result.Error(err)
+		}
+		kc = nil
+	}
+
+	if ks.GetDeletionTimestamp() != nil {
+		return r.checkDeletion(ctx, ks, kc, logger)
+	}
+
+	if !controllerutil.ContainsFinalizer(ks, cassandraKeyspaceFinalizer) {
+		patch := client.MergeFrom(ks.DeepCopy())
+		controllerutil.AddFinalizer(ks, cassandraKeyspaceFinalizer)
+		if err := r.Patch(ctx, ks, patch); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return result.Error(err)
+		}
+	}
+
+	if kc == nil || kc.Spec.Cassandra == nil {
+		logger.Info("Waiting for the K8ssandraCluster to be created", "K8ssandraCluster", kcKey)
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	replication := desiredKeyspaceReplication(kc, ks)
+	if len(replication) == 0 {
+		logger.Info("Waiting for a datacenter to be ready to create the keyspace")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	dc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	if dc == nil {
+		logger.Info("Waiting for a datacenter to be ready to create the keyspace")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	if recResult := r.reconcileReplication(ctx, ks, replication, dc, remoteClient, logger); recResult.Completed() {
+		return recResult
+	}
+
+	if recResult := r.reconcileDurableWrites(ctx, ks, kc, dc, remoteClient, logger); recResult.Completed() {
+		return recResult
+	}
+
+	return result.Done()
+}
+
+// reconcileReplication creates the keyspace of ks if it does not exist, or updates its
+// replication, and reports the actual replication in the status of ks.
+func (r *CassandraKeyspaceReconciler) reconcileReplication(
+	ctx context.Context,
+	ks *api.CassandraKeyspace,
+	replication map[string]int,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) result.ReconcileResult {
+	keyspace := ks.KeyspaceName()
+	managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, dc, remoteClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		return result.Error(err)
+	}
+
+	keyspaces, err := managementApi.ListKeyspaces(keyspace)
+	if err != nil {
+		logger.Error(err, "Failed to list keyspaces", "Keyspace", keyspace)
+		return result.Error(err)
+	}
+
+	if len(keyspaces) == 0 {
+		logger.Info("Creating keyspace", "Keyspace", keyspace, "Replication", replication)
+		if err := managementApi.CreateKeyspaceIfNotExists(keyspace, replication); err != nil {
+			r.Recorder.Eventf(ks, corev1.EventTypeWarning, events.KeyspaceReconcileFailed,
+				"Failed to create keyspace %s: %v", keyspace, err)
+			return result.Error(err)
+		}
+		r.Recorder.Eventf(ks, corev1.EventTypeNormal, events.KeyspaceCreated,
+			"Created keyspace %s with replication %v", keyspace, replication)
+	} else if actualReplication, err := managementApi.GetKeyspaceReplication(keyspace); err != nil {
+		logger.Error(err, "Failed to get keyspace replication", "Keyspace", keyspace)
+		return result.Error(err)
+	} else if !cassandra.CompareReplications(actualReplication, replication) {
+		logger.Info("Updating keyspace replication", "Keyspace", keyspace, "Replication", replication)
+		if err := managementApi.EnsureKeyspaceReplication(keyspace, replication); err != nil {
+			r.Recorder.Eventf(ks, corev1.EventTypeWarning, events.KeyspaceReconcileFailed,
+				"Failed to update the replication of keyspace %s: %v", keyspace, err)
+			return result.Error(err)
+		}
+		r.Recorder.Eventf(ks, corev1.EventTypeNormal, events.KeyspaceReplicationUpdated,
+			"Updated the replication of keyspace %s to %v", keyspace, replication)
+	}
+
+	actualReplication, err := managementApi.GetKeyspaceReplication(keyspace)
+	if err != nil {
+		logger.Error(err, "Failed to get keyspace replication", "Keyspace", keyspace)
+		return result.Error(err)
+	}
+	parsedReplication, ok := cassandra.ParseReplication(actualReplication)
+	if !ok {
+		return result.Error(fmt.Errorf("keyspace %s does not use NetworkTopologyStrategy", keyspace))
+	}
+	ks.Status.Replication = parsedReplication
+	return result.Continue()
+}
+
+// reconcileDurableWrites sets the durable_writes option of the keyspace of ks when it is
+// declared in its spec.
+func (r *CassandraKeyspaceReconciler) reconcileDurableWrites(
+	ctx context.Context,
+	ks *api.CassandraKeyspace,
+	kc *api.K8ssandraCluster,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) result.ReconcileResult {
+	if ks.Spec.DurableWrites == nil {
+		ks.Status.DurableWrites = nil
+		return result.Continue()
+	}
+
+	keyspace := ks.KeyspaceName()
+	cqlClient, err := newCqlClient(ctx, r.Client, r.Cql, kc, dc, remoteClient, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	defer cqlClient.Close()
+
+	durableWrites, err := cqlClient.GetKeyspaceDurableWrites(keyspace)
+	if err != nil {
+		logger.Error(err, "Failed to get the durable_writes of the keyspace", "Keyspace", keyspace)
+		return result.Error(err)
+	}
+	if durableWrites != *ks.Spec.DurableWrites {
+		if err := cqlClient.SetKeyspaceDurableWrites(keyspace, *ks.Spec.DurableWrites); err != nil {
+			logger.Error(err, "Failed to set the durable_writes of the keyspace", "Keyspace", keyspace)
+			r.Recorder.Eventf(ks, corev1.EventTypeWarning, events.KeyspaceReconcileFailed,
+				"Failed to set the durable_writes of keyspace %s: %v", keyspace, err)
+			return result.Error(err)
+		}
+		durableWrites = *ks.Spec.DurableWrites
+	}
+	ks.Status.DurableWrites = &durableWrites
+	return result.Continue()
+}
+
+// checkDeletion drops the keyspace of ks if its deletion policy is Delete, then removes the
+// finalizer of ks. The keyspace is left as is if the K8ssandraCluster is gone or being
+// deleted, since its datacenters are being deleted as well.
+func (r *CassandraKeyspaceReconciler) checkDeletion(ctx context.Context, ks *api.CassandraKeyspace, kc *api.K8ssandraCluster, logger logr.Logger) result.ReconcileResult {
+	if !controllerutil.ContainsFinalizer(ks, cassandraKeyspaceFinalizer) {
+		return result.Done()
+	}
+
+	if ks.GetDeletionPolicy() == api.DeletionPolicyDelete && kc != nil && kc.DeletionTimestamp == nil && kc.Spec.Cassandra != nil {
+		keyspace := ks.KeyspaceName()
+		dc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+		if err != nil {
+			return result.Error(err)
+		}
+		if dc == nil {
+			logger.Info("Waiting for a datacenter to be ready to drop the keyspace")
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+		cqlClient, err := newCqlClient(ctx, r.Client, r.Cql, kc, dc, remoteClient, logger)
+		if err != nil {
+			return result.Error(err)
+		}
+		defer cqlClient.Close()
+		if err := cqlClient.DropKeyspace(keyspace); err != nil {
+			logger.Error(err, "Failed to drop keyspace", "Keyspace", keyspace)
+			r.Recorder.Eventf(ks, corev1.EventTypeWarning, events.KeyspaceReconcileFailed,
+				"Failed to drop keyspace %s: %v", keyspace, err)
+			return result.Error(err)
+		}
+		r.Recorder.Eventf(ks, corev1.EventTypeNormal, events.KeyspaceDropped, "Dropped keyspace %s", keyspace)
+	}
+
+	patch := client.MergeFrom(ks.DeepCopy())
+	controllerutil.RemoveFinalizer(ks, cassandraKeyspaceFinalizer)
+	if err := r.Patch(ctx, ks, patch); err != nil {
+		logger.Error(err, "Failed to remove finalizer")
+		return result.Error(err)
+	}
+	return result.Done()
+}
+
+// desiredKeyspaceReplication returns the replication declared in the spec of ks or, when
+// the replication follows the topology, the replication computed from the datacenters of
+// kc that are ready. A datacenter that is being added to an existing cluster is only
+// included once the rebuild has added it to the replication of the replicated keyspaces,
+// so that the two do not conflict.
+func desiredKeyspaceReplication(kc *api.K8ssandraCluster, ks *api.CassandraKeyspace) map[string]int {
+	if !ks.Spec.Replication.FollowsTopology() {
+		return ks.Spec.Replication.Datacenters
+	}
+
+	dcTemplates := make([]api.CassandraDatacenterTemplate, 0, len(kc.Spec.Cassandra.Datacenters))
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		kdcStatus, found := kc.Status.Datacenters[dcTemplate.Meta.Name]
+		if !found || kdcStatus.Cassandra == nil ||
+			kdcStatus.Cassandra.GetConditionStatus(cassdcapi.DatacenterReady) != corev1.ConditionTrue {
+			continue
+		}
+		if rebuild := kdcStatus.Rebuild; rebuild != nil &&
+			(rebuild.Progress == api.RebuildPending || rebuild.Progress == api.RebuildUpdatingReplication) {
+			continue
+		}
+		dcTemplates = append(dcTemplates, dcTemplate)
+	}
+	return cassandra.ComputeReplication(ks.Spec.Replication.GetMaxReplicationFactor(), dcTemplates...)
+}
+
+// k8ssandraClusterToKeyspaces returns the CassandraKeyspaces that reference the
+// K8ssandraCluster kc. They are reconciled when the datacenters of kc change.
+func (r *CassandraKeyspaceReconciler) k8ssandraClusterToKeyspaces(kc client.Object) []reconcile.Request {
+	ksList := &api.CassandraKeyspaceList{}
+	if err := r.List(context.Background(), ksList, client.InNamespace(kc.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0)
+	for _, ks := range ksList.Items {
+		if ks.Spec.Cluster.Name == kc.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ks.Namespace, Name: ks.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CassandraKeyspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.CassandraKeyspace{}).
+		Watches(&source.Kind{Type: &api.K8ssandraCluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.k8ssandraClusterToKeyspaces)).
+		Complete(r)
+}
@@ -0,0 +1,245 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func readyDatacenterStatus() *cassdcapi.CassandraDatacenterStatus {
+	return &cassdcapi.CassandraDatacenterStatus{
+		CassandraOperatorProgress: cassdcapi.ProgressReady,
+		Conditions: []cassdcapi.DatacenterCondition{{
+			Type:   cassdcapi.DatacenterReady,
+			Status: corev1.ConditionTrue,
+		}},
+	}
+}
+
+func TestDesiredKeyspaceReplication(t *testing.T) {
+	kc := &api.K8ssandraCluster{
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+					{Meta: api.EmbeddedObjectMeta{Name: "dc2"}, Size: 1},
+					{Meta: api.EmbeddedObjectMeta{Name: "dc3"}, Size: 5},
+					{Meta: api.EmbeddedObjectMeta{Name: "dc4"}, Size: 3},
+					{Meta: api.EmbeddedObjectMeta{Name: "dc5"}, Size: 3},
+				},
+			},
+		},
+		Status: api.K8ssandraClusterStatus{
+			Datacenters: map[string]api.K8ssandraStatus{
+				"dc1": {Cassandra: readyDatacenterStatus()},
+				"dc2": {Cassandra: readyDatacenterStatus()},
+				"dc3": {Cassandra: readyDatacenterStatus(), Rebuild: &api.RebuildStatus{Progress: api.RebuildRunning}},
+				"dc4": {Cassandra: readyDatacenterStatus(), Rebuild: &api.RebuildStatus{Progress: api.RebuildPending}},
+				"dc5": {Cassandra: &cassdcapi.CassandraDatacenterStatus{CassandraOperatorProgress: cassdcapi.ProgressUpdating}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		replication api.KeyspaceReplication
+		expected    map[string]int
+	}{
+		{
+			name:        "follows topology",
+			replication: api.KeyspaceReplication{},
+			expected:    map[string]int{"dc1": 3, "dc2": 1, "dc3": 3},
+		},
+		{
+			name:        "follows topology with max replication factor",
+			replication: api.KeyspaceReplication{MaxReplicationFactor: pointer.Int(2)},
+			expected:    map[string]int{"dc1": 2, "dc2": 1, "dc3": 2},
+		},
+		{
+			name:        "explicit datacenters",
+			replication: api.KeyspaceReplication{Datacenters: map[string]int{"dc1": 1, "dc5": 2}},
+			expected:    map[string]int{"dc1": 1, "dc5": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := &api.CassandraKeyspace{Spec: api.CassandraKeyspaceSpec{Replication: tt.replication}}
+			assert.Equal(t, tt.expected, desiredKeyspaceReplication(kc, ks))
+		})
+	}
+}
+
+func TestReconcileCassandraKeyspace(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster: "test",
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+					{Meta: api.EmbeddedObjectMeta{Name: "dc2"}, Size: 1},
+				},
+			},
+		},
+		Status: api.K8ssandraClusterStatus{
+			Datacenters: map[string]api.K8ssandraStatus{
+				"dc1": {Cassandra: readyDatacenterStatus()},
+			},
+		},
+	}
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+		Status:     *readyDatacenterStatus(),
+	}
+	superuserSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-superuser"},
+		Data:       map[string][]byte{"username": []byte("test-superuser"), "password": []byte("superuser-password")},
+	}
+	ks := &api.CassandraKeyspace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-data"},
+		Spec: api.CassandraKeyspaceSpec{
+			Cluster:       corev1.LocalObjectReference{Name: "test"},
+			DurableWrites: pointer.Bool(false),
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc, dc, superuserSecret, ks).Build()
+
+	managementApi := new(mocks.ManagementApiFacade)
+	cqlClient := new(mocks.CqlClient)
+	recorder := record.NewFakeRecorder(10)
+	r := &CassandraKeyspaceReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Client:           c,
+		Scheme:           scheme,
+		ClientCache:      clientcache.New(c, c, scheme),
+		ManagementApi:    &mockManagementApiFactory{managementApi: managementApi},
+		Cql:              &mockCqlClientFactory{cqlClient: cqlClient},
+		Recorder:         recorder,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app-data"}}
+
+	// The keyspace is created in the ready datacenter
+	managementApi.On("ListKeyspaces", "app_data").Return([]string{}, nil).Once()
+	managementApi.On("CreateKeyspaceIfNotExists", "app_data", map[string]int{"dc1": 3}).Return(nil).Once()
+	managementApi.On("GetKeyspaceReplication", "app_data").Return(map[string]string{
+		"class": "org.apache.cassandra.locator.NetworkTopologyStrategy",
+		"dc1":   "3",
+	}, nil).Once()
+	cqlClient.On("GetKeyspaceDurableWrites", "app_data").Return(true, nil).Once()
+	cqlClient.On("SetKeyspaceDurableWrites", "app_data", false).Return(nil).Once()
+	cqlClient.On("Close").Return()
+
+	res, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	managementApi.AssertExpectations(t)
+	cqlClient.AssertExpectations(t)
+
+	actual := &api.CassandraKeyspace{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+	assert.True(t, controllerutil.ContainsFinalizer(actual, cassandraKeyspaceFinalizer))
+	assert.Equal(t, map[string]int{"dc1": 3}, actual.Status.Replication)
+	assert.Equal(t, pointer.Bool(false), actual.Status.DurableWrites)
+	assert.Empty(t, actual.Status.Error)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.KeyspaceCreated)
+
+	// The second datacenter becomes ready and is added to the replication
+	kc.Status.Datacenters["dc2"] = api.K8ssandraStatus{Cassandra: readyDatacenterStatus()}
+	require.NoError(t, c.Status().Update(ctx, kc))
+	managementApi.On("ListKeyspaces", "app_data").Return([]string{"app_data"}, nil).Once()
+	managementApi.On("GetKeyspaceReplication", "app_data").Return(map[string]string{
+		"class": "org.apache.cassandra.locator.NetworkTopologyStrategy",
+		"dc1":   "3",
+	}, nil).Once()
+	managementApi.On("EnsureKeyspaceReplication", "app_data", map[string]int{"dc1": 3, "dc2": 1}).Return(nil).Once()
+	managementApi.On("GetKeyspaceReplication", "app_data").Return(map[string]string{
+		"class": "org.apache.cassandra.locator.NetworkTopologyStrategy",
+		"dc1":   "3",
+		"dc2":   "1",
+	}, nil).Once()
+	cqlClient.On("GetKeyspaceDurableWrites", "app_data").Return(false, nil).Once()
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	managementApi.AssertExpectations(t)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+	assert.Equal(t, map[string]int{"dc1": 3, "dc2": 1}, actual.Status.Replication)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.KeyspaceReplicationUpdated)
+
+	// The keyspace is dropped when the CassandraKeyspace is deleted
+	actual.Spec.DeletionPolicy = api.DeletionPolicyDelete
+	now := metav1.Now()
+	actual.DeletionTimestamp = &now
+	require.NoError(t, c.Update(ctx, actual))
+	cqlClient.On("DropKeyspace", "app_data").Return(nil).Once()
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	cqlClient.AssertExpectations(t)
+	err = c.Get(ctx, req.NamespacedName, actual)
+	assert.True(t, errors.IsNotFound(err), "the finalizer should have been removed")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.KeyspaceDropped)
+}
+
+func TestReconcileCassandraKeyspaceRetained(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	now := metav1.Now()
+	ks := &api.CassandraKeyspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "app-data",
+			Finalizers:        []string{cassandraKeyspaceFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Spec: api.CassandraKeyspaceSpec{Cluster: corev1.LocalObjectReference{Name: "test"}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ks).Build()
+	cqlClient := new(mocks.CqlClient)
+	r := &CassandraKeyspaceReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Client:           c,
+		Scheme:           scheme,
+		Cql:              &mockCqlClientFactory{cqlClient: cqlClient},
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ks)})
+	require.NoError(t, err)
+	cqlClient.AssertNotCalled(t, "DropKeyspace", "app_data")
+
+	err = c.Get(ctx, client.ObjectKeyFromObject(ks), &api.CassandraKeyspace{})
+	assert.True(t, errors.IsNotFound(err), "the finalizer should have been removed")
+}
@@ -0,0 +1,508 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/secret"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	cassandraRoleFinalizer = "cassandrarole.k8ssandra.io/finalizer"
+)
+
+// keyspacePermissions are the permissions that ALL stands for on a keyspace, or on all the
+// keyspaces. CREATE does not apply to tables.
+var keyspacePermissions = []api.Permission{
+	api.PermissionCreate,
+	api.PermissionAlter,
+	api.PermissionDrop,
+	api.PermissionSelect,
+	api.PermissionModify,
+	api.PermissionAuthorize,
+}
+
+// CassandraRoleReconciler reconciles a CassandraRole object
+type CassandraRoleReconciler struct {
+	*config.ReconcilerConfig
+	client.Client
+	Scheme      *runtime.Scheme
+	ClientCache *clientcache.ClientCache
+	Cql         cassandra.CqlClientFactory
+	Recorder    record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=cassandraroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=cassandraroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=cassandraroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=cassandra.datastax.com,namespace="k8ssandra",resources=cassandradatacenters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=events,verbs=create;patch
+
+func (r *CassandraRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("CassandraRole", req.NamespacedName)
+
+	role := &api.CassandraRole{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
+	}
+
+	role = role.DeepCopy()
+	patch := client.MergeFrom(role.DeepCopy())
+	recResult := r.reconcile(ctx, role, logger)
+	res, err := recResult.Output()
+	if role.GetDeletionTimestamp() == nil {
+		role.Status.ObservedGeneration = role.Generation
+		if err != nil {
+			role.Status.Error = err.Error()
+		} else {
+			role.Status.Error = ""
+		}
+		if patchErr := r.Status().Patch(ctx, role, patch); patchErr != nil {
+			logger.Error(patchErr, "Failed to update CassandraRole status")
+		}
+	}
+	return res, err
+}
+
+func (r *CassandraRoleReconciler) reconcile(ctx context.Context, role *api.CassandraRole, logger logr.Logger) result.ReconcileResult {
+	kc := &api.K8ssandraCluster{}
+	kcKey := types.NamespacedName{Namespace: role.Namespace, Name: role.Spec.Cluster.Name}
+	if err := r.Get(ctx, kcKey, kc); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to get K8ssandraCluster", "K8ssandraCluster", kcKey)
+			return result.Error(err)
+		}
+		kc = nil
+	}
+
+	if role.GetDeletionTimestamp() != nil {
+		return r.checkDeletion(ctx, role, kc, logger)
+	}
+
+	if !controllerutil.ContainsFinalizer(role, cassandraRoleFinalizer) {
+		patch := client.MergeFrom(role.DeepCopy())
+		controllerutil.AddFinalizer(role, cassandraRoleFinalizer)
+		if err := r.Patch(ctx, role, patch); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return result.Error(err)
+		}
+	}
+
+	if kc == nil || kc.Spec.Cassandra == nil {
+		logger.Info("Waiting for the K8ssandraCluster to be created", "K8ssandraCluster", kcKey)
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	reservedRoles, err := operatorRoles(ctx, r.Client, kc)
+	if err != nil {
+		logger.Error(err, "Failed to get the roles of the operator")
+		return result.Error(err)
+	}
+	if utils.SliceContains(reservedRoles, role.RoleName()) {
+		err := fmt.Errorf("role %s is managed by the operator and cannot be managed through a CassandraRole", role.RoleName())
+		r.Recorder.Event(role, corev1.EventTypeWarning, events.RoleReconcileFailed, err.Error())
+		return result.Error(err)
+	}
+
+	// The Secret is labeled as managed by kc, which replicates it to the datacenters
+	var credentials *corev1.Secret
+	if role.CanLogin() {
+		var err error
+		credentials, err = secret.ReconcileUserSecret(ctx, r.Client, role.SecretName(), role.RoleName(), utils.GetKey(kc))
+		if err != nil {
+			logger.Error(err, "Failed to reconcile the role Secret", "Secret", role.SecretName())
+			return result.Error(err)
+		}
+	}
+
+	dc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	if dc == nil {
+		logger.Info("Waiting for a datacenter to be ready to create the role")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	cqlClient, err := newCqlClient(ctx, r.Client, r.Cql, kc, dc, remoteClient, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	defer cqlClient.Close()
+
+	if err := r.reconcileRole(cqlClient, role, credentials); err != nil {
+		logger.Error(err, "Failed to reconcile the role", "Role", role.RoleName())
+		r.Recorder.Eventf(role, corev1.EventTypeWarning, events.RoleReconcileFailed,
+			"Failed to reconcile role %s: %v", role.RoleName(), err)
+		return result.Error(err)
+	}
+	return result.Done()
+}
+
+// reconcileRole creates the role of the CassandraRole or updates its options, then grants
+// and revokes its roles and permissions to match the spec.
+func (r *CassandraRoleReconciler) reconcileRole(cqlClient cassandra.CqlClient, role *api.CassandraRole, credentials *corev1.Secret) error {
+	name := role.RoleName()
+	actual, err := cqlClient.GetRole(name)
+	if err != nil {
+		return err
+	}
+
+	options := cassandra.RoleOptions{Login: role.CanLogin(), Superuser: role.Spec.Superuser}
+	secretHash := ""
+	password := ""
+	if credentials != nil {
+		password = string(credentials.Data["password"])
+		if password == "" {
+			return fmt.Errorf("no password found in Secret %s", credentials.Name)
+		}
+		secretHash = utils.DeepHashString(credentials.Data)
+	}
+
+	changes := make([]string, 0)
+	var memberOf []string
+	if actual == nil {
+		options.Password = password
+		if err := cqlClient.CreateRole(name, options); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(role, corev1.EventTypeNormal, events.RoleCreated, "Created role %s", name)
+	} else {
+		memberOf = actual.MemberOf
+		passwordChanged := secretHash != role.Status.SecretHash
+		if actual.Login != options.Login || actual.Superuser != options.Superuser || passwordChanged {
+			if passwordChanged {
+				options.Password = password
+			}
+			if err := cqlClient.AlterRole(name, options); err != nil {
+				return err
+			}
+			changes = append(changes, "altered options")
+		}
+	}
+	role.Status.SecretHash = secretHash
+
+	roleChanges, grantedRoles, err := reconcileMemberOf(cqlClient, name, memberOf, role.Spec.Roles, role.Status.GrantedRoles)
+	role.Status.GrantedRoles = grantedRoles
+	if err != nil {
+		return err
+	}
+	changes = append(changes, roleChanges...)
+
+	permissionChanges, grantedPermissions, err := reconcilePermissions(cqlClient, name, role.Spec.Permissions, role.Status.GrantedPermissions)
+	role.Status.GrantedPermissions = grantedPermissions
+	if err != nil {
+		return err
+	}
+	changes = append(changes, permissionChanges...)
+
+	if actual != nil && len(changes) > 0 {
+		r.Recorder.Eventf(role, corev1.EventTypeNormal, events.RoleUpdated,
+			"Updated role %s: %s", name, strings.Join(changes, ", "))
+	}
+	return nil
+}
+
+// reconcileMemberOf grants the desired roles to role, and revokes the roles of granted,
+// i.e., the roles that were granted by a previous reconciliation, that are no longer
+// desired. The roles that were granted otherwise, e.g., by the LDAP group sync, are not
+// revoked. It returns a description of the changes, and the desired roles that were granted
+// by the operator.
+func reconcileMemberOf(cqlClient cassandra.CqlClient, role string, actual, desired, granted []string) ([]string, []string, error) {
+	changes := make([]string, 0)
+	stillGranted := make([]string, 0, len(desired))
+	for _, grantedRole := range granted {
+		if utils.SliceContains(desired, grantedRole) && utils.SliceContains(actual, grantedRole) {
+			stillGranted = append(stillGranted, grantedRole)
+		}
+	}
+
+	sortedDesired := append([]string{}, desired...)
+	sort.Strings(sortedDesired)
+	for _, desiredRole := range sortedDesired {
+		if utils.SliceContains(actual, desiredRole) {
+			continue
+		}
+		if err := cqlClient.GrantRole(desiredRole, role); err != nil {
+			return changes, stillGranted, err
+		}
+		stillGranted = append(stillGranted, desiredRole)
+		changes = append(changes, "granted role "+desiredRole)
+	}
+
+	revoked := make([]string, 0)
+	for _, grantedRole := range granted {
+		if !utils.SliceContains(desired, grantedRole) && utils.SliceContains(actual, grantedRole) {
+			revoked = append(revoked, grantedRole)
+		}
+	}
+	sort.Strings(revoked)
+	for _, revokedRole := range revoked {
+		if err := cqlClient.RevokeRole(revokedRole, role); err != nil {
+			return changes, append(stillGranted, revokedRole), err
+		}
+		changes = append(changes, "revoked role "+revokedRole)
+	}
+
+	sort.Strings(stillGranted)
+	return changes, stillGranted, nil
+}
+
+// operatorRoles returns the roles that the operator creates for its own use, i.e., the
+// superuser and the CQL user of Reaper. They cannot be managed through CassandraRoles.
+func operatorRoles(ctx context.Context, c client.Reader, kc *api.K8ssandraCluster) ([]string, error) {
+	secretNames := []string{kc.Spec.Cassandra.SuperuserSecretName}
+	if secretNames[0] == "" {
+		secretNames[0] = secret.DefaultSuperuserSecretName(kc.Spec.Cassandra.Cluster)
+	}
+	if kc.Spec.Reaper != nil {
+		if kc.Spec.Reaper.CassandraUserSecretRef != "" {
+			secretNames = append(secretNames, kc.Spec.Reaper.CassandraUserSecretRef)
+		} else {
+			secretNames = append(secretNames, reaper.DefaultUserSecretName(kc.Name))
+		}
+	}
+
+	roles := make([]string, 0, len(secretNames))
+	for _, secretName := range secretNames {
+		userSecret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: kc.Namespace, Name: secretName}, userSecret); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			// The operator generates the Secret with the name of the Secret as username
+			roles = append(roles, secretName)
+		} else {
+			roles = append(roles, string(userSecret.Data["username"]))
+		}
+	}
+	return roles, nil
+}
+
+// reconcilePermissions grants the desired permissions on data resources to role, and
+// revokes the permissions of granted, i.e., the permissions that were granted by a
+// previous reconciliation, that are no longer desired. The permissions that were granted
+// otherwise, e.g., the permissions that Cassandra grants to the creator of a keyspace or a
+// table, are not revoked. It returns a description of the changes, and the desired
+// permissions that were granted by the operator.
+func reconcilePermissions(cqlClient cassandra.CqlClient, role string, permissions []api.RolePermissions, granted map[string][]string) ([]string, map[string][]string, error) {
+	actual, err := cqlClient.ListPermissions(role)
+	if err != nil {
+		return nil, granted, err
+	}
+	desired := desiredPermissions(permissions)
+
+	stillGranted := make(map[string][]string)
+	for resource, grantedPermissions := range granted {
+		for _, permission := range grantedPermissions {
+			if utils.SliceContains(desired[resource], permission) && utils.SliceContains(actual[resource], permission) {
+				stillGranted[resource] = append(stillGranted[resource], permission)
+			}
+		}
+	}
+
+	changes := make([]string, 0)
+	for _, resource := range sortedResources(desired) {
+		for _, permission := range desired[resource] {
+			if utils.SliceContains(actual[resource], permission) {
+				continue
+			}
+			if err := cqlClient.GrantPermission(permission, resource, role); err != nil {
+				return changes, sortPermissions(stillGranted), err
+			}
+			stillGranted[resource] = append(stillGranted[resource], permission)
+			changes = append(changes, fmt.Sprintf("granted %s on %s", permission, resource))
+		}
+	}
+
+	for _, resource := range sortedResources(granted) {
+		for _, permission := range granted[resource] {
+			if utils.SliceContains(desired[resource], permission) || !utils.SliceContains(actual[resource], permission) {
+				continue
+			}
+			if err := cqlClient.RevokePermission(permission, resource, role); err != nil {
+				stillGranted[resource] = append(stillGranted[resource], permission)
+				return changes, sortPermissions(stillGranted), err
+			}
+			changes = append(changes, fmt.Sprintf("revoked %s on %s", permission, resource))
+		}
+	}
+	return changes, sortPermissions(stillGranted), nil
+}
+
+func sortedResources(permissions map[string][]string) []string {
+	resources := make([]string, 0, len(permissions))
+	for resource := range permissions {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// sortPermissions sorts the permissions of each resource, and returns nil if there are
+// none.
+func sortPermissions(permissions map[string][]string) map[string][]string {
+	if len(permissions) == 0 {
+		return nil
+	}
+	for _, resourcePermissions := range permissions {
+		sort.Strings(resourcePermissions)
+	}
+	return permissions
+}
+
+// desiredPermissions returns the sorted permissions of each data resource, with ALL
+// expanded to the permissions that apply to the resource, as Cassandra stores them.
+func desiredPermissions(permissions []api.RolePermissions) map[string][]string {
+	desired := make(map[string][]string)
+	for _, rolePermissions := range permissions {
+		resource := cassandra.DataResource(rolePermissions.Keyspace, rolePermissions.Table)
+		expanded := rolePermissions.Permissions
+		if containsPermission(expanded, api.PermissionAll) {
+			expanded = keyspacePermissions
+		}
+		for _, permission := range expanded {
+			if permission == api.PermissionCreate && rolePermissions.Table != "" {
+				continue
+			}
+			if !utils.SliceContains(desired[resource], string(permission)) {
+				desired[resource] = append(desired[resource], string(permission))
+			}
+		}
+	}
+	for _, resourcePermissions := range desired {
+		sort.Strings(resourcePermissions)
+	}
+	return desired
+}
+
+func containsPermission(permissions []api.Permission, permission api.Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDeletion drops the role of the CassandraRole if its deletion policy is Delete, then
+// removes its finalizer. The role is left as is if the K8ssandraCluster is gone or being
+// deleted.
+func (r *CassandraRoleReconciler) checkDeletion(ctx context.Context, role *api.CassandraRole, kc *api.K8ssandraCluster, logger logr.Logger) result.ReconcileResult {
+	if !controllerutil.ContainsFinalizer(role, cassandraRoleFinalizer) {
+		return result.Done()
+	}
+
+	if role.GetDeletionPolicy() == api.DeletionPolicyDelete && kc != nil && kc.DeletionTimestamp == nil && kc.Spec.Cassandra != nil {
+		name := role.RoleName()
+		reservedRoles, err := operatorRoles(ctx, r.Client, kc)
+		if err != nil {
+			logger.Error(err, "Failed to get the roles of the operator")
+			return result.Error(err)
+		}
+		if utils.SliceContains(reservedRoles, name) {
+			logger.Info("Not dropping a role of the operator", "Role", name)
+			return r.removeFinalizer(ctx, role, logger)
+		}
+		dc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+		if err != nil {
+			return result.Error(err)
+		}
+		if dc == nil {
+			logger.Info("Waiting for a datacenter to be ready to drop the role")
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+		cqlClient, err := newCqlClient(ctx, r.Client, r.Cql, kc, dc, remoteClient, logger)
+		if err != nil {
+			return result.Error(err)
+		}
+		defer cqlClient.Close()
+		if err := cqlClient.DropRole(name); err != nil {
+			logger.Error(err, "Failed to drop role", "Role", name)
+			r.Recorder.Eventf(role, corev1.EventTypeWarning, events.RoleReconcileFailed,
+				"Failed to drop role %s: %v", name, err)
+			return result.Error(err)
+		}
+		r.Recorder.Eventf(role, corev1.EventTypeNormal, events.RoleDropped, "Dropped role %s", name)
+	}
+	return r.removeFinalizer(ctx, role, logger)
+}
+
+func (r *CassandraRoleReconciler) removeFinalizer(ctx context.Context, role *api.CassandraRole, logger logr.Logger) result.ReconcileResult {
+	patch := client.MergeFrom(role.DeepCopy())
+	controllerutil.RemoveFinalizer(role, cassandraRoleFinalizer)
+	if err := r.Patch(ctx, role, patch); err != nil {
+		logger.Error(err, "Failed to remove finalizer")
+		return result.Error(err)
+	}
+	return result.Done()
+}
+
+// secretToRoles returns the CassandraRoles whose password is in the Secret s, so that a
+// new password is set when it changes.
+func (r *CassandraRoleReconciler) secretToRoles(s client.Object) []reconcile.Request {
+	roleList := &api.CassandraRoleList{}
+	if err := r.List(context.Background(), roleList, client.InNamespace(s.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0)
+	for _, role := range roleList.Items {
+		if role.CanLogin() && role.SecretName() == s.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: role.Namespace, Name: role.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CassandraRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.CassandraRole{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.secretToRoles)).
+		Complete(r)
+}
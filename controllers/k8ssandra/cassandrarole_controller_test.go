@@ -0,0 +1,317 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestDesiredPermissions(t *testing.T) {
+	permissions := []api.RolePermissions{
+		{Permissions: []api.Permission{api.PermissionSelect}},
+		{Keyspace: "app", Permissions: []api.Permission{api.PermissionAll}},
+		{Keyspace: "logs", Table: "events", Permissions: []api.Permission{api.PermissionAll}},
+		{Keyspace: "logs", Table: "events", Permissions: []api.Permission{api.PermissionSelect}},
+	}
+	assert.Equal(t, map[string][]string{
+		"data":             {"SELECT"},
+		"data/app":         {"ALTER", "AUTHORIZE", "CREATE", "DROP", "MODIFY", "SELECT"},
+		"data/logs/events": {"ALTER", "AUTHORIZE", "DROP", "MODIFY", "SELECT"},
+	}, desiredPermissions(permissions))
+}
+
+func TestReconcileMemberOf(t *testing.T) {
+	tests := []struct {
+		name            string
+		actual          []string
+		desired         []string
+		granted         []string
+		expectedGrants  []string
+		expectedRevokes []string
+		expectedGranted []string
+	}{
+		{"new roles", nil, []string{"writers", "readers"}, nil, []string{"readers", "writers"}, nil, []string{"readers", "writers"}},
+		{"removed role", []string{"readers", "writers"}, []string{"readers"}, []string{"readers", "writers"}, nil, []string{"writers"}, []string{"readers"}},
+		{"role granted by the LDAP group sync", []string{"readers", "ldap_admins"}, []string{"readers"}, []string{"readers"}, nil, nil, []string{"readers"}},
+		{"desired role granted by the LDAP group sync", []string{"readers"}, []string{"readers"}, nil, nil, nil, []string{}},
+		{"granted role revoked by the LDAP group sync", nil, []string{"readers"}, []string{"readers"}, []string{"readers"}, nil, []string{"readers"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cqlClient := new(mocks.CqlClient)
+			for _, role := range tt.expectedGrants {
+				cqlClient.On("GrantRole", role, "app").Return(nil).Once()
+			}
+			for _, role := range tt.expectedRevokes {
+				cqlClient.On("RevokeRole", role, "app").Return(nil).Once()
+			}
+
+			_, granted, err := reconcileMemberOf(cqlClient, "app", tt.actual, tt.desired, tt.granted)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedGranted, granted)
+			cqlClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconcilePermissions(t *testing.T) {
+	selectOnApp := []api.RolePermissions{{Keyspace: "app", Permissions: []api.Permission{api.PermissionSelect}}}
+	tests := []struct {
+		name            string
+		actual          map[string][]string
+		desired         []api.RolePermissions
+		granted         map[string][]string
+		expectedGrants  [][]string
+		expectedRevokes [][]string
+		expectedGranted map[string][]string
+	}{
+		{
+			name:            "new permission",
+			actual:          map[string][]string{},
+			desired:         selectOnApp,
+			expectedGrants:  [][]string{{"SELECT", "data/app"}},
+			expectedGranted: map[string][]string{"data/app": {"SELECT"}},
+		},
+		{
+			name:            "removed permission",
+			actual:          map[string][]string{"data/app": {"MODIFY", "SELECT"}},
+			desired:         selectOnApp,
+			granted:         map[string][]string{"data/app": {"MODIFY", "SELECT"}},
+			expectedRevokes: [][]string{{"MODIFY", "data/app"}},
+			expectedGranted: map[string][]string{"data/app": {"SELECT"}},
+		},
+		{
+			name:            "permission granted by Cassandra to the creator of a table",
+			actual:          map[string][]string{"data/app": {"SELECT"}, "data/app/events": {"ALTER", "DROP", "SELECT"}},
+			desired:         selectOnApp,
+			granted:         map[string][]string{"data/app": {"SELECT"}},
+			expectedGranted: map[string][]string{"data/app": {"SELECT"}},
+		},
+		{
+			name:            "granted permission revoked outside of the operator",
+			actual:          map[string][]string{},
+			desired:         selectOnApp,
+			granted:         map[string][]string{"data/app": {"SELECT"}},
+			expectedGrants:  [][]string{{"SELECT", "data/app"}},
+			expectedGranted: map[string][]string{"data/app": {"SELECT"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cqlClient := new(mocks.CqlClient)
+			cqlClient.On("ListPermissions", "app").Return(tt.actual, nil)
+			for _, grant := range tt.expectedGrants {
+				cqlClient.On("GrantPermission", grant[0], grant[1], "app").Return(nil).Once()
+			}
+			for _, revoke := range tt.expectedRevokes {
+				cqlClient.On("RevokePermission", revoke[0], revoke[1], "app").Return(nil).Once()
+			}
+
+			_, granted, err := reconcilePermissions(cqlClient, "app", tt.desired, tt.granted)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedGranted, granted)
+			cqlClient.AssertExpectations(t)
+			cqlClient.AssertNumberOfCalls(t, "RevokePermission", len(tt.expectedRevokes))
+		})
+	}
+}
+
+func TestReconcileCassandraRole(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster: "test",
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+				},
+			},
+		},
+		Status: api.K8ssandraClusterStatus{
+			Datacenters: map[string]api.K8ssandraStatus{
+				"dc1": {Cassandra: readyDatacenterStatus()},
+			},
+		},
+	}
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+		Status:     *readyDatacenterStatus(),
+	}
+	superuserSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-superuser"},
+		Data:       map[string][]byte{"username": []byte("test-superuser"), "password": []byte("superuser-password")},
+	}
+	role := &api.CassandraRole{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: api.CassandraRoleSpec{
+			Cluster: corev1.LocalObjectReference{Name: "test"},
+			Roles:   []string{"readers"},
+			Permissions: []api.RolePermissions{
+				{Keyspace: "app", Permissions: []api.Permission{api.PermissionSelect, api.PermissionModify}},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc, dc, superuserSecret, role).Build()
+
+	cqlClient := new(mocks.CqlClient)
+	recorder := record.NewFakeRecorder(10)
+	r := &CassandraRoleReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Client:           c,
+		Scheme:           scheme,
+		ClientCache:      clientcache.New(c, c, scheme),
+		Cql:              &mockCqlClientFactory{cqlClient: cqlClient},
+		Recorder:         recorder,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+
+	// The role is created with a generated password and granted its roles and permissions
+	cqlClient.On("GetRole", "app").Return(nil, nil).Once()
+	cqlClient.On("CreateRole", "app", mock.MatchedBy(func(options cassandra.RoleOptions) bool {
+		return options.Login && !options.Superuser && options.Password != ""
+	})).Return(nil).Once()
+	cqlClient.On("GrantRole", "readers", "app").Return(nil).Once()
+	cqlClient.On("ListPermissions", "app").Return(map[string][]string{}, nil).Once()
+	cqlClient.On("GrantPermission", "MODIFY", "data/app", "app").Return(nil).Once()
+	cqlClient.On("GrantPermission", "SELECT", "data/app", "app").Return(nil).Once()
+	cqlClient.On("Close").Return()
+
+	res, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	cqlClient.AssertExpectations(t)
+
+	credentials := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "app-credentials"}, credentials))
+	assert.Equal(t, "app", string(credentials.Data["username"]))
+	assert.NotEmpty(t, credentials.Data["password"])
+
+	actual := &api.CassandraRole{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+	assert.True(t, controllerutil.ContainsFinalizer(actual, cassandraRoleFinalizer))
+	assert.NotEmpty(t, actual.Status.SecretHash)
+	assert.Equal(t, []string{"readers"}, actual.Status.GrantedRoles)
+	assert.Equal(t, map[string][]string{"data/app": {"MODIFY", "SELECT"}}, actual.Status.GrantedPermissions)
+	assert.Empty(t, actual.Status.Error)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.RoleCreated)
+
+	// Grants removed from the spec are revoked, and the new password is set. The
+	// permissions that the operator did not grant are left as is.
+	actual.Spec.Roles = nil
+	actual.Spec.Permissions = []api.RolePermissions{
+		{Keyspace: "app", Permissions: []api.Permission{api.PermissionSelect}},
+	}
+	require.NoError(t, c.Update(ctx, actual))
+	credentials.Data["password"] = []byte("new-password")
+	require.NoError(t, c.Update(ctx, credentials))
+
+	cqlClient.On("GetRole", "app").Return(&cassandra.Role{Name: "app", Login: true, MemberOf: []string{"readers"}}, nil).Once()
+	cqlClient.On("AlterRole", "app", cassandra.RoleOptions{Login: true, Password: "new-password"}).Return(nil).Once()
+	cqlClient.On("RevokeRole", "readers", "app").Return(nil).Once()
+	cqlClient.On("ListPermissions", "app").Return(map[string][]string{
+		"data/app":  {"MODIFY", "SELECT"},
+		"data/logs": {"SELECT"},
+	}, nil).Once()
+	cqlClient.On("RevokePermission", "MODIFY", "data/app", "app").Return(nil).Once()
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	cqlClient.AssertExpectations(t)
+	cqlClient.AssertNotCalled(t, "RevokePermission", "SELECT", "data/logs", "app")
+	require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+	assert.Equal(t, map[string][]string{"data/app": {"SELECT"}}, actual.Status.GrantedPermissions)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.RoleUpdated)
+
+	// The role is dropped when the CassandraRole is deleted
+	require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+	actual.Spec.DeletionPolicy = api.DeletionPolicyDelete
+	now := metav1.Now()
+	actual.DeletionTimestamp = &now
+	require.NoError(t, c.Update(ctx, actual))
+	cqlClient.On("DropRole", "app").Return(nil).Once()
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	cqlClient.AssertExpectations(t)
+	err = c.Get(ctx, req.NamespacedName, actual)
+	assert.True(t, errors.IsNotFound(err), "the finalizer should have been removed")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.RoleDropped)
+}
+
+func TestReconcileOperatorRole(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{Cluster: "test"},
+			Reaper:    &reaperapi.ReaperClusterTemplate{},
+		},
+	}
+	superuserSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-superuser"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("superuser-password")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc, superuserSecret).Build()
+
+	roles, err := operatorRoles(ctx, c, kc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "test-reaper"}, roles)
+
+	for _, name := range roles {
+		role := &api.CassandraRole{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "operator-role"},
+			Spec: api.CassandraRoleSpec{
+				Cluster: corev1.LocalObjectReference{Name: "test"},
+				Name:    name,
+			},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &CassandraRoleReconciler{
+			ReconcilerConfig: config.InitConfig(),
+			Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc, superuserSecret, role).Build(),
+			Scheme:           scheme,
+			Recorder:         recorder,
+		}
+
+		recResult := r.reconcile(ctx, role, logr.Discard())
+		_, err := recResult.Output()
+		assert.EqualError(t, err, "role "+name+" is managed by the operator and cannot be managed through a CassandraRole")
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, events.RoleReconcileFailed)
+	}
+}
@@ -0,0 +1,142 @@
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileConfigFiles replicates the ConfigMaps referenced by files from the namespace of
+// kc to the namespace of dc in the Kubernetes cluster of remoteClient, and mounts them in
+// the Cassandra pods of dc.
+func (r *K8ssandraClusterReconciler) reconcileConfigFiles(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	files *api.ConfigFiles,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) error {
+	configMaps := make(map[string]*corev1.ConfigMap)
+	for _, name := range cassandra.ConfigMapNames(files) {
+		configMapKey := types.NamespacedName{Namespace: kc.Namespace, Name: name}
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, configMapKey, configMap); err != nil {
+			if errors.IsNotFound(err) {
+				// reported by AddConfigFiles
+				continue
+			}
+			logger.Error(err, "Failed to get ConfigMap", "ConfigMap", configMapKey)
+			return err
+		}
+		if err := replicateConfigMap(ctx, kc, configMap, dc.Namespace, remoteClient, logger); err != nil {
+			return err
+		}
+		configMaps[name] = configMap
+	}
+	return cassandra.AddConfigFiles(dc, files, configMaps)
+}
+
+// replicateConfigMap creates or updates a copy of configMap in namespace. The copy is
+// labeled as managed by kc, and an existing ConfigMap that is not managed by kc is not
+// overwritten.
+func replicateConfigMap(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	configMap *corev1.ConfigMap,
+	namespace string,
+	remoteClient client.Client,
+	logger logr.Logger,
+) error {
+	kcKey := utils.GetKey(kc)
+	copyKey := types.NamespacedName{Namespace: namespace, Name: configMap.Name}
+	actual := &corev1.ConfigMap{}
+
+	if err := remoteClient.Get(ctx, copyKey, actual); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to get ConfigMap", "ConfigMap", copyKey)
+			return err
+		}
+		desired := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      configMap.Name,
+				Labels:    labels.ManagedByLabels(kcKey),
+			},
+			Data:       configMap.Data,
+			BinaryData: configMap.BinaryData,
+		}
+		logger.Info("Creating ConfigMap", "ConfigMap", copyKey)
+		if err = remoteClient.Create(ctx, desired); err != nil {
+			logger.Error(err, "Failed to create ConfigMap", "ConfigMap", copyKey)
+			return err
+		}
+		return nil
+	}
+
+	if actual.UID == configMap.UID {
+		// The datacenter is in the same namespace and Kubernetes cluster as kc.
+		return nil
+	}
+	if !labels.IsManagedBy(actual, kcKey) {
+		return fmt.Errorf("ConfigMap %s already exists and is not managed by K8ssandraCluster %s", copyKey, kcKey)
+	}
+	if reflect.DeepEqual(actual.Data, configMap.Data) && reflect.DeepEqual(actual.BinaryData, configMap.BinaryData) {
+		return nil
+	}
+
+	logger.Info("Updating ConfigMap", "ConfigMap", copyKey)
+	actual = actual.DeepCopy()
+	actual.Data = configMap.Data
+	actual.BinaryData = configMap.BinaryData
+	if err := remoteClient.Update(ctx, actual); err != nil {
+		logger.Error(err, "Failed to update ConfigMap", "ConfigMap", copyKey)
+		return err
+	}
+	return nil
+}
+
+// configMapToK8ssandraClusters returns the K8ssandraClusters in the namespace of configMap
+// that reference it in their config files.
+func (r *K8ssandraClusterReconciler) configMapToK8ssandraClusters(configMap client.Object) []reconcile.Request {
+	kcList := &api.K8ssandraClusterList{}
+	if err := r.List(context.Background(), kcList, client.InNamespace(configMap.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, kc := range kcList.Items {
+		if referencesConfigMap(&kc, configMap.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: utils.GetKey(&kc)})
+		}
+	}
+	return requests
+}
+
+func referencesConfigMap(kc *api.K8ssandraCluster, name string) bool {
+	if kc.Spec.Cassandra == nil {
+		return false
+	}
+	if utils.SliceContains(cassandra.ConfigMapNames(kc.Spec.Cassandra.ConfigFiles), name) {
+		return true
+	}
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		if utils.SliceContains(cassandra.ConfigMapNames(dcTemplate.ConfigFiles), name) {
+			return true
+		}
+	}
+	return false
+}
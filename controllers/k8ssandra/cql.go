@@ -0,0 +1,60 @@
+package k8ssandra
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/secret"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newCqlClient connects to the ready datacenter dc of kc as its superuser. c reads the
+// superuser Secret and the encryption stores of kc, and remoteClient lists the pods of dc.
+// The caller must close the returned client.
+func newCqlClient(
+	ctx context.Context,
+	c client.Reader,
+	cqlFactory cassandra.CqlClientFactory,
+	kc *api.K8ssandraCluster,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) (cassandra.CqlClient, error) {
+	username, password, err := superuserCredentials(ctx, c, kc)
+	if err != nil {
+		logger.Error(err, "Failed to get the superuser credentials")
+		return nil, err
+	}
+	tlsConfig, err := cqlTLSConfig(ctx, c, kc, dc.Name)
+	if err != nil {
+		logger.Error(err, "Failed to get the encryption stores", "CassandraDatacenter", dc.Name)
+		return nil, err
+	}
+	cqlClient, err := cqlFactory.NewCqlClient(ctx, dc, remoteClient, username, password, tlsConfig, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create CqlClient")
+		return nil, err
+	}
+	return cqlClient, nil
+}
+
+// superuserCredentials returns the username and the password of the superuser of kc. The
+// name of its Secret is defaulted like reconcileSuperuserSecret does, since the default is
+// not persisted in kc.
+func superuserCredentials(ctx context.Context, c client.Reader, kc *api.K8ssandraCluster) (string, string, error) {
+	secretName := kc.Spec.Cassandra.SuperuserSecretName
+	if secretName == "" {
+		secretName = secret.DefaultSuperuserSecretName(kc.Spec.Cassandra.Cluster)
+	}
+	key := types.NamespacedName{Namespace: kc.Namespace, Name: secretName}
+	superuserSecret := &corev1.Secret{}
+	if err := c.Get(ctx, key, superuserSecret); err != nil {
+		return "", "", err
+	}
+	return string(superuserSecret.Data["username"]), string(superuserSecret.Data["password"]), nil
+}
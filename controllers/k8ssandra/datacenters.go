@@ -3,21 +3,73 @@ package k8ssandra
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+
 	"github.com/go-logr/logr"
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/secret"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// dcReconciliation holds the state of the reconciliation of a single datacenter. The
+// datacenters of a rollout group are reconciled concurrently, so they record what they
+// observed here instead of updating the K8ssandraCluster status directly. The status is
+// updated once all the datacenters of the group have been reconciled.
+type dcReconciliation struct {
+	dcTemplate   api.CassandraDatacenterTemplate
+	desiredDc    *cassdcapi.CassandraDatacenter
+	remoteClient client.Client
+	logger       logr.Logger
+
+	// dcConfig is the config desiredDc was created from.
+	dcConfig *cassandra.DatacenterConfig
+
+	// actualDc is the existing CassandraDatacenter, or nil if it was just created.
+	actualDc *cassdcapi.CassandraDatacenter
+
+	// created is true if the CassandraDatacenter did not exist and has been created.
+	created bool
+
+	// ready is true if the CassandraDatacenter is ready.
+	ready bool
+
+	// stopped is true if the CassandraDatacenter is stopped.
+	stopped bool
+
+	// unreachable is true if the Kubernetes cluster of the datacenter could not be reached.
+	unreachable bool
+
+	// readyNodes is the number of Cassandra pods that are ready.
+	readyNodes int32
+
+	// traceProbability is the desired trace probability, or an empty string if it is not
+	// set.
+	traceProbability string
+
+	// pendingRestart lists the config properties whose change was rolled out by updating
+	// the CassandraDatacenter.
+	pendingRestart []string
+
+	// configStatus is the config status of the datacenter. It is recorded if it is set or
+	// if the datacenter is ready.
+	configStatus *api.ConfigStatus
+
+	result result.ReconcileResult
+}
+
 func (r *K8ssandraClusterReconciler) reconcileDatacenters(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) (result.ReconcileResult, []*cassdcapi.CassandraDatacenter) {
 	kcKey := utils.GetKey(kc)
 
@@ -35,111 +87,164 @@ func (r *K8ssandraClusterReconciler) reconcileDatacenters(ctx context.Context, k
 		return result.Error(err), actualDcs
 	}
 
-	// Reconcile CassandraDatacenter objects only
+	dcs := make([]*dcReconciliation, 0, len(kc.Spec.Cassandra.Datacenters))
+	var unsupportedProperties []string
 	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
-
-		if !secret.HasReplicatedSecrets(ctx, r.Client, kcKey, dcTemplate.K8sContext) {
-			// ReplicatedSecret has not replicated yet, wait until it has
-			logger.Info("Waiting for replication to complete")
-			return result.RequeueSoon(r.DefaultDelay), actualDcs
-		}
-
 		// Note that it is necessary to use a copy of the CassandraClusterTemplate because
 		// its fields are pointers, and without the copy we could end of with shared
 		// references that would lead to unexpected and incorrect values.
 		dcConfig := cassandra.Coalesce(kc.Spec.Cassandra.DeepCopy(), dcTemplate.DeepCopy())
 		cassandra.ApplySystemReplication(dcConfig, *systemReplication)
-		if !cassandra.IsCassandra3(dcConfig.ServerVersion) && kc.HasStargates() {
+		dcConfig.Stopped = kc.IsDatacenterStopped(&dcTemplate)
+		if !version.Cassandra3_11.Contains(dcConfig.ServerVersion) && kc.HasStargates() {
 			// if we're not running Cassandra 3.11 and have Stargate pods, we need to allow alter RF during range movements
 			cassandra.AllowAlterRfDuringRangeMovement(dcConfig)
 		}
+		tuning := cassandra.ApplyResourceTuning(dcConfig)
 		reaperTemplate := reaper.Coalesce(kc.Spec.Reaper.DeepCopy(), dcTemplate.Reaper.DeepCopy())
 		if reaperTemplate != nil {
 			reaper.AddReaperSettingsToDcConfig(reaperTemplate, dcConfig)
 		}
+		if kc.Spec.Cassandra.TLS.IsEnabled() {
+			stores, err := r.encryptionStores(ctx, kc, dcTemplate.Meta.Name)
+			if err != nil {
+				logger.Error(err, "Failed to get encryption stores", "CassandraDatacenter", dcTemplate.Meta.Name)
+				return result.Error(err), actualDcs
+			}
+			cassandra.ApplyEncryption(dcConfig, kc.Spec.Cassandra.TLS, stores)
+		}
+		if kc.Spec.Cassandra.Auth.IsLDAPEnabled() {
+			bindSecret, err := r.ldapBindSecret(ctx, kc)
+			if err != nil {
+				logger.Error(err, "Failed to get the LDAP bind secret", "CassandraDatacenter", dcTemplate.Meta.Name)
+				return result.Error(err), actualDcs
+			}
+			cassandra.ApplyLDAP(dcConfig, kc.Spec.Cassandra.Auth.LDAP, utils.DeepHashString(bindSecret.Data))
+		}
+		if unsupported := cassandra.UnsupportedProperties(dcConfig.CassandraConfig, dcConfig.ServerVersion); len(unsupported) > 0 {
+			unsupportedProperties = append(unsupportedProperties,
+				fmt.Sprintf("%s (%s): %s", dcTemplate.Meta.Name, dcConfig.ServerVersion, strings.Join(unsupported, ", ")))
+		}
 		desiredDc, err := cassandra.NewDatacenter(kcKey, dcConfig)
-		dcKey := types.NamespacedName{Namespace: desiredDc.Namespace, Name: desiredDc.Name}
-		logger := logger.WithValues("CassandraDatacenter", dcKey, "K8SContext", dcTemplate.K8sContext)
-
 		if err != nil {
-			logger.Error(err, "Failed to create new CassandraDatacenter")
+			logger.Error(err, "Failed to create new CassandraDatacenter", "CassandraDatacenter", dcTemplate.Meta.Name)
 			return result.Error(err), actualDcs
 		}
+		setTuningForDatacenter(kc, dcTemplate.Meta.Name, tuning)
 
-		annotations.AddHashAnnotation(desiredDc)
-
-		actualDc := &cassdcapi.CassandraDatacenter{}
+		dcKey := types.NamespacedName{Namespace: desiredDc.Namespace, Name: desiredDc.Name}
+		logger := logger.WithValues("CassandraDatacenter", dcKey, "K8SContext", dcTemplate.K8sContext)
 
 		remoteClient, err := r.ClientCache.GetRemoteClient(dcTemplate.K8sContext)
 		if err != nil {
 			logger.Error(err, "Failed to get remote client")
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.DatacenterUnreachable,
+				"Failed to get a client for CassandraDatacenter %s in context %s: %v", dcKey.Name, dcTemplate.K8sContext, err)
+			setSummaryForDatacenter(kc, dcTemplate.Meta.Name, &api.DatacenterSummary{
+				K8sContext:  dcTemplate.K8sContext,
+				Unreachable: true,
+			})
 			return result.Error(err), actualDcs
 		}
 
-		if recResult := r.reconcileSeedsEndpoints(ctx, desiredDc, seeds, remoteClient, logger); recResult.Completed() {
-			return recResult, actualDcs
+		if err = r.reconcileConfigFiles(ctx, kc, dcConfig.ConfigFiles, desiredDc, remoteClient, logger); err != nil {
+			logger.Error(err, "Failed to reconcile config files")
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.ConfigFilesFailed,
+				"Failed to reconcile the config files of CassandraDatacenter %s: %v", dcKey.Name, err)
+			return result.Error(err), actualDcs
 		}
 
-		if err = remoteClient.Get(ctx, dcKey, actualDc); err == nil {
-			// cassdc already exists, we'll update it
-			if err = r.setStatusForDatacenter(kc, actualDc); err != nil {
-				logger.Error(err, "Failed to update status for datacenter")
-				return result.Error(err), actualDcs
-			}
+		dcs = append(dcs, &dcReconciliation{
+			dcTemplate:       dcTemplate,
+			desiredDc:        desiredDc,
+			remoteClient:     remoteClient,
+			logger:           logger,
+			dcConfig:         dcConfig,
+			traceProbability: cassandra.TraceProbability(dcConfig.CassandraConfig),
+		})
+	}
 
-			if !annotations.CompareHashAnnotations(actualDc, desiredDc) {
-				logger.Info("Updating datacenter")
+	r.setCassandraConfigValid(kc, unsupportedProperties)
 
-				if actualDc.Spec.SuperuserSecretName != desiredDc.Spec.SuperuserSecretName {
-					// If actualDc is created with SuperuserSecretName, it can't be changed anymore. We should reject all changes coming from K8ssandraCluster
-					desiredDc.Spec.SuperuserSecretName = actualDc.Spec.SuperuserSecretName
-					err = fmt.Errorf("tried to update superuserSecretName in K8ssandraCluster")
-					logger.Error(err, "SuperuserSecretName is immutable, reverting to existing value in CassandraDatacenter")
-				}
+	// Version changes are rolled out by reconcileUpgrade, and authentication is enabled by
+	// reconcileAuth. They may hold back the version or the config of some datacenters. The
+	// hash annotations must be computed afterwards.
+	upgradeResult := r.reconcileUpgrade(ctx, kc, dcs, logger)
+	authResult := r.reconcileAuth(ctx, kc, dcs, logger)
+	for _, dc := range dcs {
+		annotations.AddHashAnnotation(dc.desiredDc)
+	}
 
-				actualDc = actualDc.DeepCopy()
-				resourceVersion := actualDc.GetResourceVersion()
-				desiredDc.DeepCopyInto(actualDc)
-				actualDc.SetResourceVersion(resourceVersion)
-				if err = remoteClient.Update(ctx, actualDc); err != nil {
-					logger.Error(err, "Failed to update datacenter")
-					return result.Error(err), actualDcs
+	for _, group := range rolloutGroups(kc, dcs) {
+		r.reconcileDatacenterGroup(ctx, kc, group, seeds)
+
+		// The steps below update the schema. They are run for one datacenter at a time so
+		// that concurrent replication changes do not overwrite each other.
+		results := make([]result.ReconcileResult, 0, len(group))
+		for _, dc := range group {
+			if dc.actualDc != nil {
+				if err = r.setStatusForDatacenter(kc, dc.actualDc); err != nil {
+					dc.logger.Error(err, "Failed to update status for datacenter")
+					dc.result = result.Error(err)
 				}
 			}
 
-			if !cassandra.DatacenterReady(actualDc) {
-				logger.Info("Waiting for datacenter to become ready")
-				return result.RequeueSoon(r.DefaultDelay), actualDcs
-			}
+			setSummaryForDatacenter(kc, dc.desiredDc.Name, &api.DatacenterSummary{
+				K8sContext:  dc.dcTemplate.K8sContext,
+				Unreachable: dc.unreachable,
+				ReadyNodes:  dc.readyNodes,
+			})
 
-			logger.Info("The datacenter is ready")
+			if dc.ready || dc.configStatus != nil {
+				setConfigStatusForDatacenter(kc, dc.desiredDc.Name, dc.configStatus)
+			}
 
-			actualDcs = append(actualDcs, actualDc)
+			if dc.created && kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionTrue {
+				// The datacenter is being added to an existing cluster, its data
+				// needs to be streamed from the other datacenters once it is ready.
+				setRebuildPending(kc, dc.desiredDc.Name)
+			}
 
-			if recResult := r.updateReplicationOfSystemKeyspaces(ctx, kc, desiredDc, remoteClient, logger); recResult.Completed() {
-				return recResult, actualDcs
+			if dc.stopped && !dc.result.Completed() {
+				// The schema cannot be updated and the data cannot be streamed while the
+				// datacenter is stopped, this is deferred until it is resumed.
+				actualDcs = append(actualDcs, dc.actualDc)
 			}
 
-		} else {
-			if errors.IsNotFound(err) {
-				// cassdc doesn't exist, we'll create it
-				if err = remoteClient.Create(ctx, desiredDc); err != nil {
-					logger.Error(err, "Failed to create datacenter")
-					return result.Error(err), actualDcs
+			if dc.ready && !dc.result.Completed() {
+				actualDcs = append(actualDcs, dc.actualDc)
+
+				if recResult := r.updateReplicationOfSystemKeyspaces(ctx, kc, dc.desiredDc, dc.remoteClient, dc.logger); recResult.Completed() {
+					dc.result = recResult
+				} else {
+					dc.result = r.reconcileRebuild(ctx, kc, dc.dcTemplate, dc.actualDc, dc.remoteClient, dc.logger)
 				}
-				return result.RequeueSoon(r.DefaultDelay), actualDcs
-			} else {
-				logger.Error(err, "Failed to get datacenter")
-				return result.Error(err), actualDcs
 			}
+
+			setErrorForDatacenter(kc, dc.desiredDc.Name, dc.result)
+			results = append(results, dc.result)
+		}
+
+		if recResult := result.Aggregate(results...); recResult.Completed() {
+			return recResult, actualDcs
 		}
 	}
 
-	// If we reach this point all CassandraDatacenters are ready. We only set the
-	// CassandraInitialized condition if it is unset, i.e., only once. This allows us to
-	// distinguish whether we are deploying a CassandraDatacenter as part of a new cluster
-	// or as part of an existing cluster.
-	if kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionUnknown {
+	if recResult := result.Aggregate(upgradeResult, authResult); recResult.Completed() {
+		return recResult, actualDcs
+	}
+
+	// The system keyspaces whose replication factor was raised are repaired once all the
+	// datacenters are ready, so that the new replicas get their data.
+	if recResult := r.reconcileSystemRepair(ctx, kc, dcs, logger); recResult.Completed() {
+		return recResult, actualDcs
+	}
+
+	// If we reach this point all CassandraDatacenters are ready or stopped. We only set the
+	// CassandraInitialized condition if it is unset, i.e., only once, and once all of them
+	// have been ready. This allows us to distinguish whether we are deploying a
+	// CassandraDatacenter as part of a new cluster or as part of an existing cluster.
+	if kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionUnknown && !hasStoppedDatacenters(kc) {
 		now := metav1.Now()
 		(&kc.Status).SetCondition(api.K8ssandraClusterCondition{
 			Type:               api.CassandraInitialized,
@@ -151,6 +256,188 @@ func (r *K8ssandraClusterReconciler) reconcileDatacenters(ctx context.Context, k
 	return result.Continue(), actualDcs
 }
 
+// rolloutGroups splits dcs into groups according to the rollout policy of kc. The groups
+// are reconciled in order, and the datacenters of a group are reconciled concurrently. A
+// group is only reconciled once all the datacenters of the previous groups are ready.
+func rolloutGroups(kc *api.K8ssandraCluster, dcs []*dcReconciliation) [][]*dcReconciliation {
+	if len(dcs) == 0 {
+		return nil
+	}
+
+	switch kc.Spec.Cassandra.RolloutPolicy {
+	case api.RolloutSerial:
+		groups := make([][]*dcReconciliation, 0, len(dcs))
+		for _, dc := range dcs {
+			groups = append(groups, []*dcReconciliation{dc})
+		}
+		return groups
+	case api.RolloutParallel:
+		return [][]*dcReconciliation{dcs}
+	default:
+		// The first datacenter needs to be bootstrapped on its own, but once the cluster
+		// is initialized there is no reason to wait for it.
+		if len(dcs) == 1 || kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionTrue {
+			return [][]*dcReconciliation{dcs}
+		}
+		return [][]*dcReconciliation{dcs[:1], dcs[1:]}
+	}
+}
+
+// reconcileDatacenterGroup reconciles the CassandraDatacenters of group concurrently.
+// The outcome is recorded in each dcReconciliation.
+func (r *K8ssandraClusterReconciler) reconcileDatacenterGroup(ctx context.Context, kc *api.K8ssandraCluster, group []*dcReconciliation, seeds []corev1.Pod) {
+	var wg sync.WaitGroup
+	for _, dc := range group {
+		wg.Add(1)
+		go func(dc *dcReconciliation) {
+			defer wg.Done()
+			dc.result = r.reconcileDatacenter(ctx, kc, dc, seeds)
+		}(dc)
+	}
+	wg.Wait()
+}
+
+// reconcileDatacenter creates or updates the CassandraDatacenter of dc as well as its seeds
+// Endpoints. It must not modify kc since it runs concurrently for the datacenters of a
+// rollout group.
+func (r *K8ssandraClusterReconciler) reconcileDatacenter(ctx context.Context, kc *api.K8ssandraCluster, dc *dcReconciliation, seeds []corev1.Pod) result.ReconcileResult {
+	logger := dc.logger
+	desiredDc := dc.desiredDc
+	remoteClient := dc.remoteClient
+	dcKey := types.NamespacedName{Namespace: desiredDc.Namespace, Name: desiredDc.Name}
+
+	if !secret.HasReplicatedSecrets(ctx, r.Client, utils.GetKey(kc), dc.dcTemplate.K8sContext) {
+		// ReplicatedSecret has not replicated yet, wait until it has
+		logger.Info("Waiting for replication to complete")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	if recResult := r.reconcileSeedsEndpoints(ctx, desiredDc, seeds, remoteClient, logger); recResult.Completed() {
+		return recResult
+	}
+
+	actualDc := &cassdcapi.CassandraDatacenter{}
+
+	if err := remoteClient.Get(ctx, dcKey, actualDc); err == nil {
+		// cassdc already exists, we'll update it
+		dc.actualDc = actualDc
+
+		pods, err := listDatacenterPods(ctx, actualDc, remoteClient)
+		if err != nil {
+			logger.Error(err, "Failed to list datacenter pods")
+			return result.Error(err)
+		}
+		dc.readyNodes = countReadyPods(pods)
+
+		if desiredDc.Spec.Stopped && !actualDc.Spec.Stopped {
+			// Stargate and Reaper are scaled down before the Cassandra nodes are stopped
+			if stopped, err := r.stopStargateAndReaper(ctx, kc, actualDc, remoteClient, logger); err != nil {
+				return result.Error(err)
+			} else if !stopped {
+				logger.Info("Waiting for Stargate and Reaper to be scaled down")
+				return result.RequeueSoon(r.DefaultDelay)
+			}
+		}
+
+		changes, err := cassandra.DiffConfig(actualDc.Spec.Config, desiredDc.Spec.Config, actualDc.Spec.ServerVersion)
+		if err != nil {
+			logger.Error(err, "Failed to compare the config of the datacenter")
+			return result.Error(err)
+		}
+		restarting := actualDc.Spec.RollingRestartRequested || cassandra.PodTemplateChanged(actualDc, desiredDc)
+		if !changes.RestartRequired() && len(changes.Live) > 0 && !restarting {
+			// The changes are applied to the running nodes once the datacenter is ready,
+			// keeping the current config avoids a rolling restart. They are persisted by
+			// the next update that restarts the nodes anyway. Until then, the nodes that
+			// restart for another reason, e.g., when they are rescheduled, start with the
+			// current config and get the changes again from reconcileLiveSettings.
+			desiredDc.Spec.Config = actualDc.Spec.Config
+			delete(desiredDc.Annotations, api.ResourceHashAnnotation)
+			annotations.AddHashAnnotation(desiredDc)
+		}
+
+		if !annotations.CompareHashAnnotations(actualDc, desiredDc) {
+			logger.Info("Updating datacenter")
+
+			if actualDc.Spec.SuperuserSecretName != desiredDc.Spec.SuperuserSecretName {
+				// If actualDc is created with SuperuserSecretName, it can't be changed anymore. We should reject all changes coming from K8ssandraCluster
+				desiredDc.Spec.SuperuserSecretName = actualDc.Spec.SuperuserSecretName
+				err = fmt.Errorf("tried to update superuserSecretName in K8ssandraCluster")
+				logger.Error(err, "SuperuserSecretName is immutable, reverting to existing value in CassandraDatacenter")
+			}
+
+			// Do not cancel a rolling restart that cass-operator has not picked up yet
+			desiredDc.Spec.RollingRestartRequested = actualDc.Spec.RollingRestartRequested
+
+			actualDc = actualDc.DeepCopy()
+			resourceVersion := actualDc.GetResourceVersion()
+			desiredDc.DeepCopyInto(actualDc)
+			actualDc.SetResourceVersion(resourceVersion)
+			if err = remoteClient.Update(ctx, actualDc); err != nil {
+				logger.Error(err, "Failed to update datacenter")
+				r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.UpdateDatacenterFailed,
+					"Failed to update CassandraDatacenter %s in context %s: %v", dcKey.Name, dc.dcTemplate.K8sContext, err)
+				return result.Error(err)
+			}
+			r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.UpdatedDatacenter,
+				"Updated CassandraDatacenter %s in context %s", dcKey.Name, dc.dcTemplate.K8sContext)
+			dc.actualDc = actualDc
+
+			if changes.RestartRequired() {
+				dc.pendingRestart = changes.Properties()
+				dc.configStatus = previousConfigStatus(kc, dcKey.Name).DeepCopy()
+				dc.configStatus.PendingRestart = dc.pendingRestart
+			}
+		}
+
+		if desiredDc.Spec.Stopped {
+			if !cassandra.DatacenterStopped(actualDc) {
+				logger.Info("Waiting for datacenter to stop")
+				return result.RequeueSoon(r.DefaultDelay)
+			}
+			logger.Info("The datacenter is stopped")
+			dc.stopped = true
+			return result.Continue()
+		}
+
+		if !cassandra.DatacenterReady(actualDc) {
+			logger.Info("Waiting for datacenter to become ready")
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+
+		logger.Info("The datacenter is ready")
+		dc.ready = true
+
+		if err := r.reconcileLiveSettings(ctx, kc, dc, changes, pods); err != nil {
+			logger.Error(err, "Failed to apply live settings")
+			return result.Error(err)
+		}
+
+		return result.Continue()
+	} else if errors.IsNotFound(err) {
+		// cassdc doesn't exist, we'll create it
+		if err = remoteClient.Create(ctx, desiredDc); err != nil {
+			logger.Error(err, "Failed to create datacenter")
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.CreateDatacenterFailed,
+				"Failed to create CassandraDatacenter %s in context %s: %v", dcKey.Name, dc.dcTemplate.K8sContext, err)
+			return result.Error(err)
+		}
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.CreatedDatacenter,
+			"Created CassandraDatacenter %s in context %s", dcKey.Name, dc.dcTemplate.K8sContext)
+		dc.created = true
+		return result.RequeueSoon(r.DefaultDelay)
+	} else {
+		if _, ok := err.(errors.APIStatus); !ok {
+			// The request did not reach the API server
+			dc.unreachable = true
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.DatacenterUnreachable,
+				"Failed to get CassandraDatacenter %s in context %s: %v", dcKey.Name, dc.dcTemplate.K8sContext, err)
+		}
+		logger.Error(err, "Failed to get datacenter")
+		return result.Error(err)
+	}
+}
+
 func (r *K8ssandraClusterReconciler) setStatusForDatacenter(kc *api.K8ssandraCluster, dc *cassdcapi.CassandraDatacenter) error {
 	if len(kc.Status.Datacenters) == 0 {
 		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus, 0)
@@ -158,8 +445,11 @@ func (r *K8ssandraClusterReconciler) setStatusForDatacenter(kc *api.K8ssandraClu
 
 	kdcStatus, found := kc.Status.Datacenters[dc.Name]
 
-	if found {
+	if found && kdcStatus.Cassandra != nil {
 		dc.Status.DeepCopyInto(kdcStatus.Cassandra)
+	} else if found {
+		kdcStatus.Cassandra = dc.Status.DeepCopy()
+		kc.Status.Datacenters[dc.Name] = kdcStatus
 	} else {
 		kc.Status.Datacenters[dc.Name] = api.K8ssandraStatus{
 			Cassandra: dc.Status.DeepCopy(),
@@ -168,3 +458,59 @@ func (r *K8ssandraClusterReconciler) setStatusForDatacenter(kc *api.K8ssandraClu
 
 	return nil
 }
+
+// setSummaryForDatacenter sets the summary of the datacenter named dcName. The fields of
+// the summary that do not depend on the reconciliation of the datacenter are filled in
+// by updateStatus.
+func setSummaryForDatacenter(kc *api.K8ssandraCluster, dcName string, summary *api.DatacenterSummary) {
+	if len(kc.Status.Datacenters) == 0 {
+		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus, 0)
+	}
+	kdcStatus := kc.Status.Datacenters[dcName]
+	kdcStatus.Summary = summary
+	kc.Status.Datacenters[dcName] = kdcStatus
+}
+
+// setTuningForDatacenter records the settings that were computed from the resources of
+// the datacenter.
+func setTuningForDatacenter(kc *api.K8ssandraCluster, dcName string, tuning *api.TuningStatus) {
+	kdcStatus, found := kc.Status.Datacenters[dcName]
+	if !found && tuning == nil {
+		return
+	}
+	if len(kc.Status.Datacenters) == 0 {
+		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus, 0)
+	}
+	kdcStatus.Tuning = tuning
+	kc.Status.Datacenters[dcName] = kdcStatus
+}
+
+func setRebuildPending(kc *api.K8ssandraCluster, dcName string) {
+	if len(kc.Status.Datacenters) == 0 {
+		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus, 0)
+	}
+	kdcStatus := kc.Status.Datacenters[dcName]
+	kdcStatus.Rebuild = &api.RebuildStatus{Progress: api.RebuildPending}
+	kc.Status.Datacenters[dcName] = kdcStatus
+}
+
+// setErrorForDatacenter records the error of recResult, if any, in the status of the
+// datacenter named dcName. The error is cleared when recResult is not an error.
+func setErrorForDatacenter(kc *api.K8ssandraCluster, dcName string, recResult result.ReconcileResult) {
+	errMsg := ""
+	if recResult.Completed() {
+		if _, err := recResult.Output(); err != nil {
+			errMsg = err.Error()
+		}
+	}
+
+	kdcStatus, found := kc.Status.Datacenters[dcName]
+	if !found && errMsg == "" {
+		return
+	}
+	if len(kc.Status.Datacenters) == 0 {
+		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus, 0)
+	}
+	kdcStatus.Error = errMsg
+	kc.Status.Datacenters[dcName] = kdcStatus
+}
@@ -0,0 +1,177 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/test/framework"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRolloutGroups(t *testing.T) {
+	dcs := []*dcReconciliation{
+		{dcTemplate: api.CassandraDatacenterTemplate{Meta: api.EmbeddedObjectMeta{Name: "dc1"}}},
+		{dcTemplate: api.CassandraDatacenterTemplate{Meta: api.EmbeddedObjectMeta{Name: "dc2"}}},
+		{dcTemplate: api.CassandraDatacenterTemplate{Meta: api.EmbeddedObjectMeta{Name: "dc3"}}},
+	}
+
+	groupNames := func(groups [][]*dcReconciliation) [][]string {
+		names := make([][]string, 0, len(groups))
+		for _, group := range groups {
+			groupNames := make([]string, 0, len(group))
+			for _, dc := range group {
+				groupNames = append(groupNames, dc.dcTemplate.Meta.Name)
+			}
+			names = append(names, groupNames)
+		}
+		return names
+	}
+
+	newCluster := func(policy api.RolloutPolicy, initialized bool) *api.K8ssandraCluster {
+		kc := &api.K8ssandraCluster{
+			Spec: api.K8ssandraClusterSpec{
+				Cassandra: &api.CassandraClusterTemplate{RolloutPolicy: policy},
+			},
+		}
+		if initialized {
+			kc.Status.SetCondition(api.K8ssandraClusterCondition{
+				Type:   api.CassandraInitialized,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		return kc
+	}
+
+	tests := []struct {
+		name        string
+		policy      api.RolloutPolicy
+		initialized bool
+		want        [][]string
+	}{
+		{"serial", api.RolloutSerial, false, [][]string{{"dc1"}, {"dc2"}, {"dc3"}}},
+		{"serial initialized", api.RolloutSerial, true, [][]string{{"dc1"}, {"dc2"}, {"dc3"}}},
+		{"parallel", api.RolloutParallel, false, [][]string{{"dc1", "dc2", "dc3"}}},
+		{"first dc then parallel", api.RolloutFirstDcThenParallel, false, [][]string{{"dc1"}, {"dc2", "dc3"}}},
+		{"first dc then parallel initialized", api.RolloutFirstDcThenParallel, true, [][]string{{"dc1", "dc2", "dc3"}}},
+		{"default", "", false, [][]string{{"dc1"}, {"dc2", "dc3"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, groupNames(rolloutGroups(newCluster(tt.policy, tt.initialized), dcs)))
+		})
+	}
+
+	t.Run("no datacenters", func(t *testing.T) {
+		assert.Empty(t, rolloutGroups(newCluster(api.RolloutParallel, false), nil))
+	})
+}
+
+// createMultiDcClusterInParallel verifies that all the CassandraDatacenters are created
+// without waiting for each other when the rollout policy is Parallel.
+func createMultiDcClusterInParallel(t *testing.T, ctx context.Context, f *framework.Framework, namespace string) {
+	require := require.New(t)
+
+	k8sCtx0 := "cluster-0"
+	k8sCtx1 := "cluster-1"
+	k8sCtx2 := "cluster-2"
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test",
+		},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:       "test",
+				RolloutPolicy: api.RolloutParallel,
+				ServerVersion: "3.11.10",
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &defaultStorageClass,
+					},
+				},
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc1"},
+						K8sContext: k8sCtx0,
+						Size:       3,
+					},
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc2"},
+						K8sContext: k8sCtx1,
+						Size:       3,
+					},
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc3"},
+						K8sContext: k8sCtx2,
+						Size:       3,
+					},
+				},
+			},
+		},
+	}
+
+	err := f.Client.Create(ctx, kc)
+	require.NoError(err, "failed to create K8ssandraCluster")
+
+	verifyFinalizerAdded(ctx, t, f, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+
+	verifySuperUserSecretCreated(ctx, t, f, kc)
+
+	verifyReplicatedSecretReconciled(ctx, t, f, kc)
+
+	verifySystemReplicationAnnotationSet(ctx, t, f, kc)
+
+	dcKeys := []framework.ClusterKey{
+		{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc1"}, K8sContext: k8sCtx0},
+		{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc2"}, K8sContext: k8sCtx1},
+		{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc3"}, K8sContext: k8sCtx2},
+	}
+
+	t.Log("check that all datacenters are created before any of them is ready")
+	for _, dcKey := range dcKeys {
+		require.Eventually(f.DatacenterExists(ctx, dcKey), timeout, interval, "timed out waiting for %s", dcKey)
+	}
+
+	t.Log("update the datacenters status to ready")
+	for _, dcKey := range dcKeys {
+		err = f.SetDatacenterStatusReady(ctx, dcKey)
+		require.NoError(err, "failed to set %s status ready", dcKey)
+	}
+
+	kcKey := framework.ClusterKey{K8sContext: k8sCtx0, NamespacedName: types.NamespacedName{Namespace: namespace, Name: "test"}}
+
+	t.Log("check that the K8ssandraCluster status is updated")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		if kc.Status.GetConditionStatus(api.CassandraInitialized) != corev1.ConditionTrue || len(kc.Status.Datacenters) != len(dcKeys) {
+			return false
+		}
+		for _, kdcStatus := range kc.Status.Datacenters {
+			if kdcStatus.Error != "" {
+				t.Logf("unexpected datacenter error: %s", kdcStatus.Error)
+				return false
+			}
+		}
+		return true
+	}, timeout, interval, "timed out waiting for K8ssandraCluster status update")
+
+	t.Log("deleting K8ssandraCluster")
+	err = f.DeleteK8ssandraCluster(ctx, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+	require.NoError(err, "failed to delete K8ssandraCluster")
+	for _, dcKey := range dcKeys {
+		verifyObjectDoesNotExist(ctx, t, f, dcKey, &cassdcapi.CassandraDatacenter{})
+	}
+}
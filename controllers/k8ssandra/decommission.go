@@ -0,0 +1,532 @@
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	k8ssandralabels "github.com/k8ssandra/k8ssandra-operator/pkg/labels"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/stargate"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// localKeyspaces are not replicated across datacenters, so their replication never
+// needs to be updated when a datacenter is removed.
+var localKeyspaces = map[string]bool{
+	"system":                true,
+	"system_schema":         true,
+	"system_views":          true,
+	"system_virtual_schema": true,
+}
+
+// checkDcDeletion decommissions datacenters that have been removed from the spec. A
+// datacenter is considered removed when it still has an entry in the status but no
+// longer has one in kc.Spec.Cassandra.Datacenters. Only one datacenter is decommissioned
+// at a time, and the rest of the reconciliation is blocked until it has been deleted.
+//
+// The decommission goes through the following steps, each of which is recorded in the
+// DecommissionProgress status field so that it can be resumed after a restart:
+//
+//  1. The datacenter is removed from the replication settings of all keyspaces.
+//  2. The nodes of the datacenter are decommissioned one at a time.
+//  3. The CassandraDatacenter and its Stargate and Reaper objects are deleted.
+func (r *K8ssandraClusterReconciler) checkDcDeletion(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) result.ReconcileResult {
+	dcName := getDcToDecommission(kc)
+	if dcName == "" {
+		if kc.Status.GetConditionStatus(api.DatacenterDecommissioning) == corev1.ConditionTrue {
+			setDecommissioningCondition(kc, corev1.ConditionFalse)
+		}
+		return result.Continue()
+	}
+
+	logger = logger.WithValues("DecommissionedDatacenter", dcName)
+
+	dc, remoteClient, err := r.findDcForDeletion(ctx, kc, dcName, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+
+	if dc == nil {
+		// The Stargate and Reaper objects outlive the CassandraDatacenter if it was deleted
+		// otherwise, or if a previous reconciliation was interrupted
+		if err := r.deleteOrphanedDcResources(ctx, kc, dcName, logger); err != nil {
+			return result.Error(err)
+		}
+		logger.Info("CassandraDatacenter no longer exists, removing it from status")
+		delete(kc.Status.Datacenters, dcName)
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	switch kc.Status.Datacenters[dcName].DecommissionProgress {
+	case "":
+		logger.Info("Starting decommission")
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.DecommissionStarted, "Decommissioning CassandraDatacenter %s", dcName)
+		setDecommissioningCondition(kc, corev1.ConditionTrue)
+		setDecommissionProgress(kc, dcName, api.DecommUpdatingReplication)
+	case api.DecommUpdatingReplication:
+		if recResult := r.removeDcFromReplication(ctx, kc, dcName, logger); recResult.Completed() {
+			return recResult
+		}
+		setDecommissionProgress(kc, dcName, api.DecommDecommissioning)
+	case api.DecommDecommissioning:
+		if recResult := r.decommissionNodes(ctx, kc, dc, remoteClient, logger); recResult.Completed() {
+			return recResult
+		}
+		setDecommissionProgress(kc, dcName, api.DecommDeleting)
+	case api.DecommDeleting:
+		if err := r.deleteDcResources(ctx, kc, dc, remoteClient, logger); err != nil {
+			return result.Error(err)
+		}
+		logger.Info("Datacenter decommissioned")
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.DecommissionCompleted, "Decommissioned CassandraDatacenter %s", dcName)
+		delete(kc.Status.Datacenters, dcName)
+	}
+
+	return result.RequeueSoon(r.DefaultDelay)
+}
+
+// getDcToDecommission returns the name of the first datacenter, in alphabetical order,
+// that is present in the status but not in the spec. It returns an empty string if
+// there is no such datacenter.
+func getDcToDecommission(kc *api.K8ssandraCluster) string {
+	dcNames := make([]string, 0, len(kc.Status.Datacenters))
+	for dcName := range kc.Status.Datacenters {
+		dcNames = append(dcNames, dcName)
+	}
+	sort.Strings(dcNames)
+
+	for _, dcName := range dcNames {
+		found := false
+		for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+			if dcTemplate.Meta.Name == dcName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return dcName
+		}
+	}
+	return ""
+}
+
+func setDecommissionProgress(kc *api.K8ssandraCluster, dcName string, progress api.DecommissionProgress) {
+	kdcStatus := kc.Status.Datacenters[dcName]
+	kdcStatus.DecommissionProgress = progress
+	kc.Status.Datacenters[dcName] = kdcStatus
+}
+
+func setDecommissioningCondition(kc *api.K8ssandraCluster, status corev1.ConditionStatus) {
+	now := metav1.Now()
+	kc.Status.SetCondition(api.K8ssandraClusterCondition{
+		Type:               api.DatacenterDecommissioning,
+		Status:             status,
+		LastTransitionTime: &now,
+	})
+}
+
+// findDcForDeletion searches all known clusters for the CassandraDatacenter named dcName
+// that belongs to kc. The spec no longer tells us in which cluster the datacenter lives,
+// so we have to look for it. It returns nil if the CassandraDatacenter does not exist.
+func (r *K8ssandraClusterReconciler) findDcForDeletion(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	dcName string,
+	logger logr.Logger,
+) (*cassdcapi.CassandraDatacenter, client.Client, error) {
+	selector := k8ssandralabels.CreatedByK8ssandraControllerLabels(utils.GetKey(kc))
+
+	for _, k8sContext := range r.knownK8sContexts() {
+		remoteClient, err := r.ClientCache.GetRemoteClient(k8sContext)
+		if err != nil {
+			logger.Error(err, "Failed to get remote client", "K8sContext", k8sContext)
+			return nil, nil, err
+		}
+
+		dcList := &cassdcapi.CassandraDatacenterList{}
+		if err := remoteClient.List(ctx, dcList, client.MatchingLabels(selector)); err != nil {
+			logger.Error(err, "Failed to list CassandraDatacenters", "K8sContext", k8sContext)
+			return nil, nil, err
+		}
+
+		for _, dc := range dcList.Items {
+			if dc.Name == dcName {
+				return &dc, remoteClient, nil
+			}
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// knownK8sContexts returns the contexts of all the known clusters, sorted by name, followed
+// by the empty context of the local cluster.
+func (r *K8ssandraClusterReconciler) knownK8sContexts() []string {
+	remoteClients := r.ClientCache.GetRemoteClients()
+	k8sContexts := make([]string, 0, len(remoteClients)+1)
+	for k8sContext := range remoteClients {
+		k8sContexts = append(k8sContexts, k8sContext)
+	}
+	sort.Strings(k8sContexts)
+	// The local client is used for datacenters that do not specify a K8sContext
+	return append(k8sContexts, "")
+}
+
+// findReadyDatacenter returns the first datacenter declared in the spec that exists and
+// is ready, along with the client for its cluster. It returns a nil datacenter if no
+// ready datacenter can be found.
+func findReadyDatacenter(
+	ctx context.Context,
+	clientCache *clientcache.ClientCache,
+	kc *api.K8ssandraCluster,
+	logger logr.Logger,
+) (*cassdcapi.CassandraDatacenter, client.Client, error) {
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		remoteClient, err := clientCache.GetRemoteClient(dcTemplate.K8sContext)
+		if err != nil {
+			logger.Error(err, "Failed to get remote client", "K8sContext", dcTemplate.K8sContext)
+			return nil, nil, err
+		}
+
+		namespace := kc.Namespace
+		if dcTemplate.Meta.Namespace != "" {
+			namespace = dcTemplate.Meta.Namespace
+		}
+
+		dc := &cassdcapi.CassandraDatacenter{}
+		dcKey := client.ObjectKey{Namespace: namespace, Name: dcTemplate.Meta.Name}
+		if err := remoteClient.Get(ctx, dcKey, dc); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			logger.Error(err, "Failed to get CassandraDatacenter", "CassandraDatacenter", dcKey)
+			return nil, nil, err
+		}
+
+		if cassandra.DatacenterReady(dc) {
+			return dc, remoteClient, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// removeDcFromReplication removes dcName from the replication settings of every keyspace
+// that uses NetworkTopologyStrategy. This needs to happen before the nodes are
+// decommissioned, otherwise Cassandra refuses to decommission them.
+func (r *K8ssandraClusterReconciler) removeDcFromReplication(ctx context.Context, kc *api.K8ssandraCluster, dcName string, logger logr.Logger) result.ReconcileResult {
+	dc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	if dc == nil {
+		logger.Info("Waiting for a datacenter to become ready before updating replication")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, dc, remoteClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		return result.Error(err)
+	}
+
+	keyspaces, err := managementApi.ListKeyspaces("")
+	if err != nil {
+		logger.Error(err, "Failed to list keyspaces")
+		return result.Error(err)
+	}
+
+	for _, keyspace := range keyspaces {
+		if localKeyspaces[keyspace] {
+			continue
+		}
+
+		replication, err := managementApi.GetKeyspaceReplication(keyspace)
+		if err != nil {
+			logger.Error(err, "Failed to get keyspace replication", "Keyspace", keyspace)
+			return result.Error(err)
+		}
+
+		dcReplication, ok := cassandra.ParseReplication(replication)
+		if !ok {
+			continue
+		}
+		if _, found := dcReplication[dcName]; !found {
+			continue
+		}
+
+		delete(dcReplication, dcName)
+		if len(dcReplication) == 0 {
+			err = fmt.Errorf("cannot remove datacenter %s from keyspace %s because it is the only datacenter that replicates it", dcName, keyspace)
+			logger.Error(err, "Failed to update keyspace replication", "Keyspace", keyspace)
+			return result.Error(err)
+		}
+
+		logger.Info("Removing datacenter from keyspace replication", "Keyspace", keyspace)
+		if err := managementApi.AlterKeyspace(keyspace, dcReplication); err != nil {
+			logger.Error(err, "Failed to update keyspace replication", "Keyspace", keyspace)
+			return result.Error(err)
+		}
+	}
+
+	return result.Continue()
+}
+
+// decommissionNodes decommissions the nodes of dc one at a time. The gossip state is read
+// from one of the remaining datacenters since the nodes of dc are not reliable once
+// they start leaving the cluster. A node that is not in the ring anymore has left.
+// result.Continue() is returned once all nodes have left.
+func (r *K8ssandraClusterReconciler) decommissionNodes(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) result.ReconcileResult {
+	pods := &corev1.PodList{}
+	if err := remoteClient.List(ctx, pods, client.InNamespace(dc.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: dc.Name}); err != nil {
+		logger.Error(err, "Failed to list datacenter pods")
+		return result.Error(err)
+	}
+
+	if len(pods.Items) == 0 {
+		return result.Continue()
+	}
+
+	liveDc, liveClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		return result.Error(err)
+	}
+	if liveDc == nil {
+		logger.Info("Waiting for a datacenter to become ready before decommissioning nodes")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	liveManagementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, liveDc, liveClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		return result.Error(err)
+	}
+
+	nodes, err := liveManagementApi.GetRingStatus()
+	if err != nil {
+		logger.Error(err, "Failed to get ring status")
+		return result.Error(err)
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		hostId := dc.Status.NodeStatuses[pod.Name].HostID
+		node := findNode(nodes, hostId, pod.Status.PodIP)
+
+		switch {
+		case node == nil && hostId == "":
+			// The node never joined the cluster
+			continue
+		case node == nil && len(nodes) > 0:
+			// The nodes are found by host ID whatever their address, a node that is not in
+			// the ring has left the cluster and its gossip state has expired, or it was
+			// removed
+			logger.Info("Node is not in the ring anymore", "Pod", pod.Name, "HostId", hostId)
+			continue
+		case node == nil:
+			// The ring is empty while the live datacenter is part of it, its gossip state
+			// has to tell whether the node left before the datacenter can be deleted
+			logger.Info("Waiting for the gossip state of node", "Pod", pod.Name, "HostId", hostId)
+			return result.RequeueSoon(r.DefaultDelay)
+		case node.State == "LEFT":
+			continue
+		case node.State == "LEAVING":
+			logger.Info("Waiting for node to leave the cluster", "Pod", pod.Name)
+			return result.RequeueSoon(r.DefaultDelay)
+		default:
+			managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, dc, remoteClient, logger)
+			if err != nil {
+				logger.Error(err, "Failed to create ManagementApiFacade")
+				return result.Error(err)
+			}
+			logger.Info("Decommissioning node", "Pod", pod.Name)
+			if err := managementApi.DecommissionNode(pod); err != nil {
+				return result.Error(err)
+			}
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+	}
+
+	return result.Continue()
+}
+
+// findNode returns the node of nodes whose host ID is hostId, or whose internal address is
+// address if hostId is unknown or not found. It returns nil if there is no such node.
+func findNode(nodes []cassandra.NodeStatus, hostId, address string) *cassandra.NodeStatus {
+	for i := range nodes {
+		if hostId != "" && nodes[i].HostId == hostId {
+			return &nodes[i]
+		}
+	}
+	for i := range nodes {
+		if address != "" && nodes[i].Endpoint == address {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// deleteDcResources deletes the Stargate and Reaper objects of dc, the seeds Endpoints
+// that we created for it, and finally dc itself.
+func (r *K8ssandraClusterReconciler) deleteDcResources(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) error {
+	kcKey := utils.GetKey(kc)
+
+	sg := &stargateapi.Stargate{}
+	sgKey := client.ObjectKey{Namespace: dc.Namespace, Name: stargate.ResourceName(kc, dc)}
+	if err := r.deleteIfCreatedBy(ctx, kcKey, sgKey, sg, remoteClient); err != nil {
+		logger.Error(err, "Failed to delete Stargate", "Stargate", sgKey)
+		return err
+	}
+
+	rp := &reaperapi.Reaper{}
+	rpKey := client.ObjectKey{Namespace: dc.Namespace, Name: reaper.ResourceName(kc.Name, dc.Name)}
+	if err := r.deleteIfCreatedBy(ctx, kcKey, rpKey, rp, remoteClient); err != nil {
+		logger.Error(err, "Failed to delete Reaper", "Reaper", rpKey)
+		return err
+	}
+
+	endpoints := &corev1.Endpoints{}
+	endpointsKey := client.ObjectKey{Namespace: dc.Namespace, Name: dc.GetAdditionalSeedsServiceName()}
+	if err := remoteClient.Get(ctx, endpointsKey, endpoints); err == nil {
+		if err = remoteClient.Delete(ctx, endpoints); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete endpoints", "Endpoints", endpointsKey)
+			return err
+		}
+	} else if !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to get endpoints", "Endpoints", endpointsKey)
+		return err
+	}
+
+	if err := remoteClient.Delete(ctx, dc); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete CassandraDatacenter")
+		return err
+	}
+
+	return nil
+}
+
+// deleteOrphanedDcResources deletes the Stargate and Reaper objects and the seeds Endpoints
+// of the datacenter named dcName once its CassandraDatacenter no longer exists. They are
+// searched in all the known clusters since the spec no longer tells in which one the
+// datacenter lived.
+func (r *K8ssandraClusterReconciler) deleteOrphanedDcResources(ctx context.Context, kc *api.K8ssandraCluster, dcName string, logger logr.Logger) error {
+	selector := k8ssandralabels.CreatedByK8ssandraControllerLabels(utils.GetKey(kc))
+	// The seeds Endpoints are named and labeled after the datacenter
+	removedDc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: dcName},
+		Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: kc.Spec.Cassandra.Cluster},
+	}
+
+	for _, k8sContext := range r.knownK8sContexts() {
+		remoteClient, err := r.ClientCache.GetRemoteClient(k8sContext)
+		if err != nil {
+			logger.Error(err, "Failed to get remote client", "K8sContext", k8sContext)
+			return err
+		}
+
+		stargates := &stargateapi.StargateList{}
+		if err := remoteClient.List(ctx, stargates, client.MatchingLabels(selector)); err != nil {
+			logger.Error(err, "Failed to list Stargates", "K8sContext", k8sContext)
+			return err
+		}
+		for i := range stargates.Items {
+			sg := &stargates.Items[i]
+			if sg.Spec.DatacenterRef.Name != dcName {
+				continue
+			}
+			if err := remoteClient.Delete(ctx, sg); err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete Stargate", "Stargate", utils.GetKey(sg))
+				return err
+			}
+		}
+
+		reapers := &reaperapi.ReaperList{}
+		if err := remoteClient.List(ctx, reapers, client.MatchingLabels(selector)); err != nil {
+			logger.Error(err, "Failed to list Reapers", "K8sContext", k8sContext)
+			return err
+		}
+		for i := range reapers.Items {
+			rp := &reapers.Items[i]
+			if rp.Spec.DatacenterRef.Name != dcName {
+				continue
+			}
+			if err := remoteClient.Delete(ctx, rp); err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete Reaper", "Reaper", utils.GetKey(rp))
+				return err
+			}
+		}
+
+		endpoints := &corev1.EndpointsList{}
+		if err := remoteClient.List(ctx, endpoints, client.MatchingLabels(removedDc.GetDatacenterLabels())); err != nil {
+			logger.Error(err, "Failed to list endpoints", "K8sContext", k8sContext)
+			return err
+		}
+		for i := range endpoints.Items {
+			if endpoints.Items[i].Name != removedDc.GetAdditionalSeedsServiceName() {
+				continue
+			}
+			if err := remoteClient.Delete(ctx, &endpoints.Items[i]); err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete endpoints", "Endpoints", utils.GetKey(&endpoints.Items[i]))
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteIfCreatedBy deletes the object with the given key if it exists and was created
+// by the K8ssandraCluster with key kcKey.
+func (r *K8ssandraClusterReconciler) deleteIfCreatedBy(
+	ctx context.Context,
+	kcKey client.ObjectKey,
+	key client.ObjectKey,
+	obj client.Object,
+	remoteClient client.Client,
+) error {
+	if err := remoteClient.Get(ctx, key, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !k8ssandralabels.IsCreatedByK8ssandraController(obj, kcKey) {
+		return nil
+	}
+
+	if err := remoteClient.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
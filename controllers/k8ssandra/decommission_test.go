@@ -0,0 +1,341 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	"github.com/k8ssandra/k8ssandra-operator/test/framework"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// removeDatacenter creates a two-dc cluster, then removes dc2 from the spec and verifies
+// that dc2 is decommissioned and deleted.
+func removeDatacenter(t *testing.T, ctx context.Context, f *framework.Framework, namespace string) {
+	require := require.New(t)
+
+	k8sCtx0 := "cluster-0"
+	k8sCtx1 := "cluster-1"
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test",
+		},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster: "test",
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{
+						Meta: api.EmbeddedObjectMeta{
+							Name: "dc1",
+						},
+						K8sContext:    k8sCtx0,
+						Size:          3,
+						ServerVersion: "3.11.10",
+						StorageConfig: &cassdcapi.StorageConfig{
+							CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+								StorageClassName: &defaultStorageClass,
+							},
+						},
+					},
+					{
+						Meta: api.EmbeddedObjectMeta{
+							Name: "dc2",
+						},
+						K8sContext:    k8sCtx1,
+						Size:          3,
+						ServerVersion: "3.11.10",
+						StorageConfig: &cassdcapi.StorageConfig{
+							CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+								StorageClassName: &defaultStorageClass,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := f.Client.Create(ctx, kc)
+	require.NoError(err, "failed to create K8ssandraCluster")
+
+	verifyFinalizerAdded(ctx, t, f, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+
+	verifySuperUserSecretCreated(ctx, t, f, kc)
+
+	verifyReplicatedSecretReconciled(ctx, t, f, kc)
+
+	verifySystemReplicationAnnotationSet(ctx, t, f, kc)
+
+	dc1Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc1"}, K8sContext: k8sCtx0}
+	dc2Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc2"}, K8sContext: k8sCtx1}
+	kcKey := framework.ClusterKey{K8sContext: k8sCtx0, NamespacedName: types.NamespacedName{Namespace: namespace, Name: "test"}}
+
+	t.Log("check that dc1 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc1Key), timeout, interval)
+
+	t.Log("update dc1 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc1Key)
+	require.NoError(err, "failed to set dc1 status ready")
+
+	t.Log("check that dc2 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc2Key), timeout, interval)
+
+	t.Log("update dc2 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc2Key)
+	require.NoError(err, "failed to set dc2 status ready")
+
+	t.Log("check that the K8ssandraCluster status is updated")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		return kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionTrue && len(kc.Status.Datacenters) == 2
+	}, timeout, interval, "timed out waiting for K8ssandraCluster status update")
+
+	t.Log("remove dc2 from the K8ssandraCluster")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kc.Spec.Cassandra.Datacenters = kc.Spec.Cassandra.Datacenters[:1]
+		if err := f.Client.Update(ctx, kc); err != nil {
+			t.Logf("failed to update K8ssandraCluster: %v", err)
+			return false
+		}
+		return true
+	}, timeout, interval, "timed out updating K8ssandraCluster")
+
+	t.Log("check that dc2 was deleted")
+	verifyObjectDoesNotExist(ctx, t, f, dc2Key, &cassdcapi.CassandraDatacenter{})
+
+	t.Log("check that dc2 was removed from the K8ssandraCluster status")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		if _, found := kc.Status.Datacenters[dc2Key.Name]; found {
+			return false
+		}
+		return kc.Status.GetConditionStatus(api.DatacenterDecommissioning) == corev1.ConditionFalse
+	}, timeout, interval, "timed out waiting for dc2 to be removed from the K8ssandraCluster status")
+
+	t.Log("check that dc1 still exists")
+	require.True(f.DatacenterExists(ctx, dc1Key)(), "dc1 should not be deleted")
+
+	t.Log("deleting K8ssandraCluster")
+	err = f.DeleteK8ssandraCluster(ctx, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+	require.NoError(err, "failed to delete K8ssandraCluster")
+	verifyObjectDoesNotExist(ctx, t, f, dc1Key, &cassdcapi.CassandraDatacenter{})
+}
+
+func TestDecommissionNodes(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Datacenters: []api.CassandraDatacenterTemplate{{Meta: api.EmbeddedObjectMeta{Name: "dc1"}}},
+			},
+		},
+	}
+	dc1 := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+		Status:     *readyDatacenterStatus(),
+	}
+	dc2 := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc2"},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			NodeStatuses: cassdcapi.CassandraStatusMap{
+				"dc2-pod-0": {HostID: "host-0"},
+				"dc2-pod-1": {HostID: "host-1"},
+			},
+		},
+	}
+	newPod := func(name, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc2"},
+			},
+			Status: corev1.PodStatus{PodIP: ip},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		nodes            []cassandra.NodeStatus
+		wantDecommission string
+		wantRequeue      bool
+	}{
+		{
+			name: "nodes are found by host id",
+			nodes: []cassandra.NodeStatus{
+				{HostId: "host-0", Endpoint: "10.0.0.10", State: "LEFT"},
+				{HostId: "host-1", Endpoint: "10.0.0.11", State: "NORMAL"},
+			},
+			wantDecommission: "dc2-pod-1",
+			wantRequeue:      true,
+		},
+		{
+			name: "nodes are found by internal address",
+			nodes: []cassandra.NodeStatus{
+				{HostId: "other-0", Endpoint: "10.0.0.1", State: "NORMAL"},
+			},
+			wantDecommission: "dc2-pod-0",
+			wantRequeue:      true,
+		},
+		{
+			name: "node is leaving",
+			nodes: []cassandra.NodeStatus{
+				{HostId: "host-0", State: "LEAVING"},
+			},
+			wantRequeue: true,
+		},
+		{
+			name: "node is not in the ring anymore",
+			nodes: []cassandra.NodeStatus{
+				{HostId: "host-1", State: "LEFT"},
+			},
+		},
+		{
+			name:        "ring status is empty",
+			nodes:       []cassandra.NodeStatus{},
+			wantRequeue: true,
+		},
+		{
+			name: "all the nodes left",
+			nodes: []cassandra.NodeStatus{
+				{HostId: "host-0", State: "LEFT"},
+				{HostId: "host-1", State: "LEFT"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// dc2-pod-2 never joined the cluster
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(dc1, newPod("dc2-pod-0", "10.0.0.1"), newPod("dc2-pod-1", "10.0.0.2"), newPod("dc2-pod-2", "")).
+				Build()
+			managementApi := new(mocks.ManagementApiFacade)
+			managementApi.On("GetRingStatus").Return(tc.nodes, nil)
+			if tc.wantDecommission != "" {
+				managementApi.On("DecommissionNode", mock.MatchedBy(podNamed(tc.wantDecommission))).Return(nil).Once()
+			}
+			r := &K8ssandraClusterReconciler{
+				ReconcilerConfig: config.InitConfig(),
+				ClientCache:      clientcache.New(c, c, scheme),
+				ManagementApi:    &mockManagementApiFactory{managementApi: managementApi},
+			}
+
+			recResult := r.decommissionNodes(ctx, kc, dc2, c, logr.Discard())
+			assert.Equal(t, tc.wantRequeue, recResult.Completed())
+			managementApi.AssertExpectations(t)
+			if tc.wantDecommission == "" {
+				managementApi.AssertNotCalled(t, "DecommissionNode", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCheckDcDeletionWithoutDatacenter(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, stargateapi.AddToScheme(scheme))
+	require.NoError(t, reaperapi.AddToScheme(scheme))
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:     "test",
+				Datacenters: []api.CassandraDatacenterTemplate{{Meta: api.EmbeddedObjectMeta{Name: "dc1"}}},
+			},
+		},
+		Status: api.K8ssandraClusterStatus{
+			Datacenters: map[string]api.K8ssandraStatus{
+				"dc1": {},
+				"dc2": {DecommissionProgress: api.DecommDeleting},
+			},
+		},
+	}
+	createdBy := labels.CreatedByK8ssandraControllerLabels(utils.GetKey(kc))
+	objects := []client.Object{
+		&stargateapi.Stargate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dc2-ns", Name: "test-dc2-stargate", Labels: createdBy},
+			Spec:       stargateapi.StargateSpec{DatacenterRef: corev1.LocalObjectReference{Name: "dc2"}},
+		},
+		&stargateapi.Stargate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-dc1-stargate", Labels: createdBy},
+			Spec:       stargateapi.StargateSpec{DatacenterRef: corev1.LocalObjectReference{Name: "dc1"}},
+		},
+		&reaperapi.Reaper{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dc2-ns", Name: "test-dc2-reaper", Labels: createdBy},
+			Spec:       reaperapi.ReaperSpec{DatacenterRef: reaperapi.CassandraDatacenterRef{Name: "dc2"}},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "dc2-ns",
+				Name:      "test-dc2-additional-seed-service",
+				Labels:    map[string]string{cassdcapi.ClusterLabel: "test", cassdcapi.DatacenterLabel: "dc2"},
+			},
+		},
+	}
+	localClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	remoteClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	clientCache := clientcache.New(localClient, localClient, scheme)
+	clientCache.AddClient("cluster-1", remoteClient)
+	r := &K8ssandraClusterReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		ClientCache:      clientCache,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	recResult := r.checkDcDeletion(ctx, kc, logr.Discard())
+	assert.True(t, recResult.Completed())
+	assert.NotContains(t, kc.Status.Datacenters, "dc2")
+
+	err := remoteClient.Get(ctx, types.NamespacedName{Namespace: "dc2-ns", Name: "test-dc2-stargate"}, &stargateapi.Stargate{})
+	assert.True(t, errors.IsNotFound(err), "the Stargate of dc2 should be deleted")
+	err = remoteClient.Get(ctx, types.NamespacedName{Namespace: "dc2-ns", Name: "test-dc2-reaper"}, &reaperapi.Reaper{})
+	assert.True(t, errors.IsNotFound(err), "the Reaper of dc2 should be deleted")
+	err = remoteClient.Get(ctx, types.NamespacedName{Namespace: "dc2-ns", Name: "test-dc2-additional-seed-service"}, &corev1.Endpoints{})
+	assert.True(t, errors.IsNotFound(err), "the seeds Endpoints of dc2 should be deleted")
+	assert.NoError(t, remoteClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-dc1-stargate"}, &stargateapi.Stargate{}))
+}
@@ -0,0 +1,525 @@
+package k8ssandra
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/secret"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// keystorePasswordKey is the key of the password in the keystore password Secret.
+const keystorePasswordKey = "password"
+
+var (
+	certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+	issuerGVK      = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}
+)
+
+func caSecretName(kc *api.K8ssandraCluster) string {
+	return kc.Name + "-ca"
+}
+
+func keystorePasswordSecretName(kc *api.K8ssandraCluster) string {
+	return kc.Name + "-keystore-password"
+}
+
+func storesSecretName(kc *api.K8ssandraCluster, dcName string) string {
+	return kc.Name + "-" + dcName + "-keystore"
+}
+
+func certificateSecretName(kc *api.K8ssandraCluster, dcName string) string {
+	return kc.Name + "-" + dcName + "-certificate"
+}
+
+// reconcileEncryptionStores issues the certificates of the datacenters of kc and stores them,
+// along with the CA certificates, in a keystore and a truststore. The stores of each
+// datacenter are written to a Secret that is replicated with the other secrets of kc. It
+// also returns how long until the operator has to renew a certificate, or zero if it does
+// not have to.
+func (r *K8ssandraClusterReconciler) reconcileEncryptionStores(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) (result.ReconcileResult, time.Duration) {
+	tls := kc.Spec.Cassandra.TLS
+	if !tls.IsEnabled() {
+		return result.Continue(), 0
+	}
+
+	logger.Info("Reconciling encryption stores")
+	password, err := r.reconcileKeystorePassword(ctx, kc, logger)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile the keystore password")
+		r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.EncryptionStoresFailed,
+			"Failed to reconcile the keystore password: %v", err)
+		return result.Error(err), 0
+	}
+
+	if tls.GetProvider() == api.CertificateProviderCertManager {
+		// cert-manager renews the certificates, the new ones are picked up when it updates
+		// their Secrets.
+		return r.reconcileCertManagerStores(ctx, kc, password, logger), 0
+	}
+	return r.reconcileInternalStores(ctx, kc, password, logger)
+}
+
+// reconcileKeystorePassword returns the password of the keystores and truststores of kc. It
+// is generated if its Secret does not exist.
+func (r *K8ssandraClusterReconciler) reconcileKeystorePassword(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) ([]byte, error) {
+	key := types.NamespacedName{Namespace: kc.Namespace, Name: keystorePasswordSecretName(kc)}
+	passwordSecret := &corev1.Secret{}
+	if err := r.Get(ctx, key, passwordSecret); err == nil {
+		if password := passwordSecret.Data[keystorePasswordKey]; len(password) > 0 {
+			return password, nil
+		}
+		return nil, fmt.Errorf("Secret %s has no %s key", key, keystorePasswordKey)
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	password, err := secret.GeneratePassword()
+	if err != nil {
+		return nil, err
+	}
+	passwordSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string][]byte{keystorePasswordKey: password},
+	}
+	logger.Info("Creating keystore password", "Secret", key)
+	return password, r.createOwnedSecret(ctx, kc, passwordSecret)
+}
+
+// reconcileInternalStores issues the certificates of the datacenters with the CA of kc.
+// A certificate is issued again when it is about to expire, unless it expires with the CA:
+// the CA must be renewed first.
+func (r *K8ssandraClusterReconciler) reconcileInternalStores(ctx context.Context, kc *api.K8ssandraCluster, password []byte, logger logr.Logger) (result.ReconcileResult, time.Duration) {
+	tls := kc.Spec.Cassandra.TLS
+	ca, err := r.reconcileCA(ctx, kc, logger)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile the CA")
+		r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.EncryptionStoresFailed,
+			"Failed to reconcile the CA: %v", err)
+		return result.Error(err), 0
+	}
+
+	var renewIn time.Duration
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		dc := datacenterForCertificate(kc, dcTemplate)
+		key := types.NamespacedName{Namespace: kc.Namespace, Name: storesSecretName(kc, dc.Name)}
+		actual := &corev1.Secret{}
+		if err := r.Get(ctx, key, actual); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to get encryption stores", "Secret", key)
+			return result.Error(err), 0
+		}
+
+		certificatePEM := actual.Data[encryption.CertificateKey]
+		if !internalStoresUpToDate(actual.Data, ca, password, tls.GetRenewBefore()) {
+			logger.Info("Issuing certificate", "CassandraDatacenter", dc.Name)
+			data, err := issueInternalStores(ca, dc, password, tls.GetCertificateDuration())
+			if err == nil {
+				err = r.writeStoresSecret(ctx, kc, key, data)
+			}
+			if err != nil {
+				logger.Error(err, "Failed to issue certificate", "CassandraDatacenter", dc.Name)
+				r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.EncryptionStoresFailed,
+					"Failed to issue the certificate of CassandraDatacenter %s: %v", dc.Name, err)
+				return result.Error(err), 0
+			}
+			r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.EncryptionStoresIssued,
+				"Issued the certificate of CassandraDatacenter %s", dc.Name)
+			certificatePEM = data[encryption.CertificateKey]
+		}
+
+		if certificate, err := encryption.ParseCertificate(certificatePEM); err == nil && certificate.NotAfter.Before(ca.Certificate.NotAfter) {
+			if d := time.Until(certificate.NotAfter.Add(-tls.GetRenewBefore())); d > 0 && (renewIn == 0 || d < renewIn) {
+				renewIn = d
+			}
+		}
+	}
+	return result.Continue(), renewIn
+}
+
+// reconcileCA returns the CA of kc. It is generated if its Secret does not exist.
+func (r *K8ssandraClusterReconciler) reconcileCA(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) (*encryption.CA, error) {
+	key := types.NamespacedName{Namespace: kc.Namespace, Name: caSecretName(kc)}
+	caSecret := &corev1.Secret{}
+	if err := r.Get(ctx, key, caSecret); err == nil {
+		return encryption.ParseCA(caSecret.Data[encryption.CertificateKey], caSecret.Data[encryption.PrivateKeyKey])
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	ca, err := encryption.NewCA(key.Name, api.DefaultCADuration)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		return nil, err
+	}
+	caSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			encryption.CertificateKey: ca.CertificatePEM(),
+			encryption.PrivateKeyKey:  keyPEM,
+		},
+	}
+	logger.Info("Creating CA", "Secret", key)
+	return ca, r.createOwnedSecret(ctx, kc, caSecret)
+}
+
+// internalStoresUpToDate returns true if data holds JKS stores protected by password, with a
+// certificate issued by ca that does not need to be renewed yet, along with its private key.
+func internalStoresUpToDate(data map[string][]byte, ca *encryption.CA, password []byte, renewBefore time.Duration) bool {
+	stores := encryption.Stores{Type: encryption.StoreTypeJKS}
+	if len(data[stores.KeystoreKey()]) == 0 || len(data[stores.TruststoreKey()]) == 0 || len(data[encryption.PrivateKeyKey]) == 0 {
+		return false
+	}
+	if !bytes.Equal(data[encryption.PasswordKey], password) || !bytes.Equal(data[encryption.CAKey], ca.CertificatePEM()) {
+		return false
+	}
+	certificate, err := encryption.ParseCertificate(data[encryption.CertificateKey])
+	if err != nil {
+		return false
+	}
+	// A certificate that expires with the CA cannot be renewed until the CA is.
+	return !certificate.NotAfter.Before(ca.Certificate.NotAfter) ||
+		!encryption.NeedsRenewal(data[encryption.CertificateKey], renewBefore)
+}
+
+// issueInternalStores issues a certificate for dc with ca, and returns the content of the
+// stores Secret of dc.
+func issueInternalStores(ca *encryption.CA, dc *cassdcapi.CassandraDatacenter, password []byte, duration time.Duration) (map[string][]byte, error) {
+	certificatePEM, keyPEM, err := ca.Issue(dc.Name, cassandra.CertificateDNSNames(dc), duration)
+	if err != nil {
+		return nil, err
+	}
+	caPEM := ca.CertificatePEM()
+	keystore, truststore, err := encryption.NewJKSStores(certificatePEM, keyPEM, caPEM, password)
+	if err != nil {
+		return nil, err
+	}
+	stores := encryption.Stores{Type: encryption.StoreTypeJKS}
+	return map[string][]byte{
+		encryption.CertificateKey: certificatePEM,
+		encryption.PrivateKeyKey:  keyPEM,
+		encryption.CAKey:          caPEM,
+		encryption.PasswordKey:    password,
+		stores.KeystoreKey():      keystore,
+		stores.TruststoreKey():    truststore,
+	}, nil
+}
+
+// reconcileCertManagerStores creates the cert-manager Certificates of the datacenters, and
+// copies the stores that cert-manager generates into the stores Secrets. It requeues until
+// cert-manager has issued all the certificates.
+func (r *K8ssandraClusterReconciler) reconcileCertManagerStores(ctx context.Context, kc *api.K8ssandraCluster, password []byte, logger logr.Logger) result.ReconcileResult {
+	tls := kc.Spec.Cassandra.TLS
+	issuerRef := tls.IssuerRef
+	if issuerRef == nil {
+		var err error
+		if issuerRef, err = r.reconcileCAIssuer(ctx, kc, logger); err != nil {
+			logger.Error(err, "Failed to reconcile the CA issuer")
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.EncryptionStoresFailed,
+				"Failed to reconcile the CA issuer: %v", err)
+			return result.Error(err)
+		}
+	}
+
+	stores := encryption.Stores{Type: tls.GetKeystoreFormat()}
+	pending := false
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		dc := datacenterForCertificate(kc, dcTemplate)
+		certificate := newDatacenterCertificate(kc, dc, issuerRef)
+		if err := r.reconcileCertManagerObject(ctx, kc, certificate, logger); err != nil {
+			logger.Error(err, "Failed to reconcile Certificate", "Certificate", certificate.GetName())
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.EncryptionStoresFailed,
+				"Failed to reconcile the Certificate of CassandraDatacenter %s: %v", dc.Name, err)
+			return result.Error(err)
+		}
+
+		certificateKey := types.NamespacedName{Namespace: kc.Namespace, Name: certificateSecretName(kc, dc.Name)}
+		certificateSecret := &corev1.Secret{}
+		if err := r.Get(ctx, certificateKey, certificateSecret); err != nil {
+			if errors.IsNotFound(err) {
+				logger.Info("Waiting for cert-manager to issue the certificate", "Certificate", certificate.GetName())
+				pending = true
+				continue
+			}
+			logger.Error(err, "Failed to get certificate", "Secret", certificateKey)
+			return result.Error(err)
+		}
+		issued := certificateSecret.Data
+		if len(issued[stores.KeystoreKey()]) == 0 || len(issued[stores.TruststoreKey()]) == 0 {
+			logger.Info("Waiting for cert-manager to create the stores", "Certificate", certificate.GetName())
+			pending = true
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: kc.Namespace, Name: storesSecretName(kc, dc.Name)}
+		data := map[string][]byte{
+			encryption.CertificateKey: issued[encryption.CertificateKey],
+			encryption.PrivateKeyKey:  issued[encryption.PrivateKeyKey],
+			encryption.CAKey:          issued[encryption.CAKey],
+			encryption.PasswordKey:    password,
+			stores.KeystoreKey():      issued[stores.KeystoreKey()],
+			stores.TruststoreKey():    issued[stores.TruststoreKey()],
+		}
+		if err := r.writeStoresSecret(ctx, kc, key, data); err != nil {
+			logger.Error(err, "Failed to write encryption stores", "Secret", key)
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.EncryptionStoresFailed,
+				"Failed to write the encryption stores of CassandraDatacenter %s: %v", dc.Name, err)
+			return result.Error(err)
+		}
+	}
+
+	if pending {
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+	return result.Continue()
+}
+
+// reconcileCAIssuer creates a self-signed CA for kc with cert-manager, and returns a reference
+// to the Issuer of the certificates that are signed by it.
+func (r *K8ssandraClusterReconciler) reconcileCAIssuer(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) (*api.IssuerReference, error) {
+	selfSignedIssuer := newCertManagerObject(issuerGVK, kc.Namespace, kc.Name+"-selfsigned-issuer", map[string]interface{}{
+		"selfSigned": map[string]interface{}{},
+	})
+	caCertificate := newCertManagerObject(certificateGVK, kc.Namespace, caSecretName(kc), map[string]interface{}{
+		"isCA":       true,
+		"commonName": caSecretName(kc),
+		"secretName": caSecretName(kc),
+		"duration":   api.DefaultCADuration.String(),
+		"privateKey": map[string]interface{}{"algorithm": "RSA", "size": int64(2048)},
+		"issuerRef":  issuerRefSpec(&api.IssuerReference{Name: selfSignedIssuer.GetName(), Kind: issuerGVK.Kind}),
+	})
+	caIssuer := newCertManagerObject(issuerGVK, kc.Namespace, kc.Name+"-ca-issuer", map[string]interface{}{
+		"ca": map[string]interface{}{"secretName": caSecretName(kc)},
+	})
+	for _, obj := range []*unstructured.Unstructured{selfSignedIssuer, caCertificate, caIssuer} {
+		if err := r.reconcileCertManagerObject(ctx, kc, obj, logger); err != nil {
+			return nil, err
+		}
+	}
+	return &api.IssuerReference{Name: caIssuer.GetName(), Kind: issuerGVK.Kind}, nil
+}
+
+func newDatacenterCertificate(kc *api.K8ssandraCluster, dc *cassdcapi.CassandraDatacenter, issuerRef *api.IssuerReference) *unstructured.Unstructured {
+	tls := kc.Spec.Cassandra.TLS
+	dnsNames := make([]interface{}, 0)
+	for _, dnsName := range cassandra.CertificateDNSNames(dc) {
+		dnsNames = append(dnsNames, dnsName)
+	}
+	return newCertManagerObject(certificateGVK, kc.Namespace, kc.Name+"-"+dc.Name, map[string]interface{}{
+		"secretName":  certificateSecretName(kc, dc.Name),
+		"commonName":  dc.Name,
+		"dnsNames":    dnsNames,
+		"duration":    tls.GetCertificateDuration().String(),
+		"renewBefore": tls.GetRenewBefore().String(),
+		"usages":      []interface{}{"digital signature", "key encipherment", "server auth", "client auth"},
+		"issuerRef":   issuerRefSpec(issuerRef),
+		"keystores": map[string]interface{}{
+			strings.ToLower(string(tls.GetKeystoreFormat())): map[string]interface{}{
+				"create": true,
+				"passwordSecretRef": map[string]interface{}{
+					"name": keystorePasswordSecretName(kc),
+					"key":  keystorePasswordKey,
+				},
+			},
+		},
+	})
+}
+
+func newCertManagerObject(gvk schema.GroupVersionKind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func issuerRefSpec(issuerRef *api.IssuerReference) map[string]interface{} {
+	kind := issuerRef.Kind
+	if kind == "" {
+		kind = issuerGVK.Kind
+	}
+	return map[string]interface{}{"name": issuerRef.Name, "kind": kind, "group": issuerGVK.Group}
+}
+
+// reconcileCertManagerObject creates desired, or updates it if it differs from the existing
+// object.
+func (r *K8ssandraClusterReconciler) reconcileCertManagerObject(ctx context.Context, kc *api.K8ssandraCluster, desired *unstructured.Unstructured, logger logr.Logger) error {
+	if err := controllerutil.SetControllerReference(kc, desired, r.Scheme); err != nil {
+		return err
+	}
+	annotations.AddHashAnnotation(desired)
+
+	key := client.ObjectKeyFromObject(desired)
+	actual := &unstructured.Unstructured{}
+	actual.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := r.Get(ctx, key, actual); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Creating "+desired.GetKind(), desired.GetKind(), key)
+			return r.Create(ctx, desired)
+		}
+		return err
+	}
+	if !annotations.CompareHashAnnotations(actual, desired) {
+		logger.Info("Updating "+desired.GetKind(), desired.GetKind(), key)
+		desired.SetResourceVersion(actual.GetResourceVersion())
+		return r.Update(ctx, desired)
+	}
+	return nil
+}
+
+// writeStoresSecret creates or updates the stores Secret of a datacenter. The Secret is
+// labeled as managed by kc so that it is replicated, and an existing Secret that is not
+// managed by kc is not overwritten.
+func (r *K8ssandraClusterReconciler) writeStoresSecret(ctx context.Context, kc *api.K8ssandraCluster, key types.NamespacedName, data map[string][]byte) error {
+	kcKey := utils.GetKey(kc)
+	actual := &corev1.Secret{}
+	if err := r.Get(ctx, key, actual); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		desired := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels:    labels.ManagedByLabels(kcKey),
+			},
+			Data: data,
+		}
+		return r.createOwnedSecret(ctx, kc, desired)
+	}
+
+	if !labels.IsManagedBy(actual, kcKey) {
+		return fmt.Errorf("Secret %s already exists and is not managed by K8ssandraCluster %s", key, kcKey)
+	}
+	if reflect.DeepEqual(actual.Data, data) {
+		return nil
+	}
+	actual = actual.DeepCopy()
+	actual.Data = data
+	return r.Update(ctx, actual)
+}
+
+func (r *K8ssandraClusterReconciler) createOwnedSecret(ctx context.Context, kc *api.K8ssandraCluster, s *corev1.Secret) error {
+	if err := controllerutil.SetControllerReference(kc, s, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, s)
+}
+
+// encryptionStores returns the encryption stores of the datacenter named dcName. Their hash
+// changes when the certificate is renewed.
+func (r *K8ssandraClusterReconciler) encryptionStores(ctx context.Context, kc *api.K8ssandraCluster, dcName string) (*cassandra.EncryptionStores, error) {
+	key := types.NamespacedName{Namespace: kc.Namespace, Name: storesSecretName(kc, dcName)}
+	storesSecret := &corev1.Secret{}
+	if err := r.Get(ctx, key, storesSecret); err != nil {
+		return nil, err
+	}
+	return &cassandra.EncryptionStores{
+		Stores: encryption.Stores{
+			SecretRef: corev1.LocalObjectReference{Name: key.Name},
+			Type:      kc.Spec.Cassandra.TLS.GetKeystoreFormat(),
+		},
+		Password: string(storesSecret.Data[encryption.PasswordKey]),
+		Hash:     utils.DeepHashString(storesSecret.Data),
+	}, nil
+}
+
+// cqlTLSConfig returns the TLS configuration of the CQL connections of the operator to the
+// datacenter dcName, or nil if client encryption is disabled. The nodes are verified with
+// the CA of the stores of the datacenter, and the operator presents the certificate of the
+// datacenter, which allows it to connect when client authentication is required.
+func cqlTLSConfig(ctx context.Context, c client.Reader, kc *api.K8ssandraCluster, dcName string) (*tls.Config, error) {
+	if !kc.Spec.Cassandra.TLS.IsClientEncryptionEnabled() {
+		return nil, nil
+	}
+	key := types.NamespacedName{Namespace: kc.Namespace, Name: storesSecretName(kc, dcName)}
+	storesSecret := &corev1.Secret{}
+	if err := c.Get(ctx, key, storesSecret); err != nil {
+		return nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(storesSecret.Data[encryption.CAKey]) {
+		return nil, fmt.Errorf("no CA certificate found in Secret %s", key)
+	}
+	tlsConfig := &tls.Config{RootCAs: rootCAs}
+	if certificatePEM, keyPEM := storesSecret.Data[encryption.CertificateKey], storesSecret.Data[encryption.PrivateKeyKey]; len(certificatePEM) > 0 && len(keyPEM) > 0 {
+		certificate, err := tls.X509KeyPair(certificatePEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// datacenterForCertificate returns a CassandraDatacenter with the names that the certificate
+// of the datacenter of dcTemplate is issued for.
+func datacenterForCertificate(kc *api.K8ssandraCluster, dcTemplate api.CassandraDatacenterTemplate) *cassdcapi.CassandraDatacenter {
+	namespace := dcTemplate.Meta.Namespace
+	if namespace == "" {
+		namespace = kc.Namespace
+	}
+	return &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: dcTemplate.Meta.Name},
+		Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: kc.Spec.Cassandra.Cluster},
+	}
+}
+
+// secretToK8ssandraClusters returns the K8ssandraClusters in the namespace of s whose
+// encryption stores are derived from it: their CA, their keystore password, or the
+// certificates issued by cert-manager. It also returns the K8ssandraClusters that bind to
+// their LDAP server with it.
+func (r *K8ssandraClusterReconciler) secretToK8ssandraClusters(s client.Object) []reconcile.Request {
+	kcList := &api.K8ssandraClusterList{}
+	if err := r.List(context.Background(), kcList, client.InNamespace(s.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, kc := range kcList.Items {
+		if utils.SliceContains(encryptionSecretNames(&kc), s.GetName()) || isLDAPBindSecret(&kc, s.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: utils.GetKey(&kc)})
+		}
+	}
+	return requests
+}
+
+func encryptionSecretNames(kc *api.K8ssandraCluster) []string {
+	if kc.Spec.Cassandra == nil || !kc.Spec.Cassandra.TLS.IsEnabled() {
+		return nil
+	}
+	names := []string{caSecretName(kc), keystorePasswordSecretName(kc)}
+	if kc.Spec.Cassandra.TLS.GetProvider() == api.CertificateProviderCertManager {
+		for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+			names = append(names, certificateSecretName(kc, dcTemplate.Meta.Name))
+		}
+	}
+	return names
+}
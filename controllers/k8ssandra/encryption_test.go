@@ -0,0 +1,100 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileInternalEncryptionStores(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster: "test",
+				TLS: &api.TLSConfig{
+					InternodeEncryption: "all",
+					CertificateDuration: &metav1.Duration{Duration: 48 * time.Hour},
+					RenewBefore:         &metav1.Duration{Duration: 24 * time.Hour},
+				},
+				Datacenters: []api.CassandraDatacenterTemplate{{Meta: api.EmbeddedObjectMeta{Name: "dc1"}}},
+			},
+		},
+	}
+	r := &K8ssandraClusterReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	recResult, renewIn := r.reconcileEncryptionStores(ctx, kc, logr.Discard())
+	require.False(t, recResult.Completed())
+	assert.InDelta(t, 24*time.Hour, renewIn, float64(time.Minute))
+
+	for _, name := range []string{"test-ca", "test-keystore-password"} {
+		assert.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &corev1.Secret{}))
+	}
+	storesSecret := &corev1.Secret{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-dc1-keystore"}, storesSecret))
+	assert.True(t, labels.IsManagedBy(storesSecret, utils.GetKey(kc)), "the stores should be replicated")
+	for _, key := range []string{encryption.CertificateKey, encryption.PrivateKeyKey, encryption.CAKey, encryption.PasswordKey, "keystore.jks", "truststore.jks"} {
+		assert.NotEmpty(t, storesSecret.Data[key], key)
+	}
+
+	stores, err := r.encryptionStores(ctx, kc, "dc1")
+	require.NoError(t, err)
+	assert.Equal(t, "test-dc1-keystore", stores.SecretRef.Name)
+	assert.Equal(t, string(storesSecret.Data[encryption.PasswordKey]), stores.Password)
+
+	// The certificate is not issued again until it must be renewed
+	recResult, _ = r.reconcileEncryptionStores(ctx, kc, logr.Discard())
+	require.False(t, recResult.Completed())
+	renewed, err := r.encryptionStores(ctx, kc, "dc1")
+	require.NoError(t, err)
+	assert.Equal(t, stores.Hash, renewed.Hash)
+
+	kc.Spec.Cassandra.TLS.RenewBefore = &metav1.Duration{Duration: 72 * time.Hour}
+	recResult, _ = r.reconcileEncryptionStores(ctx, kc, logr.Discard())
+	require.False(t, recResult.Completed())
+	renewed, err = r.encryptionStores(ctx, kc, "dc1")
+	require.NoError(t, err)
+	assert.NotEqual(t, stores.Hash, renewed.Hash)
+}
+
+func TestEncryptionSecretNames(t *testing.T) {
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Datacenters: []api.CassandraDatacenterTemplate{{Meta: api.EmbeddedObjectMeta{Name: "dc1"}}},
+			},
+		},
+	}
+	assert.Empty(t, encryptionSecretNames(kc))
+
+	kc.Spec.Cassandra.TLS = &api.TLSConfig{ClientEncryption: &api.ClientEncryption{}}
+	assert.Equal(t, []string{"test-ca", "test-keystore-password"}, encryptionSecretNames(kc))
+
+	kc.Spec.Cassandra.TLS.Provider = api.CertificateProviderCertManager
+	assert.Equal(t, []string{"test-ca", "test-keystore-password", "test-dc1-certificate"}, encryptionSecretNames(kc))
+}
@@ -29,9 +29,11 @@ import (
 	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +43,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
 )
 
 const (
@@ -54,6 +57,8 @@ type K8ssandraClusterReconciler struct {
 	Scheme        *runtime.Scheme
 	ClientCache   *clientcache.ClientCache
 	ManagementApi cassandra.ManagementApiFactory
+	Cql           cassandra.CqlClientFactory
+	Recorder      record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=k8ssandra.io,namespace="k8ssandra",resources=k8ssandraclusters;clientconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -64,7 +69,11 @@ type K8ssandraClusterReconciler struct {
 // +kubebuilder:rbac:groups=stargate.k8ssandra.io,namespace="k8ssandra",resources=stargates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=reaper.k8ssandra.io,namespace="k8ssandra",resources=reapers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=pods;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=configmaps,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=endpoints,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,namespace="k8ssandra",resources=deployments,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=cert-manager.io,namespace="k8ssandra",resources=issuers;certificates,verbs=get;list;watch;create;update
 
 func (r *K8ssandraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithValues("K8ssandraCluster", req.NamespacedName)
@@ -80,8 +89,11 @@ func (r *K8ssandraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	kc = kc.DeepCopy()
 	patch := client.MergeFromWithOptions(kc.DeepCopy())
+	previousStatus := kc.Status.DeepCopy()
 	result, err := r.reconcile(ctx, kc, logger)
 	if kc.GetDeletionTimestamp() == nil {
+		updateStatus(kc, err)
+		recordStatusEvents(r.Recorder, kc, previousStatus)
 		if patchErr := r.Status().Patch(ctx, kc, patch); patchErr != nil {
 			logger.Error(patchErr, "failed to update k8ssandracluster status")
 		} else {
@@ -108,53 +120,106 @@ func (r *K8ssandraClusterReconciler) reconcile(ctx context.Context, kc *api.K8ss
 	// Reconcile the ReplicatedSecret and superuserSecret first (otherwise CassandraDatacenter will not start)
 
 	if recResult := r.reconcileSuperuserSecret(ctx, kc, kcLogger); recResult.Completed() {
-		return recResult.Output()
+		return setProgressing(kc, api.ReasonReconcilingSecrets, "Reconciling the superuser secret", recResult)
 	}
 
 	if recResult := r.reconcileReaperSecrets(ctx, kc, kcLogger); recResult.Completed() {
-		return recResult.Output()
+		return setProgressing(kc, api.ReasonReconcilingSecrets, "Reconciling the Reaper secrets", recResult)
+	}
+
+	if recResult := r.reconcileLDAPBindSecret(ctx, kc, kcLogger); recResult.Completed() {
+		return setProgressing(kc, api.ReasonReconcilingSecrets, "Reconciling the LDAP bind secret", recResult)
+	}
+
+	recResult, renewIn := r.reconcileEncryptionStores(ctx, kc, kcLogger)
+	if recResult.Completed() {
+		return setProgressing(kc, api.ReasonReconcilingSecrets, "Reconciling the encryption stores", recResult)
 	}
 
 	if recResult := r.reconcileReplicatedSecret(ctx, kc, kcLogger); recResult.Completed() {
-		return recResult.Output()
+		return setProgressing(kc, api.ReasonReconcilingSecrets, "Replicating secrets", recResult)
+	}
+
+	if recResult := r.checkDcDeletion(ctx, kc, kcLogger); recResult.Completed() {
+		return setProgressing(kc, api.ReasonDecommissioningDatacenter, "Decommissioning removed datacenters", recResult)
 	}
 
 	var actualDcs []*cassdcapi.CassandraDatacenter
 	if recResult, dcs := r.reconcileDatacenters(ctx, kc, kcLogger); recResult.Completed() {
-		return recResult.Output()
+		return setProgressing(kc, api.ReasonReconcilingDatacenters, "Reconciling CassandraDatacenters", recResult)
 	} else {
 		actualDcs = dcs
 	}
 
 	kcLogger.Info("All dcs reconciled")
 
-	if recResult := r.reconcileStargateAuthSchema(ctx, kc, actualDcs, kcLogger); recResult.Completed() {
-		return recResult.Output()
-	}
+	if hasStoppedDatacenters(kc) {
+		// Rolling restarts and schema changes require all the nodes to be up. They are
+		// deferred until the stopped datacenters are resumed.
+		kcLogger.Info("Some datacenters are stopped, skipping rolling restart and schema reconciliation")
+	} else {
+		if recResult := r.reconcileRollingRestart(ctx, kc, actualDcs, kcLogger); recResult.Completed() {
+			return setProgressing(kc, api.ReasonRollingRestart, "Restarting datacenters", recResult)
+		}
 
-	if recResult := r.reconcileReaperSchema(ctx, kc, actualDcs, kcLogger); recResult.Completed() {
-		return recResult.Output()
+		if recResult := r.reconcileStargateAuthSchema(ctx, kc, actualDcs, kcLogger); recResult.Completed() {
+			return setProgressing(kc, api.ReasonReconcilingSchema, "Reconciling the Stargate auth schema", recResult)
+		}
+
+		if recResult := r.reconcileReaperSchema(ctx, kc, actualDcs, kcLogger); recResult.Completed() {
+			return setProgressing(kc, api.ReasonReconcilingSchema, "Reconciling the Reaper schema", recResult)
+		}
 	}
 
 	if recResult := r.reconcileStargateAndReaper(ctx, kc, actualDcs, kcLogger); recResult.Completed() {
-		return recResult.Output()
+		return setProgressing(kc, api.ReasonReconcilingStargateAndReaper, "Reconciling Stargate and Reaper", recResult)
+	}
+
+	recResult, syncIn := r.reconcileLDAPGroups(ctx, kc, kcLogger)
+	if recResult.Completed() {
+		return setProgressing(kc, api.ReasonReconcilingRoles, "Reconciling the roles of the LDAP groups", recResult)
 	}
 
 	kcLogger.Info("Finished reconciling the k8ssandracluster")
+	setReconciled(kc)
 
+	// Come back to renew the certificates issued by the operator, and to sync the members
+	// of the LDAP groups
+	if requeueAfter := minPositiveDuration(renewIn, syncIn); requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	return result.Done().Output()
 }
 
+// minPositiveDuration returns the shortest of the positive durations, or 0 if there is none.
+func minPositiveDuration(durations ...time.Duration) time.Duration {
+	var min time.Duration
+	for _, d := range durations {
+		if d > 0 && (min == 0 || d < min) {
+			min = d
+		}
+	}
+	return min
+}
+
 func (r *K8ssandraClusterReconciler) reconcileStargateAndReaper(ctx context.Context, kc *api.K8ssandraCluster, dcs []*cassdcapi.CassandraDatacenter, logger logr.Logger) result.ReconcileResult {
+	serverVersion := clusterServerVersion(dcs)
 	for i, dcTemplate := range kc.Spec.Cassandra.Datacenters {
 		dc := dcs[i]
 		dcKey := utils.GetKey(dc)
 		logger := logger.WithValues("CassandraDatacenter", dcKey)
+		if kc.IsDatacenterStopped(&dcTemplate) {
+			// Stargate and Reaper were scaled down before the datacenter was stopped
+			logger.Info("Skipping Stargate and Reaper for stopped dc " + dc.Name)
+			continue
+		}
 		logger.Info("Reconciling Stargate and Reaper for dc " + dc.Name)
 		if remoteClient, err := r.ClientCache.GetRemoteClient(dcTemplate.K8sContext); err != nil {
 			logger.Error(err, "Failed to get remote client")
 			return result.Error(err)
-		} else if recResult := r.reconcileStargate(ctx, kc, dcTemplate, dc, logger, remoteClient); recResult.Completed() {
+		} else if err := r.resumeStargateAndReaper(ctx, kc, dc, remoteClient, logger); err != nil {
+			return result.Error(err)
+		} else if recResult := r.reconcileStargate(ctx, kc, dcTemplate, dc, serverVersion, logger, remoteClient); recResult.Completed() {
 			return recResult
 		} else if recResult := r.reconcileReaper(ctx, kc, dcTemplate, dc, logger, remoteClient); recResult.Completed() {
 			return recResult
@@ -168,17 +233,10 @@ func (r *K8ssandraClusterReconciler) SetupWithManager(mgr ctrl.Manager, clusters
 	cb := ctrl.NewControllerManagedBy(mgr).
 		For(&api.K8ssandraCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})) // No generation changed predicate here?
 
-	clusterLabelFilter := func(mapObj client.Object) []reconcile.Request {
-		requests := make([]reconcile.Request, 0)
-
-		kcName := labels.GetLabel(mapObj, api.K8ssandraClusterNameLabel)
-		kcNamespace := labels.GetLabel(mapObj, api.K8ssandraClusterNamespaceLabel)
-
-		if kcName != "" && kcNamespace != "" {
-			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: kcNamespace, Name: kcName}})
-		}
-		return requests
-	}
+	cb = cb.Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(r.configMapToK8ssandraClusters))
+	cb = cb.Watches(&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(r.secretToK8ssandraClusters))
 
 	for _, c := range clusters {
 		cb = cb.Watches(source.NewKindWithCache(&cassdcapi.CassandraDatacenter{}, c.GetCache()),
@@ -187,7 +245,24 @@ func (r *K8ssandraClusterReconciler) SetupWithManager(mgr ctrl.Manager, clusters
 			handler.EnqueueRequestsFromMapFunc(clusterLabelFilter))
 		cb = cb.Watches(source.NewKindWithCache(&reaperapi.Reaper{}, c.GetCache()),
 			handler.EnqueueRequestsFromMapFunc(clusterLabelFilter))
+		// The live settings are applied again to the Cassandra nodes that restart
+		cb = cb.Watches(source.NewKindWithCache(&corev1.Pod{}, c.GetCache()),
+			handler.EnqueueRequestsFromMapFunc(cassandraPodToK8ssandraCluster(c.GetClient())),
+			builder.WithPredicates(cassandraPodBecameReady))
 	}
 
 	return cb.Complete(r)
 }
+
+// clusterLabelFilter returns the K8ssandraCluster that mapObj is labeled with.
+func clusterLabelFilter(mapObj client.Object) []reconcile.Request {
+	requests := make([]reconcile.Request, 0)
+
+	kcName := labels.GetLabel(mapObj, api.K8ssandraClusterNameLabel)
+	kcNamespace := labels.GetLabel(mapObj, api.K8ssandraClusterNamespaceLabel)
+
+	if kcName != "" && kcNamespace != "" {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: kcNamespace, Name: kcName}})
+	}
+	return requests
+}
@@ -2,6 +2,7 @@ package k8ssandra
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
@@ -53,6 +54,7 @@ var (
 	testEnv             *testutils.MultiClusterTestEnv
 	seedsResolver       = &fakeSeedsResolver{}
 	managementApi       = &fakeManagementApiFactory{}
+	cqlClientFactory    = &fakeCqlClientFactory{}
 )
 
 func TestK8ssandraCluster(t *testing.T) {
@@ -75,6 +77,8 @@ func TestK8ssandraCluster(t *testing.T) {
 			Scheme:           scheme.Scheme,
 			ClientCache:      clientCache,
 			ManagementApi:    managementApi,
+			Cql:              cqlClientFactory,
+			Recorder:         mgr.GetEventRecorderFor("k8ssandracluster-controller"),
 		}).SetupWithManager(mgr, clusters)
 		return err
 	})
@@ -87,11 +91,17 @@ func TestK8ssandraCluster(t *testing.T) {
 
 	t.Run("CreateSingleDcCluster", testEnv.ControllerTest(ctx, createSingleDcCluster))
 	t.Run("CreateMultiDcCluster", testEnv.ControllerTest(ctx, createMultiDcCluster))
+	t.Run("CreateMultiDcClusterInParallel", testEnv.ControllerTest(ctx, createMultiDcClusterInParallel))
 	t.Run("ApplyClusterTemplateConfigs", testEnv.ControllerTest(ctx, applyClusterTemplateConfigs))
 	t.Run("ApplyDatacenterTemplateConfigs", testEnv.ControllerTest(ctx, applyDatacenterTemplateConfigs))
 	t.Run("ApplyClusterTemplateAndDatacenterTemplateConfigs", testEnv.ControllerTest(ctx, applyClusterTemplateAndDatacenterTemplateConfigs))
 	t.Run("CreateMultiDcClusterWithStargate", testEnv.ControllerTest(ctx, createMultiDcClusterWithStargate))
 	t.Run("CreateMultiDcClusterWithReaper", testEnv.ControllerTest(ctx, createMultiDcClusterWithReaper))
+	t.Run("RemoveDatacenter", testEnv.ControllerTest(ctx, removeDatacenter))
+	t.Run("AddDatacenter", testEnv.ControllerTest(ctx, addDatacenter))
+	t.Run("RollingRestart", testEnv.ControllerTest(ctx, rollingRestart))
+	t.Run("UpgradeCluster", testEnv.ControllerTest(ctx, upgradeCluster))
+	t.Run("StopCluster", testEnv.ControllerTest(ctx, stopCluster))
 }
 
 // createSingleDcCluster verifies that the CassandraDatacenter is created and that the
@@ -1281,6 +1291,9 @@ func verifyReplicatedSecretReconciled(ctx context.Context, t *testing.T, f *fram
 }
 
 func findDatacenterCondition(status *cassdcapi.CassandraDatacenterStatus, condType cassdcapi.DatacenterConditionType) *cassdcapi.DatacenterCondition {
+	if status == nil {
+		return nil
+	}
 	for _, condition := range status.Conditions {
 		if condition.Type == condType {
 			return &condition
@@ -1312,10 +1325,36 @@ type fakeManagementApiFactory struct {
 func (f fakeManagementApiFactory) NewManagementApiFacade(context.Context, *cassdcapi.CassandraDatacenter, client.Client, logr.Logger) (cassandra.ManagementApiFacade, error) {
 	m := new(mocks.ManagementApiFacade)
 	m.On("EnsureKeyspaceReplication", mock.Anything, mock.Anything).Return(nil)
+	m.On("GetKeyspaceReplication", mock.Anything).Return(map[string]string{}, nil)
+	m.On("AlterKeyspace", mock.Anything, mock.Anything).Return(nil)
 	m.On("ListTables", stargate.AuthKeyspace).Return([]string{"token"}, nil)
 	m.On("CreateTable", mock.MatchedBy(func(def *httphelper.TableDefinition) bool {
-		return def.KeyspaceName == stargate.AuthKeyspace && def.TableName == stargate.AuthTable
+		return def.KeyspaceName == stargate.AuthKeyspace
 	})).Return(nil)
+	m.On("ListKeyspaces", "").Return([]string{}, nil)
+	m.On("GetTableDefinition", stargate.AuthKeyspace, mock.Anything).Return(func(keyspace, table string) *httphelper.TableDefinition {
+		for _, definition := range stargate.AuthSchema.Tables {
+			if definition.TableName == table {
+				return definition
+			}
+		}
+		return nil
+	}, nil)
+	m.On("GetEndpointStates").Return([]httphelper.EndpointState{}, nil)
+	m.On("GetRingStatus").Return([]cassandra.NodeStatus{}, nil)
+	m.On("GetSchemaVersions").Return(map[string][]string{}, nil)
+	m.On("SetLiveSetting", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return m, nil
+}
+
+type fakeCqlClientFactory struct {
+}
+
+func (f fakeCqlClientFactory) NewCqlClient(context.Context, *cassdcapi.CassandraDatacenter, client.Client, string, string, *tls.Config, logr.Logger) (cassandra.CqlClient, error) {
+	m := new(mocks.CqlClient)
+	m.On("ListSchemaMigrations", stargate.AuthKeyspace).Return([]int{}, nil)
+	m.On("RecordSchemaMigration", stargate.AuthKeyspace, mock.Anything, mock.Anything).Return(nil)
+	m.On("Close").Return()
 	return m, nil
 }
 
@@ -0,0 +1,207 @@
+package k8ssandra
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/ldap"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileLDAPGroups grants the Cassandra roles of the group mappings of the LDAP provider
+// to the members of the mapped groups, and revokes them from the roles that are no longer
+// members. Only the grants made by the group sync, which are recorded in
+// kc.Status.LDAPGrantedRoles, are revoked. The mapped roles and a role for each member are
+// created if they do not exist. The returned duration is when the members of the groups
+// should be read again.
+func (r *K8ssandraClusterReconciler) reconcileLDAPGroups(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) (result.ReconcileResult, time.Duration) {
+	auth := kc.Spec.Cassandra.Auth
+	hasMappings := auth.IsLDAPEnabled() && len(auth.LDAP.GroupMappings) > 0
+	if !hasMappings && len(kc.Status.LDAPGrantedRoles) == 0 {
+		return result.Continue(), 0
+	}
+
+	// When the group mappings are removed, the grants that were made for them are revoked
+	desiredMembers := make(map[string]map[string]bool)
+	if hasMappings {
+		var err error
+		if desiredMembers, err = r.readLDAPGroups(ctx, kc, auth.LDAP); err != nil {
+			logger.Error(err, "Failed to read the LDAP groups")
+			r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.LDAPGroupSyncFailed,
+				"Failed to read the LDAP groups: %v", err)
+			return result.Error(err), 0
+		}
+	}
+
+	readyDc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		return result.Error(err), 0
+	}
+	if readyDc == nil {
+		logger.Info("Waiting for a datacenter to be ready to grant the roles of the LDAP groups")
+		return result.RequeueSoon(r.DefaultDelay), 0
+	}
+
+	cqlClient, err := newCqlClient(ctx, r.Client, r.Cql, kc, readyDc, remoteClient, logger)
+	if err != nil {
+		r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.LDAPGroupSyncFailed,
+			"Failed to connect to CassandraDatacenter %s: %v", readyDc.Name, err)
+		return result.Error(err), 0
+	}
+	defer cqlClient.Close()
+
+	changes, granted, err := syncRoleMembers(cqlClient, desiredMembers, kc.Status.LDAPGrantedRoles)
+	kc.Status.LDAPGrantedRoles = granted
+	if err != nil {
+		logger.Error(err, "Failed to grant the roles of the LDAP groups")
+		r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.LDAPGroupSyncFailed,
+			"Failed to grant the roles of the LDAP groups: %v", err)
+		return result.Error(err), 0
+	}
+	if len(changes) > 0 {
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.LDAPGroupsSynced,
+			"Updated the members of the roles of the LDAP groups: %s", strings.Join(changes, ", "))
+	}
+	if !hasMappings {
+		return result.Continue(), 0
+	}
+	return result.Continue(), auth.LDAP.GetGroupSyncPeriod()
+}
+
+// readLDAPGroups returns the names of the members of the groups of ldapConfig, for each
+// mapped role.
+func (r *K8ssandraClusterReconciler) readLDAPGroups(ctx context.Context, kc *api.K8ssandraCluster, ldapConfig *api.LDAPConfig) (map[string]map[string]bool, error) {
+	bindSecret, err := r.ldapBindSecret(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+	ldapClient, err := ldap.Dial(ldapConfig.ServerURL, string(bindSecret.Data["username"]), string(bindSecret.Data["password"]))
+	if err != nil {
+		return nil, err
+	}
+	defer ldapClient.Close()
+
+	members := make(map[string]map[string]bool)
+	for _, mapping := range ldapConfig.GroupMappings {
+		groupMembers, err := ldapClient.GroupMembers(mapping.Group, ldapConfig.GetUsernameAttribute())
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range mapping.Roles {
+			if members[role] == nil {
+				members[role] = make(map[string]bool)
+			}
+			for _, member := range groupMembers {
+				members[role][member] = true
+			}
+		}
+	}
+	return members, nil
+}
+
+// syncRoleMembers grants each role of desiredMembers to its members, and revokes the roles
+// of granted, i.e., the grants made by a previous sync, from the members that are no longer
+// desired. The members that were granted a role otherwise, e.g., through a CassandraRole,
+// keep it. It returns a description of the grants and revocations that were made, and the
+// grants made by the sync that are still in effect.
+func syncRoleMembers(cqlClient cassandra.CqlClient, desiredMembers map[string]map[string]bool, granted map[string][]string) ([]string, map[string][]string, error) {
+	roles := make([]string, 0, len(desiredMembers)+len(granted))
+	for role := range desiredMembers {
+		roles = append(roles, role)
+	}
+	for role := range granted {
+		if desiredMembers[role] == nil {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+
+	changes := make([]string, 0)
+	stillGranted := make(map[string][]string)
+	recordGrant := func(role, member string) {
+		stillGranted[role] = append(stillGranted[role], member)
+	}
+	for _, role := range roles {
+		if desiredMembers[role] != nil {
+			if err := cqlClient.CreateRoleIfNotExists(role, false); err != nil {
+				return changes, mergeGrants(stillGranted, granted, roles), err
+			}
+		}
+		actualMembers, err := cqlClient.ListRoleMembers(role)
+		if err != nil {
+			return changes, mergeGrants(stillGranted, granted, roles), err
+		}
+
+		for _, member := range granted[role] {
+			if !utils.SliceContains(actualMembers, member) {
+				// The role was revoked by someone else
+				continue
+			}
+			if desiredMembers[role][member] {
+				recordGrant(role, member)
+				continue
+			}
+			if err := cqlClient.RevokeRole(role, member); err != nil {
+				recordGrant(role, member)
+				return changes, mergeGrants(stillGranted, granted, roles), err
+			}
+			changes = append(changes, "revoked "+role+" from "+member)
+		}
+
+		members := make([]string, 0, len(desiredMembers[role]))
+		for member := range desiredMembers[role] {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+		for _, member := range members {
+			if utils.SliceContains(actualMembers, member) {
+				continue
+			}
+			if err := cqlClient.CreateRoleIfNotExists(member, true); err != nil {
+				return changes, mergeGrants(stillGranted, granted, roles), err
+			}
+			if err := cqlClient.GrantRole(role, member); err != nil {
+				return changes, mergeGrants(stillGranted, granted, roles), err
+			}
+			recordGrant(role, member)
+			changes = append(changes, "granted "+role+" to "+member)
+		}
+		sort.Strings(stillGranted[role])
+	}
+	return changes, stillGranted, nil
+}
+
+// mergeGrants returns the grants of synced, with the grants of previous for the roles that
+// were not synced yet when the sync was interrupted, so that they are not forgotten.
+func mergeGrants(synced, previous map[string][]string, roles []string) map[string][]string {
+	for role, members := range previous {
+		if _, found := synced[role]; !found {
+			synced[role] = members
+		}
+	}
+	return synced
+}
+
+func (r *K8ssandraClusterReconciler) ldapBindSecret(ctx context.Context, kc *api.K8ssandraCluster) (*corev1.Secret, error) {
+	key := types.NamespacedName{Namespace: kc.Namespace, Name: kc.Spec.Cassandra.Auth.LDAP.BindSecretRef.Name}
+	bindSecret := &corev1.Secret{}
+	if err := r.Get(ctx, key, bindSecret); err != nil {
+		return nil, err
+	}
+	return bindSecret, nil
+}
+
+// isLDAPBindSecret returns true if name is the bind Secret of the LDAP provider of kc.
+func isLDAPBindSecret(kc *api.K8ssandraCluster, name string) bool {
+	return kc.Spec.Cassandra != nil && kc.Spec.Cassandra.Auth.IsLDAPEnabled() &&
+		kc.Spec.Cassandra.Auth.LDAP.BindSecretRef.Name == name
+}
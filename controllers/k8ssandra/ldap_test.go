@@ -0,0 +1,231 @@
+package k8ssandra
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/ldap/ldaptest"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type mockCqlClientFactory struct {
+	cqlClient *mocks.CqlClient
+	username  string
+	password  string
+}
+
+func (f *mockCqlClientFactory) NewCqlClient(_ context.Context, _ *cassdcapi.CassandraDatacenter, _ client.Client, username, password string, _ *tls.Config, _ logr.Logger) (cassandra.CqlClient, error) {
+	f.username = username
+	f.password = password
+	return f.cqlClient, nil
+}
+
+func TestReconcileLDAPGroups(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	bindDN := "cn=k8ssandra,dc=example,dc=com"
+	server := ldaptest.NewServer(t, bindDN, "bind-password",
+		ldaptest.Entry{DN: "uid=alice,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"alice"}}},
+		ldaptest.Entry{DN: "uid=bob,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"bob"}}},
+		ldaptest.Entry{DN: "cn=dba,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"member": {"uid=alice,ou=people,dc=example,dc=com"},
+		}},
+		ldaptest.Entry{DN: "cn=staff,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"uniqueMember": {"uid=alice,ou=people,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com"},
+		}},
+	)
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:             "test",
+				SuperuserSecretName: "test-superuser",
+				Auth: &api.Auth{
+					Provider: api.AuthProviderLDAP,
+					LDAP: &api.LDAPConfig{
+						ServerURL:         server.URL(),
+						BindSecretRef:     corev1.LocalObjectReference{Name: "ldap-bind"},
+						SearchBase:        "ou=people,dc=example,dc=com",
+						UsernameAttribute: "uid",
+						GroupMappings: []api.LDAPGroupMapping{
+							{Group: "cn=dba,ou=groups,dc=example,dc=com", Roles: []string{"dba"}},
+							{Group: "cn=staff,ou=groups,dc=example,dc=com", Roles: []string{"readers"}},
+						},
+						GroupSyncPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+				Datacenters: []api.CassandraDatacenterTemplate{{Meta: api.EmbeddedObjectMeta{Name: "dc1"}}},
+			},
+		},
+		Status: api.K8ssandraClusterStatus{
+			// mallory was granted dba by a previous sync, bob was granted readers otherwise
+			LDAPGrantedRoles: map[string][]string{"dba": {"alice", "mallory"}},
+		},
+	}
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			CassandraOperatorProgress: cassdcapi.ProgressReady,
+			Conditions: []cassdcapi.DatacenterCondition{{
+				Type:   cassdcapi.DatacenterReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+	secrets := []client.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ldap-bind"},
+			Data:       map[string][]byte{"username": []byte(bindDN), "password": []byte("bind-password")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-superuser"},
+			Data:       map[string][]byte{"username": []byte("test-superuser"), "password": []byte("superuser-password")},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc, dc).WithObjects(secrets...).Build()
+
+	cqlClient := new(mocks.CqlClient)
+	cqlClient.On("CreateRoleIfNotExists", mock.Anything, mock.Anything).Return(nil)
+	cqlClient.On("ListRoleMembers", "dba").Return([]string{"alice", "mallory"}, nil)
+	cqlClient.On("ListRoleMembers", "readers").Return([]string{"bob"}, nil)
+	cqlClient.On("GrantRole", "readers", "alice").Return(nil)
+	cqlClient.On("RevokeRole", "dba", "mallory").Return(nil)
+	cqlClient.On("Close").Return()
+	cqlFactory := &mockCqlClientFactory{cqlClient: cqlClient}
+
+	recorder := record.NewFakeRecorder(10)
+	r := &K8ssandraClusterReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Client:           c,
+		Scheme:           scheme,
+		ClientCache:      clientcache.New(c, c, scheme),
+		Cql:              cqlFactory,
+		Recorder:         recorder,
+	}
+
+	recResult, syncIn := r.reconcileLDAPGroups(ctx, kc, logr.Discard())
+	require.False(t, recResult.Completed())
+	assert.Equal(t, 5*time.Minute, syncIn)
+	assert.Equal(t, "test-superuser", cqlFactory.username)
+	assert.Equal(t, "superuser-password", cqlFactory.password)
+
+	cqlClient.AssertExpectations(t)
+	cqlClient.AssertCalled(t, "CreateRoleIfNotExists", "dba", false)
+	cqlClient.AssertCalled(t, "CreateRoleIfNotExists", "readers", false)
+	cqlClient.AssertCalled(t, "CreateRoleIfNotExists", "alice", true)
+	cqlClient.AssertNotCalled(t, "GrantRole", "dba", "alice")
+	cqlClient.AssertNotCalled(t, "GrantRole", "readers", "bob")
+	assert.Equal(t, map[string][]string{"dba": {"alice"}, "readers": {"alice"}}, kc.Status.LDAPGrantedRoles)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.LDAPGroupsSynced)
+
+	// The group cannot be read
+	server.RemoveEntry("cn=staff,ou=groups,dc=example,dc=com")
+	recResult, _ = r.reconcileLDAPGroups(ctx, kc, logr.Discard())
+	require.True(t, recResult.Completed())
+	_, err := recResult.Output()
+	assert.Error(t, err)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, events.LDAPGroupSyncFailed)
+}
+
+func TestReconcileLDAPGroupsWithoutMappings(t *testing.T) {
+	kc := &api.K8ssandraCluster{
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Auth: &api.Auth{
+					Provider: api.AuthProviderLDAP,
+					LDAP:     &api.LDAPConfig{ServerURL: "ldap://localhost:389"},
+				},
+			},
+		},
+	}
+	r := &K8ssandraClusterReconciler{}
+
+	recResult, syncIn := r.reconcileLDAPGroups(context.Background(), kc, logr.Discard())
+	assert.False(t, recResult.Completed())
+	assert.Zero(t, syncIn)
+}
+
+func TestSyncRoleMembersWithCassandraRole(t *testing.T) {
+	cqlClient := new(mocks.CqlClient)
+	members := map[string][]string{"readers": {}}
+	cqlClient.On("CreateRoleIfNotExists", mock.Anything, mock.Anything).Return(nil)
+	cqlClient.On("ListRoleMembers", "readers").Return(func(role string) []string {
+		return members[role]
+	}, nil)
+	cqlClient.On("GrantRole", "readers", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		members["readers"] = append(members["readers"], args.String(1))
+	})
+	cqlClient.On("RevokeRole", "readers", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		remaining := make([]string, 0)
+		for _, member := range members["readers"] {
+			if member != args.String(1) {
+				remaining = append(remaining, member)
+			}
+		}
+		members["readers"] = remaining
+	})
+
+	// The CassandraRole of bob grants readers to bob
+	_, roleGrants, err := reconcileMemberOf(cqlClient, "bob", nil, []string{"readers"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"readers"}, roleGrants)
+
+	// The LDAP group mapped to readers has alice and bob as members; bob already is a member
+	_, ldapGrants, err := syncRoleMembers(cqlClient, map[string]map[string]bool{
+		"readers": {"alice": true, "bob": true},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"readers": {"alice"}}, ldapGrants)
+	cqlClient.AssertNumberOfCalls(t, "GrantRole", 2)
+
+	// bob leaves the group, but keeps the role that the CassandraRole granted
+	_, ldapGrants, err = syncRoleMembers(cqlClient, map[string]map[string]bool{
+		"readers": {"alice": true},
+	}, ldapGrants)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"readers": {"alice"}}, ldapGrants)
+	cqlClient.AssertNotCalled(t, "RevokeRole", "readers", "bob")
+
+	// The CassandraRole of bob does not revoke the role on the next reconciliation either
+	_, roleGrants, err = reconcileMemberOf(cqlClient, "bob", []string{"readers"}, []string{"readers"}, roleGrants)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"readers"}, roleGrants)
+
+	// The CassandraRole of alice does not revoke the role that the sync granted
+	_, aliceGrants, err := reconcileMemberOf(cqlClient, "alice", []string{"readers"}, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, aliceGrants)
+
+	// The group mapping is removed: only alice, to whom the sync granted the role, loses it
+	_, ldapGrants, err = syncRoleMembers(cqlClient, map[string]map[string]bool{}, ldapGrants)
+	require.NoError(t, err)
+	assert.Empty(t, ldapGrants)
+	cqlClient.AssertCalled(t, "RevokeRole", "readers", "alice")
+	assert.Equal(t, []string{"bob"}, members["readers"])
+}
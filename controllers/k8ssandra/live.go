@@ -0,0 +1,223 @@
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileLiveSettings applies the live settings of dc to its running nodes. These are the
+// live settings whose change was not persisted in the CassandraDatacenter to avoid a
+// rolling restart, the trace probability, and the previously applied live settings that
+// have been reverted since. The settings are applied to all the nodes when they change,
+// and only to the nodes that restarted since they were last applied otherwise. The
+// resulting status is recorded in dc.configStatus. It must not modify kc since it runs
+// concurrently for the datacenters of a rollout group.
+func (r *K8ssandraClusterReconciler) reconcileLiveSettings(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	dc *dcReconciliation,
+	changes *cassandra.ConfigChanges,
+	pods []corev1.Pod,
+) error {
+	logger := dc.logger
+	previous := previousConfigStatus(kc, dc.desiredDc.Name)
+
+	persisted, err := cassandra.LiveSettings(dc.actualDc.Spec.Config)
+	if err != nil {
+		logger.Error(err, "Failed to parse the config of the datacenter")
+		return err
+	}
+
+	desired := make(map[string]string)
+	if !changes.RestartRequired() {
+		for setting, value := range changes.Live {
+			desired[setting] = value
+		}
+	}
+	if dc.traceProbability != "" {
+		desired[cassandra.TraceProbabilitySetting] = dc.traceProbability
+	}
+
+	// changed holds the settings that need to be applied to all the nodes
+	changed := make(map[string]string)
+	for setting, value := range desired {
+		if previous.AppliedLive[setting] != value {
+			changed[setting] = value
+		}
+	}
+	for setting := range previous.AppliedLive {
+		if _, found := desired[setting]; !found {
+			// The setting was reverted, or it has been persisted by a rolling restart
+			if value, found := persisted[setting]; found {
+				changed[setting] = value
+			} else {
+				changed[setting] = cassandra.LiveSettingDefault(setting, dc.actualDc.Spec.ServerVersion)
+			}
+		}
+	}
+
+	var managementApi cassandra.ManagementApiFacade
+	applied := false
+	now := metav1.Now()
+	for i := range pods {
+		pod := &pods[i]
+		startedAt := cassandraStartTime(pod)
+		if startedAt == nil {
+			continue
+		}
+
+		settings := changed
+		if previous.LastLiveUpdate != nil && !startedAt.Before(previous.LastLiveUpdate) {
+			// The node restarted with the persisted config
+			settings = desired
+		}
+		if len(settings) == 0 {
+			continue
+		}
+
+		if managementApi == nil {
+			if managementApi, err = r.ManagementApi.NewManagementApiFacade(ctx, dc.actualDc, dc.remoteClient, logger); err != nil {
+				logger.Error(err, "Failed to create ManagementApiFacade")
+				return err
+			}
+		}
+		for _, setting := range sortedKeys(settings) {
+			if err := managementApi.SetLiveSetting(pod, setting, settings[setting]); err != nil {
+				r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.LiveSettingsFailed,
+					"Failed to set %s on pod %s: %v", setting, pod.Name, err)
+				return err
+			}
+		}
+		applied = true
+	}
+
+	if len(changed) > 0 && applied {
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.LiveSettingsApplied,
+			"Applied %s to the nodes of CassandraDatacenter %s without restarting them", formatSettings(changed), dc.desiredDc.Name)
+	}
+
+	status := &api.ConfigStatus{PendingRestart: dc.pendingRestart}
+	if status.PendingRestart == nil && dc.actualDc.Status.ObservedGeneration != dc.actualDc.Generation {
+		// cass-operator has not processed the last update yet
+		status.PendingRestart = previous.PendingRestart
+	}
+	if len(desired) > 0 {
+		status.AppliedLive = desired
+		status.LastLiveUpdate = previous.LastLiveUpdate
+		if applied {
+			status.LastLiveUpdate = &now
+		}
+	}
+	if len(status.AppliedLive) > 0 || len(status.PendingRestart) > 0 {
+		dc.configStatus = status
+	}
+	return nil
+}
+
+// previousConfigStatus returns the config status of the datacenter named dcName, or an
+// empty status if there is none.
+func previousConfigStatus(kc *api.K8ssandraCluster, dcName string) *api.ConfigStatus {
+	if status := kc.Status.Datacenters[dcName].Config; status != nil {
+		return status
+	}
+	return &api.ConfigStatus{}
+}
+
+// setConfigStatusForDatacenter records the config status of the datacenter named dcName.
+// A nil status removes it.
+func setConfigStatusForDatacenter(kc *api.K8ssandraCluster, dcName string, status *api.ConfigStatus) {
+	kdcStatus, found := kc.Status.Datacenters[dcName]
+	if !found && status == nil {
+		return
+	}
+	if len(kc.Status.Datacenters) == 0 {
+		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus, 0)
+	}
+	kdcStatus.Config = status
+	kc.Status.Datacenters[dcName] = kdcStatus
+}
+
+// cassandraPodBecameReady selects the updates of the pods in which the Cassandra container
+// became ready, i.e., a node started or restarted. The live settings that were not
+// persisted in the CassandraDatacenter are lost on restart and have to be applied again.
+var cassandraPodBecameReady = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		startedAt := cassandraStartTime(newPod)
+		if startedAt == nil {
+			return false
+		}
+		previousStart := cassandraStartTime(oldPod)
+		return previousStart == nil || !previousStart.Equal(startedAt)
+	},
+}
+
+// cassandraPodToK8ssandraCluster returns a function that maps a Cassandra pod to the
+// K8ssandraCluster of its CassandraDatacenter, which is read with c.
+func cassandraPodToK8ssandraCluster(c client.Reader) handler.MapFunc {
+	return func(pod client.Object) []reconcile.Request {
+		dcName := labels.GetLabel(pod, cassdcapi.DatacenterLabel)
+		if dcName == "" {
+			return nil
+		}
+		dc := &cassdcapi.CassandraDatacenter{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: pod.GetNamespace(), Name: dcName}, dc); err != nil {
+			return nil
+		}
+		return clusterLabelFilter(dc)
+	}
+}
+
+// cassandraStartTime returns the time at which the Cassandra container of pod started, or
+// nil if it is not running and ready.
+func cassandraStartTime(pod *corev1.Pod) *metav1.Time {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "cassandra" && status.Ready && status.State.Running != nil {
+			return &status.State.Running.StartedAt
+		}
+	}
+	return nil
+}
+
+func sortedKeys(settings map[string]string) []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatSettings(settings map[string]string) string {
+	formatted := make([]string, 0, len(settings))
+	for _, setting := range sortedKeys(settings) {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", setting, settings[setting]))
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,282 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+type mockManagementApiFactory struct {
+	managementApi *mocks.ManagementApiFacade
+}
+
+func (f *mockManagementApiFactory) NewManagementApiFacade(context.Context, *cassdcapi.CassandraDatacenter, client.Client, logr.Logger) (cassandra.ManagementApiFacade, error) {
+	return f.managementApi, nil
+}
+
+func TestReconcileLiveSettings(t *testing.T) {
+	lastUpdate := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	newPod := func(name string, startedAt time.Time) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "cassandra",
+					Ready: true,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(startedAt)},
+					},
+				}},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		newPod("pod-0", lastUpdate.Add(-time.Hour)),
+		newPod("pod-1", lastUpdate.Add(time.Hour)),
+	}
+
+	newCluster := func(status *api.ConfigStatus) *api.K8ssandraCluster {
+		return &api.K8ssandraCluster{
+			Status: api.K8ssandraClusterStatus{
+				Datacenters: map[string]api.K8ssandraStatus{"dc1": {Config: status}},
+			},
+		}
+	}
+	newDc := func(config string) *dcReconciliation {
+		dc := &cassdcapi.CassandraDatacenter{
+			ObjectMeta: metav1.ObjectMeta{Name: "dc1"},
+			Spec: cassdcapi.CassandraDatacenterSpec{
+				ServerVersion: "4.0.1",
+				Config:        []byte(config),
+			},
+		}
+		return &dcReconciliation{desiredDc: dc, actualDc: dc, logger: logr.Discard()}
+	}
+
+	type test struct {
+		name          string
+		kc            *api.K8ssandraCluster
+		dc            *dcReconciliation
+		changes       *cassandra.ConfigChanges
+		wantCalls     map[string]map[string]string
+		wantStatus    *api.ConfigStatus
+		updatedStatus bool
+	}
+
+	tests := []test{
+		{
+			name: "live change is applied to all the nodes",
+			kc:   newCluster(nil),
+			dc:   newDc(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":32}}`),
+			changes: &cassandra.ConfigChanges{
+				Live: map[string]string{"compaction_throughput_mb_per_sec": "64"},
+			},
+			wantCalls: map[string]map[string]string{
+				"pod-0": {"compaction_throughput_mb_per_sec": "64"},
+				"pod-1": {"compaction_throughput_mb_per_sec": "64"},
+			},
+			wantStatus: &api.ConfigStatus{
+				AppliedLive: map[string]string{"compaction_throughput_mb_per_sec": "64"},
+			},
+			updatedStatus: true,
+		},
+		{
+			name: "live settings are applied again to the nodes that restarted",
+			kc: newCluster(&api.ConfigStatus{
+				AppliedLive:    map[string]string{"compaction_throughput_mb_per_sec": "64", "trace_probability": "0.1"},
+				LastLiveUpdate: &lastUpdate,
+			}),
+			dc: func() *dcReconciliation {
+				dc := newDc(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":32}}`)
+				dc.traceProbability = "0.1"
+				return dc
+			}(),
+			changes: &cassandra.ConfigChanges{
+				Live: map[string]string{"compaction_throughput_mb_per_sec": "64"},
+			},
+			wantCalls: map[string]map[string]string{
+				"pod-1": {"compaction_throughput_mb_per_sec": "64", "trace_probability": "0.1"},
+			},
+			wantStatus: &api.ConfigStatus{
+				AppliedLive: map[string]string{"compaction_throughput_mb_per_sec": "64", "trace_probability": "0.1"},
+			},
+			updatedStatus: true,
+		},
+		{
+			name: "reverted live settings are reset",
+			kc: newCluster(&api.ConfigStatus{
+				AppliedLive:    map[string]string{"compaction_throughput_mb_per_sec": "64", "trace_probability": "0.1"},
+				LastLiveUpdate: &lastUpdate,
+			}),
+			dc:      newDc(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":32}}`),
+			changes: &cassandra.ConfigChanges{Live: map[string]string{}},
+			wantCalls: map[string]map[string]string{
+				"pod-0": {"compaction_throughput_mb_per_sec": "32", "trace_probability": "0"},
+			},
+			wantStatus: nil,
+		},
+		{
+			name: "changes that require a restart are pending",
+			kc:   newCluster(nil),
+			dc: func() *dcReconciliation {
+				dc := newDc(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":64,"num_tokens":16}}`)
+				dc.pendingRestart = []string{"compaction_throughput_mb_per_sec", "num_tokens"}
+				return dc
+			}(),
+			changes: &cassandra.ConfigChanges{
+				Live:    map[string]string{"compaction_throughput_mb_per_sec": "64"},
+				Restart: []string{"num_tokens"},
+			},
+			wantCalls: map[string]map[string]string{},
+			wantStatus: &api.ConfigStatus{
+				PendingRestart: []string{"compaction_throughput_mb_per_sec", "num_tokens"},
+			},
+		},
+		{
+			name: "pending changes are kept until cass-operator processes the update",
+			kc: newCluster(&api.ConfigStatus{
+				PendingRestart: []string{"num_tokens"},
+			}),
+			dc: func() *dcReconciliation {
+				dc := newDc(`{"cassandra-yaml":{"num_tokens":16}}`)
+				dc.actualDc.Generation = 2
+				dc.actualDc.Status.ObservedGeneration = 1
+				return dc
+			}(),
+			changes:   &cassandra.ConfigChanges{Live: map[string]string{}},
+			wantCalls: map[string]map[string]string{},
+			wantStatus: &api.ConfigStatus{
+				PendingRestart: []string{"num_tokens"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := make(map[string]map[string]string)
+			managementApi := new(mocks.ManagementApiFacade)
+			managementApi.On("SetLiveSetting", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					pod := args.Get(0).(*corev1.Pod)
+					if calls[pod.Name] == nil {
+						calls[pod.Name] = make(map[string]string)
+					}
+					calls[pod.Name][args.String(1)] = args.String(2)
+				}).
+				Return(nil)
+			r := &K8ssandraClusterReconciler{
+				Recorder:      record.NewFakeRecorder(10),
+				ManagementApi: &mockManagementApiFactory{managementApi: managementApi},
+			}
+
+			err := r.reconcileLiveSettings(context.Background(), tc.kc, tc.dc, tc.changes, pods)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCalls, calls)
+
+			status := tc.dc.configStatus
+			if tc.updatedStatus {
+				require.NotNil(t, status)
+				require.NotNil(t, status.LastLiveUpdate)
+				assert.True(t, status.LastLiveUpdate.After(lastUpdate.Time))
+				status.LastLiveUpdate = nil
+			}
+			assert.Equal(t, tc.wantStatus, status)
+		})
+	}
+}
+
+func TestLiveSettingsAfterPodRestart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	newPod := func(ready bool, startedAt time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "dc1-pod-0",
+				Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "cassandra",
+					Ready: ready,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(startedAt)},
+					},
+				}},
+			},
+		}
+	}
+	kc := &api.K8ssandraCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "dc1",
+			Labels: map[string]string{
+				api.K8ssandraClusterNameLabel:      "test",
+				api.K8ssandraClusterNamespaceLabel: "default",
+			},
+		},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ServerVersion: "4.0.1",
+			Config:        []byte(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":32}}`),
+		},
+	}
+	changes := &cassandra.ConfigChanges{Live: map[string]string{"compaction_throughput_mb_per_sec": "64"}}
+
+	managementApi := new(mocks.ManagementApiFacade)
+	r := &K8ssandraClusterReconciler{
+		Recorder:      record.NewFakeRecorder(10),
+		ManagementApi: &mockManagementApiFactory{managementApi: managementApi},
+	}
+	reconcile := func(pod *corev1.Pod) {
+		dcRec := &dcReconciliation{desiredDc: dc, actualDc: dc, logger: logr.Discard()}
+		require.NoError(t, r.reconcileLiveSettings(context.Background(), kc, dcRec, changes, []corev1.Pod{*pod}))
+		setConfigStatusForDatacenter(kc, "dc1", dcRec.configStatus)
+	}
+
+	pod := newPod(true, time.Now().Add(-time.Hour))
+	managementApi.On("SetLiveSetting", mock.MatchedBy(podNamed("dc1-pod-0")), "compaction_throughput_mb_per_sec", "64").Return(nil).Once()
+	reconcile(pod)
+	managementApi.AssertExpectations(t)
+
+	// The node restarts with the config of the CassandraDatacenter
+	restartedAt := time.Now()
+	restarting := newPod(false, restartedAt)
+	restarted := newPod(true, restartedAt)
+	assert.False(t, cassandraPodBecameReady.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: restarting}))
+	assert.True(t, cassandraPodBecameReady.Update(event.UpdateEvent{ObjectOld: restarting, ObjectNew: restarted}))
+	assert.False(t, cassandraPodBecameReady.Update(event.UpdateEvent{ObjectOld: restarted, ObjectNew: restarted}))
+
+	// The K8ssandraCluster is reconciled, and the live setting is applied again
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+	requests := cassandraPodToK8ssandraCluster(c)(restarted)
+	require.Len(t, requests, 1)
+	assert.Equal(t, utils.GetKey(kc), requests[0].NamespacedName)
+
+	managementApi.On("SetLiveSetting", mock.MatchedBy(podNamed("dc1-pod-0")), "compaction_throughput_mb_per_sec", "64").Return(nil).Once()
+	reconcile(restarted)
+	managementApi.AssertExpectations(t)
+
+	// It is not applied again until the node restarts
+	reconcile(restarted)
+	managementApi.AssertNumberOfCalls(t, "SetLiveSetting", 2)
+}
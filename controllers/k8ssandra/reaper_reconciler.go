@@ -127,7 +127,15 @@ func (r *K8ssandraClusterReconciler) reconcileReaper(
 
 		logger.Info("Reaper present for DC " + actualDc.Name)
 
-		desiredReaper := reaper.NewReaper(reaperKey, kc, actualDc, reaperTemplate)
+		var encryptionStores *cassandra.EncryptionStores
+		if kc.Spec.Cassandra.TLS.IsClientEncryptionEnabled() {
+			var err error
+			if encryptionStores, err = r.encryptionStores(ctx, kc, dcTemplate.Meta.Name); err != nil {
+				logger.Error(err, "Failed to get encryption stores")
+				return result.Error(err)
+			}
+		}
+		desiredReaper := reaper.NewReaper(reaperKey, kc, actualDc, reaperTemplate, encryptionStores)
 
 		if err := remoteClient.Get(ctx, reaperKey, actualReaper); err != nil {
 			if errors.IsNotFound(err) {
@@ -247,10 +255,7 @@ func (r *K8ssandraClusterReconciler) setStatusForReaper(kc *api.K8ssandraCluster
 
 func (r *K8ssandraClusterReconciler) removeReaperStatus(kc *api.K8ssandraCluster, dcName string) {
 	if kdcStatus, found := kc.Status.Datacenters[dcName]; found {
-		kc.Status.Datacenters[dcName] = api.K8ssandraStatus{
-			Reaper:    nil,
-			Cassandra: kdcStatus.Cassandra.DeepCopy(),
-			Stargate:  kdcStatus.Stargate.DeepCopy(),
-		}
+		kdcStatus.Reaper = nil
+		kc.Status.Datacenters[dcName] = kdcStatus
 	}
 }
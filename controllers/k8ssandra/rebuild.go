@@ -0,0 +1,218 @@
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileRebuild streams data to a datacenter that has been added to an existing
+// cluster. Datacenters that need to be rebuilt are flagged with a RebuildStatus when
+// their CassandraDatacenter is created. Once the datacenter is ready, it is added to the
+// replication of the keyspaces listed in ReplicatedKeyspaces, and then each node is
+// rebuilt in turn from another datacenter. The replication of the system keyspaces is
+// handled by updateReplicationOfSystemKeyspaces.
+func (r *K8ssandraClusterReconciler) reconcileRebuild(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	dcTemplate api.CassandraDatacenterTemplate,
+	dc *cassdcapi.CassandraDatacenter,
+	remoteClient client.Client,
+	logger logr.Logger,
+) result.ReconcileResult {
+	rebuild := kc.Status.Datacenters[dc.Name].Rebuild
+	if rebuild == nil || rebuild.Progress == api.RebuildCompleted {
+		return result.Continue()
+	}
+
+	managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, dc, remoteClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		return result.Error(err)
+	}
+
+	if rebuild.Progress == api.RebuildPending || rebuild.Progress == api.RebuildUpdatingReplication {
+		rebuild.Progress = api.RebuildUpdatingReplication
+		if err := addDcToReplication(kc, dcTemplate, managementApi, logger); err != nil {
+			rebuild.LastError = err.Error()
+			return result.Error(err)
+		}
+
+		sourceDc := findRebuildSourceDc(kc, dc.Name)
+		if sourceDc == "" {
+			err := fmt.Errorf("no datacenter available to rebuild %s from", dc.Name)
+			logger.Error(err, "Cannot rebuild datacenter")
+			rebuild.LastError = err.Error()
+			return result.Error(err)
+		}
+
+		logger.Info("Starting rebuild", "SourceDatacenter", sourceDc)
+		rebuild.SourceDatacenter = sourceDc
+		rebuild.Progress = api.RebuildRunning
+		rebuild.LastError = ""
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.RebuildStarted,
+			"Rebuilding CassandraDatacenter %s from %s", dc.Name, sourceDc)
+	}
+
+	previousProgress := rebuild.Progress
+	recResult := r.rebuildNodes(ctx, dc, rebuild, managementApi, remoteClient, logger)
+	if rebuild.Progress != previousProgress {
+		switch rebuild.Progress {
+		case api.RebuildCompleted:
+			r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.RebuildCompleted, "Rebuilt CassandraDatacenter %s", dc.Name)
+		case api.RebuildFailed:
+			r.Recorder.Event(kc, corev1.EventTypeWarning, events.RebuildFailed, rebuild.LastError)
+		}
+	}
+	return recResult
+}
+
+// addDcToReplication adds the datacenter of dcTemplate to the replication settings of each
+// of the keyspaces listed in kc.Spec.Cassandra.ReplicatedKeyspaces.
+func addDcToReplication(kc *api.K8ssandraCluster, dcTemplate api.CassandraDatacenterTemplate, managementApi cassandra.ManagementApiFacade, logger logr.Logger) error {
+	dcReplication := cassandra.ComputeReplication(3, dcTemplate)
+
+	for _, keyspace := range kc.Spec.Cassandra.ReplicatedKeyspaces {
+		actualReplication, err := managementApi.GetKeyspaceReplication(keyspace)
+		if err != nil {
+			logger.Error(err, "Failed to get keyspace replication", "Keyspace", keyspace)
+			return err
+		}
+
+		replication, ok := cassandra.ParseReplication(actualReplication)
+		if !ok {
+			err = fmt.Errorf("keyspace %s does not use NetworkTopologyStrategy", keyspace)
+			logger.Error(err, "Cannot update keyspace replication", "Keyspace", keyspace)
+			return err
+		}
+
+		if _, found := replication[dcTemplate.Meta.Name]; found {
+			continue
+		}
+
+		replication[dcTemplate.Meta.Name] = dcReplication[dcTemplate.Meta.Name]
+		logger.Info("Adding datacenter to keyspace replication", "Keyspace", keyspace, "Replication", replication)
+		if err = managementApi.AlterKeyspace(keyspace, replication); err != nil {
+			logger.Error(err, "Failed to update keyspace replication", "Keyspace", keyspace)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findRebuildSourceDc returns the first datacenter in the spec, other than dcName, that is
+// not being rebuilt itself. It returns an empty string if there is none.
+func findRebuildSourceDc(kc *api.K8ssandraCluster, dcName string) string {
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		if dcTemplate.Meta.Name == dcName {
+			continue
+		}
+		kdcStatus, found := kc.Status.Datacenters[dcTemplate.Meta.Name]
+		if !found || kdcStatus.Cassandra == nil {
+			continue
+		}
+		if kdcStatus.Rebuild == nil || kdcStatus.Rebuild.Progress == api.RebuildCompleted {
+			return dcTemplate.Meta.Name
+		}
+	}
+	return ""
+}
+
+// rebuildNodes rebuilds the nodes of dc one at a time. Progress is recorded in rebuild so
+// that the rebuild can be followed across reconciliations. A failed rebuild is retried
+// after r.LongDelay.
+func (r *K8ssandraClusterReconciler) rebuildNodes(
+	ctx context.Context,
+	dc *cassdcapi.CassandraDatacenter,
+	rebuild *api.RebuildStatus,
+	managementApi cassandra.ManagementApiFacade,
+	remoteClient client.Client,
+	logger logr.Logger,
+) result.ReconcileResult {
+	pods := &corev1.PodList{}
+	if err := remoteClient.List(ctx, pods, client.InNamespace(dc.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: dc.Name}); err != nil {
+		logger.Error(err, "Failed to list datacenter pods")
+		return result.Error(err)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	if rebuild.JobId != "" {
+		var pod *corev1.Pod
+		for i := range pods.Items {
+			if pods.Items[i].Name == rebuild.CurrentPod {
+				pod = &pods.Items[i]
+				break
+			}
+		}
+
+		if pod == nil {
+			logger.Info("Pod being rebuilt no longer exists, restarting rebuild", "Pod", rebuild.CurrentPod)
+			rebuild.JobId = ""
+		} else {
+			job, err := managementApi.GetJobDetails(pod, rebuild.JobId)
+			if err != nil {
+				logger.Error(err, "Failed to get rebuild job details", "Pod", pod.Name)
+				return result.Error(err)
+			}
+
+			switch {
+			case job.Status == cassandra.JobStatusCompleted:
+				logger.Info("Rebuild completed", "Pod", pod.Name)
+				rebuild.RebuiltPods = append(rebuild.RebuiltPods, pod.Name)
+				rebuild.CurrentPod = ""
+				rebuild.JobId = ""
+				rebuild.LastError = ""
+			case job.Status == cassandra.JobStatusError:
+				logger.Info("Rebuild failed, it will be retried", "Pod", pod.Name, "Error", job.Error)
+				rebuild.Progress = api.RebuildFailed
+				rebuild.LastError = fmt.Sprintf("rebuild of pod %s failed: %s", pod.Name, job.Error)
+				rebuild.JobId = ""
+				return result.RequeueSoon(r.LongDelay)
+			case job.Id == "":
+				logger.Info("Rebuild job not found, restarting rebuild", "Pod", pod.Name)
+				rebuild.JobId = ""
+			default:
+				logger.Info("Waiting for rebuild to complete", "Pod", pod.Name)
+				return result.RequeueSoon(r.DefaultDelay)
+			}
+		}
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if utils.SliceContains(rebuild.RebuiltPods, pod.Name) {
+			continue
+		}
+
+		jobId, err := managementApi.RebuildNode(pod, rebuild.SourceDatacenter)
+		if err != nil {
+			rebuild.Progress = api.RebuildFailed
+			rebuild.LastError = fmt.Sprintf("rebuild of pod %s failed: %s", pod.Name, err)
+			return result.Error(err)
+		}
+
+		logger.Info("Rebuild started", "Pod", pod.Name, "JobId", jobId)
+		rebuild.Progress = api.RebuildRunning
+		rebuild.CurrentPod = pod.Name
+		rebuild.JobId = jobId
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	logger.Info("All nodes rebuilt")
+	rebuild.Progress = api.RebuildCompleted
+	rebuild.CurrentPod = ""
+	return result.Continue()
+}
@@ -0,0 +1,263 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/k8ssandra/k8ssandra-operator/test/framework"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// addDatacenter creates a single-dc cluster, then adds dc2 to the spec and verifies that
+// dc2 is flagged for rebuild and that the rebuild completes once dc2 is ready.
+func addDatacenter(t *testing.T, ctx context.Context, f *framework.Framework, namespace string) {
+	require := require.New(t)
+
+	k8sCtx0 := "cluster-0"
+	k8sCtx1 := "cluster-1"
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test",
+		},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster: "test",
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{
+						Meta: api.EmbeddedObjectMeta{
+							Name: "dc1",
+						},
+						K8sContext:    k8sCtx0,
+						Size:          3,
+						ServerVersion: "3.11.10",
+						StorageConfig: &cassdcapi.StorageConfig{
+							CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+								StorageClassName: &defaultStorageClass,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := f.Client.Create(ctx, kc)
+	require.NoError(err, "failed to create K8ssandraCluster")
+
+	verifyFinalizerAdded(ctx, t, f, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+
+	verifySuperUserSecretCreated(ctx, t, f, kc)
+
+	verifyReplicatedSecretReconciled(ctx, t, f, kc)
+
+	verifySystemReplicationAnnotationSet(ctx, t, f, kc)
+
+	dc1Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc1"}, K8sContext: k8sCtx0}
+	dc2Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc2"}, K8sContext: k8sCtx1}
+	kcKey := framework.ClusterKey{K8sContext: k8sCtx0, NamespacedName: types.NamespacedName{Namespace: namespace, Name: "test"}}
+
+	t.Log("check that dc1 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc1Key), timeout, interval)
+
+	t.Log("update dc1 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc1Key)
+	require.NoError(err, "failed to set dc1 status ready")
+
+	t.Log("check that the cluster is initialized")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		if kc.Status.GetConditionStatus(api.CassandraInitialized) != corev1.ConditionTrue {
+			return false
+		}
+		kdcStatus, found := kc.Status.Datacenters[dc1Key.Name]
+		return found && kdcStatus.Rebuild == nil
+	}, timeout, interval, "timed out waiting for K8ssandraCluster to be initialized")
+
+	t.Log("add dc2 to the K8ssandraCluster")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kc.Spec.Cassandra.Datacenters = append(kc.Spec.Cassandra.Datacenters, api.CassandraDatacenterTemplate{
+			Meta: api.EmbeddedObjectMeta{
+				Name: "dc2",
+			},
+			K8sContext:    k8sCtx1,
+			Size:          3,
+			ServerVersion: "3.11.10",
+			StorageConfig: &cassdcapi.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &defaultStorageClass,
+				},
+			},
+		})
+		if err := f.Client.Update(ctx, kc); err != nil {
+			t.Logf("failed to update K8ssandraCluster: %v", err)
+			return false
+		}
+		return true
+	}, timeout, interval, "timed out updating K8ssandraCluster")
+
+	t.Log("check that dc2 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc2Key), timeout, interval)
+
+	t.Log("check that dc2 is pending rebuild")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kdcStatus, found := kc.Status.Datacenters[dc2Key.Name]
+		return found && kdcStatus.Rebuild != nil && kdcStatus.Rebuild.Progress == api.RebuildPending
+	}, timeout, interval, "timed out waiting for dc2 rebuild status")
+
+	t.Log("update dc2 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc2Key)
+	require.NoError(err, "failed to set dc2 status ready")
+
+	t.Log("check that the dc2 rebuild completed")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		rebuild := kc.Status.Datacenters[dc2Key.Name].Rebuild
+		return rebuild != nil && rebuild.Progress == api.RebuildCompleted && rebuild.SourceDatacenter == dc1Key.Name
+	}, timeout, interval, "timed out waiting for dc2 rebuild to complete")
+
+	t.Log("deleting K8ssandraCluster")
+	err = f.DeleteK8ssandraCluster(ctx, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+	require.NoError(err, "failed to delete K8ssandraCluster")
+	verifyObjectDoesNotExist(ctx, t, f, dc1Key, &cassdcapi.CassandraDatacenter{})
+	verifyObjectDoesNotExist(ctx, t, f, dc2Key, &cassdcapi.CassandraDatacenter{})
+}
+
+func TestReconcileRebuild(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc2"},
+		}}
+	}
+	dc2 := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc2"}}
+	remoteClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPod("dc2-pod-b"), newPod("dc2-pod-a")).Build()
+
+	kc := &api.K8ssandraCluster{
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				ReplicatedKeyspaces: []string{"app"},
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+					{Meta: api.EmbeddedObjectMeta{Name: "dc2"}, Size: 3},
+				},
+			},
+		},
+		Status: api.K8ssandraClusterStatus{
+			Datacenters: map[string]api.K8ssandraStatus{
+				"dc1": {Cassandra: &cassdcapi.CassandraDatacenterStatus{}},
+				"dc2": {Cassandra: &cassdcapi.CassandraDatacenterStatus{}, Rebuild: &api.RebuildStatus{Progress: api.RebuildPending}},
+			},
+		},
+	}
+	dcTemplate := kc.Spec.Cassandra.Datacenters[1]
+
+	managementApi := new(mocks.ManagementApiFacade)
+	recorder := record.NewFakeRecorder(10)
+	r := &K8ssandraClusterReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Recorder:         recorder,
+		ManagementApi:    &mockManagementApiFactory{managementApi: managementApi},
+	}
+	rebuild := kc.Status.Datacenters["dc2"].Rebuild
+	completed := &httphelper.JobDetails{Id: "job", Status: cassandra.JobStatusCompleted}
+
+	// dc2 is added to the replication of the replicated keyspaces, then its first pod is
+	// rebuilt from dc1.
+	managementApi.On("GetKeyspaceReplication", "app").Return(map[string]string{
+		"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc1": "3",
+	}, nil).Once()
+	managementApi.On("AlterKeyspace", "app", map[string]int{"dc1": 3, "dc2": 3}).Return(nil).Once()
+	managementApi.On("RebuildNode", mock.MatchedBy(podNamed("dc2-pod-a")), "dc1").Return("job-1", nil).Once()
+	recResult := r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{})
+	assert.True(t, recResult.Completed())
+	assert.Equal(t, api.RebuildRunning, rebuild.Progress)
+	assert.Equal(t, "dc1", rebuild.SourceDatacenter)
+	assert.Equal(t, "dc2-pod-a", rebuild.CurrentPod)
+	assert.Equal(t, "job-1", rebuild.JobId)
+
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc2-pod-a")), "job-1").Return(&httphelper.JobDetails{Id: "job-1", Status: cassandra.JobStatusWaiting}, nil).Once()
+	recResult = r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{})
+	assert.True(t, recResult.Completed())
+	assert.Equal(t, api.RebuildRunning, rebuild.Progress)
+	assert.Equal(t, "job-1", rebuild.JobId)
+
+	// A failed job is recorded, and the pod is rebuilt again on the next reconciliation.
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc2-pod-a")), "job-1").Return(&httphelper.JobDetails{Id: "job-1", Status: cassandra.JobStatusError, Error: "boom"}, nil).Once()
+	recResult = r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{})
+	assert.True(t, recResult.Completed())
+	res, err := recResult.Output()
+	require.NoError(t, err)
+	assert.Equal(t, r.LongDelay, res.RequeueAfter)
+	assert.Equal(t, api.RebuildFailed, rebuild.Progress)
+	assert.Equal(t, "rebuild of pod dc2-pod-a failed: boom", rebuild.LastError)
+	assert.Empty(t, rebuild.JobId)
+	assert.Empty(t, rebuild.RebuiltPods)
+
+	managementApi.On("RebuildNode", mock.MatchedBy(podNamed("dc2-pod-a")), "dc1").Return("job-2", nil).Once()
+	r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{})
+	assert.Equal(t, api.RebuildRunning, rebuild.Progress)
+	assert.Equal(t, "job-2", rebuild.JobId)
+
+	// Once a job completes, the next pod is rebuilt.
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc2-pod-a")), "job-2").Return(completed, nil).Once()
+	managementApi.On("RebuildNode", mock.MatchedBy(podNamed("dc2-pod-b")), "dc1").Return("job-3", nil).Once()
+	r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{})
+	assert.Equal(t, []string{"dc2-pod-a"}, rebuild.RebuiltPods)
+	assert.Equal(t, "dc2-pod-b", rebuild.CurrentPod)
+	assert.Empty(t, rebuild.LastError)
+
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc2-pod-b")), "job-3").Return(completed, nil).Once()
+	recResult = r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{})
+	assert.False(t, recResult.Completed())
+	assert.Equal(t, api.RebuildCompleted, rebuild.Progress)
+	assert.Equal(t, []string{"dc2-pod-a", "dc2-pod-b"}, rebuild.RebuiltPods)
+	managementApi.AssertExpectations(t)
+
+	assert.Len(t, recorder.Events, 3)
+
+	// A completed rebuild is not run again.
+	assert.False(t, r.reconcileRebuild(ctx, kc, dcTemplate, dc2, remoteClient, log.NullLogger{}).Completed())
+}
@@ -0,0 +1,189 @@
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requestSystemRepair adds keyspace and datacenters to the repair of the system keyspaces
+// of kc. A repair in progress is restarted so that the nodes that were already repaired
+// get the new replicas too.
+func requestSystemRepair(kc *api.K8ssandraCluster, keyspace string, datacenters []string) {
+	repair := kc.Status.SystemRepair
+	if repair == nil || repair.Progress == api.RepairCompleted {
+		repair = &api.RepairStatus{}
+		kc.Status.SystemRepair = repair
+	}
+	if !utils.SliceContains(repair.Keyspaces, keyspace) {
+		repair.Keyspaces = append(repair.Keyspaces, keyspace)
+	}
+	for _, dcName := range datacenters {
+		if !utils.SliceContains(repair.Datacenters, dcName) {
+			repair.Datacenters = append(repair.Datacenters, dcName)
+		}
+	}
+	repair.Progress = api.RepairPending
+	repair.CurrentPod = ""
+	repair.CurrentKeyspace = ""
+	repair.JobId = ""
+	repair.RepairedPods = nil
+}
+
+// reconcileSystemRepair repairs the system keyspaces after their replication factor was
+// raised, as requested in kc.Status.SystemRepair. The nodes of the datacenters are repaired
+// one at a time through the management API. It is called once all the datacenters are
+// ready.
+func (r *K8ssandraClusterReconciler) reconcileSystemRepair(ctx context.Context, kc *api.K8ssandraCluster, dcs []*dcReconciliation, logger logr.Logger) result.ReconcileResult {
+	repair := kc.Status.SystemRepair
+	if repair == nil || repair.Progress == api.RepairCompleted {
+		return result.Continue()
+	}
+
+	previousProgress := repair.Progress
+	recResult := r.repairNodes(ctx, repair, dcs, logger)
+	if repair.Progress != previousProgress {
+		switch repair.Progress {
+		case api.RepairRunning:
+			if previousProgress == api.RepairPending {
+				r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.RepairStarted,
+					"Repairing keyspaces %v in datacenters %v", repair.Keyspaces, repair.Datacenters)
+			}
+		case api.RepairCompleted:
+			r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.RepairCompleted,
+				"Repaired keyspaces %v in datacenters %v", repair.Keyspaces, repair.Datacenters)
+		case api.RepairFailed:
+			r.Recorder.Event(kc, corev1.EventTypeWarning, events.RepairFailed, repair.LastError)
+		}
+	}
+	return recResult
+}
+
+// repairNodes repairs the keyspaces of repair on the nodes of its datacenters, one node and
+// one keyspace at a time. Progress is recorded in repair so that the repair can be followed
+// across reconciliations. A failed repair is retried after r.LongDelay.
+func (r *K8ssandraClusterReconciler) repairNodes(ctx context.Context, repair *api.RepairStatus, dcs []*dcReconciliation, logger logr.Logger) result.ReconcileResult {
+	for _, dc := range dcs {
+		if !utils.SliceContains(repair.Datacenters, dc.desiredDc.Name) {
+			continue
+		}
+		if dc.actualDc == nil || dc.stopped {
+			dc.logger.Info("Waiting for the datacenter to be running to repair it")
+			return result.RequeueSoon(r.LongDelay)
+		}
+
+		pods := &corev1.PodList{}
+		if err := dc.remoteClient.List(ctx, pods, client.InNamespace(dc.actualDc.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: dc.actualDc.Name}); err != nil {
+			dc.logger.Error(err, "Failed to list datacenter pods")
+			return result.Error(err)
+		}
+		sort.Slice(pods.Items, func(i, j int) bool {
+			return pods.Items[i].Name < pods.Items[j].Name
+		})
+
+		var managementApi cassandra.ManagementApiFacade
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if utils.SliceContains(repair.RepairedPods, pod.Name) {
+				continue
+			}
+
+			if managementApi == nil {
+				var err error
+				if managementApi, err = r.ManagementApi.NewManagementApiFacade(ctx, dc.actualDc, dc.remoteClient, dc.logger); err != nil {
+					dc.logger.Error(err, "Failed to create ManagementApiFacade")
+					return result.Error(err)
+				}
+			}
+
+			if recResult := r.repairNode(pod, repair, managementApi, dc.logger); recResult.Completed() {
+				return recResult
+			}
+		}
+	}
+
+	logger.Info("All nodes repaired", "Keyspaces", repair.Keyspaces)
+	repair.Progress = api.RepairCompleted
+	repair.CurrentPod = ""
+	repair.CurrentKeyspace = ""
+	return result.Continue()
+}
+
+// repairNode repairs the keyspaces of repair on pod, one at a time. It returns a completed
+// result while a repair job is running.
+func (r *K8ssandraClusterReconciler) repairNode(pod *corev1.Pod, repair *api.RepairStatus, managementApi cassandra.ManagementApiFacade, logger logr.Logger) result.ReconcileResult {
+	if repair.CurrentPod != pod.Name || !utils.SliceContains(repair.Keyspaces, repair.CurrentKeyspace) {
+		repair.CurrentPod = pod.Name
+		repair.CurrentKeyspace = repair.Keyspaces[0]
+		repair.JobId = ""
+	}
+
+	for {
+		if repair.JobId == "" {
+			jobId, err := managementApi.RepairKeyspace(pod, repair.CurrentKeyspace)
+			if err != nil {
+				repair.Progress = api.RepairFailed
+				repair.LastError = fmt.Sprintf("repair of keyspace %s on pod %s failed: %s", repair.CurrentKeyspace, pod.Name, err)
+				return result.Error(err)
+			}
+			logger.Info("Repair started", "Pod", pod.Name, "Keyspace", repair.CurrentKeyspace, "JobId", jobId)
+			repair.Progress = api.RepairRunning
+			repair.JobId = jobId
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+
+		job, err := managementApi.GetJobDetails(pod, repair.JobId)
+		if err != nil {
+			logger.Error(err, "Failed to get repair job details", "Pod", pod.Name)
+			return result.Error(err)
+		}
+
+		switch {
+		case job.Status == cassandra.JobStatusCompleted:
+			logger.Info("Repair completed", "Pod", pod.Name, "Keyspace", repair.CurrentKeyspace)
+			repair.JobId = ""
+			repair.LastError = ""
+			next := nextKeyspace(repair.Keyspaces, repair.CurrentKeyspace)
+			if next == "" {
+				repair.RepairedPods = append(repair.RepairedPods, pod.Name)
+				repair.CurrentPod = ""
+				repair.CurrentKeyspace = ""
+				return result.Continue()
+			}
+			repair.CurrentKeyspace = next
+		case job.Status == cassandra.JobStatusError:
+			logger.Info("Repair failed, it will be retried", "Pod", pod.Name, "Keyspace", repair.CurrentKeyspace, "Error", job.Error)
+			repair.Progress = api.RepairFailed
+			repair.LastError = fmt.Sprintf("repair of keyspace %s on pod %s failed: %s", repair.CurrentKeyspace, pod.Name, job.Error)
+			repair.JobId = ""
+			return result.RequeueSoon(r.LongDelay)
+		case job.Id == "":
+			logger.Info("Repair job not found, restarting repair", "Pod", pod.Name, "Keyspace", repair.CurrentKeyspace)
+			repair.JobId = ""
+		default:
+			logger.Info("Waiting for repair to complete", "Pod", pod.Name, "Keyspace", repair.CurrentKeyspace)
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+	}
+}
+
+// nextKeyspace returns the keyspace that follows keyspace in keyspaces, or an empty string
+// if it is the last one.
+func nextKeyspace(keyspaces []string, keyspace string) string {
+	for i, ks := range keyspaces {
+		if ks == keyspace && i+1 < len(keyspaces) {
+			return keyspaces[i+1]
+		}
+	}
+	return ""
+}
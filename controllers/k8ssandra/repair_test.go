@@ -0,0 +1,165 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestUpdateSystemKeyspaceReplication(t *testing.T) {
+	newCluster := func() *api.K8ssandraCluster {
+		return &api.K8ssandraCluster{
+			Spec: api.K8ssandraClusterSpec{
+				Cassandra: &api.CassandraClusterTemplate{
+					Datacenters: []api.CassandraDatacenterTemplate{
+						{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+						{Meta: api.EmbeddedObjectMeta{Name: "dc2"}, Size: 3},
+					},
+				},
+			},
+		}
+	}
+	r := &K8ssandraClusterReconciler{}
+
+	t.Run("replication up to date", func(t *testing.T) {
+		kc := newCluster()
+		managementApi := new(mocks.ManagementApiFacade)
+		managementApi.On("GetKeyspaceReplication", "system_auth").Return(map[string]string{
+			"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc1": "3", "dc2": "3",
+		}, nil)
+
+		require.NoError(t, r.updateSystemKeyspaceReplication(kc, "system_auth", managementApi, log.NullLogger{}))
+		managementApi.AssertNotCalled(t, "AlterKeyspace", mock.Anything, mock.Anything)
+		assert.Nil(t, kc.Status.SystemRepair)
+	})
+
+	t.Run("datacenter grew", func(t *testing.T) {
+		kc := newCluster()
+		managementApi := new(mocks.ManagementApiFacade)
+		managementApi.On("GetKeyspaceReplication", "system_auth").Return(map[string]string{
+			"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc1": "1",
+		}, nil)
+		managementApi.On("AlterKeyspace", "system_auth", map[string]int{"dc1": 3, "dc2": 3}).Return(nil).Once()
+
+		require.NoError(t, r.updateSystemKeyspaceReplication(kc, "system_auth", managementApi, log.NullLogger{}))
+		managementApi.AssertExpectations(t)
+		require.NotNil(t, kc.Status.SystemRepair)
+		assert.Equal(t, api.RepairPending, kc.Status.SystemRepair.Progress)
+		assert.Equal(t, []string{"system_auth"}, kc.Status.SystemRepair.Keyspaces)
+		assert.Equal(t, []string{"dc1"}, kc.Status.SystemRepair.Datacenters)
+	})
+
+	t.Run("replication factor does not grow", func(t *testing.T) {
+		kc := newCluster()
+		grow := false
+		kc.Spec.Cassandra.SystemReplication = &api.SystemReplicationPolicy{GrowWithDatacenterSize: &grow}
+		managementApi := new(mocks.ManagementApiFacade)
+		managementApi.On("GetKeyspaceReplication", "system_auth").Return(map[string]string{
+			"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc1": "1",
+		}, nil)
+		managementApi.On("AlterKeyspace", "system_auth", map[string]int{"dc1": 1, "dc2": 3}).Return(nil).Once()
+
+		require.NoError(t, r.updateSystemKeyspaceReplication(kc, "system_auth", managementApi, log.NullLogger{}))
+		managementApi.AssertExpectations(t)
+		assert.Nil(t, kc.Status.SystemRepair)
+	})
+}
+
+func TestReconcileSystemRepair(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+		}}
+	}
+	dc1 := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"}}
+	remoteClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPod("dc1-pod-b"), newPod("dc1-pod-a")).Build()
+	dcs := []*dcReconciliation{
+		{desiredDc: dc1, actualDc: dc1, remoteClient: remoteClient, ready: true, logger: log.NullLogger{}},
+		{desiredDc: &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc2"}}, logger: log.NullLogger{}},
+	}
+
+	kc := &api.K8ssandraCluster{}
+	requestSystemRepair(kc, "system_auth", []string{"dc1"})
+	requestSystemRepair(kc, "system_traces", []string{"dc1"})
+
+	managementApi := new(mocks.ManagementApiFacade)
+	recorder := record.NewFakeRecorder(10)
+	r := &K8ssandraClusterReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Recorder:         recorder,
+		ManagementApi:    &mockManagementApiFactory{managementApi: managementApi},
+	}
+	completed := &httphelper.JobDetails{Id: "job", Status: cassandra.JobStatusCompleted}
+
+	// The pods are repaired in order of their names, one keyspace at a time.
+	managementApi.On("RepairKeyspace", mock.MatchedBy(podNamed("dc1-pod-a")), "system_auth").Return("job-1", nil).Once()
+	recResult := r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	assert.True(t, recResult.Completed())
+	assert.Equal(t, api.RepairRunning, kc.Status.SystemRepair.Progress)
+	assert.Equal(t, "dc1-pod-a", kc.Status.SystemRepair.CurrentPod)
+	assert.Equal(t, "job-1", kc.Status.SystemRepair.JobId)
+
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc1-pod-a")), "job-1").Return(&httphelper.JobDetails{Id: "job-1", Status: cassandra.JobStatusError, Error: "boom"}, nil).Once()
+	recResult = r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	assert.True(t, recResult.Completed())
+	assert.Equal(t, api.RepairFailed, kc.Status.SystemRepair.Progress)
+	assert.Equal(t, "repair of keyspace system_auth on pod dc1-pod-a failed: boom", kc.Status.SystemRepair.LastError)
+	assert.Empty(t, kc.Status.SystemRepair.JobId)
+
+	managementApi.On("RepairKeyspace", mock.MatchedBy(podNamed("dc1-pod-a")), "system_auth").Return("job-2", nil).Once()
+	r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc1-pod-a")), "job-2").Return(completed, nil).Once()
+	managementApi.On("RepairKeyspace", mock.MatchedBy(podNamed("dc1-pod-a")), "system_traces").Return("job-3", nil).Once()
+	r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	assert.Equal(t, "system_traces", kc.Status.SystemRepair.CurrentKeyspace)
+
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc1-pod-a")), "job-3").Return(completed, nil).Once()
+	managementApi.On("RepairKeyspace", mock.MatchedBy(podNamed("dc1-pod-b")), "system_auth").Return("job-4", nil).Once()
+	r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	assert.Equal(t, []string{"dc1-pod-a"}, kc.Status.SystemRepair.RepairedPods)
+	assert.Equal(t, "dc1-pod-b", kc.Status.SystemRepair.CurrentPod)
+
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc1-pod-b")), "job-4").Return(completed, nil).Once()
+	managementApi.On("RepairKeyspace", mock.MatchedBy(podNamed("dc1-pod-b")), "system_traces").Return("job-5", nil).Once()
+	r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	managementApi.On("GetJobDetails", mock.MatchedBy(podNamed("dc1-pod-b")), "job-5").Return(completed, nil).Once()
+	recResult = r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{})
+	assert.False(t, recResult.Completed())
+	assert.Equal(t, api.RepairCompleted, kc.Status.SystemRepair.Progress)
+	assert.Equal(t, []string{"dc1-pod-a", "dc1-pod-b"}, kc.Status.SystemRepair.RepairedPods)
+	managementApi.AssertExpectations(t)
+
+	assert.Len(t, recorder.Events, 3)
+
+	// A completed repair is not run again.
+	assert.False(t, r.reconcileSystemRepair(ctx, kc, dcs, log.NullLogger{}).Completed())
+}
+
+func podNamed(name string) func(*corev1.Pod) bool {
+	return func(pod *corev1.Pod) bool {
+		return pod.Name == name
+	}
+}
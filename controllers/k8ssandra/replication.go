@@ -9,6 +9,7 @@ import (
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -47,9 +48,10 @@ func (r *K8ssandraClusterReconciler) checkSystemReplication(ctx context.Context,
 	return &replication, nil
 }
 
-// updateReplicationOfSystemKeyspaces ensures that the replication for the system_auth,
-// system_traces, and system_distributed keyspaces is up to date. It ensures that there are
-// replicas for each DC and that there is a max of 3 replicas per DC.
+// updateReplicationOfSystemKeyspaces ensures that the replication of the system keyspaces,
+// and of the additional keyspaces of the system replication policy, is up to date. Each
+// datacenter of kc is given a replication factor according to the policy. A repair of the
+// datacenters whose replication factor was raised is requested in kc.Status.SystemRepair.
 func (r *K8ssandraClusterReconciler) updateReplicationOfSystemKeyspaces(ctx context.Context, kc *api.K8ssandraCluster, dc *cassdcapi.CassandraDatacenter, remoteClient client.Client, logger logr.Logger) result.ReconcileResult {
 	managementApiFacade, err := r.ManagementApi.NewManagementApiFacade(ctx, dc, remoteClient, logger)
 	if err != nil {
@@ -57,17 +59,53 @@ func (r *K8ssandraClusterReconciler) updateReplicationOfSystemKeyspaces(ctx cont
 		return result.Error(err)
 	}
 
-	keyspaces := []string{"system_traces", "system_distributed", "system_auth"}
-	replication := cassandra.ComputeReplication(3, kc.Spec.Cassandra.Datacenters...)
-
-	logger.Info("Preparing to update replication for system keyspaces", "replication", replication)
+	// The additional keyspaces may not exist, e.g., the DSE keyspaces on Cassandra
+	existingKeyspaces, err := managementApiFacade.ListKeyspaces("")
+	if err != nil {
+		logger.Error(err, "Failed to list keyspaces")
+		return result.Error(err)
+	}
 
-	for _, ks := range keyspaces {
-		if err := managementApiFacade.EnsureKeyspaceReplication(ks, replication); err != nil {
-			logger.Error(err, "Failed to update replication", "keyspace", ks)
+	for _, ks := range cassandra.SystemKeyspaces(kc.Spec.Cassandra) {
+		if !utils.SliceContains(existingKeyspaces, ks) {
+			continue
+		}
+		if err := r.updateSystemKeyspaceReplication(kc, ks, managementApiFacade, logger); err != nil {
 			return result.Error(err)
 		}
 	}
 
 	return result.Continue()
 }
+
+// updateSystemKeyspaceReplication sets the replication of keyspace according to the system
+// replication policy of kc, and requests a repair of the datacenters whose replication
+// factor is raised.
+func (r *K8ssandraClusterReconciler) updateSystemKeyspaceReplication(kc *api.K8ssandraCluster, keyspace string, managementApi cassandra.ManagementApiFacade, logger logr.Logger) error {
+	actualReplication, err := managementApi.GetKeyspaceReplication(keyspace)
+	if err != nil {
+		logger.Error(err, "Failed to get keyspace replication", "Keyspace", keyspace)
+		return err
+	}
+
+	currentReplication, ok := cassandra.ParseReplication(actualReplication)
+	if !ok {
+		currentReplication = map[string]int{}
+	}
+	replication := cassandra.ComputeSystemKeyspacesReplication(kc.Spec.Cassandra, currentReplication)
+	if cassandra.CompareReplications(actualReplication, replication) {
+		return nil
+	}
+
+	logger.Info("Updating replication of system keyspace", "Keyspace", keyspace, "Replication", replication)
+	if err := managementApi.AlterKeyspace(keyspace, replication); err != nil {
+		logger.Error(err, "Failed to update replication", "Keyspace", keyspace)
+		return err
+	}
+
+	if raised := cassandra.RaisedReplication(currentReplication, replication); len(raised) > 0 {
+		logger.Info("Requesting repair of system keyspace", "Keyspace", keyspace, "Datacenters", raised)
+		requestSystemRepair(kc, keyspace, raised)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package k8ssandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/stargate"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileRollingRestart performs the rolling restart requested through
+// kc.Spec.RollingRestart. The datacenters are restarted one at a time, in the order in
+// which they are declared, by setting RollingRestartRequested on the CassandraDatacenter.
+// This is only called once all the datacenters are ready, so the restart of a datacenter
+// is complete once cass-operator reports that it finished the rolling restart.
+func (r *K8ssandraClusterReconciler) reconcileRollingRestart(ctx context.Context, kc *api.K8ssandraCluster, dcs []*cassdcapi.CassandraDatacenter, logger logr.Logger) result.ReconcileResult {
+	request := kc.Spec.RollingRestart
+	if request == nil || request.RequestedAt.IsZero() {
+		return result.Continue()
+	}
+
+	status := kc.Status.RollingRestart
+	if status == nil || !status.RequestedAt.Equal(&request.RequestedAt) {
+		logger.Info("Starting rolling restart", "RequestedAt", request.RequestedAt)
+		now := metav1.Now().Rfc3339Copy()
+		status = &api.RollingRestartStatus{
+			RequestedAt: request.RequestedAt,
+			StartTime:   &now,
+		}
+		kc.Status.RollingRestart = status
+		r.Recorder.Event(kc, corev1.EventTypeNormal, events.RollingRestartStarted, "Starting rolling restart")
+	} else if status.CompletionTime != nil {
+		return result.Continue()
+	}
+
+	for i, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		dc := dcs[i]
+		if utils.SliceContains(status.RestartedDatacenters, dc.Name) {
+			continue
+		}
+
+		logger := logger.WithValues("CassandraDatacenter", utils.GetKey(dc), "K8SContext", dcTemplate.K8sContext)
+
+		if status.Datacenter == dc.Name && status.DatacenterStartTime != nil {
+			if cassandra.DatacenterRestartedAfter(status.DatacenterStartTime.Time, dc) && cassandra.DatacenterReady(dc) {
+				logger.Info("Datacenter restarted")
+				status.RestartedDatacenters = append(status.RestartedDatacenters, dc.Name)
+				status.Datacenter = ""
+				status.DatacenterStartTime = nil
+				continue
+			}
+			logger.Info("Waiting for datacenter rolling restart to complete")
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+
+		remoteClient, err := r.ClientCache.GetRemoteClient(dcTemplate.K8sContext)
+		if err != nil {
+			logger.Error(err, "Failed to get remote client")
+			return result.Error(err)
+		}
+
+		logger.Info("Requesting datacenter rolling restart")
+		patch := client.MergeFromWithOptions(dc.DeepCopy())
+		dc.Spec.RollingRestartRequested = true
+		if err := remoteClient.Patch(ctx, dc, patch); err != nil {
+			logger.Error(err, "Failed to request datacenter rolling restart")
+			return result.Error(err)
+		}
+
+		now := metav1.Now().Rfc3339Copy()
+		status.Datacenter = dc.Name
+		status.DatacenterStartTime = &now
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	if request.RestartStargate {
+		if err := r.restartStargates(ctx, kc, dcs, request.RequestedAt, logger); err != nil {
+			return result.Error(err)
+		}
+	}
+
+	logger.Info("Rolling restart completed", "RequestedAt", request.RequestedAt)
+	now := metav1.Now()
+	status.CompletionTime = &now
+	r.Recorder.Event(kc, corev1.EventTypeNormal, events.RollingRestartCompleted, "Rolling restart completed")
+
+	return result.Continue()
+}
+
+// restartStargates triggers a rollout of the Stargate deployments of each datacenter by
+// setting the RestartedAtAnnotation on their pod template, the same way kubectl rollout
+// restart does.
+func (r *K8ssandraClusterReconciler) restartStargates(ctx context.Context, kc *api.K8ssandraCluster, dcs []*cassdcapi.CassandraDatacenter, requestedAt metav1.Time, logger logr.Logger) error {
+	for i, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		if dcTemplate.Stargate.Coalesce(kc.Spec.Stargate) == nil {
+			continue
+		}
+
+		dc := dcs[i]
+		logger := logger.WithValues("CassandraDatacenter", utils.GetKey(dc), "K8SContext", dcTemplate.K8sContext)
+
+		remoteClient, err := r.ClientCache.GetRemoteClient(dcTemplate.K8sContext)
+		if err != nil {
+			logger.Error(err, "Failed to get remote client")
+			return err
+		}
+
+		deployments := &appsv1.DeploymentList{}
+		selector := client.MatchingLabels{stargateapi.StargateLabel: stargate.ResourceName(kc, dc)}
+		if err := remoteClient.List(ctx, deployments, client.InNamespace(dc.Namespace), selector); err != nil {
+			logger.Error(err, "Failed to list Stargate deployments")
+			return err
+		}
+
+		restartedAt := requestedAt.UTC().Format(time.RFC3339)
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			if deployment.Spec.Template.Annotations[api.RestartedAtAnnotation] == restartedAt {
+				continue
+			}
+
+			logger.Info("Restarting Stargate deployment", "Deployment", deployment.Name)
+			patch := client.MergeFrom(deployment.DeepCopy())
+			if deployment.Spec.Template.Annotations == nil {
+				deployment.Spec.Template.Annotations = make(map[string]string)
+			}
+			deployment.Spec.Template.Annotations[api.RestartedAtAnnotation] = restartedAt
+			if err := remoteClient.Patch(ctx, deployment, patch); err != nil {
+				logger.Error(err, "Failed to restart Stargate deployment", "Deployment", deployment.Name)
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/test/framework"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rollingRestart creates a two-dc cluster, requests a rolling restart, and verifies that
+// the datacenters are restarted one at a time.
+func rollingRestart(t *testing.T, ctx context.Context, f *framework.Framework, namespace string) {
+	require := require.New(t)
+
+	k8sCtx0 := "cluster-0"
+	k8sCtx1 := "cluster-1"
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test",
+		},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:       "test",
+				ServerVersion: "3.11.10",
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &defaultStorageClass,
+					},
+				},
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc1"},
+						K8sContext: k8sCtx0,
+						Size:       3,
+					},
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc2"},
+						K8sContext: k8sCtx1,
+						Size:       3,
+					},
+				},
+			},
+		},
+	}
+
+	err := f.Client.Create(ctx, kc)
+	require.NoError(err, "failed to create K8ssandraCluster")
+
+	verifyFinalizerAdded(ctx, t, f, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+
+	verifySuperUserSecretCreated(ctx, t, f, kc)
+
+	verifyReplicatedSecretReconciled(ctx, t, f, kc)
+
+	verifySystemReplicationAnnotationSet(ctx, t, f, kc)
+
+	dc1Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc1"}, K8sContext: k8sCtx0}
+	dc2Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc2"}, K8sContext: k8sCtx1}
+	kcKey := framework.ClusterKey{K8sContext: k8sCtx0, NamespacedName: types.NamespacedName{Namespace: namespace, Name: "test"}}
+
+	t.Log("check that dc1 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc1Key), timeout, interval)
+
+	t.Log("update dc1 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc1Key)
+	require.NoError(err, "failed to set dc1 status ready")
+
+	t.Log("check that dc2 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc2Key), timeout, interval)
+
+	t.Log("update dc2 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc2Key)
+	require.NoError(err, "failed to set dc2 status ready")
+
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		return kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionTrue
+	}, timeout, interval, "timed out waiting for K8ssandraCluster to be initialized")
+
+	t.Log("request a rolling restart")
+	requestedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kc.Spec.RollingRestart = &api.RollingRestartRequest{RequestedAt: requestedAt}
+		if err := f.Client.Update(ctx, kc); err != nil {
+			t.Logf("failed to update K8ssandraCluster: %v", err)
+			return false
+		}
+		return true
+	}, timeout, interval, "timed out updating K8ssandraCluster")
+
+	restartRequested := func(dc *cassdcapi.CassandraDatacenter) bool {
+		return dc.Spec.RollingRestartRequested
+	}
+
+	t.Log("check that a rolling restart of dc1 is requested")
+	require.Eventually(f.NewWithDatacenter(ctx, dc1Key)(restartRequested), timeout, interval)
+
+	t.Log("check that a rolling restart of dc2 is not requested yet")
+	require.False(f.NewWithDatacenter(ctx, dc2Key)(restartRequested)(), "dc2 should not be restarted before dc1")
+
+	simulateRollingRestart(ctx, t, f, dc1Key)
+
+	t.Log("check that a rolling restart of dc2 is requested")
+	require.Eventually(f.NewWithDatacenter(ctx, dc2Key)(restartRequested), timeout, interval)
+
+	simulateRollingRestart(ctx, t, f, dc2Key)
+
+	t.Log("check that the rolling restart is recorded in the K8ssandraCluster status")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		status := kc.Status.RollingRestart
+		return status != nil && status.RequestedAt.Equal(&requestedAt) && status.CompletionTime != nil &&
+			len(status.RestartedDatacenters) == 2
+	}, timeout, interval, "timed out waiting for the rolling restart to complete")
+
+	t.Log("deleting K8ssandraCluster")
+	err = f.DeleteK8ssandraCluster(ctx, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+	require.NoError(err, "failed to delete K8ssandraCluster")
+	verifyObjectDoesNotExist(ctx, t, f, dc1Key, &cassdcapi.CassandraDatacenter{})
+	verifyObjectDoesNotExist(ctx, t, f, dc2Key, &cassdcapi.CassandraDatacenter{})
+}
+
+// simulateRollingRestart updates the datacenter the way cass-operator does when it
+// performs a rolling restart.
+func simulateRollingRestart(ctx context.Context, t *testing.T, f *framework.Framework, dcKey framework.ClusterKey) {
+	t.Logf("simulate rolling restart of %s", dcKey.Name)
+
+	// Let the LastRollingRestart timestamp move past the time at which the restart was
+	// requested, given that timestamps only have a precision of one second.
+	time.Sleep(time.Second)
+
+	startTime := metav1.Now()
+	err := f.PatchDatacenterStatus(ctx, dcKey, func(dc *cassdcapi.CassandraDatacenter) {
+		dc.Status.LastRollingRestart = startTime
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterRollingRestart,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: startTime,
+		})
+	})
+	require.NoError(t, err, "failed to patch datacenter status")
+
+	err = f.PatchDatacenter(ctx, dcKey, func(dc *cassdcapi.CassandraDatacenter) {
+		dc.Spec.RollingRestartRequested = false
+	})
+	require.NoError(t, err, "failed to patch datacenter")
+
+	err = f.PatchDatacenterStatus(ctx, dcKey, func(dc *cassdcapi.CassandraDatacenter) {
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterRollingRestart,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+		})
+	})
+	require.NoError(t, err, "failed to patch datacenter status")
+}
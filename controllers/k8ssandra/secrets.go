@@ -26,6 +26,20 @@ func (r *K8ssandraClusterReconciler) reconcileSuperuserSecret(ctx context.Contex
 	return result.Continue()
 }
 
+// reconcileLDAPBindSecret marks the bind Secret of the LDAP provider as managed by kc, so
+// that it is replicated along with the other secrets.
+func (r *K8ssandraClusterReconciler) reconcileLDAPBindSecret(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) result.ReconcileResult {
+	if !kc.Spec.Cassandra.Auth.IsLDAPEnabled() {
+		return result.Continue()
+	}
+	secretName := kc.Spec.Cassandra.Auth.LDAP.BindSecretRef.Name
+	if _, err := secret.AdoptSecret(ctx, r.Client, secretName, utils.GetKey(kc)); err != nil {
+		logger.Error(err, "Failed to reconcile the LDAP bind secret", "Secret", secretName)
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
 func (r *K8ssandraClusterReconciler) reconcileReplicatedSecret(ctx context.Context, kc *api.K8ssandraCluster, logger logr.Logger) result.ReconcileResult {
 	if err := secret.ReconcileReplicatedSecret(ctx, r.Client, r.Scheme, kc, logger); err != nil {
 		logger.Error(err, "Failed to reconcile ReplicatedSecret")
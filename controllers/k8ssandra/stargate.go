@@ -11,6 +11,7 @@ import (
 	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/stargate"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +24,7 @@ func (r *K8ssandraClusterReconciler) reconcileStargate(
 	kc *api.K8ssandraCluster,
 	dcTemplate api.CassandraDatacenterTemplate,
 	actualDc *cassdcapi.CassandraDatacenter,
+	serverVersion string,
 	logger logr.Logger,
 	remoteClient client.Client,
 ) result.ReconcileResult {
@@ -39,7 +41,20 @@ func (r *K8ssandraClusterReconciler) reconcileStargate(
 	if stargateTemplate != nil {
 		logger.Info("Reconcile Stargate")
 
-		desiredStargate := r.newStargate(stargateKey, kc, stargateTemplate, actualDc)
+		var encryptionStores *cassandra.EncryptionStores
+		if kc.Spec.Cassandra.TLS.IsInternodeEncryptionEnabled() {
+			var err error
+			if encryptionStores, err = r.encryptionStores(ctx, kc, dcTemplate.Meta.Name); err != nil {
+				logger.Error(err, "Failed to get encryption stores")
+				return result.Error(err)
+			}
+			if stargateTemplate, err = r.reconcileStargateEncryption(ctx, kc, stargateKey, stargateTemplate, encryptionStores, remoteClient, logger); err != nil {
+				logger.Error(err, "Failed to reconcile the Cassandra config of Stargate")
+				return result.Error(err)
+			}
+		}
+
+		desiredStargate := r.newStargate(stargateKey, kc, stargateTemplate, actualDc, serverVersion, encryptionStores)
 		annotations.AddHashAnnotation(desiredStargate)
 
 		if err := remoteClient.Get(ctx, stargateKey, actualStargate); err != nil {
@@ -104,7 +119,7 @@ func (r *K8ssandraClusterReconciler) reconcileStargate(
 	return result.Continue()
 }
 
-func (r *K8ssandraClusterReconciler) newStargate(stargateKey types.NamespacedName, kc *api.K8ssandraCluster, stargateTemplate *stargateapi.StargateDatacenterTemplate, actualDc *cassdcapi.CassandraDatacenter) *stargateapi.Stargate {
+func (r *K8ssandraClusterReconciler) newStargate(stargateKey types.NamespacedName, kc *api.K8ssandraCluster, stargateTemplate *stargateapi.StargateDatacenterTemplate, actualDc *cassdcapi.CassandraDatacenter, serverVersion string, encryptionStores *cassandra.EncryptionStores) *stargateapi.Stargate {
 	desiredStargate := &stargateapi.Stargate{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   stargateKey.Namespace,
@@ -122,11 +137,79 @@ func (r *K8ssandraClusterReconciler) newStargate(stargateKey types.NamespacedNam
 		Spec: stargateapi.StargateSpec{
 			StargateDatacenterTemplate: *stargateTemplate,
 			DatacenterRef:              corev1.LocalObjectReference{Name: actualDc.Name},
+			ServerVersion:              serverVersion,
 		},
 	}
+	if !kc.Spec.Cassandra.Auth.IsEnabled() {
+		auth := false
+		desiredStargate.Spec.Auth = &auth
+	}
+	if encryptionStores != nil {
+		desiredStargate.Spec.EncryptionStores = encryptionStores.Stores.DeepCopy()
+		desiredStargate.Annotations[api.EncryptionStoresHashAnnotation] = encryptionStores.Hash
+	}
 	return desiredStargate
 }
 
+// reconcileStargateEncryption writes the cassandra.yaml of the Stargate resource of
+// stargateKey, with server_encryption_options that use encryptionStores, to a ConfigMap. It
+// is based on the cassandra.yaml of the ConfigMap referenced by stargateTemplate, if any.
+// It returns a copy of stargateTemplate where the datacenter and the racks reference the
+// new ConfigMap.
+func (r *K8ssandraClusterReconciler) reconcileStargateEncryption(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	stargateKey types.NamespacedName,
+	stargateTemplate *stargateapi.StargateDatacenterTemplate,
+	encryptionStores *cassandra.EncryptionStores,
+	remoteClient client.Client,
+	logger logr.Logger,
+) (*stargateapi.StargateDatacenterTemplate, error) {
+	cassandraYaml := ""
+	if ref := stargateTemplate.CassandraConfigMapRef; ref != nil {
+		configMapKey := types.NamespacedName{Namespace: stargateKey.Namespace, Name: ref.Name}
+		configMap := &corev1.ConfigMap{}
+		if err := remoteClient.Get(ctx, configMapKey, configMap); err != nil {
+			logger.Error(err, "Failed to get ConfigMap", "ConfigMap", configMapKey)
+			return nil, err
+		}
+		cassandraYaml = configMap.Data[stargate.CassandraYamlFile]
+	}
+	options := cassandra.ServerEncryptionOptions(kc.Spec.Cassandra.TLS, encryptionStores)
+	cassandraYaml, err := stargate.AddServerEncryptionOptions(cassandraYaml, options)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: stargate.EncryptionConfigMapName(stargateKey.Name)},
+		Data:       map[string]string{stargate.CassandraYamlFile: cassandraYaml},
+	}
+	if err = replicateConfigMap(ctx, kc, configMap, stargateKey.Namespace, remoteClient, logger); err != nil {
+		return nil, err
+	}
+
+	template := stargateTemplate.DeepCopy()
+	template.CassandraConfigMapRef = &corev1.LocalObjectReference{Name: configMap.Name}
+	for i := range template.Racks {
+		template.Racks[i].CassandraConfigMapRef = &corev1.LocalObjectReference{Name: configMap.Name}
+	}
+	return template, nil
+}
+
+// clusterServerVersion returns the Cassandra version that the Stargate images must be
+// chosen for, which is the oldest version of the datacenters. Stargate keeps using the
+// image for Cassandra 3.11 until every datacenter has been upgraded to 4.0.
+func clusterServerVersion(dcs []*cassdcapi.CassandraDatacenter) string {
+	oldest := ""
+	for _, dc := range dcs {
+		if oldest == "" || !version.AtLeast(dc.Spec.ServerVersion, oldest) {
+			oldest = dc.Spec.ServerVersion
+		}
+	}
+	return oldest
+}
+
 func (r *K8ssandraClusterReconciler) setStatusForStargate(kc *api.K8ssandraCluster, stargate *stargateapi.Stargate, dcName string) error {
 	if len(kc.Status.Datacenters) == 0 {
 		kc.Status.Datacenters = make(map[string]api.K8ssandraStatus)
@@ -178,8 +261,18 @@ func (r *K8ssandraClusterReconciler) reconcileStargateAuthSchema(ctx context.Con
 			return result.Error(err)
 		}
 
-		if err = stargate.ReconcileAuthTable(managementApi, logger); err != nil {
-			logger.Error(err, "Failed to reconcile Stargate auth table")
+		cqlClient, err := newCqlClient(ctx, r.Client, r.Cql, kc, dc, remoteClient, logger)
+		if err != nil {
+			return result.Error(err)
+		}
+		defer cqlClient.Close()
+
+		if err = cassandra.MigrateSchema(managementApi, cqlClient, stargate.AuthSchema, logger); err != nil {
+			if _, ok := err.(*cassandra.SchemaDisagreementError); ok {
+				logger.Info("Waiting for schema agreement to migrate the Stargate auth schema", "Reason", err.Error())
+				return result.RequeueSoon(r.DefaultDelay)
+			}
+			logger.Error(err, "Failed to migrate Stargate auth schema")
 			return result.Error(err)
 		}
 
@@ -190,10 +283,7 @@ func (r *K8ssandraClusterReconciler) reconcileStargateAuthSchema(ctx context.Con
 
 func (r *K8ssandraClusterReconciler) removeStargateStatus(kc *api.K8ssandraCluster, dcName string) {
 	if kdcStatus, found := kc.Status.Datacenters[dcName]; found {
-		kc.Status.Datacenters[dcName] = api.K8ssandraStatus{
-			Stargate:  nil,
-			Cassandra: kdcStatus.Cassandra.DeepCopy(),
-			Reaper:    kdcStatus.Reaper.DeepCopy(),
-		}
+		kdcStatus.Stargate = nil
+		kc.Status.Datacenters[dcName] = kdcStatus
 	}
 }
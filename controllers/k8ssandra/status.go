@@ -0,0 +1,205 @@
+package k8ssandra
+
+import (
+	"fmt"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// setProgressing records in the Progressing condition that the reconciliation stopped at
+// the step identified by reason, and returns the output of recResult.
+func setProgressing(kc *api.K8ssandraCluster, reason, message string, recResult result.ReconcileResult) (ctrl.Result, error) {
+	setCondition(kc, api.ClusterProgressing, corev1.ConditionTrue, reason, message)
+	return recResult.Output()
+}
+
+// setReconciled records in the Progressing condition that the reconciliation ran to
+// completion.
+func setReconciled(kc *api.K8ssandraCluster) {
+	setCondition(kc, api.ClusterProgressing, corev1.ConditionFalse, api.ReasonReconciled, "")
+}
+
+// updateStatus computes the summary of each datacenter as well as the Ready and Degraded
+// conditions from the status recorded by the reconciliation steps. err is the error
+// returned by the reconciliation, if any.
+func updateStatus(kc *api.K8ssandraCluster, err error) {
+	kc.Status.ObservedGeneration = kc.Generation
+
+	if kc.Spec.Cassandra == nil {
+		return
+	}
+
+	var notReady, stopped, unreachable, failed []string
+	for i := range kc.Spec.Cassandra.Datacenters {
+		dcTemplate := &kc.Spec.Cassandra.Datacenters[i]
+		dcName := dcTemplate.Meta.Name
+		kdcStatus := kc.Status.Datacenters[dcName]
+
+		summary := kdcStatus.Summary
+		if summary == nil {
+			summary = &api.DatacenterSummary{K8sContext: dcTemplate.K8sContext}
+		}
+		summary.Nodes = dcTemplate.Size
+		summary.Stopped = kc.IsDatacenterStopped(dcTemplate)
+		summary.BlockedBy = ""
+		if !summary.Stopped {
+			summary.BlockedBy = blockingComponent(kc, dcTemplate, kdcStatus)
+		}
+		summary.Ready = !summary.Stopped && !summary.Unreachable && summary.BlockedBy == ""
+		setSummaryForDatacenter(kc, dcName, summary)
+
+		switch {
+		case summary.Unreachable:
+			notReady = append(notReady, fmt.Sprintf("%s (unreachable)", dcName))
+			unreachable = append(unreachable, summary.K8sContext)
+		case summary.Stopped:
+			stopped = append(stopped, dcName)
+		case !summary.Ready:
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", dcName, summary.BlockedBy))
+		}
+
+		if kdcStatus.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", dcName, kdcStatus.Error))
+		}
+		if registrationError := kdcStatus.Reaper.ClusterRegistrationError(); registrationError != "" {
+			failed = append(failed, fmt.Sprintf("%s: failed to register the cluster with Reaper: %s", dcName, registrationError))
+		}
+	}
+
+	switch {
+	case len(notReady) > 0:
+		setCondition(kc, api.ClusterReady, corev1.ConditionFalse, api.ReasonDatacentersNotReady,
+			"Datacenters not ready: "+strings.Join(notReady, ", "))
+	case len(stopped) > 0:
+		setCondition(kc, api.ClusterReady, corev1.ConditionFalse, api.ReasonDatacentersStopped,
+			"Datacenters stopped: "+strings.Join(stopped, ", "))
+	default:
+		setCondition(kc, api.ClusterReady, corev1.ConditionTrue, api.ReasonDatacentersReady, "")
+	}
+
+	switch {
+	case err != nil:
+		setCondition(kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonReconcileFailed, err.Error())
+	case len(unreachable) > 0:
+		setCondition(kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonContextUnreachable,
+			"Kubernetes contexts unreachable: "+strings.Join(unreachable, ", "))
+	case len(failed) > 0:
+		setCondition(kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonDatacenterFailed, strings.Join(failed, "; "))
+	default:
+		setCondition(kc, api.ClusterDegraded, corev1.ConditionFalse, api.ReasonNoErrors, "")
+	}
+}
+
+// recordStatusEvents records an event on kc for each transition of the Ready and Degraded
+// conditions since the previous status. Problems that happen in remote Kubernetes clusters,
+// e.g., an unreachable context or a Stargate deployment that does not become ready, are
+// reflected in these conditions, so they are surfaced on the K8ssandraCluster as well.
+func recordStatusEvents(recorder record.EventRecorder, kc *api.K8ssandraCluster, previous *api.K8ssandraClusterStatus) {
+	if ready := findCondition(&kc.Status, api.ClusterReady); ready != nil {
+		if wasReady := findCondition(previous, api.ClusterReady); wasReady == nil || wasReady.Status != ready.Status {
+			if ready.Status == corev1.ConditionTrue {
+				recorder.Event(kc, corev1.EventTypeNormal, events.ClusterReady, "All datacenters are ready")
+			} else {
+				recorder.Event(kc, corev1.EventTypeNormal, events.ClusterNotReady, ready.Message)
+			}
+		}
+	}
+
+	if degraded := findCondition(&kc.Status, api.ClusterDegraded); degraded != nil {
+		wasDegraded := findCondition(previous, api.ClusterDegraded)
+		if degraded.Status == corev1.ConditionTrue {
+			if wasDegraded == nil || wasDegraded.Status != corev1.ConditionTrue || wasDegraded.Message != degraded.Message {
+				recorder.Event(kc, corev1.EventTypeWarning, events.ClusterDegraded, degraded.Message)
+			}
+		} else if wasDegraded != nil && wasDegraded.Status == corev1.ConditionTrue {
+			recorder.Event(kc, corev1.EventTypeNormal, events.ClusterRecovered, "The reconciliation no longer reports errors")
+		}
+	}
+}
+
+// setCassandraConfigValid sets the CassandraConfigValid condition of kc. unsupported
+// describes, for each datacenter, the cassandra.yaml properties that do not exist in its
+// Cassandra version. An event is recorded when they change.
+func (r *K8ssandraClusterReconciler) setCassandraConfigValid(kc *api.K8ssandraCluster, unsupported []string) {
+	if len(unsupported) == 0 {
+		setCondition(kc, api.CassandraConfigValid, corev1.ConditionTrue, api.ReasonValidConfig, "")
+		return
+	}
+	message := "Properties not supported by the Cassandra version, they are ignored: " + strings.Join(unsupported, "; ")
+	if previous := findCondition(&kc.Status, api.CassandraConfigValid); previous == nil || previous.Message != message {
+		r.Recorder.Event(kc, corev1.EventTypeWarning, events.UnsupportedCassandraProperties, message)
+	}
+	setCondition(kc, api.CassandraConfigValid, corev1.ConditionFalse, api.ReasonUnsupportedProperties, message)
+}
+
+// findCondition returns the condition of the given type, or nil if it is not set.
+func findCondition(status *api.K8ssandraClusterStatus, conditionType api.K8ssandraClusterConditionType) *api.K8ssandraClusterCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// blockingComponent returns the first component of the datacenter that is not ready, or
+// an empty string if they are all ready.
+func blockingComponent(kc *api.K8ssandraCluster, dcTemplate *api.CassandraDatacenterTemplate, kdcStatus api.K8ssandraStatus) api.DatacenterComponent {
+	cassandraStatus := kdcStatus.Cassandra
+	if cassandraStatus == nil ||
+		cassandraStatus.GetConditionStatus(cassdcapi.DatacenterReady) != corev1.ConditionTrue ||
+		cassandraStatus.CassandraOperatorProgress != cassdcapi.ProgressReady {
+		return api.ComponentCassandra
+	}
+	if dcTemplate.Stargate.Coalesce(kc.Spec.Stargate) != nil && !kdcStatus.Stargate.IsReady() {
+		return api.ComponentStargate
+	}
+	if reaper.Coalesce(kc.Spec.Reaper.DeepCopy(), dcTemplate.Reaper.DeepCopy()) != nil && !kdcStatus.Reaper.IsReady() {
+		return api.ComponentReaper
+	}
+	return ""
+}
+
+// setCondition sets the condition of the given type on kc. The LastTransitionTime is only
+// updated when the status of the condition changes.
+func setCondition(kc *api.K8ssandraCluster, conditionType api.K8ssandraClusterConditionType, status corev1.ConditionStatus, reason, message string) {
+	condition := api.K8ssandraClusterCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+	for _, c := range kc.Status.Conditions {
+		if c.Type == conditionType && c.Status == status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+	}
+	if condition.LastTransitionTime == nil {
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	kc.Status.SetCondition(condition)
+}
+
+// countReadyPods returns the number of pods whose Ready condition is true.
+func countReadyPods(pods []corev1.Pod) int32 {
+	var ready int32
+	for _, pod := range pods {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready++
+				break
+			}
+		}
+	}
+	return ready
+}
@@ -0,0 +1,246 @@
+package k8ssandra
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	readyCassandra := func() *cassdcapi.CassandraDatacenterStatus {
+		return &cassdcapi.CassandraDatacenterStatus{
+			CassandraOperatorProgress: cassdcapi.ProgressReady,
+			Conditions: []cassdcapi.DatacenterCondition{{
+				Type:   cassdcapi.DatacenterReady,
+				Status: corev1.ConditionTrue,
+			}},
+		}
+	}
+
+	newCluster := func() *api.K8ssandraCluster {
+		return &api.K8ssandraCluster{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Spec: api.K8ssandraClusterSpec{
+				Cassandra: &api.CassandraClusterTemplate{
+					Datacenters: []api.CassandraDatacenterTemplate{
+						{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, K8sContext: "cluster-0", Size: 3},
+						{
+							Meta:       api.EmbeddedObjectMeta{Name: "dc2"},
+							K8sContext: "cluster-1",
+							Size:       3,
+							Stargate: &stargateapi.StargateDatacenterTemplate{
+								StargateClusterTemplate: stargateapi.StargateClusterTemplate{Size: 1},
+							},
+						},
+					},
+				},
+			},
+			Status: api.K8ssandraClusterStatus{
+				Datacenters: map[string]api.K8ssandraStatus{
+					"dc1": {
+						Cassandra: readyCassandra(),
+						Summary:   &api.DatacenterSummary{K8sContext: "cluster-0", ReadyNodes: 3},
+					},
+					"dc2": {
+						Cassandra: readyCassandra(),
+						Stargate: &stargateapi.StargateStatus{
+							Progress: stargateapi.StargateProgressRunning,
+							Conditions: []stargateapi.StargateCondition{{
+								Type:   stargateapi.StargateReady,
+								Status: corev1.ConditionTrue,
+							}},
+						},
+						Summary: &api.DatacenterSummary{K8sContext: "cluster-1", ReadyNodes: 3},
+					},
+				},
+			},
+		}
+	}
+
+	assertCondition := func(t *testing.T, kc *api.K8ssandraCluster, conditionType api.K8ssandraClusterConditionType, status corev1.ConditionStatus, reason string) {
+		for _, condition := range kc.Status.Conditions {
+			if condition.Type == conditionType {
+				assert.Equal(t, status, condition.Status, "status of condition %s", conditionType)
+				assert.Equal(t, reason, condition.Reason, "reason of condition %s", conditionType)
+				assert.NotNil(t, condition.LastTransitionTime, "last transition time of condition %s", conditionType)
+				return
+			}
+		}
+		t.Errorf("condition %s not found", conditionType)
+	}
+
+	t.Run("all ready", func(t *testing.T) {
+		kc := newCluster()
+		updateStatus(kc, nil)
+		assert.Equal(t, int64(3), kc.Status.ObservedGeneration)
+		assertCondition(t, kc, api.ClusterReady, corev1.ConditionTrue, api.ReasonDatacentersReady)
+		assertCondition(t, kc, api.ClusterDegraded, corev1.ConditionFalse, api.ReasonNoErrors)
+		assert.Equal(t, &api.DatacenterSummary{K8sContext: "cluster-1", Nodes: 3, ReadyNodes: 3, Ready: true}, kc.Status.Datacenters["dc2"].Summary)
+	})
+
+	t.Run("stargate not ready", func(t *testing.T) {
+		kc := newCluster()
+		kdcStatus := kc.Status.Datacenters["dc2"]
+		kdcStatus.Stargate = nil
+		kc.Status.Datacenters["dc2"] = kdcStatus
+		updateStatus(kc, nil)
+		assertCondition(t, kc, api.ClusterReady, corev1.ConditionFalse, api.ReasonDatacentersNotReady)
+		assert.Equal(t, api.ComponentStargate, kc.Status.Datacenters["dc2"].Summary.BlockedBy)
+		assert.False(t, kc.Status.Datacenters["dc2"].Summary.Ready)
+	})
+
+	t.Run("datacenter not created yet", func(t *testing.T) {
+		kc := newCluster()
+		delete(kc.Status.Datacenters, "dc2")
+		updateStatus(kc, nil)
+		assertCondition(t, kc, api.ClusterReady, corev1.ConditionFalse, api.ReasonDatacentersNotReady)
+		assert.Equal(t, api.ComponentCassandra, kc.Status.Datacenters["dc2"].Summary.BlockedBy)
+		assert.Equal(t, "cluster-1", kc.Status.Datacenters["dc2"].Summary.K8sContext)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		kc := newCluster()
+		kc.Spec.Stopped = true
+		updateStatus(kc, nil)
+		assertCondition(t, kc, api.ClusterReady, corev1.ConditionFalse, api.ReasonDatacentersStopped)
+		assert.True(t, kc.Status.Datacenters["dc1"].Summary.Stopped)
+	})
+
+	t.Run("unreachable context", func(t *testing.T) {
+		kc := newCluster()
+		kc.Status.Datacenters["dc2"].Summary.Unreachable = true
+		updateStatus(kc, nil)
+		assertCondition(t, kc, api.ClusterReady, corev1.ConditionFalse, api.ReasonDatacentersNotReady)
+		assertCondition(t, kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonContextUnreachable)
+	})
+
+	t.Run("datacenter error", func(t *testing.T) {
+		kc := newCluster()
+		kdcStatus := kc.Status.Datacenters["dc1"]
+		kdcStatus.Error = "rebuild failed"
+		kc.Status.Datacenters["dc1"] = kdcStatus
+		updateStatus(kc, nil)
+		assertCondition(t, kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonDatacenterFailed)
+	})
+
+	t.Run("reaper registration error", func(t *testing.T) {
+		kc := newCluster()
+		kdcStatus := kc.Status.Datacenters["dc1"]
+		kdcStatus.Reaper = &reaperapi.ReaperStatus{}
+		kdcStatus.Reaper.SetClusterRegistered(errors.New("connection refused"))
+		kc.Status.Datacenters["dc1"] = kdcStatus
+		updateStatus(kc, nil)
+		assertCondition(t, kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonDatacenterFailed)
+		assert.Equal(t, "dc1: failed to register the cluster with Reaper: connection refused", findCondition(&kc.Status, api.ClusterDegraded).Message)
+	})
+
+	t.Run("reconcile error", func(t *testing.T) {
+		kc := newCluster()
+		updateStatus(kc, errors.New("failed"))
+		assertCondition(t, kc, api.ClusterDegraded, corev1.ConditionTrue, api.ReasonReconcileFailed)
+	})
+
+	t.Run("last transition time only changes with status", func(t *testing.T) {
+		kc := newCluster()
+		lastTransitionTime := metav1.NewTime(metav1.Now().Add(-time.Hour))
+		kc.Status.Conditions = []api.K8ssandraClusterCondition{{
+			Type:               api.ClusterReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: &lastTransitionTime,
+		}}
+		updateStatus(kc, nil)
+		assert.Equal(t, &lastTransitionTime, kc.Status.Conditions[0].LastTransitionTime)
+	})
+}
+
+func TestRecordStatusEvents(t *testing.T) {
+	newCluster := func(ready, degraded corev1.ConditionStatus, message string) *api.K8ssandraCluster {
+		return &api.K8ssandraCluster{
+			Status: api.K8ssandraClusterStatus{
+				Conditions: []api.K8ssandraClusterCondition{
+					{Type: api.ClusterReady, Status: ready},
+					{Type: api.ClusterDegraded, Status: degraded, Message: message},
+				},
+			},
+		}
+	}
+
+	recordedEvents := func(recorder *record.FakeRecorder) []string {
+		close(recorder.Events)
+		var recorded []string
+		for event := range recorder.Events {
+			recorded = append(recorded, event)
+		}
+		return recorded
+	}
+
+	t.Run("no transition", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		previous := newCluster(corev1.ConditionTrue, corev1.ConditionFalse, "")
+		kc := newCluster(corev1.ConditionTrue, corev1.ConditionFalse, "")
+		recordStatusEvents(recorder, kc, &previous.Status)
+		assert.Empty(t, recordedEvents(recorder))
+	})
+
+	t.Run("becomes ready", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		previous := newCluster(corev1.ConditionFalse, corev1.ConditionFalse, "")
+		kc := newCluster(corev1.ConditionTrue, corev1.ConditionFalse, "")
+		recordStatusEvents(recorder, kc, &previous.Status)
+		assert.Equal(t, []string{"Normal ClusterReady All datacenters are ready"}, recordedEvents(recorder))
+	})
+
+	t.Run("becomes degraded", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		previous := newCluster(corev1.ConditionTrue, corev1.ConditionFalse, "")
+		kc := newCluster(corev1.ConditionTrue, corev1.ConditionTrue, "Kubernetes contexts unreachable: cluster-1")
+		recordStatusEvents(recorder, kc, &previous.Status)
+		assert.Equal(t, []string{"Warning ClusterDegraded Kubernetes contexts unreachable: cluster-1"}, recordedEvents(recorder))
+	})
+
+	t.Run("degraded for another reason", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		previous := newCluster(corev1.ConditionTrue, corev1.ConditionTrue, "Kubernetes contexts unreachable: cluster-1")
+		kc := newCluster(corev1.ConditionTrue, corev1.ConditionTrue, "dc1: rebuild failed")
+		recordStatusEvents(recorder, kc, &previous.Status)
+		assert.Equal(t, []string{"Warning ClusterDegraded dc1: rebuild failed"}, recordedEvents(recorder))
+	})
+
+	t.Run("recovers", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		previous := newCluster(corev1.ConditionTrue, corev1.ConditionTrue, "failed")
+		kc := newCluster(corev1.ConditionTrue, corev1.ConditionFalse, "")
+		recordStatusEvents(recorder, kc, &previous.Status)
+		assert.Equal(t, []string{"Normal ClusterRecovered The reconciliation no longer reports errors"}, recordedEvents(recorder))
+	})
+}
+
+func TestSetCassandraConfigValid(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &K8ssandraClusterReconciler{Recorder: recorder}
+	kc := &api.K8ssandraCluster{}
+
+	r.setCassandraConfigValid(kc, []string{"dc1 (4.0.1): start_rpc"})
+	r.setCassandraConfigValid(kc, []string{"dc1 (4.0.1): start_rpc"})
+	condition := findCondition(&kc.Status, api.CassandraConfigValid)
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, api.ReasonUnsupportedProperties, condition.Reason)
+	assert.Equal(t, "Properties not supported by the Cassandra version, they are ignored: dc1 (4.0.1): start_rpc", condition.Message)
+
+	r.setCassandraConfigValid(kc, nil)
+	assert.Equal(t, corev1.ConditionTrue, kc.Status.GetConditionStatus(api.CassandraConfigValid))
+
+	close(recorder.Events)
+	assert.Len(t, recorder.Events, 1)
+}
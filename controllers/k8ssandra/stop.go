@@ -0,0 +1,127 @@
+package k8ssandra
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/stargate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hasStoppedDatacenters returns true if at least one datacenter of kc is stopped.
+func hasStoppedDatacenters(kc *api.K8ssandraCluster) bool {
+	for i := range kc.Spec.Cassandra.Datacenters {
+		if kc.IsDatacenterStopped(&kc.Spec.Cassandra.Datacenters[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// stopStargateAndReaper scales the Stargate and Reaper deployments of dc down to zero.
+// The number of replicas of each deployment is saved in the StoppedReplicasAnnotation so
+// that resumeStargateAndReaper can restore it. It returns true once none of the
+// deployments has pods left. The Stargate and Reaper controllers keep the deployments
+// with a StoppedReplicasAnnotation scaled down when they update them.
+func (r *K8ssandraClusterReconciler) stopStargateAndReaper(ctx context.Context, kc *api.K8ssandraCluster, dc *cassdcapi.CassandraDatacenter, remoteClient client.Client, logger logr.Logger) (bool, error) {
+	deployments, err := listStargateAndReaperDeployments(ctx, kc, dc, remoteClient)
+	if err != nil {
+		logger.Error(err, "Failed to list Stargate and Reaper deployments")
+		return false, err
+	}
+
+	stopped := true
+	for i := range deployments {
+		deployment := &deployments[i]
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		if replicas > 0 {
+			logger.Info("Scaling down deployment", "Deployment", deployment.Name)
+			patch := client.MergeFrom(deployment.DeepCopy())
+			metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, api.StoppedReplicasAnnotation, strconv.Itoa(int(replicas)))
+			zero := int32(0)
+			deployment.Spec.Replicas = &zero
+			if err := remoteClient.Patch(ctx, deployment, patch); err != nil {
+				logger.Error(err, "Failed to scale down deployment", "Deployment", deployment.Name)
+				return false, err
+			}
+			r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.ScaledDownDeployment,
+				"Scaled down deployment %s of stopped CassandraDatacenter %s", deployment.Name, dc.Name)
+		}
+
+		if deployment.Status.Replicas > 0 {
+			stopped = false
+		}
+	}
+
+	return stopped, nil
+}
+
+// resumeStargateAndReaper restores the number of replicas of the Stargate and Reaper
+// deployments of dc that were scaled down by stopStargateAndReaper.
+func (r *K8ssandraClusterReconciler) resumeStargateAndReaper(ctx context.Context, kc *api.K8ssandraCluster, dc *cassdcapi.CassandraDatacenter, remoteClient client.Client, logger logr.Logger) error {
+	deployments, err := listStargateAndReaperDeployments(ctx, kc, dc, remoteClient)
+	if err != nil {
+		logger.Error(err, "Failed to list Stargate and Reaper deployments")
+		return err
+	}
+
+	for i := range deployments {
+		deployment := &deployments[i]
+		value, found := deployment.Annotations[api.StoppedReplicasAnnotation]
+		if !found {
+			continue
+		}
+
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			logger.Error(err, "Invalid stopped replicas annotation, defaulting to one replica", "Deployment", deployment.Name, "Value", value)
+			replicas = 1
+		}
+
+		logger.Info("Scaling up deployment", "Deployment", deployment.Name, "Replicas", replicas)
+		patch := client.MergeFrom(deployment.DeepCopy())
+		delete(deployment.Annotations, api.StoppedReplicasAnnotation)
+		deploymentReplicas := int32(replicas)
+		deployment.Spec.Replicas = &deploymentReplicas
+		if err := remoteClient.Patch(ctx, deployment, patch); err != nil {
+			logger.Error(err, "Failed to scale up deployment", "Deployment", deployment.Name)
+			return err
+		}
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.ScaledUpDeployment,
+			"Scaled up deployment %s of resumed CassandraDatacenter %s to %d replicas", deployment.Name, dc.Name, replicas)
+	}
+
+	return nil
+}
+
+// listStargateAndReaperDeployments returns the Stargate and Reaper deployments of dc.
+func listStargateAndReaperDeployments(ctx context.Context, kc *api.K8ssandraCluster, dc *cassdcapi.CassandraDatacenter, remoteClient client.Client) ([]appsv1.Deployment, error) {
+	selectors := []client.MatchingLabels{
+		{stargateapi.StargateLabel: stargate.ResourceName(kc, dc)},
+		{reaperapi.ReaperLabel: reaper.ResourceName(kc.Name, dc.Name)},
+	}
+
+	var deployments []appsv1.Deployment
+	for _, selector := range selectors {
+		list := &appsv1.DeploymentList{}
+		if err := remoteClient.List(ctx, list, client.InNamespace(dc.Namespace), selector); err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, list.Items...)
+	}
+	return deployments, nil
+}
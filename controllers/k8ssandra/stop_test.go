@@ -0,0 +1,218 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	stargateapi "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	"github.com/k8ssandra/k8ssandra-operator/test/framework"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stopCluster creates a single-dc cluster with a Stargate deployment, stops the cluster,
+// and verifies that Stargate is scaled down before the datacenter is stopped and scaled
+// back up once the datacenter is resumed.
+func stopCluster(t *testing.T, ctx context.Context, f *framework.Framework, namespace string) {
+	require := require.New(t)
+
+	k8sCtx0 := "cluster-0"
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test",
+		},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:       "test",
+				ServerVersion: "4.0.1",
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &defaultStorageClass,
+					},
+				},
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc1"},
+						K8sContext: k8sCtx0,
+						Size:       3,
+					},
+				},
+			},
+		},
+	}
+
+	err := f.Client.Create(ctx, kc)
+	require.NoError(err, "failed to create K8ssandraCluster")
+
+	verifyFinalizerAdded(ctx, t, f, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+
+	verifySuperUserSecretCreated(ctx, t, f, kc)
+
+	verifyReplicatedSecretReconciled(ctx, t, f, kc)
+
+	verifySystemReplicationAnnotationSet(ctx, t, f, kc)
+
+	dc1Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc1"}, K8sContext: k8sCtx0}
+	kcKey := framework.ClusterKey{K8sContext: k8sCtx0, NamespacedName: types.NamespacedName{Namespace: namespace, Name: "test"}}
+
+	t.Log("check that dc1 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc1Key), timeout, interval)
+
+	t.Log("update dc1 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc1Key)
+	require.NoError(err, "failed to set dc1 status ready")
+
+	t.Log("create a Stargate deployment for dc1")
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test-dc1-default-stargate-deployment",
+			Labels:    map[string]string{stargateapi.StargateLabel: "test-dc1-stargate"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "stargate"},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "stargate"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "stargate", Image: "stargate"}},
+				},
+			},
+		},
+	}
+	err = f.Client.Create(ctx, deployment)
+	require.NoError(err, "failed to create Stargate deployment")
+	deployment.Status.Replicas = replicas
+	err = f.Client.Status().Update(ctx, deployment)
+	require.NoError(err, "failed to update Stargate deployment status")
+	deploymentKey := framework.ClusterKey{NamespacedName: utils.GetKey(deployment), K8sContext: k8sCtx0}
+
+	t.Log("stop the cluster")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kc.Spec.Stopped = true
+		if err := f.Client.Update(ctx, kc); err != nil {
+			t.Logf("failed to update K8ssandraCluster: %v", err)
+			return false
+		}
+		return true
+	}, timeout, interval, "timed out updating K8ssandraCluster")
+
+	t.Log("check that the Stargate deployment is scaled down")
+	require.Eventually(func() bool {
+		deployment := &appsv1.Deployment{}
+		if err := f.Get(ctx, deploymentKey, deployment); err != nil {
+			t.Logf("failed to get Stargate deployment: %v", err)
+			return false
+		}
+		return *deployment.Spec.Replicas == 0 && deployment.Annotations[api.StoppedReplicasAnnotation] == "2"
+	}, timeout, interval, "timed out waiting for the Stargate deployment to be scaled down")
+
+	stopped := func(dc *cassdcapi.CassandraDatacenter) bool {
+		return dc.Spec.Stopped
+	}
+
+	t.Log("check that dc1 is not stopped while Stargate pods are running")
+	require.Never(f.NewWithDatacenter(ctx, dc1Key)(stopped), 3*interval, interval, "dc1 should not be stopped before Stargate")
+
+	t.Log("update the Stargate deployment status")
+	err = f.Get(ctx, deploymentKey, deployment)
+	require.NoError(err, "failed to get Stargate deployment")
+	deployment.Status.Replicas = 0
+	err = f.Client.Status().Update(ctx, deployment)
+	require.NoError(err, "failed to update Stargate deployment status")
+
+	t.Log("check that dc1 is stopped")
+	require.Eventually(f.NewWithDatacenter(ctx, dc1Key)(stopped), timeout, interval)
+
+	t.Log("update dc1 status to stopped")
+	err = f.PatchDatacenterStatus(ctx, dc1Key, func(dc *cassdcapi.CassandraDatacenter) {
+		now := metav1.Now()
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterStopped,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: now,
+		})
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterReady,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: now,
+		})
+	})
+	require.NoError(err, "failed to set dc1 status stopped")
+
+	t.Log("resume the cluster")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kc.Spec.Stopped = false
+		if err := f.Client.Update(ctx, kc); err != nil {
+			t.Logf("failed to update K8ssandraCluster: %v", err)
+			return false
+		}
+		return true
+	}, timeout, interval, "timed out updating K8ssandraCluster")
+
+	t.Log("check that dc1 is resumed")
+	require.Eventually(f.NewWithDatacenter(ctx, dc1Key)(func(dc *cassdcapi.CassandraDatacenter) bool {
+		return !dc.Spec.Stopped
+	}), timeout, interval)
+
+	t.Log("check that the Stargate deployment is not scaled up before dc1 is ready")
+	err = f.Get(ctx, deploymentKey, deployment)
+	require.NoError(err, "failed to get Stargate deployment")
+	require.Equal(int32(0), *deployment.Spec.Replicas)
+
+	t.Log("update dc1 status to ready")
+	err = f.PatchDatacenterStatus(ctx, dc1Key, func(dc *cassdcapi.CassandraDatacenter) {
+		now := metav1.Now()
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterStopped,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: now,
+		})
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: now,
+		})
+	})
+	require.NoError(err, "failed to set dc1 status ready")
+
+	t.Log("check that the Stargate deployment is scaled up")
+	require.Eventually(func() bool {
+		deployment := &appsv1.Deployment{}
+		if err := f.Get(ctx, deploymentKey, deployment); err != nil {
+			t.Logf("failed to get Stargate deployment: %v", err)
+			return false
+		}
+		_, found := deployment.Annotations[api.StoppedReplicasAnnotation]
+		return *deployment.Spec.Replicas == 2 && !found
+	}, timeout, interval, "timed out waiting for the Stargate deployment to be scaled up")
+
+	t.Log("deleting K8ssandraCluster")
+	err = f.DeleteK8ssandraCluster(ctx, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+	require.NoError(err, "failed to delete K8ssandraCluster")
+	verifyObjectDoesNotExist(ctx, t, f, dc1Key, &cassdcapi.CassandraDatacenter{})
+}
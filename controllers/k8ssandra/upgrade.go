@@ -0,0 +1,427 @@
+package k8ssandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/result"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileUpgrade orchestrates changes of the Cassandra version of existing datacenters.
+// Before the first datacenter is upgraded, it checks that all the nodes are up and agree
+// on the schema, and optionally takes a snapshot of every node. The datacenters are then
+// upgraded one at a time, and upgradesstables is run on each node of a datacenter before
+// moving on to the next one. Progress is recorded in kc.Status.Upgrade.
+//
+// This is called before the CassandraDatacenters are reconciled. The datacenters that must
+// not be upgraded yet keep their current version in dcs, and their config is rendered for
+// that version, so the returned result should only be acted upon once the datacenters have
+// been reconciled.
+func (r *K8ssandraClusterReconciler) reconcileUpgrade(ctx context.Context, kc *api.K8ssandraCluster, dcs []*dcReconciliation, logger logr.Logger) result.ReconcileResult {
+	actualDcs := make(map[string]*cassdcapi.CassandraDatacenter)
+	pending := make([]*dcReconciliation, 0)
+	for _, dc := range dcs {
+		actualDc := &cassdcapi.CassandraDatacenter{}
+		if err := dc.remoteClient.Get(ctx, utils.GetKey(dc.desiredDc), actualDc); err != nil {
+			if errors.IsNotFound(err) {
+				// New datacenters are created with the desired version.
+				continue
+			}
+			dc.logger.Error(err, "Failed to get datacenter")
+			return result.Error(err)
+		}
+		actualDcs[dc.desiredDc.Name] = actualDc
+		if actualDc.Spec.ServerVersion != dc.desiredDc.Spec.ServerVersion {
+			pending = append(pending, dc)
+		}
+	}
+
+	status := kc.Status.Upgrade
+	if status == nil || status.Progress == api.UpgradeCompleted {
+		if len(pending) == 0 {
+			return result.Continue()
+		}
+		logger.Info("Starting upgrade")
+		now := metav1.Now().Rfc3339Copy()
+		status = &api.UpgradeStatus{
+			Progress:  api.UpgradePreChecks,
+			StartTime: &now,
+		}
+		kc.Status.Upgrade = status
+		r.Recorder.Event(kc, corev1.EventTypeNormal, events.UpgradeStarted, "Starting upgrade")
+	}
+
+	previous := status.DeepCopy()
+	recResult := r.runUpgrade(ctx, kc, status, dcs, actualDcs, pending, logger)
+	r.recordUpgradeEvents(kc, previous, status)
+
+	for _, dc := range pending {
+		if status.Progress == api.UpgradeUpgradingDatacenter && dc.desiredDc.Name == status.Datacenter {
+			continue
+		}
+		actualDc := actualDcs[dc.desiredDc.Name]
+		dc.desiredDc.Spec.ServerVersion = actualDc.Spec.ServerVersion
+		dc.desiredDc.Spec.ServerImage = actualDc.Spec.ServerImage
+		// The config is rendered for the version, e.g., without the cassandra.yaml
+		// properties that do not exist in that version or with their 4.1 names, so it is
+		// rendered again for the current version. Other config changes are not held back.
+		config, err := cassandra.RenderConfig(dc.dcConfig, actualDc.Spec.ServerVersion)
+		if err != nil {
+			dc.logger.Error(err, "Failed to render the config for the current version, holding back the whole config")
+			config = actualDc.Spec.Config
+		}
+		dc.desiredDc.Spec.Config = config
+	}
+
+	return recResult
+}
+
+// recordUpgradeEvents records an event on kc for each step of the upgrade that was
+// reached since previous, as well as for new errors.
+func (r *K8ssandraClusterReconciler) recordUpgradeEvents(kc *api.K8ssandraCluster, previous, status *api.UpgradeStatus) {
+	if status.Datacenter != "" && status.Datacenter != previous.Datacenter {
+		r.Recorder.Eventf(kc, corev1.EventTypeNormal, events.UpgradingDatacenter, "Upgrading CassandraDatacenter %s", status.Datacenter)
+	}
+	if status.LastError != "" && status.LastError != previous.LastError {
+		r.Recorder.Eventf(kc, corev1.EventTypeWarning, events.UpgradeBlocked, "Upgrade cannot proceed: %s", status.LastError)
+	}
+	if status.Progress == api.UpgradeCompleted && previous.Progress != api.UpgradeCompleted {
+		r.Recorder.Event(kc, corev1.EventTypeNormal, events.UpgradeCompleted, "Upgrade completed")
+	}
+}
+
+// runUpgrade performs the steps of the upgrade, starting from status.Progress, until one
+// of them needs to wait or fails.
+func (r *K8ssandraClusterReconciler) runUpgrade(
+	ctx context.Context,
+	kc *api.K8ssandraCluster,
+	status *api.UpgradeStatus,
+	dcs []*dcReconciliation,
+	actualDcs map[string]*cassdcapi.CassandraDatacenter,
+	pending []*dcReconciliation,
+	logger logr.Logger,
+) result.ReconcileResult {
+	for {
+		var recResult result.ReconcileResult
+
+		switch status.Progress {
+		case api.UpgradePreChecks:
+			recResult = r.checkUpgradePreconditions(ctx, kc, status, logger)
+			if !recResult.Completed() {
+				if kc.Spec.Cassandra.Upgrade != nil && kc.Spec.Cassandra.Upgrade.SnapshotBeforeUpgrade {
+					status.Progress = api.UpgradeSnapshotting
+					status.SnapshotName = fmt.Sprintf("upgrade-%d", status.StartTime.Unix())
+				} else {
+					startNextDatacenterUpgrade(status, pending, logger)
+				}
+			}
+		case api.UpgradeSnapshotting:
+			recResult = r.takeUpgradeSnapshots(ctx, status, dcs, actualDcs, logger)
+			if !recResult.Completed() {
+				startNextDatacenterUpgrade(status, pending, logger)
+			}
+		case api.UpgradeUpgradingDatacenter, api.UpgradeUpgradingSSTables:
+			dc := findDcReconciliation(dcs, status.Datacenter)
+			actualDc, found := actualDcs[status.Datacenter]
+			if dc == nil || !found {
+				logger.Info("Datacenter being upgraded no longer exists", "CassandraDatacenter", status.Datacenter)
+				resetDatacenterUpgrade(status)
+				startNextDatacenterUpgrade(status, pending, logger)
+				break
+			}
+
+			if status.Progress == api.UpgradeUpgradingDatacenter {
+				recResult = r.checkDatacenterUpgraded(status, dc, actualDc)
+				if !recResult.Completed() {
+					status.Progress = api.UpgradeUpgradingSSTables
+				}
+				break
+			}
+
+			recResult = r.upgradeSSTables(ctx, status, dc, actualDc)
+			if !recResult.Completed() {
+				dc.logger.Info("Datacenter upgraded")
+				status.UpgradedDatacenters = append(status.UpgradedDatacenters, status.Datacenter)
+				resetDatacenterUpgrade(status)
+				startNextDatacenterUpgrade(status, pending, logger)
+			}
+		default:
+			return result.Continue()
+		}
+
+		if recResult.Completed() {
+			return recResult
+		}
+		status.LastError = ""
+
+		if status.Progress == api.UpgradeUpgradingDatacenter {
+			// The new version is applied to the datacenter when it is reconciled.
+			return result.RequeueSoon(r.DefaultDelay)
+		}
+	}
+}
+
+// startNextDatacenterUpgrade moves the upgrade to the first of the pending datacenters
+// that has not been upgraded yet, or completes the upgrade if there is none.
+func startNextDatacenterUpgrade(status *api.UpgradeStatus, pending []*dcReconciliation, logger logr.Logger) {
+	for _, dc := range pending {
+		if utils.SliceContains(status.UpgradedDatacenters, dc.desiredDc.Name) {
+			continue
+		}
+		dc.logger.Info("Upgrading datacenter", "ServerVersion", dc.desiredDc.Spec.ServerVersion)
+		now := metav1.Now().Rfc3339Copy()
+		status.Progress = api.UpgradeUpgradingDatacenter
+		status.Datacenter = dc.desiredDc.Name
+		status.DatacenterStartTime = &now
+		return
+	}
+
+	logger.Info("Upgrade completed")
+	now := metav1.Now()
+	status.Progress = api.UpgradeCompleted
+	status.CompletionTime = &now
+}
+
+func resetDatacenterUpgrade(status *api.UpgradeStatus) {
+	status.Datacenter = ""
+	status.DatacenterStartTime = nil
+	status.CurrentPod = ""
+	status.JobId = ""
+	status.UpgradedPods = nil
+}
+
+// checkUpgradePreconditions verifies, through the management API of a ready datacenter,
+// that all the nodes of the cluster are up and agree on the schema. The nodes that left the
+// cluster are ignored.
+func (r *K8ssandraClusterReconciler) checkUpgradePreconditions(ctx context.Context, kc *api.K8ssandraCluster, status *api.UpgradeStatus, logger logr.Logger) result.ReconcileResult {
+	dc, remoteClient, err := findReadyDatacenter(ctx, r.ClientCache, kc, logger)
+	if err != nil {
+		status.LastError = err.Error()
+		return result.Error(err)
+	}
+	if dc == nil {
+		return r.upgradeCannotProceed(status, "no datacenter is ready", logger)
+	}
+
+	managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, dc, remoteClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		status.LastError = err.Error()
+		return result.Error(err)
+	}
+
+	endpoints, err := managementApi.GetEndpointStates()
+	if err != nil {
+		logger.Error(err, "Failed to get endpoint states")
+		status.LastError = err.Error()
+		return result.Error(err)
+	}
+	for _, endpoint := range endpoints {
+		if hasLeftCluster(endpoint) {
+			// Nodes that left the cluster, e.g., the nodes of a decommissioned datacenter,
+			// remain in gossip for a few days.
+			continue
+		}
+		if endpoint.IsAlive != "true" || !strings.HasPrefix(endpoint.Status, "NORMAL") {
+			return r.upgradeCannotProceed(status, fmt.Sprintf("node %s is not up and normal", endpoint.HostID), logger)
+		}
+	}
+
+	schemaVersions, err := managementApi.GetSchemaVersions()
+	if err != nil {
+		logger.Error(err, "Failed to get schema versions")
+		status.LastError = err.Error()
+		return result.Error(err)
+	}
+	if len(schemaVersions) > 1 {
+		return r.upgradeCannotProceed(status, fmt.Sprintf("nodes do not agree on the schema: %v", schemaVersions), logger)
+	}
+
+	return result.Continue()
+}
+
+// hasLeftCluster returns true if the gossip status of endpoint shows that the node was
+// decommissioned or removed from the cluster.
+func hasLeftCluster(endpoint httphelper.EndpointState) bool {
+	return strings.HasPrefix(endpoint.Status, "LEFT") || strings.HasPrefix(endpoint.Status, "removed")
+}
+
+// upgradeCannotProceed records why the current step of the upgrade cannot be performed.
+// The step is retried after r.LongDelay.
+func (r *K8ssandraClusterReconciler) upgradeCannotProceed(status *api.UpgradeStatus, reason string, logger logr.Logger) result.ReconcileResult {
+	logger.Info("Upgrade cannot proceed, it will be retried", "Progress", status.Progress, "Reason", reason)
+	status.LastError = reason
+	return result.RequeueSoon(r.LongDelay)
+}
+
+// takeUpgradeSnapshots takes the snapshot status.SnapshotName on every node of the
+// cluster. Nodes that already have it are recorded in status.SnapshottedPods.
+func (r *K8ssandraClusterReconciler) takeUpgradeSnapshots(
+	ctx context.Context,
+	status *api.UpgradeStatus,
+	dcs []*dcReconciliation,
+	actualDcs map[string]*cassdcapi.CassandraDatacenter,
+	logger logr.Logger,
+) result.ReconcileResult {
+	for _, dc := range dcs {
+		actualDc, found := actualDcs[dc.desiredDc.Name]
+		if !found {
+			continue
+		}
+
+		pods, err := listDatacenterPods(ctx, actualDc, dc.remoteClient)
+		if err != nil {
+			dc.logger.Error(err, "Failed to list datacenter pods")
+			status.LastError = err.Error()
+			return result.Error(err)
+		}
+
+		managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, actualDc, dc.remoteClient, dc.logger)
+		if err != nil {
+			dc.logger.Error(err, "Failed to create ManagementApiFacade")
+			status.LastError = err.Error()
+			return result.Error(err)
+		}
+
+		for i := range pods {
+			pod := &pods[i]
+			if utils.SliceContains(status.SnapshottedPods, pod.Name) {
+				continue
+			}
+			if err := managementApi.TakeSnapshot(pod, status.SnapshotName, nil); err != nil {
+				status.LastError = fmt.Sprintf("snapshot of pod %s failed: %s", pod.Name, err)
+				return result.Error(err)
+			}
+			status.SnapshottedPods = append(status.SnapshottedPods, pod.Name)
+		}
+	}
+
+	logger.Info("Snapshot taken on all nodes", "Snapshot", status.SnapshotName)
+	return result.Continue()
+}
+
+// checkDatacenterUpgraded waits for cass-operator to have restarted all the nodes of dc
+// with the new version.
+func (r *K8ssandraClusterReconciler) checkDatacenterUpgraded(status *api.UpgradeStatus, dc *dcReconciliation, actualDc *cassdcapi.CassandraDatacenter) result.ReconcileResult {
+	if actualDc.Spec.ServerVersion != dc.desiredDc.Spec.ServerVersion ||
+		!cassandra.DatacenterUpdatedAfter(status.DatacenterStartTime.Time, actualDc) ||
+		!cassandra.DatacenterReady(actualDc) {
+		dc.logger.Info("Waiting for datacenter upgrade to complete")
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+	return result.Continue()
+}
+
+// upgradeSSTables runs upgradesstables on the nodes of dc, one at a time.
+// A failed upgradesstables job is retried after r.LongDelay.
+func (r *K8ssandraClusterReconciler) upgradeSSTables(ctx context.Context, status *api.UpgradeStatus, dc *dcReconciliation, actualDc *cassdcapi.CassandraDatacenter) result.ReconcileResult {
+	logger := dc.logger
+
+	pods, err := listDatacenterPods(ctx, actualDc, dc.remoteClient)
+	if err != nil {
+		logger.Error(err, "Failed to list datacenter pods")
+		status.LastError = err.Error()
+		return result.Error(err)
+	}
+
+	managementApi, err := r.ManagementApi.NewManagementApiFacade(ctx, actualDc, dc.remoteClient, logger)
+	if err != nil {
+		logger.Error(err, "Failed to create ManagementApiFacade")
+		status.LastError = err.Error()
+		return result.Error(err)
+	}
+
+	if status.JobId != "" {
+		var pod *corev1.Pod
+		for i := range pods {
+			if pods[i].Name == status.CurrentPod {
+				pod = &pods[i]
+				break
+			}
+		}
+
+		if pod == nil {
+			logger.Info("Pod being upgraded no longer exists, restarting upgradesstables", "Pod", status.CurrentPod)
+			status.JobId = ""
+		} else {
+			job, err := managementApi.GetJobDetails(pod, status.JobId)
+			if err != nil {
+				logger.Error(err, "Failed to get upgradesstables job details", "Pod", pod.Name)
+				status.LastError = err.Error()
+				return result.Error(err)
+			}
+
+			switch {
+			case job.Status == cassandra.JobStatusCompleted:
+				logger.Info("Upgradesstables completed", "Pod", pod.Name)
+				status.UpgradedPods = append(status.UpgradedPods, pod.Name)
+				status.CurrentPod = ""
+				status.JobId = ""
+			case job.Status == cassandra.JobStatusError:
+				status.JobId = ""
+				return r.upgradeCannotProceed(status, fmt.Sprintf("upgradesstables of pod %s failed: %s", pod.Name, job.Error), logger)
+			case job.Id == "":
+				logger.Info("Upgradesstables job not found, restarting it", "Pod", pod.Name)
+				status.JobId = ""
+			default:
+				logger.Info("Waiting for upgradesstables to complete", "Pod", pod.Name)
+				return result.RequeueSoon(r.DefaultDelay)
+			}
+		}
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if utils.SliceContains(status.UpgradedPods, pod.Name) {
+			continue
+		}
+
+		jobId, err := managementApi.UpgradeSSTables(pod)
+		if err != nil {
+			status.LastError = fmt.Sprintf("upgradesstables of pod %s failed: %s", pod.Name, err)
+			return result.Error(err)
+		}
+
+		logger.Info("Upgradesstables started", "Pod", pod.Name, "JobId", jobId)
+		status.CurrentPod = pod.Name
+		status.JobId = jobId
+		status.LastError = ""
+		return result.RequeueSoon(r.DefaultDelay)
+	}
+
+	return result.Continue()
+}
+
+func findDcReconciliation(dcs []*dcReconciliation, dcName string) *dcReconciliation {
+	for _, dc := range dcs {
+		if dc.desiredDc.Name == dcName {
+			return dc
+		}
+	}
+	return nil
+}
+
+// listDatacenterPods returns the Cassandra pods of dc, sorted by name.
+func listDatacenterPods(ctx context.Context, dc *cassdcapi.CassandraDatacenter, remoteClient client.Client) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := remoteClient.List(ctx, pods, client.InNamespace(dc.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: dc.Name}); err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	return pods.Items, nil
+}
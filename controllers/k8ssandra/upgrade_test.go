@@ -0,0 +1,298 @@
+package k8ssandra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
+	"github.com/k8ssandra/k8ssandra-operator/test/framework"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// upgradeCluster creates a two-dc cluster, changes its serverVersion, and verifies that
+// the datacenters are upgraded one at a time.
+func upgradeCluster(t *testing.T, ctx context.Context, f *framework.Framework, namespace string) {
+	require := require.New(t)
+
+	k8sCtx0 := "cluster-0"
+	k8sCtx1 := "cluster-1"
+
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test",
+		},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:       "test",
+				ServerVersion: "3.11.10",
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &defaultStorageClass,
+					},
+				},
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc1"},
+						K8sContext: k8sCtx0,
+						Size:       3,
+					},
+					{
+						Meta:       api.EmbeddedObjectMeta{Name: "dc2"},
+						K8sContext: k8sCtx1,
+						Size:       3,
+					},
+				},
+			},
+		},
+	}
+
+	err := f.Client.Create(ctx, kc)
+	require.NoError(err, "failed to create K8ssandraCluster")
+
+	verifyFinalizerAdded(ctx, t, f, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+
+	verifySuperUserSecretCreated(ctx, t, f, kc)
+
+	verifyReplicatedSecretReconciled(ctx, t, f, kc)
+
+	verifySystemReplicationAnnotationSet(ctx, t, f, kc)
+
+	dc1Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc1"}, K8sContext: k8sCtx0}
+	dc2Key := framework.ClusterKey{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "dc2"}, K8sContext: k8sCtx1}
+	kcKey := framework.ClusterKey{K8sContext: k8sCtx0, NamespacedName: types.NamespacedName{Namespace: namespace, Name: "test"}}
+
+	t.Log("check that dc1 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc1Key), timeout, interval)
+
+	t.Log("update dc1 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc1Key)
+	require.NoError(err, "failed to set dc1 status ready")
+
+	t.Log("check that dc2 was created")
+	require.Eventually(f.DatacenterExists(ctx, dc2Key), timeout, interval)
+
+	t.Log("update dc2 status to ready")
+	err = f.SetDatacenterStatusReady(ctx, dc2Key)
+	require.NoError(err, "failed to set dc2 status ready")
+
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		return kc.Status.GetConditionStatus(api.CassandraInitialized) == corev1.ConditionTrue
+	}, timeout, interval, "timed out waiting for K8ssandraCluster to be initialized")
+
+	t.Log("change the server version")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		kc.Spec.Cassandra.ServerVersion = "3.11.11"
+		if err := f.Client.Update(ctx, kc); err != nil {
+			t.Logf("failed to update K8ssandraCluster: %v", err)
+			return false
+		}
+		return true
+	}, timeout, interval, "timed out updating K8ssandraCluster")
+
+	upgraded := func(dc *cassdcapi.CassandraDatacenter) bool {
+		return dc.Spec.ServerVersion == "3.11.11"
+	}
+
+	t.Log("check that dc1 is upgraded")
+	require.Eventually(f.NewWithDatacenter(ctx, dc1Key)(upgraded), timeout, interval)
+
+	t.Log("check that dc2 is not upgraded yet")
+	require.False(f.NewWithDatacenter(ctx, dc2Key)(upgraded)(), "dc2 should not be upgraded before dc1")
+
+	simulateDatacenterUpdate(ctx, t, f, dc1Key)
+
+	t.Log("check that dc2 is upgraded")
+	require.Eventually(f.NewWithDatacenter(ctx, dc2Key)(upgraded), timeout, interval)
+
+	simulateDatacenterUpdate(ctx, t, f, dc2Key)
+
+	t.Log("check that the upgrade is recorded in the K8ssandraCluster status")
+	require.Eventually(func() bool {
+		kc := &api.K8ssandraCluster{}
+		if err := f.Get(ctx, kcKey, kc); err != nil {
+			t.Logf("failed to get K8ssandraCluster: %v", err)
+			return false
+		}
+		status := kc.Status.Upgrade
+		return status != nil && status.Progress == api.UpgradeCompleted && status.CompletionTime != nil &&
+			len(status.UpgradedDatacenters) == 2
+	}, timeout, interval, "timed out waiting for the upgrade to complete")
+
+	t.Log("deleting K8ssandraCluster")
+	err = f.DeleteK8ssandraCluster(ctx, client.ObjectKey{Namespace: kc.Namespace, Name: kc.Name})
+	require.NoError(err, "failed to delete K8ssandraCluster")
+	verifyObjectDoesNotExist(ctx, t, f, dc1Key, &cassdcapi.CassandraDatacenter{})
+	verifyObjectDoesNotExist(ctx, t, f, dc2Key, &cassdcapi.CassandraDatacenter{})
+}
+
+// simulateDatacenterUpdate updates the status of the datacenter the way cass-operator
+// does when it applies a change to the spec.
+func simulateDatacenterUpdate(ctx context.Context, t *testing.T, f *framework.Framework, dcKey framework.ClusterKey) {
+	t.Logf("simulate update of %s", dcKey.Name)
+
+	// Timestamps only have a precision of one second.
+	time.Sleep(time.Second)
+
+	err := f.PatchDatacenterStatus(ctx, dcKey, func(dc *cassdcapi.CassandraDatacenter) {
+		dc.SetCondition(cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterUpdating,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+		})
+	})
+	require.NoError(t, err, "failed to patch datacenter status")
+}
+
+// TestReconcileUpgradeHeldBackConfig verifies that a datacenter whose upgrade is held back
+// keeps its version and gets the other config changes, rendered for its version.
+func TestReconcileUpgradeHeldBackConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	kcKey := types.NamespacedName{Namespace: "default", Name: "test"}
+	kc := &api.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kcKey.Namespace, Name: kcKey.Name},
+		Spec: api.K8ssandraClusterSpec{
+			Cassandra: &api.CassandraClusterTemplate{
+				Cluster:       "test",
+				ServerVersion: "4.1.0",
+				Datacenters: []api.CassandraDatacenterTemplate{
+					{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+				},
+			},
+		},
+	}
+
+	newDcConfig := func(serverVersion string, concurrentReads int) *cassandra.DatacenterConfig {
+		return &cassandra.DatacenterConfig{
+			Meta:          api.EmbeddedObjectMeta{Name: "dc1"},
+			Cluster:       "test",
+			ServerVersion: serverVersion,
+			Size:          3,
+			StorageConfig: &cassdcapi.StorageConfig{},
+			CassandraConfig: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ConcurrentReads: pointer.Int(concurrentReads),
+					MaxHintWindowMs: pointer.Int(3600000),
+				},
+			},
+		}
+	}
+	actualDc, err := cassandra.NewDatacenter(kcKey, newDcConfig("4.0.1", 32))
+	require.NoError(t, err)
+	dcConfig := newDcConfig("4.1.0", 64)
+	desiredDc, err := cassandra.NewDatacenter(kcKey, dcConfig)
+	require.NoError(t, err)
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(actualDc).Build()
+	r := &K8ssandraClusterReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		ClientCache:      clientcache.New(c, c, scheme),
+		ManagementApi:    &mockManagementApiFactory{managementApi: new(mocks.ManagementApiFacade)},
+		Recorder:         record.NewFakeRecorder(10),
+	}
+	dc := &dcReconciliation{
+		dcTemplate:   kc.Spec.Cassandra.Datacenters[0],
+		desiredDc:    desiredDc,
+		remoteClient: c,
+		logger:       logr.Discard(),
+		dcConfig:     dcConfig,
+	}
+
+	// The datacenter is not ready, so the upgrade cannot start.
+	recResult := r.reconcileUpgrade(context.Background(), kc, []*dcReconciliation{dc}, logr.Discard())
+	require.True(t, recResult.Completed())
+	require.NotNil(t, kc.Status.Upgrade)
+	assert.Equal(t, api.UpgradePreChecks, kc.Status.Upgrade.Progress)
+
+	expectedConfig, err := cassandra.RenderConfig(newDcConfig("4.0.1", 64), "4.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.1", dc.desiredDc.Spec.ServerVersion)
+	assert.Equal(t, actualDc.Spec.ServerImage, dc.desiredDc.Spec.ServerImage)
+	assert.JSONEq(t, string(expectedConfig), string(dc.desiredDc.Spec.Config))
+	assert.Contains(t, string(dc.desiredDc.Spec.Config), `"max_hint_window_in_ms":3600000`)
+	assert.Contains(t, string(dc.desiredDc.Spec.Config), `"concurrent_reads":64`)
+}
+
+func TestCheckUpgradePreconditions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	normal := httphelper.EndpointState{HostID: "host-1", IsAlive: "true", Status: "NORMAL,-1"}
+	left := httphelper.EndpointState{HostID: "host-2", IsAlive: "false", Status: "LEFT,1,1650000000000"}
+	down := httphelper.EndpointState{HostID: "host-3", IsAlive: "false", Status: "NORMAL,2"}
+
+	tests := []struct {
+		name          string
+		endpoints     []httphelper.EndpointState
+		expectedError string
+	}{
+		{name: "nodes up", endpoints: []httphelper.EndpointState{normal}},
+		{name: "node that left the cluster", endpoints: []httphelper.EndpointState{normal, left}},
+		{name: "node down", endpoints: []httphelper.EndpointState{normal, left, down}, expectedError: "node host-3 is not up and normal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &api.K8ssandraCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+				Spec: api.K8ssandraClusterSpec{
+					Cassandra: &api.CassandraClusterTemplate{
+						Datacenters: []api.CassandraDatacenterTemplate{{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 1}},
+					},
+				},
+			}
+			dc := &cassdcapi.CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+				Status:     *readyDatacenterStatus(),
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+			managementApi := new(mocks.ManagementApiFacade)
+			managementApi.On("GetEndpointStates").Return(tt.endpoints, nil)
+			managementApi.On("GetSchemaVersions").Return(map[string][]string{"schema-1": {"host-1"}}, nil)
+			r := &K8ssandraClusterReconciler{
+				ReconcilerConfig: config.InitConfig(),
+				ClientCache:      clientcache.New(c, c, scheme),
+				ManagementApi:    &mockManagementApiFactory{managementApi: managementApi},
+			}
+
+			status := &api.UpgradeStatus{Progress: api.UpgradePreChecks}
+			recResult := r.checkUpgradePreconditions(context.Background(), kc, status, logr.Discard())
+			assert.Equal(t, tt.expectedError != "", recResult.Completed())
+			assert.Equal(t, tt.expectedError, status.LastError)
+		})
+	}
+}
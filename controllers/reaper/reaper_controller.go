@@ -24,12 +24,14 @@ import (
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,6 +46,7 @@ type ReaperReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	NewManager func() reaper.Manager
+	Recorder   record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=reaper.k8ssandra.io,namespace="k8ssandra",resources=reapers,verbs=get;list;watch;create;update;patch;delete
@@ -51,6 +54,7 @@ type ReaperReconciler struct {
 // +kubebuilder:rbac:groups="apps",namespace="k8ssandra",resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="core",namespace="k8ssandra",resources=pods;secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="core",namespace="k8ssandra",resources=services,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="core",namespace="k8ssandra",resources=events,verbs=create;patch
 
 func (r *ReaperReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx, "Reaper", req.NamespacedName)
@@ -70,8 +74,12 @@ func (r *ReaperReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	actualReaper = actualReaper.DeepCopy()
 	patch := client.MergeFromWithOptions(actualReaper.DeepCopy())
+	wasReady := actualReaper.Status.IsReady()
 
 	result, err := r.reconcile(ctx, actualReaper, logger)
+	if !wasReady && actualReaper.Status.IsReady() {
+		r.Recorder.Event(actualReaper, corev1.EventTypeNormal, events.BecameReady, "Reaper is ready")
+	}
 
 	if patchErr := r.Status().Patch(ctx, actualReaper, patch); patchErr != nil {
 		logger.Error(patchErr, "Failed to update Reaper status")
@@ -177,10 +185,13 @@ func (r *ReaperReconciler) reconcileDeployment(
 					return ctrl.Result{Requeue: true}, nil
 				} else {
 					logger.Error(err, "Failed to create Reaper Deployment")
+					r.Recorder.Eventf(actualReaper, corev1.EventTypeWarning, events.ReconcileDeploymentError,
+						"Failed to create deployment %s: %v", desiredDeployment.Name, err)
 					return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 				}
 			}
 			logger.Info("Reaper Deployment created successfully")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeNormal, events.CreatedDeployment, "Created deployment %s", desiredDeployment.Name)
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, nil
 		} else {
 			logger.Error(err, "Failed to get Reaper Deployment")
@@ -194,6 +205,7 @@ func (r *ReaperReconciler) reconcileDeployment(
 	if !annotations.CompareHashAnnotations(actualDeployment, desiredDeployment) {
 		logger.Info("Updating Reaper Deployment")
 		resourceVersion := actualDeployment.GetResourceVersion()
+		annotations.KeepStoppedReplicas(desiredDeployment, actualDeployment)
 		desiredDeployment.DeepCopyInto(actualDeployment)
 		actualDeployment.SetResourceVersion(resourceVersion)
 		if err := controllerutil.SetControllerReference(actualReaper, actualDeployment, r.Scheme); err != nil {
@@ -201,13 +213,22 @@ func (r *ReaperReconciler) reconcileDeployment(
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 		} else if err := r.Update(ctx, actualDeployment); err != nil {
 			logger.Error(err, "Failed to update Reaper Deployment")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeWarning, events.ReconcileDeploymentError,
+				"Failed to update deployment %s: %v", actualDeployment.Name, err)
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 		} else {
 			logger.Info("Reaper Deployment updated successfully")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeNormal, events.UpdatedDeployment, "Updated deployment %s", actualDeployment.Name)
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, nil
 		}
 	}
 
+	if annotations.HasStoppedReplicas(actualDeployment) {
+		// Reaper cannot be configured until the deployment is scaled back up
+		logger.Info("Reaper Deployment is scaled down while the datacenter is stopped")
+		return ctrl.Result{RequeueAfter: r.DefaultDelay}, nil
+	}
+
 	logger.Info("Reaper Deployment ready")
 	return ctrl.Result{}, nil
 }
@@ -237,10 +258,13 @@ func (r *ReaperReconciler) reconcileService(
 					return ctrl.Result{Requeue: true}, nil
 				} else {
 					logger.Error(err, "Failed to create Reaper Service")
+					r.Recorder.Eventf(actualReaper, corev1.EventTypeWarning, events.ReconcileServiceError,
+						"Failed to create service %s: %v", desiredService.Name, err)
 					return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 				}
 			}
 			logger.Info("Reaper Service created successfully")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeNormal, events.CreatedService, "Created service %s", desiredService.Name)
 			return ctrl.Result{}, nil
 		} else {
 			logger.Error(err, "Failed to get Reaper Service")
@@ -259,9 +283,12 @@ func (r *ReaperReconciler) reconcileService(
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 		} else if err := r.Update(ctx, updatedService); err != nil {
 			logger.Error(err, "Failed to update Reaper Service")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeWarning, events.ReconcileServiceError,
+				"Failed to update service %s: %v", updatedService.Name, err)
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 		} else {
 			logger.Info("Reaper Service updated successfully")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeNormal, events.UpdatedService, "Updated service %s", updatedService.Name)
 			return ctrl.Result{}, nil
 		}
 	}
@@ -281,9 +308,17 @@ func (r *ReaperReconciler) configureReaper(ctx context.Context, actualReaper *re
 		logger.Info("registering cluster with reaper")
 		if err = manager.AddClusterToReaper(ctx, actualDc); err != nil {
 			logger.Error(err, "failed to register cluster with reaper")
+			r.Recorder.Eventf(actualReaper, corev1.EventTypeWarning, events.RegisterClusterFailed,
+				"Failed to register cluster %s with Reaper: %v", actualDc.Spec.ClusterName, err)
+			// The K8ssandraCluster controller reports the failure in the status of the
+			// K8ssandraCluster.
+			actualReaper.Status.SetClusterRegistered(err)
 			return ctrl.Result{RequeueAfter: r.DefaultDelay}, err
 		}
+		r.Recorder.Eventf(actualReaper, corev1.EventTypeNormal, events.RegisteredCluster,
+			"Registered cluster %s with Reaper", actualDc.Spec.ClusterName)
 	}
+	actualReaper.Status.SetClusterRegistered(nil)
 	return ctrl.Result{}, nil
 }
 
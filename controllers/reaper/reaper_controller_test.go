@@ -2,6 +2,8 @@ package reaper
 
 import (
 	"context"
+	"errors"
+	"github.com/go-logr/logr"
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
@@ -16,9 +18,14 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"testing"
 	"time"
@@ -43,6 +50,7 @@ func TestReaper(t *testing.T) {
 			Client:           mgr.GetClient(),
 			Scheme:           mgr.GetScheme(),
 			NewManager:       newMockManager,
+			Recorder:         mgr.GetEventRecorderFor("reaper-controller"),
 		}).SetupWithManager(mgr)
 		return err
 	})
@@ -416,3 +424,102 @@ func patchDeploymentStatus(t *testing.T, ctx context.Context, deployment *appsv1
 	err := k8sClient.Status().Patch(ctx, deployment, deploymentPatch)
 	require.NoError(t, err)
 }
+
+// TestReconcileStoppedDeployment verifies that a Reaper deployment that was scaled down
+// because its datacenter is stopped is not scaled back up when it is updated, and that
+// Reaper is not configured while it has no pods.
+func TestReconcileStoppedDeployment(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, cassdcapi.AddToScheme(s))
+	require.NoError(t, reaperapi.AddToScheme(s))
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: cassandraDatacenterName},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName:   cassandraClusterName,
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Size:          3,
+		},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			CassandraOperatorProgress: cassdcapi.ProgressReady,
+			Conditions: []cassdcapi.DatacenterCondition{{
+				Type:   cassdcapi.DatacenterReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+	rpr := newReaper("default")
+
+	// The deployment is outdated and was scaled down before the datacenter was stopped
+	deployment := reaper.NewDeployment(rpr, dc)
+	zero := int32(0)
+	deployment.Spec.Replicas = &zero
+	deployment.Annotations[k8ssandraapi.ResourceHashAnnotation] = "outdated"
+	deployment.Annotations[k8ssandraapi.StoppedReplicasAnnotation] = "1"
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(dc, rpr, deployment).Build()
+	r := &ReaperReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Client:           c,
+		Scheme:           s,
+		NewManager: func() reaper.Manager {
+			t.Fatal("Reaper should not be configured while its deployment is scaled down")
+			return nil
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	// The first reconciliation updates the deployment, the second one waits for it to be
+	// scaled back up
+	for i := 0; i < 2; i++ {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(rpr)})
+		require.NoError(t, err)
+		assert.Equal(t, r.DefaultDelay, result.RequeueAfter)
+	}
+
+	actual := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), actual))
+	assert.NotEqual(t, "outdated", actual.Annotations[k8ssandraapi.ResourceHashAnnotation], "the deployment should be updated")
+	assert.Equal(t, int32(0), *actual.Spec.Replicas)
+	assert.Equal(t, "1", actual.Annotations[k8ssandraapi.StoppedReplicasAnnotation])
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(rpr), rpr))
+	assert.False(t, rpr.Status.IsReady())
+}
+
+// TestConfigureReaper verifies that a failure to register the cluster with Reaper is
+// recorded in the ClusterRegistered condition, and that it is cleared once the
+// registration succeeds.
+func TestConfigureReaper(t *testing.T) {
+	ctx := context.Background()
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: cassandraDatacenterName},
+		Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: cassandraClusterName},
+	}
+	rpr := newReaper("default")
+
+	registrationErr := errors.New("connection refused")
+	manager := new(mocks.ReaperManager)
+	manager.On("Connect", mock.Anything).Return(nil)
+	manager.On("VerifyClusterIsConfigured", mock.Anything, mock.Anything).Return(false, nil)
+	manager.On("AddClusterToReaper", mock.Anything, mock.Anything).Return(registrationErr).Once()
+	manager.On("AddClusterToReaper", mock.Anything, mock.Anything).Return(nil).Once()
+	r := &ReaperReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		NewManager:       func() reaper.Manager { return manager },
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	_, err := r.configureReaper(ctx, rpr, dc, logr.Discard())
+	require.Error(t, err)
+	assert.Equal(t, registrationErr.Error(), rpr.Status.ClusterRegistrationError())
+	assert.Equal(t, corev1.ConditionFalse, rpr.Status.GetConditionStatus(reaperapi.ReaperClusterRegistered))
+
+	_, err = r.configureReaper(ctx, rpr, dc, logr.Discard())
+	require.NoError(t, err)
+	assert.Empty(t, rpr.Status.ClusterRegistrationError())
+	assert.Equal(t, corev1.ConditionTrue, rpr.Status.GetConditionStatus(reaperapi.ReaperClusterRegistered))
+}
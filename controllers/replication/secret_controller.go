@@ -11,12 +11,14 @@ import (
 	api "github.com/k8ssandra/k8ssandra-operator/apis/replication/v1alpha1"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/clientcache"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,10 +43,12 @@ const (
 // +kubebuilder:rbac:groups=replication.k8ssandra.io,namespace="k8ssandra",resources=replicatedsecrets,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups=replication.k8ssandra.io,namespace="k8ssandra",resources=replicatedsecrets/finalizers,verbs=update
 // +kubebuilder:rbac:groups=replication.k8ssandra.io,namespace="k8ssandra",resources=replicatedsecrets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=events,verbs=create;patch
 
 type SecretSyncController struct {
 	*config.ReconcilerConfig
 	ClientCache *clientcache.ClientCache
+	Recorder    record.EventRecorder
 	// TODO We need a better structure for empty selectors (match whole kind)
 	WatchNamespaces []string
 	selectorMutex   sync.RWMutex
@@ -193,6 +197,7 @@ func (s *SecretSyncController) Reconcile(ctx context.Context, req ctrl.Request)
 	// For status updates
 	patch := client.MergeFrom(rsec.DeepCopy())
 	// patch := client.MergeFromWithOptions(rsec.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	previousConditions := rsec.Status.Conditions
 	rsec.Status.Conditions = make([]api.ReplicationCondition, 0, len(rsec.Spec.ReplicationTargets))
 
 	for _, target := range rsec.Spec.ReplicationTargets {
@@ -205,6 +210,8 @@ func (s *SecretSyncController) Reconcile(ctx context.Context, req ctrl.Request)
 			remoteClient, err = s.ClientCache.GetRemoteClient(target.K8sContextName)
 			if err != nil {
 				logger.Error(err, "Failed to fetch remote client for managed cluster", "ReplicatedSecret", req.NamespacedName, "TargetContext", target)
+				s.recordEvent(ctx, rsec, corev1.EventTypeWarning, events.SecretReplicationFailed,
+					"Failed to get a client for context %s: %v", target.K8sContextName, err)
 				return ctrl.Result{Requeue: true}, err
 			}
 		}
@@ -244,7 +251,7 @@ func (s *SecretSyncController) Reconcile(ctx context.Context, req ctrl.Request)
 			}
 
 			if fetchedSecret.Immutable != nil && *fetchedSecret.Immutable {
-				err := fmt.Errorf("target secret is immutable")
+				err = fmt.Errorf("target secret %s is immutable", fetchedSecret.Name)
 				logger.Error(err, "Failed to modify target secret, secret is set to immutable", "Secret", fetchedSecret.Name, "TargetContext", target)
 				break TargetSecrets
 			}
@@ -260,8 +267,14 @@ func (s *SecretSyncController) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 		if err != nil {
 			cond.Status = corev1.ConditionFalse
+			s.recordEvent(ctx, rsec, corev1.EventTypeWarning, events.SecretReplicationFailed,
+				"Failed to replicate secrets to %s: %v", describeTarget(target), err)
 		} else {
 			cond.Status = corev1.ConditionTrue
+			if previous := findReplicationCondition(previousConditions, target.K8sContextName); previous == nil || previous.Status != corev1.ConditionTrue {
+				s.recordEvent(ctx, rsec, corev1.EventTypeNormal, events.SecretReplicated,
+					"Replicated secrets to %s", describeTarget(target))
+			}
 		}
 
 		timeNow := metav1.Now()
@@ -285,6 +298,41 @@ func (s *SecretSyncController) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, err
 }
 
+// recordEvent records an event on rsec. If rsec belongs to a K8ssandraCluster, the event
+// is mirrored on the K8ssandraCluster so that replication problems in remote clusters are
+// visible from the control plane.
+func (s *SecretSyncController) recordEvent(ctx context.Context, rsec *api.ReplicatedSecret, eventType, reason, messageFmt string, args ...interface{}) {
+	s.Recorder.Eventf(rsec, eventType, reason, messageFmt, args...)
+
+	kcName, found := rsec.Labels[coreapi.K8ssandraClusterNameLabel]
+	if !found {
+		return
+	}
+	kcKey := types.NamespacedName{Namespace: rsec.Labels[coreapi.K8ssandraClusterNamespaceLabel], Name: kcName}
+	kc := &coreapi.K8ssandraCluster{}
+	if err := s.ClientCache.GetLocalClient().Get(ctx, kcKey, kc); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get K8ssandraCluster to record event", "K8ssandraCluster", kcKey)
+		return
+	}
+	s.Recorder.Eventf(kc, eventType, reason, "ReplicatedSecret %s: %s", rsec.Name, fmt.Sprintf(messageFmt, args...))
+}
+
+func describeTarget(target api.ReplicationTarget) string {
+	if target.K8sContextName == "" {
+		return "the local cluster"
+	}
+	return fmt.Sprintf("context %s", target.K8sContextName)
+}
+
+func findReplicationCondition(conditions []api.ReplicationCondition, cluster string) *api.ReplicationCondition {
+	for i := range conditions {
+		if conditions[i].Cluster == cluster && conditions[i].Type == api.ReplicationDone {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
 func requiresUpdate(source, dest client.Object) bool {
 	// In case we target the same cluster
 	if source.GetUID() == dest.GetUID() {
@@ -50,6 +50,7 @@ func TestSecretController(t *testing.T) {
 		return (&SecretSyncController{
 			ReconcilerConfig: config.InitConfig(),
 			ClientCache:      clientCache,
+			Recorder:         mgr.GetEventRecorderFor("secret-sync-controller"),
 		}).SetupWithManager(mgr, clusters)
 	})
 	if err != nil {
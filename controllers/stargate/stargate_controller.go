@@ -23,12 +23,14 @@ import (
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/events"
 	stargateutil "github.com/k8ssandra/k8ssandra-operator/pkg/stargate"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,12 +46,14 @@ import (
 // +kubebuilder:rbac:groups=cassandra.datastax.com,namespace="k8ssandra",resources=cassandradatacenters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,namespace="k8ssandra",resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,namespace="k8ssandra",resources=events,verbs=create;patch
 
 // StargateReconciler reconciles a Stargate object
 type StargateReconciler struct {
 	*config.ReconcilerConfig
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -191,6 +195,7 @@ func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			if !annotations.CompareHashAnnotations(&desiredDeployment, &actualDeployment) {
 				logger.Info("Updating Stargate Deployment", "Deployment", deploymentKey)
 				resourceVersion := actualDeployment.GetResourceVersion()
+				annotations.KeepStoppedReplicas(&desiredDeployment, &actualDeployment)
 				desiredDeployment.DeepCopyInto(&actualDeployment)
 				actualDeployment.SetResourceVersion(resourceVersion)
 				// Set Stargate instance as the owner and controller
@@ -199,9 +204,12 @@ func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 					return ctrl.Result{}, err
 				} else if err := r.Update(ctx, &actualDeployment); err != nil {
 					logger.Error(err, "Failed to update Stargate Deployment", "Deployment", deploymentKey)
+					r.Recorder.Eventf(stargate, corev1.EventTypeWarning, events.ReconcileDeploymentError,
+						"Failed to update deployment %s: %v", actualDeployment.Name, err)
 					return ctrl.Result{}, err
 				} else {
 					logger.Info("Stargate Deployment updated successfully", "Deployment", deploymentKey)
+					r.Recorder.Eventf(stargate, corev1.EventTypeNormal, events.UpdatedDeployment, "Updated deployment %s", actualDeployment.Name)
 					return ctrl.Result{RequeueAfter: r.ReconcilerConfig.LongDelay}, nil
 				}
 			}
@@ -228,10 +236,13 @@ func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				return ctrl.Result{Requeue: true}, nil
 			} else {
 				logger.Error(err, "Failed to create new Stargate Deployment", "Deployment", deploymentKey)
+				r.Recorder.Eventf(stargate, corev1.EventTypeWarning, events.ReconcileDeploymentError,
+					"Failed to create deployment %s: %v", desiredDeployment.Name, err)
 				return ctrl.Result{}, err
 			}
 		} else {
 			logger.Info("Stargate Deployment created successfully", "Deployment", deploymentKey)
+			r.Recorder.Eventf(stargate, corev1.EventTypeNormal, events.CreatedDeployment, "Created deployment %s", desiredDeployment.Name)
 			return ctrl.Result{RequeueAfter: r.ReconcilerConfig.LongDelay}, nil
 		}
 	}
@@ -288,10 +299,13 @@ func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 					return ctrl.Result{Requeue: true}, nil
 				} else {
 					logger.Error(err, "Failed to create new Stargate Service", "Service", serviceKey)
+					r.Recorder.Eventf(stargate, corev1.EventTypeWarning, events.ReconcileServiceError,
+						"Failed to create service %s: %v", desiredService.Name, err)
 					return ctrl.Result{}, err
 				}
 			} else {
 				logger.Info("Stargate Service created successfully", "Service", serviceKey)
+				r.Recorder.Eventf(stargate, corev1.EventTypeNormal, events.CreatedService, "Created service %s", desiredService.Name)
 				return ctrl.Result{RequeueAfter: r.ReconcilerConfig.DefaultDelay}, nil
 			}
 		} else {
@@ -312,9 +326,12 @@ func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return ctrl.Result{}, err
 		} else if err := r.Update(ctx, actualService); err != nil {
 			logger.Error(err, "Failed to update Stargate Service", "Service", serviceKey)
+			r.Recorder.Eventf(stargate, corev1.EventTypeWarning, events.ReconcileServiceError,
+				"Failed to update service %s: %v", actualService.Name, err)
 			return ctrl.Result{}, err
 		} else {
 			logger.Info("Stargate Service updated successfully", "Service", serviceKey)
+			r.Recorder.Eventf(stargate, corev1.EventTypeNormal, events.UpdatedService, "Updated service %s", actualService.Name)
 			return ctrl.Result{RequeueAfter: r.ReconcilerConfig.LongDelay}, nil
 		}
 	}
@@ -333,6 +350,7 @@ func (r *StargateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			logger.Error(err, "Failed to update Stargate status", "Stargate", req.NamespacedName)
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Event(stargate, corev1.EventTypeNormal, events.BecameReady, "All Stargate replicas are ready")
 	}
 
 	logger.Info("Stargate successfully reconciled", "Stargate", req.NamespacedName)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	api "github.com/k8ssandra/k8ssandra-operator/apis/stargate/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,9 +15,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/k8ssandra/k8ssandra-operator/pkg/config"
@@ -37,6 +42,7 @@ func TestStargate(t *testing.T) {
 			ReconcilerConfig: config.InitConfig(),
 			Client:           mgr.GetClient(),
 			Scheme:           scheme.Scheme,
+			Recorder:         mgr.GetEventRecorderFor("stargate-controller"),
 		}).SetupWithManager(mgr)
 		return err
 	})
@@ -364,3 +370,69 @@ func testCreateStargateMultiRack(t *testing.T, testClient client.Client) {
 	assert.Equal(t, api.StargateReady, stargate.Status.Conditions[0].Type)
 	assert.Equal(t, corev1.ConditionTrue, stargate.Status.Conditions[0].Status)
 }
+
+// TestReconcileStoppedDeployment verifies that a Stargate deployment that was scaled down
+// because its datacenter is stopped is not scaled back up when it is updated.
+func TestReconcileStoppedDeployment(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, cassdcapi.AddToScheme(s))
+	require.NoError(t, api.AddToScheme(s))
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			Size:          3,
+			ServerVersion: "3.11.10",
+			ServerType:    "cassandra",
+			ClusterName:   "test",
+		},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			CassandraOperatorProgress: cassdcapi.ProgressReady,
+			Conditions: []cassdcapi.DatacenterCondition{{
+				Type:   cassdcapi.DatacenterReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+	sg := &api.Stargate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-dc1-stargate"},
+		Spec: api.StargateSpec{
+			StargateDatacenterTemplate: api.StargateDatacenterTemplate{
+				StargateClusterTemplate: api.StargateClusterTemplate{Size: 2},
+			},
+			DatacenterRef: corev1.LocalObjectReference{Name: "dc1"},
+		},
+		Status: api.StargateStatus{Progress: api.StargateProgressRunning},
+	}
+
+	desiredDeployments := stargate.NewDeployments(sg, dc)
+	require.Len(t, desiredDeployments, 1)
+	var deployment appsv1.Deployment
+	for _, desired := range desiredDeployments {
+		deployment = *desired.DeepCopy()
+	}
+	// The deployment is outdated and was scaled down before the datacenter was stopped
+	zero := int32(0)
+	deployment.Spec.Replicas = &zero
+	deployment.Annotations[k8ssandraapi.ResourceHashAnnotation] = "outdated"
+	deployment.Annotations[k8ssandraapi.StoppedReplicasAnnotation] = "1"
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(dc, sg, &deployment).Build()
+	r := &StargateReconciler{
+		ReconcilerConfig: config.InitConfig(),
+		Client:           c,
+		Scheme:           s,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(sg)})
+	require.NoError(t, err)
+
+	actual := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(&deployment), actual))
+	assert.NotEqual(t, "outdated", actual.Annotations[k8ssandraapi.ResourceHashAnnotation], "the deployment should be updated")
+	assert.Equal(t, int32(0), *actual.Spec.Replicas)
+	assert.Equal(t, "2", actual.Annotations[k8ssandraapi.StoppedReplicasAnnotation])
+}
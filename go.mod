@@ -6,12 +6,16 @@ require (
 	github.com/Jeffail/gabs v1.4.0
 	github.com/bombsimon/logrusr v1.1.0
 	github.com/datastax/go-cassandra-native-protocol v0.0.0-20210829124742-a80a54434112
+	github.com/go-asn1-ber/asn1-ber v1.5.1
+	github.com/go-ldap/ldap/v3 v3.4.1
 	github.com/go-logr/logr v0.4.0
 	github.com/go-sql-driver/mysql v1.5.0 // indirect
+	github.com/gocql/gocql v0.0.0-20210515062232-b7ef815b4556
 	github.com/google/uuid v1.2.0
 	github.com/gruntwork-io/terratest v0.37.7
 	github.com/k8ssandra/cass-operator v1.9.0
 	github.com/k8ssandra/reaper-client-go v0.3.1-0.20210617111910-fe2ba92f8efb
+	github.com/pavel-v-chernykh/keystore-go v2.1.0+incompatible
 	github.com/pkg/errors v0.9.1
 	github.com/rs/zerolog v1.20.0
 	github.com/sirupsen/logrus v1.8.1
@@ -21,7 +25,9 @@ require (
 	k8s.io/apimachinery v0.22.2
 	k8s.io/client-go v0.22.2
 	k8s.io/kubernetes v1.22.2
+	k8s.io/utils v0.0.0-20210819203725-bdf08cb9a70a
 	sigs.k8s.io/controller-runtime v0.9.2
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
@@ -133,9 +133,11 @@ func main() {
 
 	reconcilerConfig := config.InitConfig()
 
+	var clientCache *clientcache.ClientCache
+
 	if isControlPlane() {
 		// Fetch ClientConfigs and create the clientCache
-		clientCache := clientcache.New(mgr.GetClient(), uncachedClient, scheme)
+		clientCache = clientcache.New(mgr.GetClient(), uncachedClient, scheme)
 
 		cConfigs := configapi.ClientConfigList{}
 		err = uncachedClient.List(ctx, &cConfigs, client.InNamespace(watchNamespace))
@@ -194,15 +196,42 @@ func main() {
 			Scheme:           mgr.GetScheme(),
 			ClientCache:      clientCache,
 			ManagementApi:    cassandra.NewManagementApiFactory(),
+			Cql:              cassandra.NewCqlClientFactory(),
+			Recorder:         mgr.GetEventRecorderFor(k8ssandraiov1alpha1.CreatedByLabelValueK8ssandraClusterController),
 		}).SetupWithManager(mgr, additionalClusters); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "K8ssandraCluster")
 			os.Exit(1)
 		}
 
+		if err = (&k8ssandractrl.CassandraKeyspaceReconciler{
+			ReconcilerConfig: reconcilerConfig,
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ClientCache:      clientCache,
+			ManagementApi:    cassandra.NewManagementApiFactory(),
+			Cql:              cassandra.NewCqlClientFactory(),
+			Recorder:         mgr.GetEventRecorderFor("cassandrakeyspace-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CassandraKeyspace")
+			os.Exit(1)
+		}
+		if err = (&k8ssandractrl.CassandraRoleReconciler{
+			ReconcilerConfig: reconcilerConfig,
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			ClientCache:      clientCache,
+			Cql:              cassandra.NewCqlClientFactory(),
+			Recorder:         mgr.GetEventRecorderFor("cassandrarole-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CassandraRole")
+			os.Exit(1)
+		}
+
 		if err = (&replicationctrl.SecretSyncController{
 			ReconcilerConfig: reconcilerConfig,
 			ClientCache:      clientCache,
 			WatchNamespaces:  []string{watchNamespace},
+			Recorder:         mgr.GetEventRecorderFor("secret-sync-controller"),
 		}).SetupWithManager(mgr, additionalClusters); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "SecretSync")
 			os.Exit(1)
@@ -213,6 +242,7 @@ func main() {
 		ReconcilerConfig: reconcilerConfig,
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorderFor(k8ssandraiov1alpha1.CreatedByLabelValueStargateController),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Stargate")
 		os.Exit(1)
@@ -223,11 +253,23 @@ func main() {
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		NewManager:       reaper.NewManager,
+		Recorder:         mgr.GetEventRecorderFor(k8ssandraiov1alpha1.CreatedByLabelValueReaperController),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Reaper")
 		os.Exit(1)
 	}
 
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&k8ssandraiov1alpha1.K8ssandraCluster{}).SetupWebhookWithManager(mgr, clientCache); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "K8ssandraCluster")
+			os.Exit(1)
+		}
+		if err = (&k8ssandraiov1alpha1.CassandraKeyspace{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CassandraKeyspace")
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
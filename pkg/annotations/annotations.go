@@ -1,8 +1,12 @@
 package annotations
 
 import (
+	"strconv"
+
 	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Annotated interface {
@@ -44,3 +48,25 @@ func AddHashAnnotation(obj Annotated) {
 func CompareHashAnnotations(r1, r2 Annotated) bool {
 	return CompareAnnotations(r1, r2, k8ssandraapi.ResourceHashAnnotation)
 }
+
+// HasStoppedReplicas returns true if obj was scaled down to zero because its datacenter is
+// stopped.
+func HasStoppedReplicas(obj Annotated) bool {
+	return GetAnnotation(obj, k8ssandraapi.StoppedReplicasAnnotation) != ""
+}
+
+// KeepStoppedReplicas keeps desired scaled down to zero if actual was scaled down because its
+// datacenter is stopped. The StoppedReplicasAnnotation is updated with the replicas of
+// desired, so that they are restored once the datacenter is resumed.
+func KeepStoppedReplicas(desired, actual *appsv1.Deployment) {
+	if !HasStoppedReplicas(actual) {
+		return
+	}
+	replicas := int32(1)
+	if desired.Spec.Replicas != nil {
+		replicas = *desired.Spec.Replicas
+	}
+	metav1.SetMetaDataAnnotation(&desired.ObjectMeta, k8ssandraapi.StoppedReplicasAnnotation, strconv.Itoa(int(replicas)))
+	zero := int32(0)
+	desired.Spec.Replicas = &zero
+}
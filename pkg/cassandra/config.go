@@ -2,13 +2,21 @@ package cassandra
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 
 	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
 )
 
 const (
+	passwordAuthenticator = "PasswordAuthenticator"
+	allowAllAuthenticator = "AllowAllAuthenticator"
+	ldapAuthenticator     = "com.instaclustr.cassandra.ldap.LDAPAuthenticator"
+	ldapRoleManager       = "com.instaclustr.cassandra.ldap.LDAPCassandraRoleManager"
+
 	systemReplicationDcNames = "-Dcassandra.system_distributed_replication_dc_names"
 	systemReplicationFactor  = "-Dcassandra.system_distributed_replication_per_dc"
 )
@@ -18,41 +26,29 @@ const (
 type config struct {
 	cassandraVersion string
 
+	javaVersion int
+
 	*api.CassandraYaml
 
-	JvmOptions *jvmOptions
-}
+	auth *authOptions
 
-// jvmOptions is an internal type that is intended to be marshaled into JSON that is valid
-// for the jvm options portion of the value supplied to CassandraDatacenter.Spec.Config.
-type jvmOptions struct {
-	InitialHeapSize   *int64   `json:"initial_heap_size,omitempty"`
-	MaxHeapSize       *int64   `json:"max_heap_size,omitempty"`
-	HeapNewGenSize    *int64   `json:"heap_size_young_generation,omitempty"`
-	AdditionalOptions []string `json:"additional-jvm-opts,omitempty"`
-}
+	JvmOptions *jvmOptions
 
-func IsCassandra3(version string) bool {
-	return strings.HasPrefix(version, "3.")
-}
+	GcOptions *gcOptions
 
-func isCassandra4(version string) bool {
-	return strings.HasPrefix(version, "4.0")
+	CassandraEnv *cassandraEnv
 }
 
 func (c config) MarshalJSON() ([]byte, error) {
 	config := make(map[string]interface{})
 
 	if c.CassandraYaml != nil {
-		if isCassandra4(c.cassandraVersion) {
-			c.StartRpc = nil
-			c.ThriftPreparedStatementCacheSizeMb = nil
-		}
+		c.CassandraYaml = supportedProperties(c.CassandraYaml, c.cassandraVersion)
 
 		// Even though we default to Cassandra's stock defaults for num_tokens, we need to
 		// explicitly set it because the config builder defaults to num_tokens: 1
 		if c.NumTokens == nil {
-			if isCassandra4(c.cassandraVersion) {
+			if version.AtLeast(c.cassandraVersion, "4.0") {
 				numTokens := 16
 				c.NumTokens = &numTokens
 			} else {
@@ -61,22 +57,188 @@ func (c config) MarshalJSON() ([]byte, error) {
 			}
 		}
 
-		config["cassandra-yaml"] = c.CassandraYaml
+		cassandraYaml := struct {
+			*api.CassandraYaml
+			*authOptions
+		}{c.CassandraYaml, c.auth}
+		if version.AtLeast(c.cassandraVersion, "4.1") {
+			renamed, err := renameProperties4_1(cassandraYaml)
+			if err != nil {
+				return nil, err
+			}
+			config["cassandra-yaml"] = renamed
+		} else {
+			config["cassandra-yaml"] = cassandraYaml
+		}
 	}
 
 	if c.JvmOptions != nil {
-		if strings.HasPrefix(c.cassandraVersion, "3.11") {
-			config["jvm-options"] = c.JvmOptions
+		if version.Cassandra3_11.Contains(c.cassandraVersion) {
+			jvmOptions := *c.JvmOptions
+			if c.GcOptions != nil {
+				jvmOptions.gcOptions = c.GcOptions
+				jvmOptions.AdditionalOptions = append(append([]string{}, c.GcOptions.AdditionalOptions...), jvmOptions.AdditionalOptions...)
+			}
+			config["jvm-options"] = jvmOptions
 		} else {
 			config["jvm-server-options"] = c.JvmOptions
+			if c.GcOptions != nil {
+				config[fmt.Sprintf("jvm%d-server-options", c.javaVersion)] = c.GcOptions
+			}
 		}
 	}
 
+	if c.CassandraEnv != nil {
+		config["cassandra-env-sh"] = c.CassandraEnv
+	}
+
 	return json.Marshal(&config)
 }
 
-func newConfig(apiConfig *api.CassandraConfig, cassandraVersion string) config {
-	cfg := config{cassandraVersion: cassandraVersion}
+// authOptions are the cassandra.yaml properties that are derived from api.Auth. They are
+// not exposed in api.CassandraYaml.
+type authOptions struct {
+	Authenticator string `json:"authenticator"`
+
+	Authorizer string `json:"authorizer"`
+
+	RoleManager string `json:"role_manager,omitempty"`
+
+	RolesValidityMillis *int64 `json:"roles_validity_in_ms,omitempty"`
+
+	RolesUpdateIntervalMillis *int64 `json:"roles_update_interval_in_ms,omitempty"`
+
+	PermissionsValidityMillis *int64 `json:"permissions_validity_in_ms,omitempty"`
+
+	PermissionsUpdateIntervalMillis *int64 `json:"permissions_update_interval_in_ms,omitempty"`
+
+	CredentialsValidityMillis *int64 `json:"credentials_validity_in_ms,omitempty"`
+
+	CredentialsUpdateIntervalMillis *int64 `json:"credentials_update_interval_in_ms,omitempty"`
+}
+
+func newAuthOptions(auth *api.Auth) *authOptions {
+	if auth == nil {
+		return nil
+	}
+	options := &authOptions{
+		Authenticator: allowAllAuthenticator,
+		Authorizer:    "AllowAllAuthorizer",
+	}
+	if auth.IsEnabled() {
+		options.Authenticator = passwordAuthenticator
+		options.Authorizer = "CassandraAuthorizer"
+		if auth.IsLDAPEnabled() {
+			options.Authenticator = ldapAuthenticator
+			options.RoleManager = ldapRoleManager
+		}
+	}
+	options.RolesValidityMillis = auth.CacheValidityPeriodMillis
+	options.PermissionsValidityMillis = auth.CacheValidityPeriodMillis
+	options.CredentialsValidityMillis = auth.CacheValidityPeriodMillis
+	options.RolesUpdateIntervalMillis = auth.CacheUpdateIntervalMillis
+	options.PermissionsUpdateIntervalMillis = auth.CacheUpdateIntervalMillis
+	options.CredentialsUpdateIntervalMillis = auth.CacheUpdateIntervalMillis
+	return options
+}
+
+// IsAuthEnabled returns true if config, which is a JSON document as rendered by
+// CreateJsonConfig, enables authentication. This is the case when it does not set the
+// authenticator since the default one is the PasswordAuthenticator.
+func IsAuthEnabled(config []byte) (bool, error) {
+	sections, err := parseConfig(config)
+	if err != nil {
+		return false, err
+	}
+	authenticator, found := yamlSection(sections)["authenticator"]
+	return !found || authenticator != allowAllAuthenticator, nil
+}
+
+// authProperties are the cassandra.yaml properties that select how clients are
+// authenticated and authorized.
+var authProperties = []string{"authenticator", "authorizer", "role_manager"}
+
+// HoldBackAuth returns desired, which like actual is a JSON document as rendered by
+// CreateJsonConfig, with the authentication properties of actual. The other changes of
+// desired are kept. actual is returned as is if it is equivalent to the result, so that
+// the config of a datacenter is not rewritten needlessly.
+func HoldBackAuth(actual, desired []byte) ([]byte, error) {
+	actualSections, err := parseConfig(actual)
+	if err != nil {
+		return nil, err
+	}
+	desiredSections, err := parseConfig(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	actualYaml, _ := actualSections["cassandra-yaml"].(map[string]interface{})
+	desiredYaml, found := desiredSections["cassandra-yaml"].(map[string]interface{})
+	if !found {
+		desiredYaml = make(map[string]interface{})
+	}
+	for _, property := range authProperties {
+		if value, found := actualYaml[property]; found {
+			desiredYaml[property] = value
+		} else {
+			delete(desiredYaml, property)
+		}
+	}
+	if found || len(desiredYaml) > 0 {
+		desiredSections["cassandra-yaml"] = desiredYaml
+	}
+
+	if reflect.DeepEqual(actualSections, desiredSections) {
+		return actual, nil
+	}
+	return json.Marshal(desiredSections)
+}
+
+// supportedProperties returns a copy of cassandraYaml without the properties that do not
+// exist in cassandraVersion.
+func supportedProperties(cassandraYaml *api.CassandraYaml, cassandraVersion string) *api.CassandraYaml {
+	supported := cassandraYaml.DeepCopy()
+	value := reflect.ValueOf(supported).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if !propertyExists(value.Type().Field(i), cassandraVersion) {
+			value.Field(i).Set(reflect.Zero(value.Field(i).Type()))
+		}
+	}
+	return supported
+}
+
+// UnsupportedProperties returns the names of the properties of config that are set but do
+// not exist in cassandraVersion. They are not rendered in the configuration of the
+// CassandraDatacenter.
+func UnsupportedProperties(config *api.CassandraConfig, cassandraVersion string) []string {
+	if config == nil || config.CassandraYaml == nil {
+		return nil
+	}
+	var unsupported []string
+	value := reflect.ValueOf(config.CassandraYaml).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !value.Field(i).IsZero() && !propertyExists(field, cassandraVersion) {
+			unsupported = append(unsupported, api.CassandraYamlPropertyName(field))
+		}
+	}
+	return unsupported
+}
+
+// propertyExists returns true if the cassandra.yaml property of field exists in
+// cassandraVersion, according to the since and removed tags of the field.
+func propertyExists(field reflect.StructField, cassandraVersion string) bool {
+	if since, found := field.Tag.Lookup("since"); found && !version.AtLeast(cassandraVersion, since) {
+		return false
+	}
+	if removed, found := field.Tag.Lookup("removed"); found && version.AtLeast(cassandraVersion, removed) {
+		return false
+	}
+	return true
+}
+
+func newConfig(apiConfig *api.CassandraConfig, auth *api.Auth, cassandraVersion string) config {
+	cfg := config{cassandraVersion: cassandraVersion, auth: newAuthOptions(auth)}
 
 	if apiConfig.CassandraYaml == nil {
 		cfg.CassandraYaml = &api.CassandraYaml{}
@@ -85,19 +247,10 @@ func newConfig(apiConfig *api.CassandraConfig, cassandraVersion string) config {
 	}
 
 	if apiConfig.JvmOptions != nil {
-		cfg.JvmOptions = &jvmOptions{}
-		if apiConfig.JvmOptions.HeapSize != nil {
-			heapSize := apiConfig.JvmOptions.HeapSize.Value()
-			cfg.JvmOptions.InitialHeapSize = &heapSize
-			cfg.JvmOptions.MaxHeapSize = &heapSize
-		}
-
-		if apiConfig.JvmOptions.HeapNewGenSize != nil {
-			newGenSize := apiConfig.JvmOptions.HeapNewGenSize.Value()
-			cfg.JvmOptions.HeapNewGenSize = &newGenSize
-		}
-
-		cfg.JvmOptions.AdditionalOptions = apiConfig.JvmOptions.AdditionalOptions
+		cfg.javaVersion = apiConfig.JvmOptions.GetJavaVersion(cassandraVersion)
+		cfg.JvmOptions = newJvmOptions(apiConfig.JvmOptions)
+		cfg.GcOptions = newGcOptions(apiConfig.JvmOptions, cfg.javaVersion)
+		cfg.CassandraEnv = newCassandraEnv(apiConfig.JvmOptions)
 	}
 
 	return cfg
@@ -157,9 +310,18 @@ func AllowAlterRfDuringRangeMovement(dcConfig *DatacenterConfig) {
 // CreateJsonConfig parses dcConfig into a raw JSON base64-encoded string. If config is nil
 // then nil, nil is returned
 func CreateJsonConfig(config *api.CassandraConfig, cassandraVersion string) ([]byte, error) {
+	return createJsonConfig(config, nil, cassandraVersion)
+}
+
+// createJsonConfig is CreateJsonConfig with the authentication settings of auth added to
+// the cassandra.yaml. They are not rendered when auth is nil.
+func createJsonConfig(config *api.CassandraConfig, auth *api.Auth, cassandraVersion string) ([]byte, error) {
 	if config == nil {
-		return nil, nil
+		if auth == nil {
+			return nil, nil
+		}
+		config = &api.CassandraConfig{}
 	}
-	cfg := newConfig(config, cassandraVersion)
+	cfg := newConfig(config, auth, cassandraVersion)
 	return json.Marshal(cfg)
 }
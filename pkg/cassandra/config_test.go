@@ -362,22 +362,231 @@ func TestCreateJsonConfig(t *testing.T) {
               }
             }`,
 		},
-		//{
-		//	name: "auth",
-		//	cassandraVersion: "4.0",
-		//	config: &api.CassandraConfig{
-		//		CassandraYaml: &api.CassandraYaml{
-		//			Authenticator: "FakeAuthenticator",
-		//			Authorizer: "FakeAuthorizer",
-		//		},
-		//	},
-		//	want: `{
-		//      "cassandra-yaml": {
-		//        "authenticator": "FakeAuthenticator",
-		//        "authorizer": "FakeAuthorizer"
-		//      }
-		//    }`,
-		//},
+		{
+			name:             "[3.11.11] allocate_tokens_for_local_replication_factor, stream_entire_sstables",
+			cassandraVersion: "3.11.11",
+			config: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					AllocateTokensForLocalReplicationFactor: intPtr(3),
+					StreamEntireSstables:                    boolPtr(true),
+					ConcurrentReads:                         intPtr(8),
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 256,
+                "concurrent_reads": 8
+              }
+            }`,
+		},
+		{
+			name:             "[4.0.0] timeouts, tombstones, phi_convict_threshold",
+			cassandraVersion: "4.0.0",
+			config: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ReadRequestTimeoutMs:             int64Ptr(10000),
+					WriteRequestTimeoutMs:            int64Ptr(5000),
+					TombstoneWarnThreshold:           intPtr(2000),
+					TombstoneFailureThreshold:        intPtr(200000),
+					PhiConvictThreshold:              float64Ptr(12),
+					HintedHandoffDisabledDatacenters: []string{"dc2"},
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16,
+                "read_request_timeout_in_ms": 10000,
+                "write_request_timeout_in_ms": 5000,
+                "tombstone_warn_threshold": 2000,
+                "tombstone_failure_threshold": 200000,
+                "phi_convict_threshold": 12,
+                "hinted_handoff_disabled_datacenters": ["dc2"]
+              }
+            }`,
+		},
+		{
+			name:             "[4.0.0] commitlog_compression, audit_logging_options",
+			cassandraVersion: "4.0.0",
+			config: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					CommitLogCompression: &api.ParameterizedClass{ClassName: "LZ4Compressor"},
+					AuditLoggingOptions: &api.AuditLogOptions{
+						Enabled:           true,
+						IncludedKeyspaces: stringPtr("ks1"),
+					},
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16,
+                "commitlog_compression": {
+                  "class_name": "LZ4Compressor"
+                },
+                "audit_logging_options": {
+                  "enabled": true,
+                  "included_keyspaces": "ks1"
+                }
+              }
+            }`,
+		},
+		{
+			name:             "[3.11.11] cms tunables, heap dump",
+			cassandraVersion: "3.11.11",
+			config: &api.CassandraConfig{
+				JvmOptions: &api.JvmOptions{
+					HeapSize:          &heapSize,
+					GarbageCollector:  stringPtr(api.GarbageCollectorCMS),
+					Cms:               &api.CmsOptions{SurvivorRatio: intPtr(8), MaxTenuringThreshold: intPtr(1)},
+					HeapDump:          &api.HeapDumpOptions{Path: "/var/lib/cassandra/dumps"},
+					AdditionalOptions: []string{"-Dcassandra.ring_delay_ms=30000"},
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 256
+              },
+              "jvm-options": {
+                "initial_heap_size": 1073741824,
+                "max_heap_size": 1073741824,
+                "garbage_collector": "CMS",
+                "survivor_ratio": 8,
+                "max_tenuring_threshold": 1,
+                "additional-jvm-opts": [
+                  "-XX:+HeapDumpOnOutOfMemoryError",
+                  "-XX:HeapDumpPath=/var/lib/cassandra/dumps",
+                  "-Dcassandra.ring_delay_ms=30000"
+                ]
+              }
+            }`,
+		},
+		{
+			name:             "[4.0.0] g1 tunables, gc logging",
+			cassandraVersion: "4.0.0",
+			config: &api.CassandraConfig{
+				JvmOptions: &api.JvmOptions{
+					HeapSize:         &heapSize,
+					GarbageCollector: stringPtr(api.GarbageCollectorG1),
+					G1:               &api.G1Options{MaxGcPauseMs: intPtr(300), ParallelGcThreads: intPtr(4)},
+					GcLogging:        &api.GcLoggingOptions{Enabled: true, FileCount: intPtr(5)},
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16
+              },
+              "jvm-server-options": {
+                "initial_heap_size": 1073741824,
+                "max_heap_size": 1073741824
+              },
+              "jvm11-server-options": {
+                "garbage_collector": "G1GC",
+                "max_gc_pause_millis": 300,
+                "parallel_gc_threads": 4,
+                "additional-jvm-opts": [
+                  "-Xlog:gc=info,heap*=trace,age*=debug,safepoint=info,promotion*=trace:file=/var/log/cassandra/gc.log:time,uptime,pid,tid,level:filecount=5,filesize=10485760"
+                ]
+              }
+            }`,
+		},
+		{
+			name:             "[4.0.0] java 8, gc logging disabled",
+			cassandraVersion: "4.0.0",
+			config: &api.CassandraConfig{
+				JvmOptions: &api.JvmOptions{
+					JavaVersion:      intPtr(api.Java8),
+					GarbageCollector: stringPtr(api.GarbageCollectorCMS),
+					GcLogging:        &api.GcLoggingOptions{Enabled: false},
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16
+              },
+              "jvm-server-options": {},
+              "jvm8-server-options": {
+                "garbage_collector": "CMS",
+                "additional-jvm-opts": [
+                  "-XX:-PrintGCDetails",
+                  "-XX:-PrintGCDateStamps",
+                  "-XX:-PrintHeapAtGC",
+                  "-XX:-PrintTenuringDistribution",
+                  "-XX:-PrintGCApplicationStoppedTime",
+                  "-XX:-PrintPromotionFailure",
+                  "-XX:-UseGCLogFileRotation"
+                ]
+              }
+            }`,
+		},
+		{
+			name:             "[4.0.0] zgc, jmx port",
+			cassandraVersion: "4.0.0",
+			config: &api.CassandraConfig{
+				JvmOptions: &api.JvmOptions{
+					GarbageCollector: stringPtr(api.GarbageCollectorZGC),
+					Jmx:              &api.JmxOptions{Port: intPtr(7299)},
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16
+              },
+              "jvm-server-options": {},
+              "jvm11-server-options": {
+                "garbage_collector": "ZGC",
+                "additional-jvm-opts": ["-XX:+UnlockExperimentalVMOptions"]
+              },
+              "cassandra-env-sh": {
+                "jmx-port": 7299
+              }
+            }`,
+		},
+		{
+			name:             "[4.1.0] heap size",
+			cassandraVersion: "4.1.0",
+			config: &api.CassandraConfig{
+				JvmOptions: &api.JvmOptions{
+					HeapSize: &heapSize,
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16
+              },
+              "jvm-server-options": {
+                "initial_heap_size": 1073741824,
+                "max_heap_size": 1073741824
+              }
+            }`,
+		},
+		{
+			name:             "[4.1.0] renamed properties",
+			cassandraVersion: "4.1.0",
+			config: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ConcurrentReads:                        intPtr(8),
+					ReadRequestTimeoutMs:                   int64Ptr(10000),
+					KeyCacheSizeMb:                         intPtr(100),
+					HintedHandoffThrottleKb:                intPtr(2048),
+					CompactionThroughputMbPerSec:           intPtr(32),
+					CommitLogSyncBatchWindowMs:             float64Ptr(1.5),
+					EnableMaterializedViews:                boolPtr(true),
+					StreamThroughputOutboundMegabitsPerSec: intPtr(100),
+				},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16,
+                "concurrent_reads": 8,
+                "read_request_timeout": "10000ms",
+                "key_cache_size": "100MiB",
+                "hinted_handoff_throttle": "2048KiB",
+                "compaction_throughput": "32MiB/s",
+                "commitlog_sync_batch_window_in_ms": 1.5,
+                "materialized_views_enabled": true,
+                "stream_throughput_outbound_megabits_per_sec": 100
+              }
+            }`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -395,14 +604,199 @@ func TestCreateJsonConfig(t *testing.T) {
 
 }
 
+func TestCreateJsonConfigDoesNotModifyConfig(t *testing.T) {
+	config := &api.CassandraConfig{
+		CassandraYaml: &api.CassandraYaml{
+			StartRpc: boolPtr(false),
+		},
+	}
+	_, err := CreateJsonConfig(config, "4.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, &api.CassandraYaml{StartRpc: boolPtr(false)}, config.CassandraYaml)
+}
+
+func TestCreateJsonConfigWithAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *api.CassandraConfig
+		auth   *api.Auth
+		want   string
+	}{
+		{
+			name: "no auth",
+			want: ``,
+		},
+		{
+			name: "enabled by default",
+			auth: &api.Auth{},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16,
+                "authenticator": "PasswordAuthenticator",
+                "authorizer": "CassandraAuthorizer"
+              }
+            }`,
+		},
+		{
+			name:   "disabled",
+			config: &api.CassandraConfig{CassandraYaml: &api.CassandraYaml{NumTokens: intPtr(8)}},
+			auth:   &api.Auth{Enabled: boolPtr(false)},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 8,
+                "authenticator": "AllowAllAuthenticator",
+                "authorizer": "AllowAllAuthorizer"
+              }
+            }`,
+		},
+		{
+			name: "caches",
+			auth: &api.Auth{
+				Enabled:                   boolPtr(true),
+				CacheValidityPeriodMillis: int64Ptr(60000),
+				CacheUpdateIntervalMillis: int64Ptr(30000),
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16,
+                "authenticator": "PasswordAuthenticator",
+                "authorizer": "CassandraAuthorizer",
+                "roles_validity_in_ms": 60000,
+                "roles_update_interval_in_ms": 30000,
+                "permissions_validity_in_ms": 60000,
+                "permissions_update_interval_in_ms": 30000,
+                "credentials_validity_in_ms": 60000,
+                "credentials_update_interval_in_ms": 30000
+              }
+            }`,
+		},
+		{
+			name: "ldap",
+			auth: &api.Auth{
+				Provider: api.AuthProviderLDAP,
+				LDAP:     &api.LDAPConfig{ServerURL: "ldap://ldap:389", SearchBase: "ou=people,dc=example,dc=com"},
+			},
+			want: `{
+              "cassandra-yaml": {
+                "num_tokens": 16,
+                "authenticator": "com.instaclustr.cassandra.ldap.LDAPAuthenticator",
+                "authorizer": "CassandraAuthorizer",
+                "role_manager": "com.instaclustr.cassandra.ldap.LDAPCassandraRoleManager"
+              }
+            }`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := createJsonConfig(tc.config, tc.auth, "4.0.1")
+			require.NoError(t, err, "failed to create json config")
+			if tc.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.JSONEq(t, tc.want, string(got))
+
+			enabled, err := IsAuthEnabled(got)
+			require.NoError(t, err)
+			assert.Equal(t, tc.auth.IsEnabled(), enabled)
+		})
+	}
+}
+
+func TestIsAuthEnabled(t *testing.T) {
+	for config, expected := range map[string]bool{
+		``:                                       true,
+		`{"cassandra-yaml": {"num_tokens": 16}}`: true,
+		`{"cassandra-yaml": {"authenticator": "PasswordAuthenticator"}}`: true,
+		`{"cassandra-yaml": {"authenticator": "AllowAllAuthenticator"}}`: false,
+	} {
+		enabled, err := IsAuthEnabled([]byte(config))
+		require.NoError(t, err)
+		assert.Equal(t, expected, enabled, config)
+	}
+}
+
+func TestHoldBackAuth(t *testing.T) {
+	const disabled = `{"cassandra-yaml":{"authenticator":"AllowAllAuthenticator","authorizer":"AllowAllAuthorizer","concurrent_reads":32}}`
+	tests := []struct {
+		name     string
+		actual   string
+		desired  string
+		expected string
+	}{
+		{
+			name:     "only auth changes",
+			actual:   disabled,
+			desired:  `{"cassandra-yaml":{"concurrent_reads":32,"authenticator":"PasswordAuthenticator","authorizer":"CassandraAuthorizer"}}`,
+			expected: disabled,
+		},
+		{
+			name:     "other changes",
+			actual:   disabled,
+			desired:  `{"cassandra-yaml":{"authenticator":"PasswordAuthenticator","authorizer":"CassandraAuthorizer","concurrent_reads":64},"jvm-server-options":{"max_heap_size":"1G"}}`,
+			expected: `{"cassandra-yaml":{"authenticator":"AllowAllAuthenticator","authorizer":"AllowAllAuthorizer","concurrent_reads":64},"jvm-server-options":{"max_heap_size":"1G"}}`,
+		},
+		{
+			name:     "role manager",
+			actual:   disabled,
+			desired:  `{"cassandra-yaml":{"authenticator":"com.instaclustr.cassandra.ldap.LDAPAuthenticator","authorizer":"CassandraAuthorizer","role_manager":"com.instaclustr.cassandra.ldap.LDAPCassandraRoleManager","concurrent_reads":32}}`,
+			expected: disabled,
+		},
+		{
+			name:     "no cassandra.yaml",
+			actual:   `{"cassandra-yaml":{"authenticator":"AllowAllAuthenticator"}}`,
+			desired:  `{"jvm-server-options":{"max_heap_size":"1G"}}`,
+			expected: `{"cassandra-yaml":{"authenticator":"AllowAllAuthenticator"},"jvm-server-options":{"max_heap_size":"1G"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := HoldBackAuth([]byte(tt.actual), []byte(tt.desired))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(config))
+		})
+	}
+}
+
+func TestUnsupportedProperties(t *testing.T) {
+	config := &api.CassandraConfig{
+		CassandraYaml: &api.CassandraYaml{
+			NumTokens:                               intPtr(16),
+			AllocateTokensForLocalReplicationFactor: intPtr(3),
+			StartRpc:                                boolPtr(false),
+			RpcKeepalive:                            boolPtr(true),
+			AuditLoggingOptions:                     &api.AuditLogOptions{Enabled: true},
+		},
+	}
+
+	assert.Equal(t, []string{"allocate_tokens_for_local_replication_factor", "audit_logging_options"}, UnsupportedProperties(config, "3.11.11"))
+	assert.Equal(t, []string{"start_rpc", "rpc_keepalive"}, UnsupportedProperties(config, "4.0.1"))
+	assert.Equal(t, []string{"start_rpc", "rpc_keepalive"}, UnsupportedProperties(config, "4.1.0"))
+	assert.Empty(t, UnsupportedProperties(&api.CassandraConfig{}, "4.0.1"))
+	assert.Empty(t, UnsupportedProperties(nil, "4.0.1"))
+}
+
 func intPtr(n int) *int {
 	return &n
 }
 
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
 
+func stringPtr(s string) *string {
+	return &s
+}
+
 func parseResource(quantity string) *resource.Quantity {
 	parsed := resource.MustParse(quantity)
 	return &parsed
@@ -0,0 +1,174 @@
+package cassandra
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/reconciliation"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	configFilesInitContainerName = "config-files-init"
+	configFilesMountPath         = "/config-files"
+
+	// serverConfigVolumeName is the volume in which the server-config-init container of
+	// cass-operator generates the configuration of Cassandra. The Cassandra container
+	// copies its content to the configuration directory of Cassandra when it starts.
+	serverConfigVolumeName = "server-config"
+	serverConfigMountPath  = "/config"
+)
+
+// defaultInitImage is the image of the init containers that the operator adds to the
+// Cassandra pods, such as the config-files-init and ldap-config containers.
+var defaultInitImage = images.Image{
+	Registry:   images.DefaultRegistry,
+	Repository: "library",
+	Name:       "busybox",
+	Tag:        "1.34.1",
+	PullPolicy: corev1.PullIfNotPresent,
+}
+
+// configFile is a file of ConfigFiles, along with the way it is rendered.
+type configFile struct {
+	// name is the name of the file in the configuration directory of Cassandra.
+	name string
+
+	// volume is the name of the volume in which the file is mounted.
+	volume string
+
+	// append is true if the file is appended to the generated one instead of replacing it.
+	append bool
+
+	source *api.ConfigFileSource
+}
+
+func (f configFile) key() string {
+	if f.source.Key != "" {
+		return f.source.Key
+	}
+	return f.name
+}
+
+func configFileList(files *api.ConfigFiles) []configFile {
+	if files == nil {
+		return nil
+	}
+	var list []configFile
+	for _, file := range []configFile{
+		{name: "logback.xml", volume: "logback-config", source: files.Logback},
+		{name: "cassandra-env.sh", volume: "cassandra-env-config", append: true, source: files.CassandraEnv},
+		{name: "cassandra-rackdc.properties", volume: "rackdc-config", append: true, source: files.RackDc},
+		{name: "commitlog_archiving.properties", volume: "commitlog-archiving-config", source: files.CommitLogArchiving},
+	} {
+		if file.source != nil {
+			list = append(list, file)
+		}
+	}
+	return list
+}
+
+// ConfigMapNames returns the names of the ConfigMaps referenced by files.
+func ConfigMapNames(files *api.ConfigFiles) []string {
+	var names []string
+	for _, file := range configFileList(files) {
+		if !utils.SliceContains(names, file.source.ConfigMapRef.Name) {
+			names = append(names, file.source.ConfigMapRef.Name)
+		}
+	}
+	return names
+}
+
+// AddConfigFiles adds files to the Cassandra pods of dc. configMaps maps the names of the
+// ConfigMaps referenced by files to their content. An error is returned if one of them,
+// or one of the referenced keys, is missing.
+//
+// The files are mounted in an init container that runs after the server-config-init
+// container of cass-operator, and that copies them to, or appends them to, the files that
+// it generated. The pod template is annotated with a hash of the files so that changing
+// them triggers a rolling restart.
+func AddConfigFiles(dc *cassdcapi.CassandraDatacenter, files *api.ConfigFiles, configMaps map[string]*corev1.ConfigMap) error {
+	list := configFileList(files)
+	if len(list) == 0 {
+		return nil
+	}
+
+	contents := make(map[string]string, len(list))
+	volumes := make([]corev1.Volume, 0, len(list))
+	volumeMounts := []corev1.VolumeMount{{Name: serverConfigVolumeName, MountPath: serverConfigMountPath}}
+	commands := make([]string, 0, len(list))
+	for _, file := range list {
+		configMap, found := configMaps[file.source.ConfigMapRef.Name]
+		if !found {
+			return fmt.Errorf("ConfigMap %s of %s not found", file.source.ConfigMapRef.Name, file.name)
+		}
+		content, found := configMap.Data[file.key()]
+		if !found {
+			return fmt.Errorf("key %s of %s not found in ConfigMap %s", file.key(), file.name, configMap.Name)
+		}
+		contents[file.name] = content
+
+		volumes = append(volumes, corev1.Volume{
+			Name: file.volume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: file.source.ConfigMapRef,
+					Items:                []corev1.KeyToPath{{Key: file.key(), Path: file.name}},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      file.volume,
+			MountPath: path.Join(configFilesMountPath, file.volume),
+		})
+
+		src := path.Join(configFilesMountPath, file.volume, file.name)
+		dest := path.Join(serverConfigMountPath, file.name)
+		if file.append {
+			commands = append(commands, fmt.Sprintf("echo >> %s && cat %s >> %s", dest, src, dest))
+		} else {
+			commands = append(commands, fmt.Sprintf("cp %s %s", src, dest))
+		}
+	}
+
+	if dc.Spec.PodTemplateSpec == nil {
+		dc.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{}
+	}
+	template := dc.Spec.PodTemplateSpec
+
+	// cass-operator adds its server-config-init container after the init containers of the
+	// template, unless the template declares it. It is declared first so that the files
+	// are copied once the configuration has been generated.
+	if !hasInitContainer(template, reconciliation.ServerConfigContainerName) {
+		template.Spec.InitContainers = append([]corev1.Container{{Name: reconciliation.ServerConfigContainerName}}, template.Spec.InitContainers...)
+	}
+	template.Spec.InitContainers = append(template.Spec.InitContainers, corev1.Container{
+		Name:            configFilesInitContainerName,
+		Image:           defaultInitImage.String(),
+		ImagePullPolicy: defaultInitImage.PullPolicy,
+		Command:         []string{"/bin/sh", "-c", strings.Join(commands, " && ")},
+		VolumeMounts:    volumeMounts,
+	})
+	template.Spec.Volumes = append(template.Spec.Volumes, volumes...)
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[api.ConfigFilesHashAnnotation] = utils.DeepHashString(contents)
+
+	return nil
+}
+
+func hasInitContainer(template *corev1.PodTemplateSpec, name string) bool {
+	for _, container := range template.Spec.InitContainers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
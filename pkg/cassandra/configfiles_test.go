@@ -0,0 +1,145 @@
+package cassandra
+
+import (
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigMapNames(t *testing.T) {
+	assert.Empty(t, ConfigMapNames(nil))
+	assert.Equal(t, []string{"config", "rackdc"}, ConfigMapNames(&api.ConfigFiles{
+		Logback:      &api.ConfigFileSource{ConfigMapRef: corev1.LocalObjectReference{Name: "config"}},
+		CassandraEnv: &api.ConfigFileSource{ConfigMapRef: corev1.LocalObjectReference{Name: "config"}},
+		RackDc:       &api.ConfigFileSource{ConfigMapRef: corev1.LocalObjectReference{Name: "rackdc"}},
+	}))
+}
+
+func TestAddConfigFiles(t *testing.T) {
+	files := &api.ConfigFiles{
+		Logback: &api.ConfigFileSource{
+			ConfigMapRef: corev1.LocalObjectReference{Name: "config"},
+		},
+		CassandraEnv: &api.ConfigFileSource{
+			ConfigMapRef: corev1.LocalObjectReference{Name: "config"},
+			Key:          "env",
+		},
+	}
+	newConfigMaps := func(logback string) map[string]*corev1.ConfigMap {
+		return map[string]*corev1.ConfigMap{
+			"config": {
+				ObjectMeta: metav1.ObjectMeta{Name: "config"},
+				Data: map[string]string{
+					"logback.xml": logback,
+					"env":         "JVM_OPTS=\"$JVM_OPTS -Dfoo=bar\"",
+				},
+			},
+		}
+	}
+
+	t.Run("no files", func(t *testing.T) {
+		dc := &cassdcapi.CassandraDatacenter{}
+		require.NoError(t, AddConfigFiles(dc, nil, nil))
+		assert.Nil(t, dc.Spec.PodTemplateSpec)
+	})
+
+	t.Run("files are copied after the server config is generated", func(t *testing.T) {
+		dc := &cassdcapi.CassandraDatacenter{
+			Spec: cassdcapi.CassandraDatacenterSpec{
+				PodTemplateSpec: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "user-init"}},
+					},
+				},
+			},
+		}
+		require.NoError(t, AddConfigFiles(dc, files, newConfigMaps("<configuration/>")))
+
+		initContainers := dc.Spec.PodTemplateSpec.Spec.InitContainers
+		require.Len(t, initContainers, 3)
+		assert.Equal(t, "server-config-init", initContainers[0].Name)
+		assert.Equal(t, "user-init", initContainers[1].Name)
+		assert.Equal(t, configFilesInitContainerName, initContainers[2].Name)
+		assert.Equal(t, []string{
+			"/bin/sh",
+			"-c",
+			"cp /config-files/logback-config/logback.xml /config/logback.xml && " +
+				"echo >> /config/cassandra-env.sh && cat /config-files/cassandra-env-config/cassandra-env.sh >> /config/cassandra-env.sh",
+		}, initContainers[2].Command)
+		assert.Equal(t, []corev1.VolumeMount{
+			{Name: "server-config", MountPath: "/config"},
+			{Name: "logback-config", MountPath: "/config-files/logback-config"},
+			{Name: "cassandra-env-config", MountPath: "/config-files/cassandra-env-config"},
+		}, initContainers[2].VolumeMounts)
+
+		assert.Equal(t, []corev1.Volume{
+			{
+				Name: "logback-config",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "config"},
+						Items:                []corev1.KeyToPath{{Key: "logback.xml", Path: "logback.xml"}},
+					},
+				},
+			},
+			{
+				Name: "cassandra-env-config",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "config"},
+						Items:                []corev1.KeyToPath{{Key: "env", Path: "cassandra-env.sh"}},
+					},
+				},
+			},
+		}, dc.Spec.PodTemplateSpec.Spec.Volumes)
+	})
+
+	t.Run("the server-config-init container of the template is kept", func(t *testing.T) {
+		dc := &cassdcapi.CassandraDatacenter{
+			Spec: cassdcapi.CassandraDatacenterSpec{
+				PodTemplateSpec: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "server-config-init", Image: "config-builder"}},
+					},
+				},
+			},
+		}
+		require.NoError(t, AddConfigFiles(dc, files, newConfigMaps("<configuration/>")))
+
+		initContainers := dc.Spec.PodTemplateSpec.Spec.InitContainers
+		require.Len(t, initContainers, 2)
+		assert.Equal(t, corev1.Container{Name: "server-config-init", Image: "config-builder"}, initContainers[0])
+		assert.Equal(t, configFilesInitContainerName, initContainers[1].Name)
+	})
+
+	t.Run("the hash annotation changes with the content", func(t *testing.T) {
+		dc1 := &cassdcapi.CassandraDatacenter{}
+		require.NoError(t, AddConfigFiles(dc1, files, newConfigMaps("<configuration/>")))
+		dc2 := &cassdcapi.CassandraDatacenter{}
+		require.NoError(t, AddConfigFiles(dc2, files, newConfigMaps("<configuration/>")))
+		dc3 := &cassdcapi.CassandraDatacenter{}
+		require.NoError(t, AddConfigFiles(dc3, files, newConfigMaps("<configuration debug=\"true\"/>")))
+
+		hash := dc1.Spec.PodTemplateSpec.Annotations[api.ConfigFilesHashAnnotation]
+		assert.NotEmpty(t, hash)
+		assert.Equal(t, hash, dc2.Spec.PodTemplateSpec.Annotations[api.ConfigFilesHashAnnotation])
+		assert.NotEqual(t, hash, dc3.Spec.PodTemplateSpec.Annotations[api.ConfigFilesHashAnnotation])
+	})
+
+	t.Run("missing ConfigMap", func(t *testing.T) {
+		dc := &cassdcapi.CassandraDatacenter{}
+		assert.Error(t, AddConfigFiles(dc, files, map[string]*corev1.ConfigMap{}))
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		configMaps := newConfigMaps("<configuration/>")
+		delete(configMaps["config"].Data, "env")
+		dc := &cassdcapi.CassandraDatacenter{}
+		assert.Error(t, AddConfigFiles(dc, files, configMaps))
+	})
+}
@@ -0,0 +1,364 @@
+package cassandra
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gocql/gocql"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	cqlPort    = 9042
+	cqlTimeout = 10 * time.Second
+)
+
+// CqlClientFactory creates request-scoped instances of CqlClient. This component exists
+// mostly to allow tests to provide mocks for the CQL client.
+type CqlClientFactory interface {
+
+	// NewCqlClient returns a new CqlClient that will connect to the ready nodes of the given
+	// datacenter as username. The k8sClient is used to fetch pods in that datacenter.
+	// tlsConfig is nil when client encryption is disabled, the names of its certificates
+	// are verified against the service of the datacenter.
+	NewCqlClient(
+		ctx context.Context,
+		dc *cassdcapi.CassandraDatacenter,
+		k8sClient client.Client,
+		username, password string,
+		tlsConfig *tls.Config,
+		logger logr.Logger,
+	) (CqlClient, error)
+}
+
+func NewCqlClientFactory() CqlClientFactory {
+	return &defaultCqlClientFactory{}
+}
+
+type defaultCqlClientFactory struct {
+}
+
+func (f defaultCqlClientFactory) NewCqlClient(
+	ctx context.Context,
+	dc *cassdcapi.CassandraDatacenter,
+	k8sClient client.Client,
+	username, password string,
+	tlsConfig *tls.Config,
+	logger logr.Logger,
+) (CqlClient, error) {
+	pods := &corev1.PodList{}
+	if err := k8sClient.List(ctx, pods, client.InNamespace(dc.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: dc.Name}); err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Status.PodIP != "" && isCassandraContainerReady(pod) {
+			hosts = append(hosts, pod.Status.PodIP)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no pods in READY state found in datacenter %v", dc.Name)
+	}
+
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Port = cqlPort
+	cluster.Timeout = cqlTimeout
+	cluster.ConnectTimeout = cqlTimeout
+	cluster.Consistency = gocql.LocalQuorum
+	cluster.DisableInitialHostLookup = true
+	cluster.PoolConfig.HostSelectionPolicy = gocql.DCAwareRoundRobinPolicy(dc.Name)
+	cluster.Authenticator = gocql.PasswordAuthenticator{Username: username, Password: password}
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = dc.GetDatacenterServiceName()
+		}
+		cluster.SslOpts = &gocql.SslOptions{Config: tlsConfig, EnableHostVerification: true}
+	}
+
+	logger.Info("Connecting to CQL", "CassandraDatacenter", dc.Name, "hosts", hosts)
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the nodes of datacenter %s: %v", dc.Name, err)
+	}
+	return &defaultCqlClient{session: session, logger: logger}, nil
+}
+
+// CqlClient manages the roles and the keyspaces of a cluster through CQL.
+type CqlClient interface {
+
+	// CreateRoleIfNotExists creates role, which can log in if login is true. Calling this
+	// method on an existing role is a no-op.
+	CreateRoleIfNotExists(role string, login bool) error
+
+	// ListRoleMembers returns the sorted names of the roles that role is granted to.
+	ListRoleMembers(role string) ([]string, error)
+
+	// GrantRole grants role to grantee.
+	GrantRole(role, grantee string) error
+
+	// RevokeRole revokes role from grantee.
+	RevokeRole(role, grantee string) error
+
+	// GetKeyspaceDurableWrites returns the durable_writes option of keyspace.
+	GetKeyspaceDurableWrites(keyspace string) (bool, error)
+
+	// SetKeyspaceDurableWrites sets the durable_writes option of keyspace.
+	SetKeyspaceDurableWrites(keyspace string, durableWrites bool) error
+
+	// DropKeyspace drops keyspace and all its data. Calling this method on a keyspace that
+	// does not exist is a no-op.
+	DropKeyspace(keyspace string) error
+
+	// GetRole returns role, or nil if it does not exist.
+	GetRole(role string) (*Role, error)
+
+	// CreateRole creates role with options. The password of options is only set if it is not
+	// empty.
+	CreateRole(role string, options RoleOptions) error
+
+	// AlterRole changes the options of role. The password of options is only set if it is
+	// not empty.
+	AlterRole(role string, options RoleOptions) error
+
+	// DropRole drops role. Calling this method on a role that does not exist is a no-op.
+	DropRole(role string) error
+
+	// ListPermissions returns the sorted permissions of role on each of its data resources,
+	// e.g., data/ks for a keyspace. The resources are named like in DataResource.
+	ListPermissions(role string) (map[string][]string, error)
+
+	// GrantPermission grants permission on the data resource to role.
+	GrantPermission(permission, resource, role string) error
+
+	// RevokePermission revokes permission on the data resource from role.
+	RevokePermission(permission, resource, role string) error
+
+	// ExecuteSchemaChange runs the CQL schema change statement, e.g., ALTER TABLE.
+	ExecuteSchemaChange(statement string) error
+
+	// ListSchemaMigrations returns the sorted versions of the migrations recorded in the
+	// MigrationsTable of keyspace.
+	ListSchemaMigrations(keyspace string) ([]int, error)
+
+	// RecordSchemaMigration records in the MigrationsTable of keyspace that the migration
+	// version was applied.
+	RecordSchemaMigration(keyspace string, version int, description string) error
+
+	// Close closes the connections to the nodes.
+	Close()
+}
+
+// Role is a role as stored in system_auth.roles.
+type Role struct {
+	Name      string
+	Login     bool
+	Superuser bool
+
+	// MemberOf are the sorted names of the roles that are granted to the role.
+	MemberOf []string
+}
+
+// RoleOptions are the options of a role that are set when it is created or altered.
+type RoleOptions struct {
+	Password  string
+	Login     bool
+	Superuser bool
+}
+
+// DataResource returns the name of the data resource of the table of keyspace, of keyspace
+// if table is empty, or of all the keyspaces if keyspace is empty.
+func DataResource(keyspace, table string) string {
+	if keyspace == "" {
+		return "data"
+	}
+	if table == "" {
+		return "data/" + keyspace
+	}
+	return "data/" + keyspace + "/" + table
+}
+
+type defaultCqlClient struct {
+	session *gocql.Session
+	logger  logr.Logger
+}
+
+func (c *defaultCqlClient) CreateRoleIfNotExists(role string, login bool) error {
+	c.logger.Info("Creating role", "role", role)
+	return c.session.Query(fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s WITH LOGIN = %t", quoteIdentifier(role), login)).Exec()
+}
+
+func (c *defaultCqlClient) ListRoleMembers(role string) ([]string, error) {
+	iter := c.session.Query("SELECT member FROM system_auth.role_members WHERE role = ?", role).Iter()
+	members := make([]string, 0)
+	var member string
+	for iter.Scan(&member) {
+		members = append(members, member)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+func (c *defaultCqlClient) GrantRole(role, grantee string) error {
+	c.logger.Info("Granting role", "role", role, "grantee", grantee)
+	return c.session.Query(fmt.Sprintf("GRANT %s TO %s", quoteIdentifier(role), quoteIdentifier(grantee))).Exec()
+}
+
+func (c *defaultCqlClient) RevokeRole(role, grantee string) error {
+	c.logger.Info("Revoking role", "role", role, "grantee", grantee)
+	return c.session.Query(fmt.Sprintf("REVOKE %s FROM %s", quoteIdentifier(role), quoteIdentifier(grantee))).Exec()
+}
+
+func (c *defaultCqlClient) GetKeyspaceDurableWrites(keyspace string) (bool, error) {
+	var durableWrites bool
+	err := c.session.Query("SELECT durable_writes FROM system_schema.keyspaces WHERE keyspace_name = ?", keyspace).Scan(&durableWrites)
+	return durableWrites, err
+}
+
+func (c *defaultCqlClient) SetKeyspaceDurableWrites(keyspace string, durableWrites bool) error {
+	c.logger.Info("Altering keyspace", "keyspace", keyspace, "durable_writes", durableWrites)
+	return c.session.Query(fmt.Sprintf("ALTER KEYSPACE %s WITH durable_writes = %t", quoteIdentifier(keyspace), durableWrites)).Exec()
+}
+
+func (c *defaultCqlClient) DropKeyspace(keyspace string) error {
+	c.logger.Info("Dropping keyspace", "keyspace", keyspace)
+	return c.session.Query(fmt.Sprintf("DROP KEYSPACE IF EXISTS %s", quoteIdentifier(keyspace))).Exec()
+}
+
+func (c *defaultCqlClient) GetRole(role string) (*Role, error) {
+	r := &Role{Name: role}
+	var memberOf []string
+	err := c.session.Query("SELECT can_login, is_superuser, member_of FROM system_auth.roles WHERE role = ?", role).
+		Scan(&r.Login, &r.Superuser, &memberOf)
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sort.Strings(memberOf)
+	r.MemberOf = memberOf
+	return r, nil
+}
+
+func (c *defaultCqlClient) CreateRole(role string, options RoleOptions) error {
+	c.logger.Info("Creating role", "role", role, "login", options.Login, "superuser", options.Superuser)
+	return c.session.Query(fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s WITH %s", quoteIdentifier(role), roleOptionsCql(options))).Exec()
+}
+
+func (c *defaultCqlClient) AlterRole(role string, options RoleOptions) error {
+	c.logger.Info("Altering role", "role", role, "login", options.Login, "superuser", options.Superuser)
+	return c.session.Query(fmt.Sprintf("ALTER ROLE %s WITH %s", quoteIdentifier(role), roleOptionsCql(options))).Exec()
+}
+
+func (c *defaultCqlClient) DropRole(role string) error {
+	c.logger.Info("Dropping role", "role", role)
+	return c.session.Query(fmt.Sprintf("DROP ROLE IF EXISTS %s", quoteIdentifier(role))).Exec()
+}
+
+func (c *defaultCqlClient) ListPermissions(role string) (map[string][]string, error) {
+	iter := c.session.Query("SELECT resource, permissions FROM system_auth.role_permissions WHERE role = ?", role).Iter()
+	permissions := make(map[string][]string)
+	var resource string
+	var resourcePermissions []string
+	for iter.Scan(&resource, &resourcePermissions) {
+		if resource != "data" && !strings.HasPrefix(resource, "data/") {
+			continue
+		}
+		sorted := append([]string{}, resourcePermissions...)
+		sort.Strings(sorted)
+		permissions[resource] = sorted
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (c *defaultCqlClient) GrantPermission(permission, resource, role string) error {
+	c.logger.Info("Granting permission", "permission", permission, "resource", resource, "role", role)
+	return c.session.Query(fmt.Sprintf("GRANT %s ON %s TO %s", permission, resourceCql(resource), quoteIdentifier(role))).Exec()
+}
+
+func (c *defaultCqlClient) RevokePermission(permission, resource, role string) error {
+	c.logger.Info("Revoking permission", "permission", permission, "resource", resource, "role", role)
+	return c.session.Query(fmt.Sprintf("REVOKE %s ON %s FROM %s", permission, resourceCql(resource), quoteIdentifier(role))).Exec()
+}
+
+func (c *defaultCqlClient) ExecuteSchemaChange(statement string) error {
+	c.logger.Info("Executing schema change", "statement", statement)
+	return c.session.Query(statement).Exec()
+}
+
+func (c *defaultCqlClient) ListSchemaMigrations(keyspace string) ([]int, error) {
+	iter := c.session.Query(fmt.Sprintf("SELECT version FROM %s.%s", quoteIdentifier(keyspace), MigrationsTable)).Iter()
+	versions := make([]int, 0)
+	var version int
+	for iter.Scan(&version) {
+		versions = append(versions, version)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+func (c *defaultCqlClient) RecordSchemaMigration(keyspace string, version int, description string) error {
+	c.logger.Info("Recording schema migration", "keyspace", keyspace, "version", version)
+	return c.session.Query(fmt.Sprintf("INSERT INTO %s.%s (version, description, applied_at) VALUES (?, ?, toTimestamp(now()))", quoteIdentifier(keyspace), MigrationsTable),
+		version, description).Exec()
+}
+
+func (c *defaultCqlClient) Close() {
+	c.session.Close()
+}
+
+// quoteIdentifier quotes name so that it is used verbatim, e.g., with its case preserved.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteString quotes s as a CQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func roleOptionsCql(options RoleOptions) string {
+	cql := fmt.Sprintf("LOGIN = %t AND SUPERUSER = %t", options.Login, options.Superuser)
+	if options.Password != "" {
+		cql += " AND PASSWORD = " + quoteString(options.Password)
+	}
+	return cql
+}
+
+// resourceCql returns the CQL name of a data resource, e.g., KEYSPACE "ks" for data/ks.
+func resourceCql(resource string) string {
+	parts := strings.SplitN(resource, "/", 3)
+	switch len(parts) {
+	case 1:
+		return "ALL KEYSPACES"
+	case 2:
+		return "KEYSPACE " + quoteIdentifier(parts[1])
+	default:
+		return "TABLE " + quoteIdentifier(parts[1]) + "." + quoteIdentifier(parts[2])
+	}
+}
+
+func isCassandraContainerReady(pod corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "cassandra" {
+			return status.Ready
+		}
+	}
+	return false
+}
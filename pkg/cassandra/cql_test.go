@@ -0,0 +1,26 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataResource(t *testing.T) {
+	assert.Equal(t, "data", DataResource("", ""))
+	assert.Equal(t, "data/ks", DataResource("ks", ""))
+	assert.Equal(t, "data/ks/tbl", DataResource("ks", "tbl"))
+}
+
+func TestResourceCql(t *testing.T) {
+	assert.Equal(t, "ALL KEYSPACES", resourceCql("data"))
+	assert.Equal(t, `KEYSPACE "ks"`, resourceCql("data/ks"))
+	assert.Equal(t, `TABLE "ks"."tbl"`, resourceCql("data/ks/tbl"))
+}
+
+func TestRoleOptionsCql(t *testing.T) {
+	assert.Equal(t, "LOGIN = true AND SUPERUSER = false",
+		roleOptionsCql(RoleOptions{Login: true}))
+	assert.Equal(t, "LOGIN = true AND SUPERUSER = true AND PASSWORD = 'it''s secret'",
+		roleOptionsCql(RoleOptions{Login: true, Superuser: true, Password: "it's secret"}))
+}
@@ -5,10 +5,12 @@ import (
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/pkg/reconciliation"
 	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // SystemReplication represents the replication factor of the system_auth, system_traces,
@@ -32,19 +34,25 @@ type DatacenterConfig struct {
 	Meta                api.EmbeddedObjectMeta
 	Cluster             string
 	SuperUserSecretName string
+	Auth                *api.Auth
 	ServerImage         string
 	ServerVersion       string
 	Size                int32
 	Resources           *corev1.ResourceRequirements
+	ResourceTuning      bool
 	SystemReplication   SystemReplication
 	StorageConfig       *cassdcapi.StorageConfig
 	Racks               []cassdcapi.Rack
+	Tolerations         []corev1.Toleration
+	NodeAffinityLabels  map[string]string
 	CassandraConfig     *api.CassandraConfig
+	ConfigFiles         *api.ConfigFiles
 	AdditionalSeeds     []string
 	Networking          *cassdcapi.NetworkingConfig
 	Users               []cassdcapi.CassandraUser
 	PodTemplateSpec     *corev1.PodTemplateSpec
 	MgmtAPIHeap         *resource.Quantity
+	Stopped             bool
 }
 
 const (
@@ -57,15 +65,29 @@ func NewDatacenter(klusterKey types.NamespacedName, template *DatacenterConfig)
 		namespace = klusterKey.Namespace
 	}
 
-	rawConfig, err := CreateJsonConfig(template.CassandraConfig, template.ServerVersion)
-	if err != nil {
-		return nil, err
-	}
-
 	if template.StorageConfig == nil {
 		return nil, DCConfigIncomplete{"template.StorageConfig"}
 	}
 
+	if template.ServerVersion != "" {
+		if err := version.Validate(template.ServerVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	if template.CassandraConfig != nil {
+		jvmOptions := template.CassandraConfig.JvmOptions
+		mountPaths := api.VolumeMountPaths(template.StorageConfig)
+		if errs := jvmOptions.Validate(template.ServerVersion, mountPaths, field.NewPath("config", "jvmOptions")); len(errs) > 0 {
+			return nil, errs.ToAggregate()
+		}
+	}
+
+	rawConfig, err := createJsonConfig(template.CassandraConfig, template.Auth, template.ServerVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	dc := &cassdcapi.CassandraDatacenter{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   namespace,
@@ -87,6 +109,8 @@ func NewDatacenter(klusterKey types.NamespacedName, template *DatacenterConfig)
 			ServerType:          "cassandra",
 			Config:              rawConfig,
 			Racks:               template.Racks,
+			Tolerations:         template.Tolerations,
+			NodeAffinityLabels:  template.NodeAffinityLabels,
 			StorageConfig:       *template.StorageConfig,
 			ClusterName:         template.Cluster,
 			SuperuserSecretName: template.SuperUserSecretName,
@@ -94,6 +118,7 @@ func NewDatacenter(klusterKey types.NamespacedName, template *DatacenterConfig)
 			Networking:          template.Networking,
 			AdditionalSeeds:     template.AdditionalSeeds,
 			PodTemplateSpec:     template.PodTemplateSpec,
+			Stopped:             template.Stopped,
 		},
 	}
 
@@ -108,6 +133,13 @@ func NewDatacenter(klusterKey types.NamespacedName, template *DatacenterConfig)
 	return dc, nil
 }
 
+// RenderConfig renders the CassandraDatacenter config of template for cassandraVersion
+// rather than for template.ServerVersion. It is used to keep the version-dependent
+// rendering of the config of a datacenter whose version change is held back.
+func RenderConfig(template *DatacenterConfig, cassandraVersion string) ([]byte, error) {
+	return createJsonConfig(template.CassandraConfig, template.Auth, cassandraVersion)
+}
+
 // setMgmtAPIHeap sets the management API heap size on a CassandraDatacenter
 func setMgmtAPIHeap(dc *cassdcapi.CassandraDatacenter, heapSize *resource.Quantity) {
 	if dc.Spec.PodTemplateSpec == nil {
@@ -147,13 +179,22 @@ func UpdateCassandraContainer(p *corev1.PodTemplateSpec, f func(c *corev1.Contai
 }
 
 // Coalesce combines the cluster and dc templates with override semantics. If a property is
-// defined in both templates, the dc-level property takes precedence.
+// defined in both templates, the dc-level property takes precedence. The CassandraConfig,
+// ConfigFiles, Resources, Networking and StorageConfig properties are merged field by
+// field, after removing the cluster-level settings listed in dcTemplate.Unset. The node
+// affinity labels of the racks are rendered by cass-operator on top of those of the dc.
+// The templates should not be shared with other objects since they may be modified.
 func Coalesce(clusterTemplate *api.CassandraClusterTemplate, dcTemplate *api.CassandraDatacenterTemplate) *DatacenterConfig {
 	dcConfig := &DatacenterConfig{}
 
+	if len(dcTemplate.Unset) > 0 {
+		unsetClusterSettings(clusterTemplate, dcTemplate.Unset)
+	}
+
 	// Handler cluster-wide settings first
 	dcConfig.Cluster = clusterTemplate.Cluster
 	dcConfig.SuperUserSecretName = clusterTemplate.SuperuserSecretName
+	dcConfig.Auth = clusterTemplate.Auth
 
 	// DC-level settings
 	dcConfig.Meta = dcTemplate.Meta
@@ -177,31 +218,22 @@ func Coalesce(clusterTemplate *api.CassandraClusterTemplate, dcTemplate *api.Cas
 		dcConfig.Racks = dcTemplate.Racks
 	}
 
-	if dcTemplate.Resources == nil {
-		dcConfig.Resources = clusterTemplate.Resources
-	} else {
-		dcConfig.Resources = dcTemplate.Resources
+	dcConfig.Tolerations = dcTemplate.Tolerations
+	if dcConfig.Tolerations == nil {
+		dcConfig.Tolerations = clusterTemplate.Tolerations
 	}
+	dcConfig.NodeAffinityLabels = mergeLabels(clusterTemplate.NodeAffinityLabels, dcTemplate.NodeAffinityLabels)
 
-	// TODO Add validation check to ensure StorageConfig is set at the cluster or DC level
-	if dcTemplate.StorageConfig == nil {
-		dcConfig.StorageConfig = clusterTemplate.StorageConfig
-	} else {
-		dcConfig.StorageConfig = dcTemplate.StorageConfig
-	}
-
-	if dcTemplate.Networking == nil {
-		dcConfig.Networking = clusterTemplate.Networking
-	} else {
-		dcConfig.Networking = dcTemplate.Networking
-	}
-
-	// TODO Do we want merge vs override?
-	if dcTemplate.CassandraConfig == nil {
-		dcConfig.CassandraConfig = clusterTemplate.CassandraConfig
-	} else {
-		dcConfig.CassandraConfig = dcTemplate.CassandraConfig
+	dcConfig.Resources = mergeResources(clusterTemplate.Resources, dcTemplate.Resources)
+	if dcTemplate.ResourceTuning != nil {
+		dcConfig.ResourceTuning = *dcTemplate.ResourceTuning
+	} else if clusterTemplate.ResourceTuning != nil {
+		dcConfig.ResourceTuning = *clusterTemplate.ResourceTuning
 	}
+	dcConfig.StorageConfig = mergeStorageConfig(clusterTemplate.StorageConfig, dcTemplate.StorageConfig)
+	dcConfig.Networking = mergeNetworking(clusterTemplate.Networking, dcTemplate.Networking)
+	dcConfig.CassandraConfig = mergeCassandraConfig(clusterTemplate.CassandraConfig, dcTemplate.CassandraConfig)
+	dcConfig.ConfigFiles = mergeConfigFiles(clusterTemplate.ConfigFiles, dcTemplate.ConfigFiles)
 
 	if dcTemplate.MgmtAPIHeap == nil {
 		dcConfig.MgmtAPIHeap = clusterTemplate.MgmtAPIHeap
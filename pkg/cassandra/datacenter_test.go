@@ -11,6 +11,7 @@ import (
 	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
 )
 
 func TestCoalesce(t *testing.T) {
@@ -170,7 +171,7 @@ func TestCoalesce(t *testing.T) {
 			},
 		},
 		{
-			name: "Override CassandraConfig",
+			name: "Merge CassandraConfig",
 			clusterTemplate: &api.CassandraClusterTemplate{
 				CassandraConfig: &api.CassandraConfig{
 					CassandraYaml: &api.CassandraYaml{
@@ -191,6 +192,7 @@ func TestCoalesce(t *testing.T) {
 			want: &DatacenterConfig{
 				CassandraConfig: &api.CassandraConfig{
 					CassandraYaml: &api.CassandraYaml{
+						ConcurrentReads:  intPtr(8),
 						ConcurrentWrites: intPtr(8),
 					},
 					JvmOptions: &api.JvmOptions{
@@ -199,6 +201,234 @@ func TestCoalesce(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Override CassandraConfig property",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						ConcurrentReads:  intPtr(8),
+						ConcurrentWrites: intPtr(8),
+					},
+					JvmOptions: &api.JvmOptions{
+						HeapSize:          parseResource("1024Mi"),
+						AdditionalOptions: []string{"-Dcassandra.ring_delay_ms=30000", "-XX:+UseG1GC"},
+					},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						ConcurrentWrites: intPtr(16),
+					},
+					JvmOptions: &api.JvmOptions{
+						AdditionalOptions: []string{"-Dcassandra.ring_delay_ms=10000", "-Dcassandra.consistent.rangemovement=false"},
+					},
+				},
+			},
+			want: &DatacenterConfig{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						ConcurrentReads:  intPtr(8),
+						ConcurrentWrites: intPtr(16),
+					},
+					JvmOptions: &api.JvmOptions{
+						HeapSize:          parseResource("1024Mi"),
+						AdditionalOptions: []string{"-XX:+UseG1GC", "-Dcassandra.ring_delay_ms=10000", "-Dcassandra.consistent.rangemovement=false"},
+					},
+				},
+			},
+		},
+		{
+			name: "Override garbage collector",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					JvmOptions: &api.JvmOptions{
+						HeapSize:         parseResource("1024Mi"),
+						HeapNewGenSize:   parseResource("256Mi"),
+						JavaVersion:      intPtr(api.Java8),
+						GarbageCollector: stringPtr(api.GarbageCollectorCMS),
+						Cms:              &api.CmsOptions{SurvivorRatio: intPtr(8)},
+					},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					JvmOptions: &api.JvmOptions{
+						GarbageCollector: stringPtr(api.GarbageCollectorG1),
+						G1:               &api.G1Options{MaxGcPauseMs: intPtr(300)},
+					},
+				},
+			},
+			want: &DatacenterConfig{
+				CassandraConfig: &api.CassandraConfig{
+					JvmOptions: &api.JvmOptions{
+						HeapSize:         parseResource("1024Mi"),
+						JavaVersion:      intPtr(api.Java8),
+						GarbageCollector: stringPtr(api.GarbageCollectorG1),
+						G1:               &api.G1Options{MaxGcPauseMs: intPtr(300)},
+					},
+				},
+			},
+		},
+		{
+			name: "Merge garbage collector tunables",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					JvmOptions: &api.JvmOptions{
+						GarbageCollector: stringPtr(api.GarbageCollectorG1),
+						G1:               &api.G1Options{MaxGcPauseMs: intPtr(300), ParallelGcThreads: intPtr(4)},
+					},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					JvmOptions: &api.JvmOptions{
+						G1: &api.G1Options{ParallelGcThreads: intPtr(8)},
+					},
+				},
+			},
+			want: &DatacenterConfig{
+				CassandraConfig: &api.CassandraConfig{
+					JvmOptions: &api.JvmOptions{
+						GarbageCollector: stringPtr(api.GarbageCollectorG1),
+						G1:               &api.G1Options{MaxGcPauseMs: intPtr(300), ParallelGcThreads: intPtr(8)},
+					},
+				},
+			},
+		},
+		{
+			name: "Merge Resources and Networking",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1000m"),
+						corev1.ResourceMemory: resource.MustParse("1024Mi"),
+					},
+				},
+				Networking: &cassdcapi.NetworkingConfig{
+					NodePort: &cassdcapi.NodePortConfig{Native: 30001, Internode: 30002},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("2048Mi"),
+					},
+				},
+				Networking: &cassdcapi.NetworkingConfig{
+					NodePort: &cassdcapi.NodePortConfig{Native: 31001},
+				},
+			},
+			want: &DatacenterConfig{
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1000m"),
+						corev1.ResourceMemory: resource.MustParse("2048Mi"),
+					},
+				},
+				Networking: &cassdcapi.NetworkingConfig{
+					NodePort: &cassdcapi.NodePortConfig{Native: 31001, Internode: 30002},
+				},
+			},
+		},
+		{
+			name: "Merge StorageConfig",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &storageClass,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("2Ti"),
+							},
+						},
+					},
+					AdditionalVolumes: cassdcapi.AdditionalVolumesSlice{
+						{Name: "logs", MountPath: "/var/log/cassandra"},
+						{Name: "extra", MountPath: "/extra"},
+					},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("4Ti"),
+							},
+						},
+					},
+					AdditionalVolumes: cassdcapi.AdditionalVolumesSlice{
+						{Name: "extra", MountPath: "/opt/extra"},
+					},
+				},
+			},
+			want: &DatacenterConfig{
+				StorageConfig: &cassdcapi.StorageConfig{
+					CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &storageClass,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("4Ti"),
+							},
+						},
+					},
+					AdditionalVolumes: cassdcapi.AdditionalVolumesSlice{
+						{Name: "logs", MountPath: "/var/log/cassandra"},
+						{Name: "extra", MountPath: "/opt/extra"},
+					},
+				},
+			},
+		},
+		{
+			name: "Unset cluster settings",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						NumTokens:       intPtr(16),
+						ConcurrentReads: intPtr(8),
+					},
+					JvmOptions: &api.JvmOptions{
+						HeapSize:          parseResource("1024Mi"),
+						AdditionalOptions: []string{"-Dcassandra.ring_delay_ms=30000", "-XX:+UseG1GC"},
+					},
+				},
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1000m"),
+						corev1.ResourceMemory: resource.MustParse("1024Mi"),
+					},
+				},
+				Networking: &cassdcapi.NetworkingConfig{
+					HostNetwork: true,
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				Unset: []string{
+					"config.cassandraYaml.num_tokens",
+					"config.jvmOptions.heapSize",
+					"config.jvmOptions.additionalOptions.-XX:+UseG1GC",
+					"resources.limits.cpu",
+					"networking.hostNetwork",
+				},
+			},
+			want: &DatacenterConfig{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						ConcurrentReads: intPtr(8),
+					},
+					JvmOptions: &api.JvmOptions{
+						AdditionalOptions: []string{"-Dcassandra.ring_delay_ms=30000"},
+					},
+				},
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1024Mi"),
+					},
+				},
+				Networking: &cassdcapi.NetworkingConfig{},
+			},
+		},
 		{
 			name: "Override racks",
 			clusterTemplate: &api.CassandraClusterTemplate{
@@ -235,6 +465,135 @@ func TestCoalesce(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Merge node affinity labels of racks",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				Tolerations: []corev1.Toleration{
+					{Key: "cassandra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				},
+				NodeAffinityLabels: map[string]string{"pool": "cassandra", "disk": "ssd"},
+				Racks: []cassdcapi.Rack{
+					{
+						Name: "rack1",
+					},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				NodeAffinityLabels: map[string]string{"pool": "cassandra-large"},
+				Racks: []cassdcapi.Rack{
+					{
+						Name:               "rack1",
+						NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "us-east1-b"},
+					},
+					{
+						Name:               "rack2",
+						NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "us-east1-c"},
+					},
+				},
+			},
+			want: &DatacenterConfig{
+				Racks: []cassdcapi.Rack{
+					{
+						Name:               "rack1",
+						NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "us-east1-b"},
+					},
+					{
+						Name:               "rack2",
+						NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "us-east1-c"},
+					},
+				},
+				Tolerations: []corev1.Toleration{
+					{Key: "cassandra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				},
+				NodeAffinityLabels: map[string]string{"pool": "cassandra-large", "disk": "ssd"},
+			},
+		},
+		{
+			name: "Override tolerations",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				Tolerations: []corev1.Toleration{
+					{Key: "cassandra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				Tolerations: []corev1.Toleration{},
+			},
+			want: &DatacenterConfig{
+				Tolerations: []corev1.Toleration{},
+			},
+		},
+		{
+			name: "Enable resource tuning for the cluster",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				ResourceTuning: pointer.Bool(true),
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{},
+			want: &DatacenterConfig{
+				ResourceTuning: true,
+			},
+		},
+		{
+			name: "Override resource tuning",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				ResourceTuning: pointer.Bool(true),
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				ResourceTuning: pointer.Bool(false),
+			},
+			want: &DatacenterConfig{},
+		},
+		{
+			name: "Override trace probability",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml:    &api.CassandraYaml{NumTokens: intPtr(16)},
+					TraceProbability: float64Ptr(0.01),
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				CassandraConfig: &api.CassandraConfig{
+					TraceProbability: float64Ptr(0.5),
+				},
+			},
+			want: &DatacenterConfig{
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml:    &api.CassandraYaml{NumTokens: intPtr(16)},
+					TraceProbability: float64Ptr(0.5),
+				},
+			},
+		},
+		{
+			name: "Merge config files",
+			clusterTemplate: &api.CassandraClusterTemplate{
+				ConfigFiles: &api.ConfigFiles{
+					Logback: &api.ConfigFileSource{
+						ConfigMapRef: corev1.LocalObjectReference{Name: "cluster-config"},
+					},
+					CassandraEnv: &api.ConfigFileSource{
+						ConfigMapRef: corev1.LocalObjectReference{Name: "cluster-config"},
+					},
+				},
+			},
+			dcTemplate: &api.CassandraDatacenterTemplate{
+				ConfigFiles: &api.ConfigFiles{
+					CassandraEnv: &api.ConfigFileSource{
+						ConfigMapRef: corev1.LocalObjectReference{Name: "dc1-config"},
+						Key:          "env",
+					},
+				},
+			},
+			want: &DatacenterConfig{
+				ConfigFiles: &api.ConfigFiles{
+					Logback: &api.ConfigFileSource{
+						ConfigMapRef: corev1.LocalObjectReference{Name: "cluster-config"},
+					},
+					CassandraEnv: &api.ConfigFileSource{
+						ConfigMapRef: corev1.LocalObjectReference{Name: "dc1-config"},
+						Key:          "env",
+					},
+				},
+			},
+		},
 		{
 			name: "set management api heap size from DatacenterTemplate",
 			clusterTemplate: &api.CassandraClusterTemplate{
@@ -332,6 +691,18 @@ func TestNewDatacenter_MgmtAPIHeapSize_Unset(t *testing.T) {
 	assert.Equal(t, (*corev1.PodTemplateSpec)(nil), dc.Spec.PodTemplateSpec)
 }
 
+// TestNewDatacenter_Stopped tests that the stopped setting is applied to the CassandraDatacenter.
+func TestNewDatacenter_Stopped(t *testing.T) {
+	template := GetDatacenterConfig()
+	template.Stopped = true
+	dc, err := NewDatacenter(
+		types.NamespacedName{Name: "testdc", Namespace: "test-namespace"},
+		&template,
+	)
+	assert.Equal(t, err, nil)
+	assert.True(t, dc.Spec.Stopped)
+}
+
 // TestNewDatacenter_Fail_NoStorageConfig tests that NewDatacenter fails when no storage config is provided.
 func TestNewDatacenter_Fail_NoStorageConfig(t *testing.T) {
 	template := GetDatacenterConfig()
@@ -343,6 +714,18 @@ func TestNewDatacenter_Fail_NoStorageConfig(t *testing.T) {
 	assert.IsType(t, DCConfigIncomplete{}, err)
 }
 
+// TestNewDatacenter_Fail_UnsupportedVersion tests that NewDatacenter fails when the Cassandra
+// version is not supported.
+func TestNewDatacenter_Fail_UnsupportedVersion(t *testing.T) {
+	template := GetDatacenterConfig()
+	template.ServerVersion = "3.0.25"
+	_, err := NewDatacenter(
+		types.NamespacedName{Name: "testdc", Namespace: "test-namespace"},
+		&template,
+	)
+	assert.EqualError(t, err, "unsupported Cassandra version 3.0.25, the supported versions are >=3.11 <4.2")
+}
+
 // GetDatacenterConfig returns a minimum viable DataCenterConfig.
 func GetDatacenterConfig() DatacenterConfig {
 	storageClass := "default"
@@ -0,0 +1,108 @@
+package cassandra
+
+import (
+	"fmt"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EncryptionStores are the encryption stores of a datacenter, along with their password
+// and a hash of their content.
+type EncryptionStores struct {
+	encryption.Stores
+
+	Password string
+
+	Hash string
+}
+
+// ApplyEncryption configures the encryption options of the cassandra.yaml of dcConfig to
+// use stores, according to tls. The other encryption options that are set in the
+// cassandra.yaml, e.g., cipher_suites, are kept. The stores are mounted in the Cassandra
+// container, and the pod template is annotated with their hash so that renewing them
+// triggers a rolling restart.
+func ApplyEncryption(dcConfig *DatacenterConfig, tls *api.TLSConfig, stores *EncryptionStores) {
+	config := dcConfig.CassandraConfig.DeepCopy()
+	if config == nil {
+		config = &api.CassandraConfig{}
+	}
+	if config.CassandraYaml == nil {
+		config.CassandraYaml = &api.CassandraYaml{}
+	}
+	cassandraYaml := config.CassandraYaml
+
+	if tls.IsInternodeEncryptionEnabled() {
+		if cassandraYaml.ServerEncryptionOptions == nil {
+			cassandraYaml.ServerEncryptionOptions = &api.ServerEncryptionOptions{}
+		}
+		internodeEncryption := tls.InternodeEncryption
+		cassandraYaml.ServerEncryptionOptions.InternodeEncryption = &internodeEncryption
+		setStores(&cassandraYaml.ServerEncryptionOptions.EncryptionOptions, stores)
+	}
+	if tls.IsClientEncryptionEnabled() {
+		if cassandraYaml.ClientEncryptionOptions == nil {
+			cassandraYaml.ClientEncryptionOptions = &api.ClientEncryptionOptions{}
+		}
+		options := cassandraYaml.ClientEncryptionOptions
+		optional := tls.ClientEncryption.Optional
+		requireClientAuth := tls.ClientEncryption.RequireClientAuth
+		options.Enabled = true
+		options.Optional = &optional
+		options.RequireClientAuth = &requireClientAuth
+		setStores(&options.EncryptionOptions, stores)
+	}
+	dcConfig.CassandraConfig = config
+
+	if dcConfig.PodTemplateSpec == nil {
+		dcConfig.PodTemplateSpec = &corev1.PodTemplateSpec{}
+	}
+	template := dcConfig.PodTemplateSpec
+	template.Spec.Volumes = append(template.Spec.Volumes, stores.Volume())
+	UpdateCassandraContainer(template, func(c *corev1.Container) {
+		c.VolumeMounts = append(c.VolumeMounts, stores.VolumeMount())
+	})
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[api.EncryptionStoresHashAnnotation] = stores.Hash
+}
+
+// ServerEncryptionOptions returns the server_encryption_options that use stores for the
+// internode encryption of tls.
+func ServerEncryptionOptions(tls *api.TLSConfig, stores *EncryptionStores) *api.ServerEncryptionOptions {
+	internodeEncryption := tls.InternodeEncryption
+	options := &api.ServerEncryptionOptions{InternodeEncryption: &internodeEncryption}
+	setStores(&options.EncryptionOptions, stores)
+	return options
+}
+
+// CertificateDNSNames returns the DNS names of the certificate of dc: the names of the
+// services of dc, and a wildcard name that matches its pods.
+func CertificateDNSNames(dc *cassdcapi.CassandraDatacenter) []string {
+	services := []string{dc.GetDatacenterServiceName(), dc.GetAllPodsServiceName(), dc.GetSeedServiceName()}
+	dnsNames := make([]string, 0, 4*len(services)+1)
+	for _, service := range services {
+		dnsNames = append(dnsNames,
+			service,
+			fmt.Sprintf("%s.%s", service, dc.Namespace),
+			fmt.Sprintf("%s.%s.svc", service, dc.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, dc.Namespace),
+		)
+	}
+	return append(dnsNames, fmt.Sprintf("*.%s.%s.svc.cluster.local", dc.GetAllPodsServiceName(), dc.Namespace))
+}
+
+func setStores(options *api.EncryptionOptions, stores *EncryptionStores) {
+	keystore := stores.KeystorePath()
+	truststore := stores.TruststorePath()
+	password := stores.Password
+	storeType := string(stores.GetType())
+	options.Keystore = &keystore
+	options.KeystorePassword = &password
+	options.Truststore = &truststore
+	options.TruststorePassword = &password
+	options.StoreType = &storeType
+}
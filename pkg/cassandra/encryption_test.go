@@ -0,0 +1,92 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/Jeffail/gabs"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyEncryption(t *testing.T) {
+	stores := &EncryptionStores{
+		Stores:   encryption.Stores{SecretRef: corev1.LocalObjectReference{Name: "test-dc1-keystore"}},
+		Password: "secret",
+		Hash:     "hash",
+	}
+	cipherSuites := []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}
+	dcConfig := &DatacenterConfig{
+		CassandraConfig: &api.CassandraConfig{
+			CassandraYaml: &api.CassandraYaml{
+				ServerEncryptionOptions: &api.ServerEncryptionOptions{
+					EncryptionOptions: api.EncryptionOptions{CipherSuites: cipherSuites},
+				},
+			},
+		},
+	}
+	original := dcConfig.CassandraConfig
+	tls := &api.TLSConfig{
+		InternodeEncryption: "dc",
+		ClientEncryption:    &api.ClientEncryption{RequireClientAuth: true},
+	}
+
+	ApplyEncryption(dcConfig, tls, stores)
+
+	assert.Nil(t, original.CassandraYaml.ServerEncryptionOptions.Keystore, "the config should be copied")
+	serverOptions := dcConfig.CassandraConfig.CassandraYaml.ServerEncryptionOptions
+	require.NotNil(t, serverOptions)
+	assert.Equal(t, "dc", *serverOptions.InternodeEncryption)
+	assert.Equal(t, "/etc/encryption/keystore.jks", *serverOptions.Keystore)
+	assert.Equal(t, "/etc/encryption/truststore.jks", *serverOptions.Truststore)
+	assert.Equal(t, "secret", *serverOptions.KeystorePassword)
+	assert.Equal(t, "JKS", *serverOptions.StoreType)
+	assert.Equal(t, cipherSuites, serverOptions.CipherSuites)
+
+	clientOptions := dcConfig.CassandraConfig.CassandraYaml.ClientEncryptionOptions
+	require.NotNil(t, clientOptions)
+	assert.True(t, clientOptions.Enabled)
+	assert.False(t, *clientOptions.Optional)
+	assert.True(t, *clientOptions.RequireClientAuth)
+	assert.Equal(t, "/etc/encryption/keystore.jks", *clientOptions.Keystore)
+
+	template := dcConfig.PodTemplateSpec
+	require.NotNil(t, template)
+	assert.Equal(t, "hash", template.Annotations[api.EncryptionStoresHashAnnotation])
+	assert.Equal(t, []corev1.Volume{stores.Volume()}, template.Spec.Volumes)
+	require.Len(t, template.Spec.Containers, 1)
+	assert.Equal(t, []corev1.VolumeMount{stores.VolumeMount()}, template.Spec.Containers[0].VolumeMounts)
+
+	rendered, err := CreateJsonConfig(dcConfig.CassandraConfig, "4.0.1")
+	require.NoError(t, err)
+	config, err := gabs.ParseJSON(rendered)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/encryption/keystore.jks", config.Path("cassandra-yaml.client_encryption_options.keystore").Data())
+	assert.Equal(t, true, config.Path("cassandra-yaml.client_encryption_options.enabled").Data())
+}
+
+func TestApplyEncryptionClientOnly(t *testing.T) {
+	stores := &EncryptionStores{Stores: encryption.Stores{SecretRef: corev1.LocalObjectReference{Name: "stores"}}}
+	dcConfig := &DatacenterConfig{}
+
+	ApplyEncryption(dcConfig, &api.TLSConfig{ClientEncryption: &api.ClientEncryption{Optional: true}, InternodeEncryption: "none"}, stores)
+
+	assert.Nil(t, dcConfig.CassandraConfig.CassandraYaml.ServerEncryptionOptions)
+	assert.True(t, *dcConfig.CassandraConfig.CassandraYaml.ClientEncryptionOptions.Optional)
+}
+
+func TestCertificateDNSNames(t *testing.T) {
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dc1"},
+		Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: "Test Cluster"},
+	}
+	dnsNames := CertificateDNSNames(dc)
+	assert.Contains(t, dnsNames, "testcluster-dc1-service")
+	assert.Contains(t, dnsNames, "testcluster-dc1-service.ns.svc.cluster.local")
+	assert.Contains(t, dnsNames, "testcluster-seed-service.ns")
+	assert.Contains(t, dnsNames, "*.testcluster-dc1-all-pods-service.ns.svc.cluster.local")
+}
@@ -0,0 +1,81 @@
+package cassandra
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var jobPollInterval = 2 * time.Second
+
+// NodeJob is an asynchronous job of the management API running on a node.
+type NodeJob struct {
+	Pod   *corev1.Pod
+	JobId string
+}
+
+// JobFailedError is returned when an asynchronous job failed, or is no longer known to its
+// node, most likely because the node was restarted. In both cases the operation has to be
+// started again.
+type JobFailedError struct {
+	Pod     string
+	JobId   string
+	Message string
+}
+
+func (e *JobFailedError) Error() string {
+	return fmt.Sprintf("job %s failed on pod %s: %s", e.JobId, e.Pod, e.Message)
+}
+
+// StartJobs starts an asynchronous operation on each of pods with start, e.g., a
+// ManagementApiFacade method such as FlushTables, and returns the jobs. The pods of a whole
+// datacenter are returned by ManagementApiFacade.GetDatacenterPods. If the operation cannot
+// be started on one of the pods, the jobs started so far are returned along with the error.
+func StartJobs(pods []corev1.Pod, start func(pod *corev1.Pod) (string, error)) ([]NodeJob, error) {
+	jobs := make([]NodeJob, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		jobId, err := start(pod)
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, NodeJob{Pod: pod, JobId: jobId})
+	}
+	return jobs, nil
+}
+
+// JobsCompleted checks the status of jobs, and returns true if all of them are completed. A
+// *JobFailedError is returned as soon as one of them failed.
+func JobsCompleted(managementApi ManagementApiFacade, jobs []NodeJob) (bool, error) {
+	completed := true
+	for _, job := range jobs {
+		details, err := managementApi.GetJobDetails(job.Pod, job.JobId)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case details.Id == "":
+			return false, &JobFailedError{Pod: job.Pod.Name, JobId: job.JobId, Message: "job not found"}
+		case details.Status == JobStatusError:
+			return false, &JobFailedError{Pod: job.Pod.Name, JobId: job.JobId, Message: details.Error}
+		case details.Status != JobStatusCompleted:
+			completed = false
+		}
+	}
+	return completed, nil
+}
+
+// WaitForJobs blocks until all the jobs are completed, one of them failed, or timeout
+// elapsed. It is meant for short operations such as flushes; the reconcilers should rather
+// record the job ids and check them with JobsCompleted on each reconciliation.
+func WaitForJobs(managementApi ManagementApiFacade, jobs []NodeJob, timeout time.Duration) error {
+	err := wait.PollImmediate(jobPollInterval, timeout, func() (bool, error) {
+		return JobsCompleted(managementApi, jobs)
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("jobs did not complete within %v", timeout)
+	}
+	return err
+}
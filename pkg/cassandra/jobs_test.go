@@ -0,0 +1,89 @@
+package cassandra
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStartJobs(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}},
+	}
+
+	jobs, err := StartJobs(pods, func(pod *corev1.Pod) (string, error) {
+		return "job-" + pod.Name, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+	assert.Equal(t, "pod-1", jobs[1].Pod.Name)
+	assert.Equal(t, "job-pod-1", jobs[1].JobId)
+
+	dummyError := errors.New("failure")
+	jobs, err = StartJobs(pods, func(pod *corev1.Pod) (string, error) {
+		if pod.Name == "pod-1" {
+			return "", dummyError
+		}
+		return "job-" + pod.Name, nil
+	})
+	assert.Equal(t, dummyError, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "job-pod-0", jobs[0].JobId)
+}
+
+func TestJobsCompleted(t *testing.T) {
+	jobs := []NodeJob{{Pod: testPod, JobId: "job-1"}}
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		completed  bool
+		err        error
+	}{
+		{"waiting", http.StatusOK, `{"id":"job-1","status":"WAITING"}`, false, nil},
+		{"completed", http.StatusOK, `{"id":"job-1","status":"COMPLETED"}`, true, nil},
+		{
+			"failed",
+			http.StatusOK,
+			`{"id":"job-1","status":"ERROR","error":"boom"}`,
+			false,
+			&JobFailedError{Pod: testPod.Name, JobId: "job-1", Message: "boom"},
+		},
+		{
+			"not found",
+			http.StatusNotFound,
+			"",
+			false,
+			&JobFailedError{Pod: testPod.Name, JobId: "job-1", Message: "job not found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facade := newTestFacade(&fakeHttpClient{statusCode: tt.statusCode, body: tt.body})
+
+			completed, err := JobsCompleted(facade, jobs)
+			assert.Equal(t, tt.completed, completed)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestWaitForJobs(t *testing.T) {
+	jobs := []NodeJob{{Pod: testPod, JobId: "job-1"}}
+
+	facade := newTestFacade(&fakeHttpClient{statusCode: http.StatusOK, body: `{"id":"job-1","status":"COMPLETED"}`})
+	require.NoError(t, WaitForJobs(facade, jobs, time.Second))
+
+	defer func(interval time.Duration) { jobPollInterval = interval }(jobPollInterval)
+	jobPollInterval = time.Millisecond
+	facade = newTestFacade(&fakeHttpClient{statusCode: http.StatusOK, body: `{"id":"job-1","status":"WAITING"}`})
+	assert.EqualError(t, WaitForJobs(facade, jobs, 10*time.Millisecond), "jobs did not complete within 10ms")
+}
@@ -0,0 +1,156 @@
+package cassandra
+
+import (
+	"fmt"
+	"reflect"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+)
+
+const (
+	gcLogFile             = "/var/log/cassandra/gc.log"
+	defaultGcLogFileCount = 10
+	defaultGcLogFileSize  = 10 * 1024 * 1024
+)
+
+// jvmOptions is an internal type that is intended to be marshaled into JSON that is valid
+// for the jvm options portion of the value supplied to CassandraDatacenter.Spec.Config.
+type jvmOptions struct {
+	InitialHeapSize   *int64   `json:"initial_heap_size,omitempty"`
+	MaxHeapSize       *int64   `json:"max_heap_size,omitempty"`
+	HeapNewGenSize    *int64   `json:"heap_size_young_generation,omitempty"`
+	AdditionalOptions []string `json:"additional-jvm-opts,omitempty"`
+
+	// gcOptions is only set with Cassandra 3.11, whose JVM options are all rendered in
+	// jvm-options. Its AdditionalOptions field is shadowed by the one above, the options
+	// that depend on the Java version are appended to the latter instead.
+	*gcOptions
+}
+
+// gcOptions is an internal type that is intended to be marshaled into JSON that is valid
+// for the options that depend on the Java version, i.e., jvm-options with Cassandra 3.11,
+// and jvm8-server-options or jvm11-server-options with Cassandra 4.0.
+type gcOptions struct {
+	GarbageCollector *string `json:"garbage_collector,omitempty"`
+
+	SurvivorRatio                  *int `json:"survivor_ratio,omitempty"`
+	MaxTenuringThreshold           *int `json:"max_tenuring_threshold,omitempty"`
+	CmsInitiatingOccupancyFraction *int `json:"cms_initiating_occupancy_fraction,omitempty"`
+	CmsWaitDuration                *int `json:"cms_wait_duration,omitempty"`
+
+	G1RSetUpdatingPauseTimePercent *int `json:"g1r_set_updating_pause_time_percent,omitempty"`
+	MaxGcPauseMillis               *int `json:"max_gc_pause_millis,omitempty"`
+	InitiatingHeapOccupancyPercent *int `json:"initiating_heap_occupancy_percent,omitempty"`
+	ParallelGcThreads              *int `json:"parallel_gc_threads,omitempty"`
+	ConcGcThreads                  *int `json:"conc_gc_threads,omitempty"`
+
+	AdditionalOptions []string `json:"additional-jvm-opts,omitempty"`
+}
+
+// cassandraEnv is an internal type that is intended to be marshaled into JSON that is
+// valid for the cassandra-env-sh portion of the value supplied to
+// CassandraDatacenter.Spec.Config.
+type cassandraEnv struct {
+	JmxPort *int `json:"jmx-port,omitempty"`
+}
+
+func newJvmOptions(apiOptions *api.JvmOptions) *jvmOptions {
+	options := &jvmOptions{}
+	if apiOptions.HeapSize != nil {
+		heapSize := apiOptions.HeapSize.Value()
+		options.InitialHeapSize = &heapSize
+		options.MaxHeapSize = &heapSize
+	}
+
+	if apiOptions.HeapNewGenSize != nil {
+		newGenSize := apiOptions.HeapNewGenSize.Value()
+		options.HeapNewGenSize = &newGenSize
+	}
+
+	if apiOptions.HeapDump != nil {
+		options.AdditionalOptions = append(options.AdditionalOptions,
+			"-XX:+HeapDumpOnOutOfMemoryError", "-XX:HeapDumpPath="+apiOptions.HeapDump.Path)
+	}
+	options.AdditionalOptions = append(options.AdditionalOptions, apiOptions.AdditionalOptions...)
+
+	return options
+}
+
+// newGcOptions returns the options of apiOptions that depend on the Java version, or nil
+// if there are none.
+func newGcOptions(apiOptions *api.JvmOptions, javaVersion int) *gcOptions {
+	options := &gcOptions{GarbageCollector: apiOptions.GarbageCollector}
+
+	if cms := apiOptions.Cms; cms != nil {
+		options.SurvivorRatio = cms.SurvivorRatio
+		options.MaxTenuringThreshold = cms.MaxTenuringThreshold
+		options.CmsInitiatingOccupancyFraction = cms.InitiatingOccupancyFraction
+		options.CmsWaitDuration = cms.WaitDurationMs
+	}
+
+	if g1 := apiOptions.G1; g1 != nil {
+		options.G1RSetUpdatingPauseTimePercent = g1.RSetUpdatingPauseTimePercent
+		options.MaxGcPauseMillis = g1.MaxGcPauseMs
+		options.InitiatingHeapOccupancyPercent = g1.InitiatingHeapOccupancyPercent
+		options.ParallelGcThreads = g1.ParallelGcThreads
+		options.ConcGcThreads = g1.ConcurrentGcThreads
+	}
+
+	if apiOptions.GarbageCollector != nil && *apiOptions.GarbageCollector == api.GarbageCollectorZGC {
+		// ZGC is experimental in Java 11
+		options.AdditionalOptions = append(options.AdditionalOptions, "-XX:+UnlockExperimentalVMOptions")
+	}
+
+	if apiOptions.GcLogging != nil {
+		options.AdditionalOptions = append(options.AdditionalOptions, gcLoggingOptions(apiOptions.GcLogging, javaVersion)...)
+	}
+
+	if reflect.ValueOf(*options).IsZero() {
+		return nil
+	}
+	return options
+}
+
+// gcLoggingOptions returns the JVM options that configure the GC log. They override the
+// defaults of Cassandra, which enable the GC log.
+func gcLoggingOptions(logging *api.GcLoggingOptions, javaVersion int) []string {
+	fileCount := int64(defaultGcLogFileCount)
+	if logging.FileCount != nil {
+		fileCount = int64(*logging.FileCount)
+	}
+	fileSize := int64(defaultGcLogFileSize)
+	if logging.FileSize != nil {
+		fileSize = logging.FileSize.Value()
+	}
+
+	if javaVersion == api.Java8 {
+		flag := "-XX:+"
+		if !logging.Enabled {
+			flag = "-XX:-"
+		}
+		options := make([]string, 0, 9)
+		for _, option := range []string{"PrintGCDetails", "PrintGCDateStamps", "PrintHeapAtGC", "PrintTenuringDistribution",
+			"PrintGCApplicationStoppedTime", "PrintPromotionFailure", "UseGCLogFileRotation"} {
+			options = append(options, flag+option)
+		}
+		if logging.Enabled {
+			options = append(options, fmt.Sprintf("-XX:NumberOfGCLogFiles=%d", fileCount), fmt.Sprintf("-XX:GCLogFileSize=%d", fileSize))
+		}
+		return options
+	}
+
+	if !logging.Enabled {
+		// -Xlog:disable also turns off the warnings that are logged to stdout by default
+		return []string{"-Xlog:disable", "-Xlog:all=warning:stdout"}
+	}
+	return []string{fmt.Sprintf(
+		"-Xlog:gc=info,heap*=trace,age*=debug,safepoint=info,promotion*=trace:file=%s:time,uptime,pid,tid,level:filecount=%d,filesize=%d",
+		gcLogFile, fileCount, fileSize)}
+}
+
+func newCassandraEnv(apiOptions *api.JvmOptions) *cassandraEnv {
+	if apiOptions.Jmx == nil || apiOptions.Jmx.Port == nil {
+		return nil
+	}
+	return &cassandraEnv{JmxPort: apiOptions.Jmx.Port}
+}
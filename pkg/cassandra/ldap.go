@@ -0,0 +1,73 @@
+package cassandra
+
+import (
+	"fmt"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	ldapPropertiesFile = "/config/ldap.properties"
+
+	ldapPropertiesJvmOption = "-Dcassandra.ldap.properties.file=" + ldapPropertiesFile
+)
+
+// ApplyLDAP configures the LDAP authenticator of dcConfig. Its properties are written by
+// an init container, which reads the bind credentials from the bind Secret of ldap, to
+// the file that the JVM option added to dcConfig points the authenticator to. The pod
+// template is annotated with bindSecretHash so that changing the credentials triggers a
+// rolling restart.
+func ApplyLDAP(dcConfig *DatacenterConfig, ldap *api.LDAPConfig, bindSecretHash string) {
+	config := dcConfig.CassandraConfig.DeepCopy()
+	if config == nil {
+		config = &api.CassandraConfig{}
+	}
+	if config.JvmOptions == nil {
+		config.JvmOptions = &api.JvmOptions{}
+	}
+	config.JvmOptions.AdditionalOptions = append(config.JvmOptions.AdditionalOptions, ldapPropertiesJvmOption)
+	dcConfig.CassandraConfig = config
+
+	if dcConfig.PodTemplateSpec == nil {
+		dcConfig.PodTemplateSpec = &corev1.PodTemplateSpec{}
+	}
+	template := dcConfig.PodTemplateSpec
+	template.Spec.InitContainers = append(template.Spec.InitContainers, ldapInitContainer(ldap))
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[api.LDAPBindSecretHashAnnotation] = bindSecretHash
+}
+
+func ldapInitContainer(ldap *api.LDAPConfig) corev1.Container {
+	bindSecretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: ldap.BindSecretRef,
+				Key:                  key,
+			},
+		}
+	}
+	return corev1.Container{
+		Name:            "ldap-config",
+		Image:           defaultInitImage.String(),
+		ImagePullPolicy: defaultInitImage.PullPolicy,
+		Env: []corev1.EnvVar{
+			{Name: "LDAP_URI", Value: fmt.Sprintf("%s/%s", ldap.ServerURL, ldap.SearchBase)},
+			{Name: "LDAP_FILTER_TEMPLATE", Value: fmt.Sprintf("(%s=%%s)", ldap.GetUsernameAttribute())},
+			{Name: "LDAP_SERVICE_DN", ValueFrom: bindSecretKey("username")},
+			{Name: "LDAP_SERVICE_PASSWORD", ValueFrom: bindSecretKey("password")},
+		},
+		Args: []string{
+			"/bin/sh",
+			"-c",
+			"printf 'ldap_uri=%s\\ncontext_factory=com.sun.jndi.ldap.LdapCtxFactory\\nservice_dn=%s\\nservice_password=%s\\nfilter_template=%s\\n' " +
+				"\"$LDAP_URI\" \"$LDAP_SERVICE_DN\" \"$LDAP_SERVICE_PASSWORD\" \"$LDAP_FILTER_TEMPLATE\" > " + ldapPropertiesFile,
+		},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      "server-config",
+			MountPath: "/config",
+		}},
+	}
+}
@@ -0,0 +1,52 @@
+package cassandra
+
+import (
+	"testing"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestApplyLDAP(t *testing.T) {
+	dcConfig := &DatacenterConfig{
+		CassandraConfig: &api.CassandraConfig{
+			JvmOptions: &api.JvmOptions{AdditionalOptions: []string{"-XX:+UseG1GC"}},
+		},
+	}
+	original := dcConfig.CassandraConfig
+	ldap := &api.LDAPConfig{
+		ServerURL:         "ldaps://ldap.example.com:636",
+		BindSecretRef:     corev1.LocalObjectReference{Name: "ldap-bind"},
+		SearchBase:        "ou=people,dc=example,dc=com",
+		UsernameAttribute: "uid",
+	}
+
+	ApplyLDAP(dcConfig, ldap, "hash")
+
+	assert.Equal(t, []string{"-XX:+UseG1GC"}, original.JvmOptions.AdditionalOptions, "the config should be copied")
+	assert.Equal(t, []string{"-XX:+UseG1GC", "-Dcassandra.ldap.properties.file=/config/ldap.properties"},
+		dcConfig.CassandraConfig.JvmOptions.AdditionalOptions)
+
+	template := dcConfig.PodTemplateSpec
+	require.NotNil(t, template)
+	assert.Equal(t, "hash", template.Annotations[api.LDAPBindSecretHashAnnotation])
+	require.Len(t, template.Spec.InitContainers, 1)
+	initContainer := template.Spec.InitContainers[0]
+	assert.Equal(t, "ldap-config", initContainer.Name)
+	assert.Equal(t, "docker.io/library/busybox:1.34.1", initContainer.Image)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "server-config", MountPath: "/config"}}, initContainer.VolumeMounts)
+
+	env := make(map[string]corev1.EnvVar)
+	for _, envVar := range initContainer.Env {
+		env[envVar.Name] = envVar
+	}
+	assert.Equal(t, "ldaps://ldap.example.com:636/ou=people,dc=example,dc=com", env["LDAP_URI"].Value)
+	assert.Equal(t, "(uid=%s)", env["LDAP_FILTER_TEMPLATE"].Value)
+	require.NotNil(t, env["LDAP_SERVICE_DN"].ValueFrom)
+	assert.Equal(t, "ldap-bind", env["LDAP_SERVICE_DN"].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "username", env["LDAP_SERVICE_DN"].ValueFrom.SecretKeyRef.Key)
+	require.NotNil(t, env["LDAP_SERVICE_PASSWORD"].ValueFrom)
+	assert.Equal(t, "password", env["LDAP_SERVICE_PASSWORD"].ValueFrom.SecretKeyRef.Key)
+}
@@ -0,0 +1,187 @@
+package cassandra
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
+)
+
+// TraceProbabilitySetting is the name of the live setting that holds the trace probability,
+// which is not a cassandra.yaml property.
+const TraceProbabilitySetting = "trace_probability"
+
+// liveSetting is a setting that can be changed on a running node.
+type liveSetting struct {
+	// endpoint is the management API endpoint that changes the setting.
+	endpoint string
+
+	// defaultValue is the value of the setting when it is not set in cassandra.yaml. It is
+	// applied when a live setting is removed from the config.
+	defaultValue string
+
+	// cassandra4DefaultValue overrides defaultValue for Cassandra 4.0 and later, if it is set.
+	cassandra4DefaultValue string
+}
+
+// liveSettings maps the settings that can be changed without restarting Cassandra to the
+// way they are changed. The keys are cassandra.yaml properties with their pre-4.1 names,
+// except for TraceProbabilitySetting.
+var liveSettings = map[string]liveSetting{
+	"compaction_throughput_mb_per_sec":                     {endpoint: "/api/v0/ops/node/compactionthroughput", defaultValue: "16", cassandra4DefaultValue: "64"},
+	"stream_throughput_outbound_megabits_per_sec":          {endpoint: "/api/v0/ops/node/streamthroughput", defaultValue: "200"},
+	"inter_dc_stream_throughput_outbound_megabits_per_sec": {endpoint: "/api/v0/ops/node/interdcstreamthroughput", defaultValue: "200"},
+	"hinted_handoff_enabled":                               {endpoint: "/api/v0/ops/node/hints/handoff", defaultValue: "true"},
+	"hinted_handoff_throttle_in_kb":                        {endpoint: "/api/v0/ops/node/hints/throttle", defaultValue: "1024"},
+	"max_hint_window_in_ms":                                {endpoint: "/api/v0/ops/node/hints/window", defaultValue: "10800000"},
+	TraceProbabilitySetting:                                {endpoint: "/api/v0/ops/node/traceprobability", defaultValue: "0"},
+}
+
+// IsLiveSetting returns true if the cassandra.yaml property can be changed on running
+// nodes.
+func IsLiveSetting(property string) bool {
+	_, found := liveSettings[property]
+	return found
+}
+
+// LiveSettingDefault returns the value of the live setting when it is not set.
+func LiveSettingDefault(setting, cassandraVersion string) string {
+	if version.AtLeast(cassandraVersion, "4.0") && liveSettings[setting].cassandra4DefaultValue != "" {
+		return liveSettings[setting].cassandra4DefaultValue
+	}
+	return liveSettings[setting].defaultValue
+}
+
+// ConfigChanges are the differences between the config of a CassandraDatacenter and its
+// desired config.
+type ConfigChanges struct {
+	// Live maps the live settings that changed to their desired value. The desired value
+	// of a live setting that is removed from the config is its default value.
+	Live map[string]string
+
+	// Restart lists the cassandra.yaml properties, and the other sections of the config,
+	// e.g., jvm-server-options, that changed and that require a restart.
+	Restart []string
+}
+
+// RestartRequired returns true if the changes cannot be applied without restarting the
+// nodes.
+func (c *ConfigChanges) RestartRequired() bool {
+	return len(c.Restart) > 0
+}
+
+// Properties returns the sorted names of all the properties and sections that changed.
+func (c *ConfigChanges) Properties() []string {
+	properties := append([]string{}, c.Restart...)
+	for property := range c.Live {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+// DiffConfig compares the config of a CassandraDatacenter with its desired config. Both
+// are JSON documents as rendered by CreateJsonConfig for cassandraVersion.
+func DiffConfig(actual, desired []byte, cassandraVersion string) (*ConfigChanges, error) {
+	actualSections, err := parseConfig(actual)
+	if err != nil {
+		return nil, err
+	}
+	desiredSections, err := parseConfig(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &ConfigChanges{Live: map[string]string{}}
+	for section := range unionKeys(actualSections, desiredSections) {
+		if section == "cassandra-yaml" {
+			continue
+		}
+		if !reflect.DeepEqual(actualSections[section], desiredSections[section]) {
+			changes.Restart = append(changes.Restart, section)
+		}
+	}
+
+	actualYaml := yamlSection(actualSections)
+	desiredYaml := yamlSection(desiredSections)
+	for property := range unionKeys(actualYaml, desiredYaml) {
+		desiredValue, found := desiredYaml[property]
+		if reflect.DeepEqual(actualYaml[property], desiredValue) {
+			continue
+		}
+		if !IsLiveSetting(property) {
+			changes.Restart = append(changes.Restart, property)
+		} else if found {
+			changes.Live[property] = fmt.Sprint(desiredValue)
+		} else {
+			changes.Live[property] = LiveSettingDefault(property, cassandraVersion)
+		}
+	}
+
+	sort.Strings(changes.Restart)
+	return changes, nil
+}
+
+// LiveSettings returns the values of the live settings that are set in config, which is a
+// JSON document as rendered by CreateJsonConfig.
+func LiveSettings(config []byte) (map[string]string, error) {
+	sections, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]string)
+	for property, value := range yamlSection(sections) {
+		if IsLiveSetting(property) {
+			settings[property] = fmt.Sprint(value)
+		}
+	}
+	return settings, nil
+}
+
+// TraceProbability returns the value of the trace probability live setting, or an empty
+// string if it is not set.
+func TraceProbability(config *api.CassandraConfig) string {
+	if config == nil || config.TraceProbability == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*config.TraceProbability, 'f', -1, 64)
+}
+
+func parseConfig(config []byte) (map[string]interface{}, error) {
+	sections := make(map[string]interface{})
+	if len(config) == 0 {
+		return sections, nil
+	}
+	// Numbers are kept as json.Number so that they are formatted as they were rendered.
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.UseNumber()
+	if err := decoder.Decode(&sections); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	return sections, nil
+}
+
+// yamlSection returns the cassandra.yaml properties of a parsed config. The properties that
+// were renamed in Cassandra 4.1 have their old name and value.
+func yamlSection(sections map[string]interface{}) map[string]interface{} {
+	if yaml, ok := sections["cassandra-yaml"].(map[string]interface{}); ok {
+		return legacyProperties(yaml)
+	}
+	return map[string]interface{}{}
+}
+
+func unionKeys(m1, m2 map[string]interface{}) map[string]struct{} {
+	keys := make(map[string]struct{}, len(m1)+len(m2))
+	for key := range m1 {
+		keys[key] = struct{}{}
+	}
+	for key := range m2 {
+		keys[key] = struct{}{}
+	}
+	return keys
+}
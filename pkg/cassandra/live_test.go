@@ -0,0 +1,127 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfig(t *testing.T) {
+	type test struct {
+		name             string
+		actual           string
+		desired          string
+		cassandraVersion string
+		want             *ConfigChanges
+	}
+
+	tests := []test{
+		{
+			name:             "no changes",
+			actual:           `{"cassandra-yaml":{"num_tokens":16},"jvm-options":{"initial_heap_size":"1G"}}`,
+			desired:          `{"cassandra-yaml":{"num_tokens":16},"jvm-options":{"initial_heap_size":"1G"}}`,
+			cassandraVersion: "4.0.1",
+			want:             &ConfigChanges{Live: map[string]string{}},
+		},
+		{
+			name:             "live changes",
+			actual:           `{"cassandra-yaml":{"compaction_throughput_mb_per_sec":16,"hinted_handoff_enabled":true}}`,
+			desired:          `{"cassandra-yaml":{"compaction_throughput_mb_per_sec":64,"hinted_handoff_enabled":false,"stream_throughput_outbound_megabits_per_sec":400}}`,
+			cassandraVersion: "4.0.1",
+			want: &ConfigChanges{
+				Live: map[string]string{
+					"compaction_throughput_mb_per_sec":            "64",
+					"hinted_handoff_enabled":                      "false",
+					"stream_throughput_outbound_megabits_per_sec": "400",
+				},
+			},
+		},
+		{
+			name:             "removed live setting is reset to its default",
+			actual:           `{"cassandra-yaml":{"compaction_throughput_mb_per_sec":128}}`,
+			desired:          `{"cassandra-yaml":{}}`,
+			cassandraVersion: "3.11.11",
+			want: &ConfigChanges{
+				Live: map[string]string{"compaction_throughput_mb_per_sec": "16"},
+			},
+		},
+		{
+			name:             "changes that require a restart",
+			actual:           `{"cassandra-yaml":{"compaction_throughput_mb_per_sec":16,"num_tokens":256},"jvm-options":{"initial_heap_size":"1G"}}`,
+			desired:          `{"cassandra-yaml":{"compaction_throughput_mb_per_sec":64,"num_tokens":16},"jvm-options":{"initial_heap_size":"2G"}}`,
+			cassandraVersion: "4.0.1",
+			want: &ConfigChanges{
+				Live:    map[string]string{"compaction_throughput_mb_per_sec": "64"},
+				Restart: []string{"jvm-options", "num_tokens"},
+			},
+		},
+		{
+			name:             "no actual config",
+			actual:           ``,
+			desired:          `{"cassandra-yaml":{"compaction_throughput_mb_per_sec":64},"jvm-options":{"initial_heap_size":"1G"}}`,
+			cassandraVersion: "4.0.1",
+			want: &ConfigChanges{
+				Live:    map[string]string{"compaction_throughput_mb_per_sec": "64"},
+				Restart: []string{"jvm-options"},
+			},
+		},
+		{
+			name:             "renamed live settings",
+			actual:           `{"cassandra-yaml":{"compaction_throughput":"16MiB/s","max_hint_window":"10800000ms","read_request_timeout":"5000ms"}}`,
+			desired:          `{"cassandra-yaml":{"compaction_throughput":"64MiB/s","read_request_timeout":"10000ms"}}`,
+			cassandraVersion: "4.1.0",
+			want: &ConfigChanges{
+				Live: map[string]string{
+					"compaction_throughput_mb_per_sec": "64",
+					"max_hint_window_in_ms":            "10800000",
+				},
+				Restart: []string{"read_request_timeout_in_ms"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DiffConfig([]byte(tc.actual), []byte(tc.desired), tc.cassandraVersion)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestConfigChangesProperties(t *testing.T) {
+	changes := &ConfigChanges{
+		Live:    map[string]string{"compaction_throughput_mb_per_sec": "64"},
+		Restart: []string{"num_tokens", "jvm-options"},
+	}
+	assert.True(t, changes.RestartRequired())
+	assert.Equal(t, []string{"compaction_throughput_mb_per_sec", "jvm-options", "num_tokens"}, changes.Properties())
+	assert.Equal(t, []string{"num_tokens", "jvm-options"}, changes.Restart)
+}
+
+func TestLiveSettings(t *testing.T) {
+	settings, err := LiveSettings([]byte(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":64,"max_hint_window_in_ms":3600000,"num_tokens":16}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"compaction_throughput_mb_per_sec": "64",
+		"max_hint_window_in_ms":            "3600000",
+	}, settings)
+
+	settings, err = LiveSettings([]byte(`{"cassandra-yaml":{"compaction_throughput":"64MiB/s","hinted_handoff_throttle":"2048KiB"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"compaction_throughput_mb_per_sec": "64",
+		"hinted_handoff_throttle_in_kb":    "2048",
+	}, settings)
+
+	_, err = LiveSettings([]byte(`{"cassandra-yaml":`))
+	assert.Error(t, err)
+}
+
+func TestLiveSettingDefault(t *testing.T) {
+	assert.Equal(t, "16", LiveSettingDefault("compaction_throughput_mb_per_sec", "3.11.11"))
+	assert.Equal(t, "64", LiveSettingDefault("compaction_throughput_mb_per_sec", "4.0.1"))
+	assert.Equal(t, "64", LiveSettingDefault("compaction_throughput_mb_per_sec", "4.1.0"))
+	assert.Equal(t, "0", LiveSettingDefault(TraceProbabilitySetting, "4.0.1"))
+}
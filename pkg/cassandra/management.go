@@ -88,9 +88,121 @@ type ManagementApiFacade interface {
 	// keyspace.
 	CreateTable(definition *httphelper.TableDefinition) error
 
+	// GetTableDefinition calls the management API "GET /api/v0/ops/tables/schema" endpoint to retrieve the live
+	// definition of the columns of the given table, in the format of the "POST /ops/tables/create" endpoint. Nil is
+	// returned if the table does not exist. The options of the table are not returned.
+	GetTableDefinition(keyspaceName, tableName string) (*httphelper.TableDefinition, error)
+
 	// EnsureKeyspaceReplication checks if the given keyspace has the given replication, and if it does not,
 	// alters it to match the desired replication.
 	EnsureKeyspaceReplication(keyspaceName string, replication map[string]int) error
+
+	// GetEndpointStates calls the management API "GET /metadata/endpoints" endpoint to retrieve the gossip state of
+	// all the nodes in the cluster, as seen by one of the nodes of the datacenter.
+	GetEndpointStates() ([]httphelper.EndpointState, error)
+
+	// DecommissionNode calls the management API "POST /ops/node/decommission" endpoint on the given pod. The call
+	// returns once the decommission has been initiated; the node remains in the LEAVING state until it has streamed
+	// its data to the rest of the cluster.
+	DecommissionNode(pod *corev1.Pod) error
+
+	// RebuildNode calls the management API "POST /api/v1/ops/node/rebuild" endpoint on the given pod to stream the
+	// data the node is responsible for from sourceDatacenter. The rebuild runs asynchronously; the returned job id
+	// can be passed to GetJobDetails to follow its progress.
+	RebuildNode(pod *corev1.Pod, sourceDatacenter string) (string, error)
+
+	// GetJobDetails calls the management API "GET /api/v0/ops/executor/job" endpoint on the given pod to retrieve
+	// the status of an asynchronous job. If the job is unknown to the node, a JobDetails with an empty Id is
+	// returned.
+	GetJobDetails(pod *corev1.Pod, jobId string) (*httphelper.JobDetails, error)
+
+	// GetSchemaVersions calls the management API "GET /metadata/endpoints" endpoint and
+	// returns the host ids of the nodes of the cluster grouped by schema version. Only the
+	// nodes that are up and NORMAL are included. The nodes agree on the schema when a
+	// single version is returned.
+	GetSchemaVersions() (map[string][]string, error)
+
+	// TakeSnapshot calls the management API "POST /api/v0/ops/node/snapshots" endpoint on
+	// the given pod to snapshot the given keyspaces, or all the keyspaces if keyspaces is
+	// empty.
+	TakeSnapshot(pod *corev1.Pod, snapshotName string, keyspaces []string) error
+
+	// UpgradeSSTables calls the management API "POST /api/v1/ops/tables/sstables/upgrade"
+	// endpoint on the given pod to rewrite the sstables of all the keyspaces that are not
+	// on the current sstable version. The job runs asynchronously; its id is returned.
+	UpgradeSSTables(pod *corev1.Pod) (string, error)
+
+	// RepairKeyspace calls the management API "POST /api/v1/ops/node/repair" endpoint on
+	// the given pod to run a full repair of the given keyspace. The repair runs
+	// asynchronously; its job id is returned.
+	RepairKeyspace(pod *corev1.Pod, keyspace string) (string, error)
+
+	// CleanupKeyspace calls the management API "POST /api/v1/ops/keyspace/cleanup" endpoint
+	// on the given pod to remove the data the node is no longer responsible for from the
+	// given tables of keyspace. The cleanup runs asynchronously; its job id is returned.
+	CleanupKeyspace(pod *corev1.Pod, keyspace string, tables []string) (string, error)
+
+	// FlushTables calls the management API "POST /api/v1/ops/tables/flush" endpoint on the
+	// given pod to flush the memtables of the given tables of keyspace. The flush runs
+	// asynchronously; its job id is returned.
+	FlushTables(pod *corev1.Pod, keyspace string, tables []string) (string, error)
+
+	// CompactTables calls the management API "POST /api/v1/ops/tables/compact" endpoint on
+	// the given pod to run a major compaction of the given tables of keyspace. The
+	// compaction runs asynchronously; its job id is returned.
+	CompactTables(pod *corev1.Pod, keyspace string, tables []string) (string, error)
+
+	// GarbageCollectTables calls the management API "POST /api/v1/ops/tables/garbagecollect"
+	// endpoint on the given pod to remove the deleted data from the sstables of the given
+	// tables of keyspace. The job runs asynchronously; its id is returned.
+	GarbageCollectTables(pod *corev1.Pod, keyspace string, tables []string) (string, error)
+
+	// DrainNode calls the management API "POST /api/v0/ops/node/drain" endpoint on the given
+	// pod. The node flushes its memtables and stops accepting writes; it must be restarted
+	// afterwards.
+	DrainNode(pod *corev1.Pod) error
+
+	// ClearSnapshots calls the management API "DELETE /api/v0/ops/node/snapshots" endpoint
+	// on the given pod to remove the given snapshot of the given keyspaces. An empty
+	// snapshotName removes all the snapshots, and empty keyspaces selects all the keyspaces.
+	ClearSnapshots(pod *corev1.Pod, snapshotName string, keyspaces []string) error
+
+	// GetRingStatus calls the management API "GET /metadata/endpoints" and
+	// "GET /api/v0/ops/node/ownership" endpoints and returns the status of all the nodes of
+	// the cluster, as seen by one of the nodes of the datacenter.
+	GetRingStatus() ([]NodeStatus, error)
+
+	// GetDatacenterPods returns the pods of the datacenter whose Cassandra container is
+	// ready. The node operations can be run on all of them with StartJobs.
+	GetDatacenterPods() ([]corev1.Pod, error)
+
+	// SetLiveSetting calls the management API endpoint that changes the given live setting
+	// on the given pod, e.g., "POST /api/v0/ops/node/compactionthroughput" for
+	// compaction_throughput_mb_per_sec. The change is not persisted, the node reverts to
+	// the value of its configuration when it restarts.
+	SetLiveSetting(pod *corev1.Pod, setting string, value string) error
+}
+
+// NodeStatus is the status of a node of the cluster, as gossiped to the other nodes.
+type NodeStatus struct {
+	HostId     string
+	Endpoint   string
+	Datacenter string
+	Rack       string
+
+	// State is the gossip status of the node, e.g., NORMAL, JOINING or LEAVING.
+	State string
+
+	Alive bool
+
+	// Load is the size of the data of the node in bytes. It is 0 until the node gossips its
+	// load.
+	Load float64
+
+	// Ownership is the fraction of the token ring owned by the node, between 0 and 1,
+	// regardless of the replication of the keyspaces. It is nil if the management API does
+	// not report the ownership of the nodes.
+	Ownership *float64
 }
 
 type defaultManagementApiFacade struct {
@@ -123,7 +235,7 @@ func (r *defaultManagementApiFacade) CreateKeyspaceIfNotExists(
 func (r *defaultManagementApiFacade) fetchDatacenterPods() ([]corev1.Pod, error) {
 	podList := &corev1.PodList{}
 	labels := client.MatchingLabels{cassdcapi.DatacenterLabel: r.dc.Name}
-	if err := r.k8sClient.List(r.ctx, podList, labels); err != nil {
+	if err := r.k8sClient.List(r.ctx, podList, client.InNamespace(r.dc.Namespace), labels); err != nil {
 		return nil, err
 	} else {
 		pods := r.filterPods(podList.Items, func(pod corev1.Pod) bool {
@@ -145,7 +257,7 @@ func (r *defaultManagementApiFacade) filterPods(pods []corev1.Pod, filter func(c
 	filtered := make([]corev1.Pod, 0)
 	for _, pod := range pods {
 		if filter(pod) {
-			filtered = append(pods, pod)
+			filtered = append(filtered, pod)
 		}
 	}
 	return filtered
@@ -290,3 +402,32 @@ func (r *defaultManagementApiFacade) EnsureKeyspaceReplication(keyspaceName stri
 		}
 	}
 }
+
+func (r *defaultManagementApiFacade) GetEndpointStates() ([]httphelper.EndpointState, error) {
+	if pods, err := r.fetchDatacenterPods(); err != nil {
+		r.logger.Error(err, "Failed to fetch datacenter pods")
+		return nil, err
+	} else {
+		for _, pod := range pods {
+			if endpoints, err := r.nodeMgmtClient.CallMetadataEndpointsEndpoint(&pod); err != nil {
+				r.logger.Error(err, fmt.Sprintf("Failed to CALL get endpoint states on pod %v", pod.Name))
+			} else {
+				return endpoints.Entity, nil
+			}
+		}
+		return nil, fmt.Errorf("CALL get endpoint states failed on all datacenter %v pods", r.dc.Name)
+	}
+}
+
+func (r *defaultManagementApiFacade) DecommissionNode(pod *corev1.Pod) error {
+	if err := r.nodeMgmtClient.CallDecommissionNodeEndpoint(pod); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL decommission on pod %v", pod.Name))
+		return err
+	}
+	r.logger.Info(fmt.Sprintf("Successfully started decommission of pod %v", pod.Name))
+	return nil
+}
+
+func (r *defaultManagementApiFacade) GetDatacenterPods() ([]corev1.Pod, error) {
+	return r.fetchDatacenterPods()
+}
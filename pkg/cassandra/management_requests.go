@@ -0,0 +1,461 @@
+package cassandra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Statuses of the asynchronous jobs reported by the management API.
+const (
+	JobStatusWaiting   = "WAITING"
+	JobStatusCompleted = "COMPLETED"
+	JobStatusError     = "ERROR"
+)
+
+// nodeMgmtRequest describes a call to a management API endpoint that httphelper.NodeMgmtClient
+// does not provide a method for.
+type nodeMgmtRequest struct {
+	endpoint    string
+	queryParams url.Values
+	method      string
+	timeout     time.Duration
+	body        []byte
+}
+
+// callNodeMgmtEndpoint performs request against the management API of pod. It mirrors the
+// private function of the same name in httphelper, and in particular returns an
+// *httphelper.RequestError when the response status code is not 2xx.
+func (r *defaultManagementApiFacade) callNodeMgmtEndpoint(pod *corev1.Pod, request nodeMgmtRequest) ([]byte, error) {
+	podHost, err := httphelper.BuildPodHostFromPod(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &url.URL{
+		Scheme:   r.nodeMgmtClient.Protocol,
+		Host:     podHost + ":8080",
+		Path:     request.endpoint,
+		RawQuery: request.queryParams.Encode(),
+	}
+
+	var reqBody io.Reader
+	if len(request.body) > 0 {
+		reqBody = bytes.NewBuffer(request.body)
+	}
+
+	ctx := r.ctx
+	if request.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, request.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.method, endpoint.String(), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Close = true
+	if len(request.body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := r.nodeMgmtClient.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			r.logger.Error(err, "Failed to close response body")
+		}
+	}()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, &httphelper.RequestError{
+			StatusCode: res.StatusCode,
+			Err:        fmt.Errorf("incorrect status code of %d when calling endpoint %s: %s", res.StatusCode, request.endpoint, string(body)),
+		}
+	}
+
+	return body, nil
+}
+
+func (r *defaultManagementApiFacade) RebuildNode(pod *corev1.Pod, sourceDatacenter string) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling rebuild on pod %v with source datacenter %s", pod.Name, sourceDatacenter))
+	request := nodeMgmtRequest{
+		endpoint:    "/api/v1/ops/node/rebuild",
+		queryParams: url.Values{"src_dc": []string{sourceDatacenter}},
+		method:      http.MethodPost,
+		timeout:     20 * time.Second,
+	}
+	if jobId, err := r.callNodeMgmtEndpoint(pod, request); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL rebuild on pod %v", pod.Name))
+		return "", err
+	} else {
+		return string(jobId), nil
+	}
+}
+
+func (r *defaultManagementApiFacade) GetJobDetails(pod *corev1.Pod, jobId string) (*httphelper.JobDetails, error) {
+	request := nodeMgmtRequest{
+		endpoint:    "/api/v0/ops/executor/job",
+		queryParams: url.Values{"job_id": []string{jobId}},
+		method:      http.MethodGet,
+	}
+	job := &httphelper.JobDetails{}
+	if body, err := r.callNodeMgmtEndpoint(pod, request); err != nil {
+		if reqErr, ok := err.(*httphelper.RequestError); ok && reqErr.NotFound() {
+			// The job is unknown, most likely because the node was restarted
+			return job, nil
+		}
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL get job %s details on pod %v", jobId, pod.Name))
+		return nil, err
+	} else if err := json.Unmarshal(body, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *defaultManagementApiFacade) GetTableDefinition(keyspaceName, tableName string) (*httphelper.TableDefinition, error) {
+	pods, err := r.fetchDatacenterPods()
+	if err != nil {
+		r.logger.Error(err, "Failed to fetch datacenter pods")
+		return nil, err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint:    "/api/v0/ops/tables/schema",
+		queryParams: url.Values{"keyspaceName": []string{keyspaceName}, "tableName": []string{tableName}},
+		method:      http.MethodGet,
+	}
+	for _, pod := range pods {
+		body, err := r.callNodeMgmtEndpoint(&pod, request)
+		if err != nil {
+			if reqErr, ok := err.(*httphelper.RequestError); ok && reqErr.NotFound() {
+				return nil, nil
+			}
+			r.logger.Error(err, fmt.Sprintf("Failed to CALL get table %s.%s definition on pod %v", keyspaceName, tableName, pod.Name))
+			continue
+		}
+
+		definition := &httphelper.TableDefinition{}
+		if err := json.Unmarshal(body, definition); err != nil {
+			return nil, err
+		}
+		return definition, nil
+	}
+	return nil, fmt.Errorf("CALL get table %s.%s definition failed on all datacenter %v pods", keyspaceName, tableName, r.dc.Name)
+}
+
+// endpointState is the gossip state of a node returned by the "GET /metadata/endpoints"
+// endpoint. httphelper.EndpointState does not include the schema version nor the location
+// of the node.
+type endpointState struct {
+	HostID     string `json:"HOST_ID"`
+	EndpointIP string `json:"ENDPOINT_IP"`
+	Datacenter string `json:"DC"`
+	Rack       string `json:"RACK"`
+	Status     string `json:"STATUS"`
+	IsAlive    string `json:"IS_ALIVE"`
+	Load       string `json:"LOAD"`
+	Schema     string `json:"SCHEMA"`
+}
+
+// isUpAndNormal returns true if the node is alive and in the NORMAL gossip state.
+func (e endpointState) isUpAndNormal() bool {
+	return e.IsAlive == "true" && strings.HasPrefix(e.Status, "NORMAL")
+}
+
+// getEndpoints returns the gossip state of the nodes of the cluster as seen by one of the
+// pods of the datacenter.
+func (r *defaultManagementApiFacade) getEndpoints(operation string) ([]endpointState, error) {
+	pods, err := r.fetchDatacenterPods()
+	if err != nil {
+		r.logger.Error(err, "Failed to fetch datacenter pods")
+		return nil, err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/metadata/endpoints",
+		method:   http.MethodGet,
+	}
+	for _, pod := range pods {
+		body, err := r.callNodeMgmtEndpoint(&pod, request)
+		if err != nil {
+			r.logger.Error(err, fmt.Sprintf("Failed to CALL get %s on pod %v", operation, pod.Name))
+			continue
+		}
+
+		endpoints := struct {
+			Entity []endpointState `json:"entity"`
+		}{}
+		if err := json.Unmarshal(body, &endpoints); err != nil {
+			return nil, err
+		}
+		return endpoints.Entity, nil
+	}
+	return nil, fmt.Errorf("CALL get %s failed on all datacenter %v pods", operation, r.dc.Name)
+}
+
+func (r *defaultManagementApiFacade) GetSchemaVersions() (map[string][]string, error) {
+	endpoints, err := r.getEndpoints("schema versions")
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string][]string)
+	for _, endpoint := range endpoints {
+		// Nodes that are down, or that left the cluster and remain in gossip for a few
+		// days, keep the schema version they had last.
+		if !endpoint.isUpAndNormal() || endpoint.Schema == "" {
+			continue
+		}
+		versions[endpoint.Schema] = append(versions[endpoint.Schema], endpoint.HostID)
+	}
+	return versions, nil
+}
+
+func (r *defaultManagementApiFacade) GetRingStatus() ([]NodeStatus, error) {
+	endpoints, err := r.getEndpoints("ring status")
+	if err != nil {
+		return nil, err
+	}
+	ownership, err := r.getOwnership()
+	if err != nil {
+		return nil, err
+	}
+	return parseRingStatus(endpoints, ownership)
+}
+
+// getOwnership returns the fraction of the token ring owned by each node of the cluster,
+// by endpoint, as seen by one of the pods of the datacenter. Nil is returned if the
+// management API does not provide the endpoint.
+func (r *defaultManagementApiFacade) getOwnership() (map[string]float64, error) {
+	pods, err := r.fetchDatacenterPods()
+	if err != nil {
+		r.logger.Error(err, "Failed to fetch datacenter pods")
+		return nil, err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/ops/node/ownership",
+		method:   http.MethodGet,
+	}
+	for _, pod := range pods {
+		body, err := r.callNodeMgmtEndpoint(&pod, request)
+		if err != nil {
+			if reqErr, ok := err.(*httphelper.RequestError); ok && reqErr.NotFound() {
+				r.logger.Info("The management API does not report the ownership of the nodes", "pod", pod.Name)
+				return nil, nil
+			}
+			r.logger.Error(err, fmt.Sprintf("Failed to CALL get ownership on pod %v", pod.Name))
+			continue
+		}
+
+		// The endpoints are formatted as Java InetAddresses, e.g., "/10.0.0.1"
+		entries := make(map[string]float64)
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return nil, err
+		}
+		ownership := make(map[string]float64, len(entries))
+		for endpoint, owned := range entries {
+			ownership[endpoint[strings.LastIndex(endpoint, "/")+1:]] = owned
+		}
+		return ownership, nil
+	}
+	return nil, fmt.Errorf("CALL get ownership failed on all datacenter %v pods", r.dc.Name)
+}
+
+// parseRingStatus converts the gossip state and the ownership of the nodes to NodeStatus,
+// sorted by datacenter, rack and endpoint. An error is returned if the load of a node
+// cannot be parsed.
+func parseRingStatus(endpoints []endpointState, ownership map[string]float64) ([]NodeStatus, error) {
+	statuses := make([]NodeStatus, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		status := NodeStatus{
+			HostId:     endpoint.HostID,
+			Endpoint:   endpoint.EndpointIP,
+			Datacenter: endpoint.Datacenter,
+			Rack:       endpoint.Rack,
+			Alive:      endpoint.IsAlive == "true",
+		}
+		// The gossip status is followed by the tokens of the node, e.g., "NORMAL,-9223372036854775808"
+		status.State = strings.SplitN(endpoint.Status, ",", 2)[0]
+		if endpoint.Load != "" {
+			load, err := strconv.ParseFloat(endpoint.Load, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the load of node %s: %v", endpoint.EndpointIP, err)
+			}
+			status.Load = load
+		}
+		if owned, found := ownership[endpoint.EndpointIP]; found {
+			status.Ownership = &owned
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Datacenter != statuses[j].Datacenter {
+			return statuses[i].Datacenter < statuses[j].Datacenter
+		}
+		if statuses[i].Rack != statuses[j].Rack {
+			return statuses[i].Rack < statuses[j].Rack
+		}
+		return statuses[i].Endpoint < statuses[j].Endpoint
+	})
+	return statuses, nil
+}
+
+func (r *defaultManagementApiFacade) TakeSnapshot(pod *corev1.Pod, snapshotName string, keyspaces []string) error {
+	r.logger.Info(fmt.Sprintf("Calling snapshot %s on pod %v", snapshotName, pod.Name))
+	body, err := json.Marshal(map[string]interface{}{
+		"snapshot_name": snapshotName,
+		"keyspaces":     keyspaces,
+	})
+	if err != nil {
+		return err
+	}
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/ops/node/snapshots",
+		method:   http.MethodPost,
+		timeout:  60 * time.Second,
+		body:     body,
+	}
+	if _, err := r.callNodeMgmtEndpoint(pod, request); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL snapshot on pod %v", pod.Name))
+		return err
+	}
+	return nil
+}
+
+// startJob calls the management API endpoint of an asynchronous operation on pod with a
+// JSON body, and returns the id of the job.
+func (r *defaultManagementApiFacade) startJob(pod *corev1.Pod, operation string, endpoint string, body map[string]interface{}) (string, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	request := nodeMgmtRequest{
+		endpoint: endpoint,
+		method:   http.MethodPost,
+		timeout:  20 * time.Second,
+		body:     jsonBody,
+	}
+	if jobId, err := r.callNodeMgmtEndpoint(pod, request); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL %s on pod %v", operation, pod.Name))
+		return "", err
+	} else {
+		return string(jobId), nil
+	}
+}
+
+// tablesRequest returns the body of the requests of the operations on the tables of a
+// keyspace. An empty keyspace selects all the keyspaces, and empty tables all the tables of
+// the keyspace.
+func tablesRequest(keyspace string, tables []string) map[string]interface{} {
+	if keyspace == "" {
+		keyspace = "ALL"
+	}
+	body := map[string]interface{}{"keyspace_name": keyspace}
+	if len(tables) > 0 {
+		body["tables"] = tables
+	}
+	return body
+}
+
+func (r *defaultManagementApiFacade) UpgradeSSTables(pod *corev1.Pod) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling upgradesstables on pod %v", pod.Name))
+	return r.startJob(pod, "upgradesstables", "/api/v1/ops/tables/sstables/upgrade", tablesRequest("", nil))
+}
+
+func (r *defaultManagementApiFacade) RepairKeyspace(pod *corev1.Pod, keyspace string) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling repair of keyspace %s on pod %v", keyspace, pod.Name))
+	body := map[string]interface{}{
+		"keyspace_name": keyspace,
+		"full":          true,
+	}
+	return r.startJob(pod, "repair", "/api/v1/ops/node/repair", body)
+}
+
+func (r *defaultManagementApiFacade) CleanupKeyspace(pod *corev1.Pod, keyspace string, tables []string) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling cleanup of keyspace %s on pod %v", keyspace, pod.Name))
+	return r.startJob(pod, "cleanup", "/api/v1/ops/keyspace/cleanup", tablesRequest(keyspace, tables))
+}
+
+func (r *defaultManagementApiFacade) FlushTables(pod *corev1.Pod, keyspace string, tables []string) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling flush of keyspace %s on pod %v", keyspace, pod.Name))
+	return r.startJob(pod, "flush", "/api/v1/ops/tables/flush", tablesRequest(keyspace, tables))
+}
+
+func (r *defaultManagementApiFacade) CompactTables(pod *corev1.Pod, keyspace string, tables []string) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling compaction of keyspace %s on pod %v", keyspace, pod.Name))
+	return r.startJob(pod, "compact", "/api/v1/ops/tables/compact", tablesRequest(keyspace, tables))
+}
+
+func (r *defaultManagementApiFacade) GarbageCollectTables(pod *corev1.Pod, keyspace string, tables []string) (string, error) {
+	r.logger.Info(fmt.Sprintf("Calling garbagecollect of keyspace %s on pod %v", keyspace, pod.Name))
+	return r.startJob(pod, "garbagecollect", "/api/v1/ops/tables/garbagecollect", tablesRequest(keyspace, tables))
+}
+
+func (r *defaultManagementApiFacade) DrainNode(pod *corev1.Pod) error {
+	if err := r.nodeMgmtClient.CallDrainEndpoint(pod); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL drain on pod %v", pod.Name))
+		return err
+	}
+	return nil
+}
+
+func (r *defaultManagementApiFacade) ClearSnapshots(pod *corev1.Pod, snapshotName string, keyspaces []string) error {
+	r.logger.Info(fmt.Sprintf("Clearing snapshot %s on pod %v", snapshotName, pod.Name))
+	queryParams := url.Values{}
+	if snapshotName != "" {
+		queryParams.Set("snapshotNames", snapshotName)
+	}
+	for _, keyspace := range keyspaces {
+		queryParams.Add("keyspace", keyspace)
+	}
+	request := nodeMgmtRequest{
+		endpoint:    "/api/v0/ops/node/snapshots",
+		queryParams: queryParams,
+		method:      http.MethodDelete,
+		timeout:     60 * time.Second,
+	}
+	if _, err := r.callNodeMgmtEndpoint(pod, request); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL clear snapshot on pod %v", pod.Name))
+		return err
+	}
+	return nil
+}
+
+func (r *defaultManagementApiFacade) SetLiveSetting(pod *corev1.Pod, setting string, value string) error {
+	liveSetting, found := liveSettings[setting]
+	if !found {
+		return fmt.Errorf("%s cannot be changed on a running node", setting)
+	}
+	r.logger.Info(fmt.Sprintf("Setting %s to %s on pod %v", setting, value, pod.Name))
+	request := nodeMgmtRequest{
+		endpoint:    liveSetting.endpoint,
+		queryParams: url.Values{"value": []string{value}},
+		method:      http.MethodPost,
+	}
+	if _, err := r.callNodeMgmtEndpoint(pod, request); err != nil {
+		r.logger.Error(err, fmt.Sprintf("Failed to CALL set %s on pod %v", setting, pod.Name))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,395 @@
+package cassandra
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeHttpClient struct {
+	requests   []*http.Request
+	statusCode int
+	body       string
+}
+
+func (c *fakeHttpClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	return &http.Response{
+		StatusCode: c.statusCode,
+		Body:       ioutil.NopCloser(strings.NewReader(c.body)),
+	}, nil
+}
+
+func newTestFacade(httpClient *fakeHttpClient) *defaultManagementApiFacade {
+	return &defaultManagementApiFacade{
+		ctx: context.Background(),
+		nodeMgmtClient: &httphelper.NodeMgmtClient{
+			Client:   httpClient,
+			Log:      logr.Discard(),
+			Protocol: "http",
+		},
+		logger: logr.Discard(),
+	}
+}
+
+var testPod = &corev1.Pod{
+	ObjectMeta: metav1.ObjectMeta{Name: "test-dc1-default-sts-0"},
+	Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+}
+
+func TestRebuildNode(t *testing.T) {
+	httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: "job-1"}
+	facade := newTestFacade(httpClient)
+
+	jobId, err := facade.RebuildNode(testPod, "dc1")
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", jobId)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+	assert.Equal(t, "http://10.0.0.1:8080/api/v1/ops/node/rebuild?src_dc=dc1", httpClient.requests[0].URL.String())
+}
+
+func TestRebuildNodeError(t *testing.T) {
+	httpClient := &fakeHttpClient{statusCode: http.StatusInternalServerError}
+	facade := newTestFacade(httpClient)
+
+	_, err := facade.RebuildNode(testPod, "dc1")
+	require.Error(t, err)
+	reqErr, ok := err.(*httphelper.RequestError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, reqErr.StatusCode)
+}
+
+func TestGetJobDetails(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   *httphelper.JobDetails
+	}{
+		{"completed", http.StatusOK, `{"id":"job-1","type":"rebuild","status":"COMPLETED"}`, &httphelper.JobDetails{Id: "job-1", Type: "rebuild", Status: JobStatusCompleted}},
+		{"failed", http.StatusOK, `{"id":"job-1","type":"rebuild","status":"ERROR","error":"boom"}`, &httphelper.JobDetails{Id: "job-1", Type: "rebuild", Status: JobStatusError, Error: "boom"}},
+		{"not found", http.StatusNotFound, "", &httphelper.JobDetails{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeHttpClient{statusCode: tt.statusCode, body: tt.body}
+			facade := newTestFacade(httpClient)
+
+			job, err := facade.GetJobDetails(testPod, "job-1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, job)
+			require.Len(t, httpClient.requests, 1)
+			assert.Equal(t, "http://10.0.0.1:8080/api/v0/ops/executor/job?job_id=job-1", httpClient.requests[0].URL.String())
+		})
+	}
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: "OK"}
+	facade := newTestFacade(httpClient)
+
+	err := facade.TakeSnapshot(testPod, "upgrade-1", []string{"ks1"})
+	require.NoError(t, err)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+	assert.Equal(t, "http://10.0.0.1:8080/api/v0/ops/node/snapshots", httpClient.requests[0].URL.String())
+	body, err := ioutil.ReadAll(httpClient.requests[0].Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"snapshot_name":"upgrade-1","keyspaces":["ks1"]}`, string(body))
+}
+
+func TestUpgradeSSTables(t *testing.T) {
+	httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: "job-1"}
+	facade := newTestFacade(httpClient)
+
+	jobId, err := facade.UpgradeSSTables(testPod)
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", jobId)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+	assert.Equal(t, "http://10.0.0.1:8080/api/v1/ops/tables/sstables/upgrade", httpClient.requests[0].URL.String())
+	body, err := ioutil.ReadAll(httpClient.requests[0].Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"keyspace_name":"ALL"}`, string(body))
+}
+
+func TestRepairKeyspace(t *testing.T) {
+	httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: "job-1"}
+	facade := newTestFacade(httpClient)
+
+	jobId, err := facade.RepairKeyspace(testPod, "system_auth")
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", jobId)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+	assert.Equal(t, "http://10.0.0.1:8080/api/v1/ops/node/repair", httpClient.requests[0].URL.String())
+	body, err := ioutil.ReadAll(httpClient.requests[0].Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"keyspace_name":"system_auth","full":true}`, string(body))
+}
+
+func TestGetSchemaVersions(t *testing.T) {
+	pod := testPod.DeepCopy()
+	pod.Namespace = "default"
+	pod.Labels = map[string]string{cassdcapi.DatacenterLabel: "dc1"}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "cassandra", Ready: true}}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected map[string][]string
+	}{
+		{
+			"agreement",
+			`{"entity":[` +
+				`{"HOST_ID":"host-1","IS_ALIVE":"true","STATUS":"NORMAL,-1","SCHEMA":"schema-1"},` +
+				`{"HOST_ID":"host-2","IS_ALIVE":"true","STATUS":"NORMAL,1","SCHEMA":"schema-1"}]}`,
+			map[string][]string{"schema-1": {"host-1", "host-2"}},
+		},
+		{
+			"disagreement",
+			`{"entity":[` +
+				`{"HOST_ID":"host-1","IS_ALIVE":"true","STATUS":"NORMAL,-1","SCHEMA":"schema-1"},` +
+				`{"HOST_ID":"host-2","IS_ALIVE":"true","STATUS":"NORMAL,1","SCHEMA":"schema-2"}]}`,
+			map[string][]string{"schema-1": {"host-1"}, "schema-2": {"host-2"}},
+		},
+		{
+			"left, down and unknown schema nodes",
+			`{"entity":[` +
+				`{"HOST_ID":"host-1","IS_ALIVE":"true","STATUS":"NORMAL,-1","SCHEMA":"schema-1"},` +
+				`{"HOST_ID":"host-2","IS_ALIVE":"false","STATUS":"LEFT,1,1650000000000","SCHEMA":"schema-0"},` +
+				`{"HOST_ID":"host-3","IS_ALIVE":"false","STATUS":"NORMAL,2","SCHEMA":"schema-0"},` +
+				`{"HOST_ID":"host-4","IS_ALIVE":"true","STATUS":"NORMAL,3","SCHEMA":""}]}`,
+			map[string][]string{"schema-1": {"host-1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: tt.body}
+			facade := newTestFacade(httpClient)
+			facade.dc = &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"}}
+			facade.k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+
+			versions, err := facade.GetSchemaVersions()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, versions)
+			assert.Equal(t, "http://10.0.0.1:8080/api/v0/metadata/endpoints", httpClient.requests[0].URL.String())
+		})
+	}
+}
+
+func TestGetTableDefinition(t *testing.T) {
+	pod := testPod.DeepCopy()
+	pod.Namespace = "default"
+	pod.Labels = map[string]string{cassdcapi.DatacenterLabel: "dc1"}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "cassandra", Ready: true}}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   *httphelper.TableDefinition
+	}{
+		{
+			"existing table",
+			http.StatusOK,
+			`{"keyspace_name":"ks","table_name":"t","columns":[{"name":"id","type":"uuid","kind":"PARTITION_KEY","position":0},{"name":"value","type":"text","kind":"REGULAR","position":0}]}`,
+			httphelper.NewTableDefinition("ks", "t",
+				httphelper.NewPartitionKeyColumn("id", "uuid", 0),
+				httphelper.NewRegularColumn("value", "text"),
+			),
+		},
+		{
+			"missing table",
+			http.StatusNotFound,
+			"",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeHttpClient{statusCode: tt.statusCode, body: tt.body}
+			facade := newTestFacade(httpClient)
+			facade.dc = &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"}}
+			facade.k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+
+			definition, err := facade.GetTableDefinition("ks", "t")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, definition)
+			assert.Equal(t, "http://10.0.0.1:8080/api/v0/ops/tables/schema?keyspaceName=ks&tableName=t", httpClient.requests[0].URL.String())
+		})
+	}
+}
+
+func TestTableOperations(t *testing.T) {
+	tests := []struct {
+		name         string
+		start        func(facade *defaultManagementApiFacade) (string, error)
+		expectedUrl  string
+		expectedBody string
+	}{
+		{
+			"cleanup",
+			func(facade *defaultManagementApiFacade) (string, error) {
+				return facade.CleanupKeyspace(testPod, "ks1", nil)
+			},
+			"http://10.0.0.1:8080/api/v1/ops/keyspace/cleanup",
+			`{"keyspace_name":"ks1"}`,
+		},
+		{
+			"flush",
+			func(facade *defaultManagementApiFacade) (string, error) {
+				return facade.FlushTables(testPod, "ks1", []string{"table1", "table2"})
+			},
+			"http://10.0.0.1:8080/api/v1/ops/tables/flush",
+			`{"keyspace_name":"ks1","tables":["table1","table2"]}`,
+		},
+		{
+			"compact",
+			func(facade *defaultManagementApiFacade) (string, error) {
+				return facade.CompactTables(testPod, "", nil)
+			},
+			"http://10.0.0.1:8080/api/v1/ops/tables/compact",
+			`{"keyspace_name":"ALL"}`,
+		},
+		{
+			"garbagecollect",
+			func(facade *defaultManagementApiFacade) (string, error) {
+				return facade.GarbageCollectTables(testPod, "ks1", []string{"table1"})
+			},
+			"http://10.0.0.1:8080/api/v1/ops/tables/garbagecollect",
+			`{"keyspace_name":"ks1","tables":["table1"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: "job-1"}
+			facade := newTestFacade(httpClient)
+
+			jobId, err := tt.start(facade)
+			require.NoError(t, err)
+			assert.Equal(t, "job-1", jobId)
+			require.Len(t, httpClient.requests, 1)
+			assert.Equal(t, http.MethodPost, httpClient.requests[0].Method)
+			assert.Equal(t, tt.expectedUrl, httpClient.requests[0].URL.String())
+			body, err := ioutil.ReadAll(httpClient.requests[0].Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+func TestClearSnapshots(t *testing.T) {
+	httpClient := &fakeHttpClient{statusCode: http.StatusOK, body: "OK"}
+	facade := newTestFacade(httpClient)
+
+	err := facade.ClearSnapshots(testPod, "upgrade-1", []string{"ks1", "ks2"})
+	require.NoError(t, err)
+	require.Len(t, httpClient.requests, 1)
+	assert.Equal(t, http.MethodDelete, httpClient.requests[0].Method)
+	assert.Equal(t, "http://10.0.0.1:8080/api/v0/ops/node/snapshots?keyspace=ks1&keyspace=ks2&snapshotNames=upgrade-1", httpClient.requests[0].URL.String())
+}
+
+func TestGetDatacenterPods(t *testing.T) {
+	newPod := func(namespace, name string, ready bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "cassandra", Ready: ready}},
+			},
+		}
+	}
+
+	facade := newTestFacade(&fakeHttpClient{})
+	facade.dc = &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"}}
+	facade.k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newPod("default", "dc1-pod-0", true),
+		newPod("default", "dc1-pod-1", false),
+		newPod("default", "dc1-pod-2", true),
+		newPod("other", "dc1-pod-0", true),
+	).Build()
+
+	pods, err := facade.GetDatacenterPods()
+	require.NoError(t, err)
+	require.Len(t, pods, 2)
+	assert.Equal(t, "dc1-pod-0", pods[0].Name)
+	assert.Equal(t, "default", pods[0].Namespace)
+	assert.Equal(t, "dc1-pod-2", pods[1].Name)
+}
+
+func TestGetOwnership(t *testing.T) {
+	pod := testPod.DeepCopy()
+	pod.Namespace = "default"
+	pod.Labels = map[string]string{cassdcapi.DatacenterLabel: "dc1"}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "cassandra", Ready: true}}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   map[string]float64
+	}{
+		{
+			"ownership",
+			http.StatusOK,
+			`{"/10.0.0.1":0.25,"pod-1/10.0.0.2":0.75}`,
+			map[string]float64{"10.0.0.1": 0.25, "10.0.0.2": 0.75},
+		},
+		{
+			"unsupported endpoint",
+			http.StatusNotFound,
+			"",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &fakeHttpClient{statusCode: tt.statusCode, body: tt.body}
+			facade := newTestFacade(httpClient)
+			facade.dc = &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "dc1"}}
+			facade.k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+
+			ownership, err := facade.getOwnership()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ownership)
+			assert.Equal(t, "http://10.0.0.1:8080/api/v0/ops/node/ownership", httpClient.requests[0].URL.String())
+		})
+	}
+}
+
+func TestParseRingStatus(t *testing.T) {
+	endpoints := []endpointState{
+		{HostID: "host-3", EndpointIP: "10.0.0.3", Datacenter: "dc2", Rack: "rack1", Status: "LEAVING,-3074457345618258603", IsAlive: "true", Load: "2048.0"},
+		{HostID: "host-2", EndpointIP: "10.0.0.2", Datacenter: "dc1", Rack: "rack1", Status: "NORMAL,3074457345618258602", IsAlive: "false", Load: "1024.0"},
+		{HostID: "host-1", EndpointIP: "10.0.0.1", Datacenter: "dc1", Rack: "rack1", Status: "NORMAL,-9223372036854775808", IsAlive: "true"},
+	}
+	quarter, half := 0.25, 0.5
+	statuses, err := parseRingStatus(endpoints, map[string]float64{"10.0.0.1": quarter, "10.0.0.2": half})
+	require.NoError(t, err)
+	assert.Equal(t, []NodeStatus{
+		{HostId: "host-1", Endpoint: "10.0.0.1", Datacenter: "dc1", Rack: "rack1", State: "NORMAL", Alive: true, Ownership: &quarter},
+		{HostId: "host-2", Endpoint: "10.0.0.2", Datacenter: "dc1", Rack: "rack1", State: "NORMAL", Load: 1024, Ownership: &half},
+		{HostId: "host-3", Endpoint: "10.0.0.3", Datacenter: "dc2", Rack: "rack1", State: "LEAVING", Alive: true, Load: 2048},
+	}, statuses)
+
+	endpoints[0].Load = "2 KiB"
+	_, err = parseRingStatus(endpoints, nil)
+	assert.EqualError(t, err, `failed to parse the load of node 10.0.0.3: strconv.ParseFloat: parsing "2 KiB": invalid syntax`)
+}
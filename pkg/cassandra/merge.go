@@ -0,0 +1,249 @@
+package cassandra
+
+import (
+	"reflect"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The functions below merge cluster-level settings with dc-level settings. The dc-level
+// settings take precedence field by field. They assume that their arguments are not shared
+// with other objects, i.e., they may return or modify them instead of copying them.
+
+func mergeCassandraConfig(cluster, dc *api.CassandraConfig) *api.CassandraConfig {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	merged := &api.CassandraConfig{
+		CassandraYaml:    mergeCassandraYaml(cluster.CassandraYaml, dc.CassandraYaml),
+		JvmOptions:       api.MergeJvmOptions(cluster.JvmOptions, dc.JvmOptions),
+		TraceProbability: dc.TraceProbability,
+	}
+	if merged.TraceProbability == nil {
+		merged.TraceProbability = cluster.TraceProbability
+	}
+	return merged
+}
+
+// mergeCassandraYaml sets each property of dc that is not set to the value of cluster.
+func mergeCassandraYaml(cluster, dc *api.CassandraYaml) *api.CassandraYaml {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	utils.MergeFields(cluster, dc)
+	return dc
+}
+
+// mergeConfigFiles sets each file of dc that is not set to the file of cluster.
+func mergeConfigFiles(cluster, dc *api.ConfigFiles) *api.ConfigFiles {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	utils.MergeFields(cluster, dc)
+	return dc
+}
+
+func mergeResources(cluster, dc *corev1.ResourceRequirements) *corev1.ResourceRequirements {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	return &corev1.ResourceRequirements{
+		Limits:   mergeResourceList(cluster.Limits, dc.Limits),
+		Requests: mergeResourceList(cluster.Requests, dc.Requests),
+	}
+}
+
+func mergeResourceList(cluster, dc corev1.ResourceList) corev1.ResourceList {
+	if len(cluster) == 0 {
+		return dc
+	}
+	if len(dc) == 0 {
+		return cluster
+	}
+	for name, quantity := range dc {
+		cluster[name] = quantity
+	}
+	return cluster
+}
+
+// mergeLabels merges the dc labels into the cluster labels, the dc values taking
+// precedence.
+func mergeLabels(cluster, dc map[string]string) map[string]string {
+	if len(cluster) == 0 {
+		return dc
+	}
+	if len(dc) == 0 {
+		return cluster
+	}
+	for key, value := range dc {
+		cluster[key] = value
+	}
+	return cluster
+}
+
+// mergeNetworking merges the NodePort settings port by port. Host networking is enabled if
+// it is enabled at either level, it can be disabled for a dc with the
+// networking.hostNetwork unset path.
+func mergeNetworking(cluster, dc *cassdcapi.NetworkingConfig) *cassdcapi.NetworkingConfig {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	merged := &cassdcapi.NetworkingConfig{
+		NodePort:    dc.NodePort,
+		HostNetwork: cluster.HostNetwork || dc.HostNetwork,
+	}
+	if cluster.NodePort != nil && dc.NodePort != nil {
+		merged.NodePort = &cassdcapi.NodePortConfig{
+			Native:       mergeInt(cluster.NodePort.Native, dc.NodePort.Native),
+			NativeSSL:    mergeInt(cluster.NodePort.NativeSSL, dc.NodePort.NativeSSL),
+			Internode:    mergeInt(cluster.NodePort.Internode, dc.NodePort.Internode),
+			InternodeSSL: mergeInt(cluster.NodePort.InternodeSSL, dc.NodePort.InternodeSSL),
+		}
+	} else if dc.NodePort == nil {
+		merged.NodePort = cluster.NodePort
+	}
+	return merged
+}
+
+func mergeInt(cluster, dc int) int {
+	if dc == 0 {
+		return cluster
+	}
+	return dc
+}
+
+// mergeStorageConfig merges the data volume claim specs field by field. The additional
+// volumes are merged by name, a dc volume replaces the cluster volume with the same name.
+func mergeStorageConfig(cluster, dc *cassdcapi.StorageConfig) *cassdcapi.StorageConfig {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	merged := &cassdcapi.StorageConfig{
+		CassandraDataVolumeClaimSpec: mergeVolumeClaimSpec(cluster.CassandraDataVolumeClaimSpec, dc.CassandraDataVolumeClaimSpec),
+	}
+	for _, volume := range cluster.AdditionalVolumes {
+		if findAdditionalVolume(dc.AdditionalVolumes, volume.Name) == nil {
+			merged.AdditionalVolumes = append(merged.AdditionalVolumes, volume)
+		}
+	}
+	merged.AdditionalVolumes = append(merged.AdditionalVolumes, dc.AdditionalVolumes...)
+	return merged
+}
+
+func mergeVolumeClaimSpec(cluster, dc *corev1.PersistentVolumeClaimSpec) *corev1.PersistentVolumeClaimSpec {
+	if cluster == nil {
+		return dc
+	}
+	if dc == nil {
+		return cluster
+	}
+	if len(dc.AccessModes) == 0 {
+		dc.AccessModes = cluster.AccessModes
+	}
+	if dc.Selector == nil {
+		dc.Selector = cluster.Selector
+	}
+	if dc.StorageClassName == nil {
+		dc.StorageClassName = cluster.StorageClassName
+	}
+	if dc.VolumeMode == nil {
+		dc.VolumeMode = cluster.VolumeMode
+	}
+	if dc.DataSource == nil {
+		dc.DataSource = cluster.DataSource
+	}
+	dc.Resources.Limits = mergeResourceList(cluster.Resources.Limits, dc.Resources.Limits)
+	dc.Resources.Requests = mergeResourceList(cluster.Resources.Requests, dc.Resources.Requests)
+	return dc
+}
+
+func findAdditionalVolume(volumes cassdcapi.AdditionalVolumesSlice, name string) *cassdcapi.AdditionalVolumes {
+	for i := range volumes {
+		if volumes[i].Name == name {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
+
+// unsetClusterSettings removes from clusterTemplate the settings identified by paths, see
+// api.CassandraDatacenterTemplate.Unset for the supported paths. Unknown paths are ignored,
+// they are rejected by the validating webhook.
+func unsetClusterSettings(clusterTemplate *api.CassandraClusterTemplate, paths []string) {
+	for _, path := range paths {
+		switch {
+		case strings.HasPrefix(path, api.UnsetCassandraYamlPrefix):
+			unsetCassandraYamlProperty(clusterTemplate.CassandraConfig, strings.TrimPrefix(path, api.UnsetCassandraYamlPrefix))
+		case path == api.UnsetHeapSize, path == api.UnsetHeapNewGenSize, path == api.UnsetAdditionalJvmOptions,
+			strings.HasPrefix(path, api.UnsetAdditionalJvmOptions+"."):
+			if clusterTemplate.CassandraConfig != nil {
+				clusterTemplate.CassandraConfig.JvmOptions.Unset(path)
+			}
+		case strings.HasPrefix(path, api.UnsetResourceLimitsPrefix):
+			if clusterTemplate.Resources != nil {
+				delete(clusterTemplate.Resources.Limits, corev1.ResourceName(strings.TrimPrefix(path, api.UnsetResourceLimitsPrefix)))
+			}
+		case strings.HasPrefix(path, api.UnsetResourceRequestsPrefix):
+			if clusterTemplate.Resources != nil {
+				delete(clusterTemplate.Resources.Requests, corev1.ResourceName(strings.TrimPrefix(path, api.UnsetResourceRequestsPrefix)))
+			}
+		case path == api.UnsetNodePort:
+			if clusterTemplate.Networking != nil {
+				clusterTemplate.Networking.NodePort = nil
+			}
+		case path == api.UnsetHostNetwork:
+			if clusterTemplate.Networking != nil {
+				clusterTemplate.Networking.HostNetwork = false
+			}
+		case strings.HasPrefix(path, api.UnsetAdditionalVolumesPrefix):
+			if clusterTemplate.StorageConfig != nil {
+				storageConfig := clusterTemplate.StorageConfig
+				name := strings.TrimPrefix(path, api.UnsetAdditionalVolumesPrefix)
+				volumes := make(cassdcapi.AdditionalVolumesSlice, 0, len(storageConfig.AdditionalVolumes))
+				for _, volume := range storageConfig.AdditionalVolumes {
+					if volume.Name != name {
+						volumes = append(volumes, volume)
+					}
+				}
+				storageConfig.AdditionalVolumes = volumes
+			}
+		}
+	}
+}
+
+// unsetCassandraYamlProperty sets the property of config whose cassandra.yaml name is
+// property to nil.
+func unsetCassandraYamlProperty(config *api.CassandraConfig, property string) {
+	if config == nil || config.CassandraYaml == nil {
+		return
+	}
+	value := reflect.ValueOf(config.CassandraYaml).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if api.CassandraYamlPropertyName(value.Type().Field(i)) == property {
+			value.Field(i).Set(reflect.Zero(value.Field(i).Type()))
+			return
+		}
+	}
+}
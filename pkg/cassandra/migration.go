@@ -0,0 +1,283 @@
+package cassandra
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
+)
+
+// MigrationsTable is the table of an operator-owned keyspace that records the migrations
+// applied to the keyspace.
+const MigrationsTable = "schema_migrations"
+
+// Migration is a versioned change to the schema of an operator-owned keyspace. A migration
+// is recorded in the MigrationsTable of the keyspace once it is applied, and is never
+// applied again. Its statements can be run more than once if the operator stops before the
+// migration is recorded, they should be idempotent, e.g., use IF NOT EXISTS.
+type Migration struct {
+	Version     int
+	Description string
+
+	// Tables are the tables created by the migration, with the management API. Tables that
+	// already exist are left as is.
+	Tables []*httphelper.TableDefinition
+
+	// Statements are the CQL statements of the migration, run in order after the Tables are
+	// created.
+	Statements []string
+}
+
+// Schema is the schema of an operator-owned keyspace, as a list of migrations. The
+// keyspace must exist before its schema is migrated.
+type Schema struct {
+	Keyspace   string
+	Migrations []Migration
+
+	// Tables are the definitions of the tables of the keyspace once all the migrations are
+	// applied. They are compared with the live definitions after the migrations.
+	Tables []*httphelper.TableDefinition
+}
+
+// SchemaDisagreementError is returned by MigrateSchema when the nodes do not agree on the
+// schema. The migration should be retried later, once the schema has propagated.
+type SchemaDisagreementError struct {
+	Versions map[string][]string
+}
+
+func (e *SchemaDisagreementError) Error() string {
+	return fmt.Sprintf("nodes do not agree on the schema: %v", e.Versions)
+}
+
+// ColumnDiff is a difference between the expected and the live definition of a column.
+// Expected is nil if the column should not exist, and Actual is nil if the column does not
+// exist.
+type ColumnDiff struct {
+	Column   string
+	Expected *httphelper.ColumnDefinition
+	Actual   *httphelper.ColumnDefinition
+}
+
+func (d ColumnDiff) String() string {
+	switch {
+	case d.Actual == nil:
+		return fmt.Sprintf("column %s is missing", d.Column)
+	case d.Expected == nil:
+		return fmt.Sprintf("column %s is unexpected", d.Column)
+	default:
+		return fmt.Sprintf("column %s is %s, expected %s", d.Column, describeColumn(d.Actual), describeColumn(d.Expected))
+	}
+}
+
+// MigrateSchema applies the migrations of schema that are not recorded in the
+// MigrationsTable of its keyspace, in version order, then checks the live definitions of
+// its tables. The MigrationsTable is created if it does not exist. The nodes must agree on
+// the schema before each schema change; if they do not, a *SchemaDisagreementError is
+// returned right away rather than waiting for the schema to propagate. The migrations that
+// were recorded are not applied again when MigrateSchema is called anew.
+//
+// Regular and static columns that are missing from the live definitions are added. Other
+// differences are returned as an error; unexpected columns are ignored.
+func MigrateSchema(managementApi ManagementApiFacade, cqlClient CqlClient, schema *Schema, logger logr.Logger) error {
+	logger = logger.WithValues("keyspace", schema.Keyspace)
+
+	if err := checkSchemaAgreement(managementApi); err != nil {
+		return err
+	}
+
+	tables, err := managementApi.ListTables(schema.Keyspace)
+	if err != nil {
+		return err
+	}
+	if !utils.SliceContains(tables, MigrationsTable) {
+		logger.Info("Creating the schema migrations table")
+		if err := managementApi.CreateTable(migrationsTableDefinition(schema.Keyspace)); err != nil {
+			return err
+		}
+		if err := checkSchemaAgreement(managementApi); err != nil {
+			return err
+		}
+	}
+
+	applied, err := cqlClient.ListSchemaMigrations(schema.Keyspace)
+	if err != nil {
+		return err
+	}
+
+	migrations := append([]Migration{}, schema.Migrations...)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for _, migration := range migrations {
+		if containsVersion(applied, migration.Version) {
+			continue
+		}
+		logger.Info("Applying schema migration", "version", migration.Version, "description", migration.Description)
+		if err := applyMigration(managementApi, cqlClient, tables, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d of keyspace %s: %v", migration.Version, schema.Keyspace, err)
+		}
+		if err := cqlClient.RecordSchemaMigration(schema.Keyspace, migration.Version, migration.Description); err != nil {
+			return err
+		}
+		if err := checkSchemaAgreement(managementApi); err != nil {
+			return err
+		}
+	}
+
+	return checkTables(managementApi, cqlClient, schema, logger)
+}
+
+func applyMigration(managementApi ManagementApiFacade, cqlClient CqlClient, tables []string, migration Migration) error {
+	for _, table := range migration.Tables {
+		if utils.SliceContains(tables, table.TableName) {
+			continue
+		}
+		if err := managementApi.CreateTable(table); err != nil {
+			return err
+		}
+	}
+	for _, statement := range migration.Statements {
+		if err := cqlClient.ExecuteSchemaChange(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTables compares the live definitions of the tables of schema with the expected ones,
+// and adds the missing regular and static columns.
+func checkTables(managementApi ManagementApiFacade, cqlClient CqlClient, schema *Schema, logger logr.Logger) error {
+	mismatches := make([]string, 0)
+	for _, expected := range schema.Tables {
+		actual, err := managementApi.GetTableDefinition(schema.Keyspace, expected.TableName)
+		if err != nil {
+			return err
+		}
+		if actual == nil {
+			mismatches = append(mismatches, fmt.Sprintf("table %s is missing", expected.TableName))
+			continue
+		}
+		for _, diff := range DiffTable(expected, actual) {
+			switch {
+			case diff.Expected == nil:
+				logger.Info("Ignoring unexpected column", "table", expected.TableName, "column", diff.Column)
+			case diff.Actual == nil && (diff.Expected.Kind == httphelper.ColumnKindRegular || diff.Expected.Kind == httphelper.ColumnKindStatic):
+				logger.Info("Adding missing column", "table", expected.TableName, "column", diff.Column)
+				if err := checkSchemaAgreement(managementApi); err != nil {
+					return err
+				}
+				if err := cqlClient.ExecuteSchemaChange(addColumnCql(expected, diff.Expected)); err != nil {
+					return err
+				}
+			default:
+				mismatches = append(mismatches, fmt.Sprintf("table %s: %s", expected.TableName, diff))
+			}
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("the schema of keyspace %s does not match its migrations: %s", schema.Keyspace, strings.Join(mismatches, "; "))
+	}
+	return checkSchemaAgreement(managementApi)
+}
+
+// DiffTable returns the differences between the columns of the expected and the actual
+// definitions of a table, sorted by column name. The options of the tables are not
+// compared.
+func DiffTable(expected, actual *httphelper.TableDefinition) []ColumnDiff {
+	actualColumns := make(map[string]*httphelper.ColumnDefinition)
+	for _, column := range actual.Columns {
+		actualColumns[column.Name] = column
+	}
+
+	diffs := make([]ColumnDiff, 0)
+	for _, column := range expected.Columns {
+		actualColumn, found := actualColumns[column.Name]
+		delete(actualColumns, column.Name)
+		if !found {
+			diffs = append(diffs, ColumnDiff{Column: column.Name, Expected: column})
+		} else if !sameColumn(column, actualColumn) {
+			diffs = append(diffs, ColumnDiff{Column: column.Name, Expected: column, Actual: actualColumn})
+		}
+	}
+	for name, column := range actualColumns {
+		diffs = append(diffs, ColumnDiff{Column: name, Actual: column})
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Column < diffs[j].Column
+	})
+	return diffs
+}
+
+func sameColumn(expected, actual *httphelper.ColumnDefinition) bool {
+	if !strings.EqualFold(expected.Type, actual.Type) || expected.Kind != actual.Kind {
+		return false
+	}
+	switch expected.Kind {
+	case httphelper.ColumnKindPartitionKey:
+		return expected.Position == actual.Position
+	case httphelper.ColumnKindClusteringColumn:
+		return expected.Position == actual.Position && clusteringOrder(expected) == clusteringOrder(actual)
+	default:
+		return true
+	}
+}
+
+func clusteringOrder(column *httphelper.ColumnDefinition) httphelper.ClusteringOrder {
+	if column.Order == "" {
+		return httphelper.ClusteringOrderAsc
+	}
+	return column.Order
+}
+
+func describeColumn(column *httphelper.ColumnDefinition) string {
+	switch column.Kind {
+	case httphelper.ColumnKindPartitionKey:
+		return fmt.Sprintf("%s %s %d", column.Type, column.Kind, column.Position)
+	case httphelper.ColumnKindClusteringColumn:
+		return fmt.Sprintf("%s %s %d %s", column.Type, column.Kind, column.Position, clusteringOrder(column))
+	default:
+		return fmt.Sprintf("%s %s", column.Type, column.Kind)
+	}
+}
+
+func addColumnCql(table *httphelper.TableDefinition, column *httphelper.ColumnDefinition) string {
+	cql := fmt.Sprintf("ALTER TABLE %s.%s ADD %s %s",
+		quoteIdentifier(table.KeyspaceName), quoteIdentifier(table.TableName), quoteIdentifier(column.Name), column.Type)
+	if column.Kind == httphelper.ColumnKindStatic {
+		cql += " static"
+	}
+	return cql
+}
+
+func migrationsTableDefinition(keyspace string) *httphelper.TableDefinition {
+	return httphelper.NewTableDefinition(keyspace, MigrationsTable,
+		httphelper.NewPartitionKeyColumn("version", "int", 0),
+		httphelper.NewRegularColumn("description", "text"),
+		httphelper.NewRegularColumn("applied_at", "timestamp"),
+	)
+}
+
+func containsVersion(versions []int, version int) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSchemaAgreement returns a *SchemaDisagreementError if the nodes do not agree on the
+// schema.
+func checkSchemaAgreement(managementApi ManagementApiFacade) error {
+	versions, err := managementApi.GetSchemaVersions()
+	if err != nil {
+		return err
+	}
+	if len(versions) > 1 {
+		return &SchemaDisagreementError{Versions: versions}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTable(t *testing.T) {
+	expected := httphelper.NewTableDefinition("ks", "t",
+		httphelper.NewPartitionKeyColumn("id", "uuid", 0),
+		httphelper.NewClusteringColumn("ts", "timestamp", 0, ""),
+		httphelper.NewRegularColumn("value", "text"),
+		httphelper.NewStaticColumn("owner", "text"),
+	)
+
+	tests := []struct {
+		name     string
+		actual   *httphelper.TableDefinition
+		expected []ColumnDiff
+	}{
+		{
+			name: "same columns",
+			actual: httphelper.NewTableDefinition("ks", "t",
+				httphelper.NewPartitionKeyColumn("id", "uuid", 0),
+				httphelper.NewClusteringColumn("ts", "timestamp", 0, httphelper.ClusteringOrderAsc),
+				httphelper.NewRegularColumn("value", "text"),
+				httphelper.NewStaticColumn("owner", "text"),
+			),
+			expected: []ColumnDiff{},
+		},
+		{
+			name: "missing, unexpected and mismatched columns",
+			actual: httphelper.NewTableDefinition("ks", "t",
+				httphelper.NewPartitionKeyColumn("id", "uuid", 0),
+				httphelper.NewClusteringColumn("ts", "timestamp", 0, httphelper.ClusteringOrderDesc),
+				httphelper.NewRegularColumn("extra", "int"),
+				httphelper.NewRegularColumn("owner", "text"),
+			),
+			expected: []ColumnDiff{
+				{Column: "extra", Actual: httphelper.NewRegularColumn("extra", "int")},
+				{Column: "owner", Expected: httphelper.NewStaticColumn("owner", "text"), Actual: httphelper.NewRegularColumn("owner", "text")},
+				{Column: "ts", Expected: httphelper.NewClusteringColumn("ts", "timestamp", 0, ""), Actual: httphelper.NewClusteringColumn("ts", "timestamp", 0, httphelper.ClusteringOrderDesc)},
+				{Column: "value", Expected: httphelper.NewRegularColumn("value", "text")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DiffTable(expected, tt.actual))
+		})
+	}
+}
+
+func TestAddColumnCql(t *testing.T) {
+	table := httphelper.NewTableDefinition("ks", "t")
+	assert.Equal(t, `ALTER TABLE "ks"."t" ADD "value" text`, addColumnCql(table, httphelper.NewRegularColumn("value", "text")))
+	assert.Equal(t, `ALTER TABLE "ks"."t" ADD "owner" text static`, addColumnCql(table, httphelper.NewStaticColumn("owner", "text")))
+}
@@ -0,0 +1,151 @@
+package cassandra
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// renamedProperty is the name that Cassandra 4.1 gives to a cassandra.yaml property.
+type renamedProperty struct {
+	name string
+
+	// unit is appended to the value of the property, e.g., ms for a duration in
+	// milliseconds. It is empty for the properties whose value has no unit.
+	unit string
+}
+
+// renamedProperties4_1 maps the cassandra.yaml properties that were renamed in Cassandra 4.1
+// to their new name. Their values are durations or data sizes with a unit, e.g., 100ms or
+// 16MiB, instead of numbers in the unit of the old name. The properties that are not in
+// this table keep their name, which 4.1 still accepts if it was deprecated.
+var renamedProperties4_1 = map[string]renamedProperty{
+	"max_hint_window_in_ms":                      {"max_hint_window", "ms"},
+	"hints_flush_period_in_ms":                   {"hints_flush_period", "ms"},
+	"commitlog_sync_period_in_ms":                {"commitlog_sync_period", "ms"},
+	"periodic_commitlog_sync_lag_block_in_ms":    {"periodic_commitlog_sync_lag_block", "ms"},
+	"cdc_free_space_check_interval_ms":           {"cdc_free_space_check_interval", "ms"},
+	"native_transport_idle_timeout_in_ms":        {"native_transport_idle_timeout", "ms"},
+	"internode_tcp_connect_timeout_in_ms":        {"internode_tcp_connect_timeout", "ms"},
+	"internode_tcp_user_timeout_in_ms":           {"internode_tcp_user_timeout", "ms"},
+	"internode_streaming_tcp_user_timeout_in_ms": {"internode_streaming_tcp_user_timeout", "ms"},
+	"read_request_timeout_in_ms":                 {"read_request_timeout", "ms"},
+	"range_request_timeout_in_ms":                {"range_request_timeout", "ms"},
+	"write_request_timeout_in_ms":                {"write_request_timeout", "ms"},
+	"counter_write_request_timeout_in_ms":        {"counter_write_request_timeout", "ms"},
+	"cas_contention_timeout_in_ms":               {"cas_contention_timeout", "ms"},
+	"truncate_request_timeout_in_ms":             {"truncate_request_timeout", "ms"},
+	"request_timeout_in_ms":                      {"request_timeout", "ms"},
+	"slow_query_log_timeout_in_ms":               {"slow_query_log_timeout", "ms"},
+	"user_defined_function_warn_timeout":         {"user_defined_functions_warn_timeout", "ms"},
+	"user_defined_function_fail_timeout":         {"user_defined_functions_fail_timeout", "ms"},
+	"cache_load_timeout_seconds":                 {"cache_load_timeout", "s"},
+	"roles_validity_in_ms":                       {"roles_validity", "ms"},
+	"roles_update_interval_in_ms":                {"roles_update_interval", "ms"},
+	"permissions_validity_in_ms":                 {"permissions_validity", "ms"},
+	"permissions_update_interval_in_ms":          {"permissions_update_interval", "ms"},
+	"credentials_validity_in_ms":                 {"credentials_validity", "ms"},
+	"credentials_update_interval_in_ms":          {"credentials_update_interval", "ms"},
+
+	"hinted_handoff_throttle_in_kb":                                          {"hinted_handoff_throttle", "KiB"},
+	"max_hints_file_size_in_mb":                                              {"max_hints_file_size", "MiB"},
+	"batchlog_replay_throttle_in_kb":                                         {"batchlog_replay_throttle", "KiB"},
+	"prepared_statements_cache_size_mb":                                      {"prepared_statements_cache_size", "MiB"},
+	"key_cache_size_in_mb":                                                   {"key_cache_size", "MiB"},
+	"row_cache_size_in_mb":                                                   {"row_cache_size", "MiB"},
+	"counter_cache_size_in_mb":                                               {"counter_cache_size", "MiB"},
+	"file_cache_size_in_mb":                                                  {"file_cache_size", "MiB"},
+	"commitlog_segment_size_in_mb":                                           {"commitlog_segment_size", "MiB"},
+	"commitlog_total_space_in_mb":                                            {"commitlog_total_space", "MiB"},
+	"memtable_heap_space_in_mb":                                              {"memtable_heap_space", "MiB"},
+	"memtable_offheap_space_in_mb":                                           {"memtable_offheap_space", "MiB"},
+	"cdc_total_space_in_mb":                                                  {"cdc_total_space", "MiB"},
+	"index_summary_capacity_in_mb":                                           {"index_summary_capacity", "MiB"},
+	"trickle_fsync_interval_in_kb":                                           {"trickle_fsync_interval", "KiB"},
+	"column_index_size_in_kb":                                                {"column_index_size", "KiB"},
+	"column_index_cache_size_in_kb":                                          {"column_index_cache_size", "KiB"},
+	"sstable_preemptive_open_interval_in_mb":                                 {"sstable_preemptive_open_interval", "MiB"},
+	"native_transport_max_frame_size_in_mb":                                  {"native_transport_max_frame_size", "MiB"},
+	"native_transport_receive_queue_capacity_in_bytes":                       {"native_transport_receive_queue_capacity", "B"},
+	"internode_socket_send_buffer_size_in_bytes":                             {"internode_socket_send_buffer_size", "B"},
+	"internode_socket_receive_buffer_size_in_bytes":                          {"internode_socket_receive_buffer_size", "B"},
+	"internode_application_send_queue_capacity_in_bytes":                     {"internode_application_send_queue_capacity", "B"},
+	"internode_application_send_queue_reserve_endpoint_capacity_in_bytes":    {"internode_application_send_queue_reserve_endpoint_capacity", "B"},
+	"internode_application_send_queue_reserve_global_capacity_in_bytes":      {"internode_application_send_queue_reserve_global_capacity", "B"},
+	"internode_application_receive_queue_capacity_in_bytes":                  {"internode_application_receive_queue_capacity", "B"},
+	"internode_application_receive_queue_reserve_endpoint_capacity_in_bytes": {"internode_application_receive_queue_reserve_endpoint_capacity", "B"},
+	"internode_application_receive_queue_reserve_global_capacity_in_bytes":   {"internode_application_receive_queue_reserve_global_capacity", "B"},
+	"internode_max_message_size_in_bytes":                                    {"internode_max_message_size", "B"},
+	"max_mutation_size_in_kb":                                                {"max_mutation_size", "KiB"},
+	"max_value_size_in_mb":                                                   {"max_value_size", "MiB"},
+	"batch_size_warn_threshold_in_kb":                                        {"batch_size_warn_threshold", "KiB"},
+	"batch_size_fail_threshold_in_kb":                                        {"batch_size_fail_threshold", "KiB"},
+	"compaction_large_partition_warning_threshold_mb":                        {"compaction_large_partition_warning_threshold", "MiB"},
+	"compaction_throughput_mb_per_sec":                                       {"compaction_throughput", "MiB/s"},
+
+	"enable_user_defined_functions":          {"user_defined_functions_enabled", ""},
+	"enable_scripted_user_defined_functions": {"scripted_user_defined_functions_enabled", ""},
+	"enable_user_defined_functions_threads":  {"user_defined_functions_threads_enabled", ""},
+	"enable_materialized_views":              {"materialized_views_enabled", ""},
+	"enable_sasi_indexes":                    {"sasi_indexes_enabled", ""},
+	"enable_transient_replication":           {"transient_replication_enabled", ""},
+	"enable_drop_compact_storage":            {"drop_compact_storage_enabled", ""},
+}
+
+// legacyProperties4_1 maps the new names of renamedProperties4_1 to their old name.
+var legacyProperties4_1 = func() map[string]string {
+	legacy := make(map[string]string, len(renamedProperties4_1))
+	for oldName, renamed := range renamedProperties4_1 {
+		legacy[renamed.name] = oldName
+	}
+	return legacy
+}()
+
+// renameProperties4_1 marshals cassandraYaml, which holds cassandra.yaml properties with
+// their pre-4.1 names, into a map of the properties with their Cassandra 4.1 names.
+func renameProperties4_1(cassandraYaml interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(cassandraYaml)
+	if err != nil {
+		return nil, err
+	}
+	properties := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&properties); err != nil {
+		return nil, err
+	}
+	for oldName, renamed := range renamedProperties4_1 {
+		value, found := properties[oldName]
+		if !found {
+			continue
+		}
+		delete(properties, oldName)
+		if renamed.unit != "" {
+			value = fmt.Sprintf("%v%s", value, renamed.unit)
+		}
+		properties[renamed.name] = value
+	}
+	return properties, nil
+}
+
+// legacyProperties returns a copy of the cassandra.yaml properties of a rendered config
+// where the properties renamed by renameProperties4_1 have their old name and value. The
+// configs of all versions can then be compared the same way.
+func legacyProperties(properties map[string]interface{}) map[string]interface{} {
+	legacy := make(map[string]interface{}, len(properties))
+	for name, value := range properties {
+		oldName, found := legacyProperties4_1[name]
+		if !found {
+			legacy[name] = value
+			continue
+		}
+		if unit := renamedProperties4_1[oldName].unit; unit != "" {
+			if s, ok := value.(string); ok {
+				value = json.Number(strings.TrimSuffix(s, unit))
+			}
+		}
+		legacy[oldName] = value
+	}
+	return legacy
+}
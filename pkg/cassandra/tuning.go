@@ -0,0 +1,164 @@
+package cassandra
+
+import (
+	"fmt"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	mebibyte = 1024 * 1024
+	gibibyte = 1024 * mebibyte
+
+	// newGenSizePerCore is the size of the young generation per CPU core, as computed by
+	// cassandra-env.sh.
+	newGenSizePerCore = 100 * mebibyte
+)
+
+// ApplyResourceTuning sets the heap size, the young generation size, concurrent_reads,
+// concurrent_writes, concurrent_compactors and memtable_flush_writers of dcConfig from
+// the CPU and memory of the Cassandra container, if dcConfig.ResourceTuning is enabled.
+// The limits are used if they are set, the requests otherwise. Settings that are set
+// explicitly in dcConfig.CassandraConfig are left untouched. The computed settings are
+// returned, or nil if none were computed.
+//
+// The heap size follows the heuristic of cassandra-env.sh, which is applied to the memory
+// of the container instead of the memory of the host:
+//
+//	max(min(1/2 memory, 1GiB), min(1/4 memory, 8GiB))
+//
+// The young generation gets 100MiB per core, up to 1/4 of the heap. It is only computed
+// when the garbage collector is CMS, which is the default of Cassandra.
+func ApplyResourceTuning(dcConfig *DatacenterConfig) *api.TuningStatus {
+	if !dcConfig.ResourceTuning {
+		return nil
+	}
+
+	memory := containerResource(dcConfig.Resources, corev1.ResourceMemory)
+	cpu := containerResource(dcConfig.Resources, corev1.ResourceCPU)
+	if memory == nil && cpu == nil {
+		return nil
+	}
+
+	if dcConfig.CassandraConfig == nil {
+		dcConfig.CassandraConfig = &api.CassandraConfig{}
+	}
+	config := dcConfig.CassandraConfig
+	if config.JvmOptions == nil {
+		config.JvmOptions = &api.JvmOptions{}
+	}
+	if config.CassandraYaml == nil {
+		config.CassandraYaml = &api.CassandraYaml{}
+	}
+	jvmOptions := config.JvmOptions
+	cassandraYaml := config.CassandraYaml
+
+	tuning := &api.TuningStatus{}
+
+	if memory != nil && jvmOptions.HeapSize == nil {
+		jvmOptions.HeapSize = computeHeapSize(memory.Value())
+		tuning.HeapSize = jvmOptions.HeapSize
+	}
+
+	if cpu != nil {
+		cores := int(cpu.ScaledValue(resource.Milli)+999) / 1000
+		if cores < 1 {
+			cores = 1
+		}
+
+		gc := jvmOptions.GarbageCollector
+		if jvmOptions.HeapSize != nil && jvmOptions.HeapNewGenSize == nil && (gc == nil || *gc == api.GarbageCollectorCMS) {
+			jvmOptions.HeapNewGenSize = computeHeapNewGenSize(jvmOptions.HeapSize.Value(), cores)
+			tuning.HeapNewGenSize = jvmOptions.HeapNewGenSize
+		}
+
+		// cassandra.yaml recommends 8 concurrent writes per core, and 16 concurrent reads
+		// per drive. The volumes of a pod are typically backed by SSDs, which sustain as
+		// many concurrent reads as writes.
+		concurrentRequests := 8 * cores
+		if concurrentRequests < 16 {
+			concurrentRequests = 16
+		}
+		if cassandraYaml.ConcurrentReads == nil {
+			concurrentReads := concurrentRequests
+			cassandraYaml.ConcurrentReads = &concurrentReads
+			tuning.ConcurrentReads = &concurrentReads
+		}
+		if cassandraYaml.ConcurrentWrites == nil {
+			concurrentWrites := concurrentRequests
+			cassandraYaml.ConcurrentWrites = &concurrentWrites
+			tuning.ConcurrentWrites = &concurrentWrites
+		}
+		if cassandraYaml.ConcurrentCompactors == nil {
+			concurrentCompactors := clamp(cores, 2, 8)
+			cassandraYaml.ConcurrentCompactors = &concurrentCompactors
+			tuning.ConcurrentCompactors = &concurrentCompactors
+		}
+		if cassandraYaml.MemtableFlushWriters == nil {
+			memtableFlushWriters := clamp(cores/2, 2, 8)
+			cassandraYaml.MemtableFlushWriters = &memtableFlushWriters
+			tuning.MemtableFlushWriters = &memtableFlushWriters
+		}
+	}
+
+	if *tuning == (api.TuningStatus{}) {
+		return nil
+	}
+	return tuning
+}
+
+// containerResource returns the limit of name in resources if it is set, or the request
+// otherwise.
+func containerResource(resources *corev1.ResourceRequirements, name corev1.ResourceName) *resource.Quantity {
+	if resources == nil {
+		return nil
+	}
+	if quantity, found := resources.Limits[name]; found && !quantity.IsZero() {
+		return &quantity
+	}
+	if quantity, found := resources.Requests[name]; found && !quantity.IsZero() {
+		return &quantity
+	}
+	return nil
+}
+
+func computeHeapSize(memory int64) *resource.Quantity {
+	heapSize := memory / 4
+	if heapSize > 8*gibibyte {
+		heapSize = 8 * gibibyte
+	} else if heapSize < gibibyte {
+		// min(1/2 memory, 1GiB) is larger than 1/4 memory
+		heapSize = memory / 2
+		if heapSize > gibibyte {
+			heapSize = gibibyte
+		}
+	}
+	return mebibytes(heapSize)
+}
+
+func computeHeapNewGenSize(heapSize int64, cores int) *resource.Quantity {
+	newGenSize := int64(cores) * newGenSizePerCore
+	if newGenSize > heapSize/4 {
+		newGenSize = heapSize / 4
+	}
+	return mebibytes(newGenSize)
+}
+
+// mebibytes returns bytes rounded down to the MiB.
+func mebibytes(bytes int64) *resource.Quantity {
+	quantity := resource.MustParse(fmt.Sprintf("%dMi", bytes/mebibyte))
+	return &quantity
+}
+
+// clamp returns value bounded by lower and upper.
+func clamp(value, lower, upper int) int {
+	if value < lower {
+		return lower
+	}
+	if value > upper {
+		return upper
+	}
+	return value
+}
@@ -0,0 +1,212 @@
+package cassandra
+
+import (
+	"testing"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyResourceTuning(t *testing.T) {
+	type test struct {
+		name       string
+		dcConfig   *DatacenterConfig
+		wantConfig *api.CassandraConfig
+		want       *api.TuningStatus
+	}
+
+	tests := []test{
+		{
+			name:       "no resources",
+			dcConfig:   &DatacenterConfig{ResourceTuning: true},
+			wantConfig: nil,
+			want:       nil,
+		},
+		{
+			name: "tuning disabled",
+			dcConfig: &DatacenterConfig{
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("16Gi"),
+						corev1.ResourceCPU:    resource.MustParse("4"),
+					},
+				},
+			},
+			wantConfig: nil,
+			want:       nil,
+		},
+		{
+			name: "memory and cpu limits",
+			dcConfig: &DatacenterConfig{
+				ResourceTuning: true,
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("16Gi"),
+						corev1.ResourceCPU:    resource.MustParse("4"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("8Gi"),
+						corev1.ResourceCPU:    resource.MustParse("2"),
+					},
+				},
+			},
+			wantConfig: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ConcurrentReads:      intPtr(32),
+					ConcurrentWrites:     intPtr(32),
+					ConcurrentCompactors: intPtr(4),
+					MemtableFlushWriters: intPtr(2),
+				},
+				JvmOptions: &api.JvmOptions{
+					HeapSize:       parseResource("4096Mi"),
+					HeapNewGenSize: parseResource("400Mi"),
+				},
+			},
+			want: &api.TuningStatus{
+				HeapSize:             parseResource("4096Mi"),
+				HeapNewGenSize:       parseResource("400Mi"),
+				ConcurrentReads:      intPtr(32),
+				ConcurrentWrites:     intPtr(32),
+				ConcurrentCompactors: intPtr(4),
+				MemtableFlushWriters: intPtr(2),
+			},
+		},
+		{
+			name: "small memory and fractional cpu requests",
+			dcConfig: &DatacenterConfig{
+				ResourceTuning: true,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1500Mi"),
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+					},
+				},
+			},
+			wantConfig: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ConcurrentReads:      intPtr(16),
+					ConcurrentWrites:     intPtr(16),
+					ConcurrentCompactors: intPtr(2),
+					MemtableFlushWriters: intPtr(2),
+				},
+				JvmOptions: &api.JvmOptions{
+					HeapSize:       parseResource("750Mi"),
+					HeapNewGenSize: parseResource("100Mi"),
+				},
+			},
+			want: &api.TuningStatus{
+				HeapSize:             parseResource("750Mi"),
+				HeapNewGenSize:       parseResource("100Mi"),
+				ConcurrentReads:      intPtr(16),
+				ConcurrentWrites:     intPtr(16),
+				ConcurrentCompactors: intPtr(2),
+				MemtableFlushWriters: intPtr(2),
+			},
+		},
+		{
+			name: "heap size is capped",
+			dcConfig: &DatacenterConfig{
+				ResourceTuning: true,
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			},
+			wantConfig: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{},
+				JvmOptions: &api.JvmOptions{
+					HeapSize: parseResource("8192Mi"),
+				},
+			},
+			want: &api.TuningStatus{
+				HeapSize: parseResource("8192Mi"),
+			},
+		},
+		{
+			name: "explicit values win",
+			dcConfig: &DatacenterConfig{
+				ResourceTuning: true,
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("16Gi"),
+						corev1.ResourceCPU:    resource.MustParse("16"),
+					},
+				},
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						ConcurrentReads: intPtr(64),
+					},
+					JvmOptions: &api.JvmOptions{
+						HeapSize:         parseResource("2Gi"),
+						GarbageCollector: stringPtr(api.GarbageCollectorG1),
+					},
+				},
+			},
+			wantConfig: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ConcurrentReads:      intPtr(64),
+					ConcurrentWrites:     intPtr(128),
+					ConcurrentCompactors: intPtr(8),
+					MemtableFlushWriters: intPtr(8),
+				},
+				JvmOptions: &api.JvmOptions{
+					HeapSize:         parseResource("2Gi"),
+					GarbageCollector: stringPtr(api.GarbageCollectorG1),
+				},
+			},
+			want: &api.TuningStatus{
+				ConcurrentWrites:     intPtr(128),
+				ConcurrentCompactors: intPtr(8),
+				MemtableFlushWriters: intPtr(8),
+			},
+		},
+		{
+			name: "young generation is computed from an explicit heap size",
+			dcConfig: &DatacenterConfig{
+				ResourceTuning: true,
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("8"),
+					},
+				},
+				CassandraConfig: &api.CassandraConfig{
+					CassandraYaml: &api.CassandraYaml{
+						ConcurrentReads:      intPtr(64),
+						ConcurrentWrites:     intPtr(64),
+						ConcurrentCompactors: intPtr(4),
+						MemtableFlushWriters: intPtr(4),
+					},
+					JvmOptions: &api.JvmOptions{
+						HeapSize: parseResource("2Gi"),
+					},
+				},
+			},
+			wantConfig: &api.CassandraConfig{
+				CassandraYaml: &api.CassandraYaml{
+					ConcurrentReads:      intPtr(64),
+					ConcurrentWrites:     intPtr(64),
+					ConcurrentCompactors: intPtr(4),
+					MemtableFlushWriters: intPtr(4),
+				},
+				JvmOptions: &api.JvmOptions{
+					HeapSize:       parseResource("2Gi"),
+					HeapNewGenSize: parseResource("512Mi"),
+				},
+			},
+			want: &api.TuningStatus{
+				HeapNewGenSize: parseResource("512Mi"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ApplyResourceTuning(tc.dcConfig)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantConfig, tc.dcConfig.CassandraConfig)
+		})
+	}
+}
@@ -2,13 +2,16 @@ package cassandra
 
 import (
 	"math"
+	"sort"
 	"strconv"
 	"time"
 
 	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/utils"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 )
 
 func DatacenterUpdatedAfter(t time.Time, dc *cassdcapi.CassandraDatacenter) bool {
@@ -19,6 +22,55 @@ func DatacenterUpdatedAfter(t time.Time, dc *cassdcapi.CassandraDatacenter) bool
 	return updateCondition.LastTransitionTime.After(t)
 }
 
+// DatacenterRestartedAfter returns true if cass-operator completed a rolling restart of dc
+// that was started at or after t.
+func DatacenterRestartedAfter(t time.Time, dc *cassdcapi.CassandraDatacenter) bool {
+	if dc.Spec.RollingRestartRequested || dc.Status.LastRollingRestart.Time.Before(t) {
+		return false
+	}
+	restartCondition, found := dc.GetCondition(cassdcapi.DatacenterRollingRestart)
+	if !found || restartCondition.Status != corev1.ConditionFalse {
+		return false
+	}
+	return !restartCondition.LastTransitionTime.Before(&dc.Status.LastRollingRestart)
+}
+
+// PodTemplateChanged returns true if updating actual with the spec of desired changes the
+// pods that cass-operator renders for the datacenter, which makes it restart the nodes.
+// The config is not compared.
+func PodTemplateChanged(actual, desired *cassdcapi.CassandraDatacenter) bool {
+	return !equality.Semantic.DeepEqual(podTemplateSpec(actual), podTemplateSpec(desired))
+}
+
+// podTemplateSpec returns the fields of the spec of dc that are rendered in the pods of
+// the datacenter.
+func podTemplateSpec(dc *cassdcapi.CassandraDatacenter) cassdcapi.CassandraDatacenterSpec {
+	return cassdcapi.CassandraDatacenterSpec{
+		ServerVersion:               dc.Spec.ServerVersion,
+		ServerImage:                 dc.Spec.ServerImage,
+		ServerType:                  dc.Spec.ServerType,
+		DockerImageRunsAsCassandra:  dc.Spec.DockerImageRunsAsCassandra,
+		ConfigSecret:                dc.Spec.ConfigSecret,
+		ManagementApiAuth:           dc.Spec.ManagementApiAuth,
+		NodeAffinityLabels:          dc.Spec.NodeAffinityLabels,
+		Resources:                   dc.Spec.Resources,
+		SystemLoggerResources:       dc.Spec.SystemLoggerResources,
+		ConfigBuilderResources:      dc.Spec.ConfigBuilderResources,
+		Racks:                       dc.Spec.Racks,
+		StorageConfig:               dc.Spec.StorageConfig,
+		ConfigBuilderImage:          dc.Spec.ConfigBuilderImage,
+		AllowMultipleNodesPerWorker: dc.Spec.AllowMultipleNodesPerWorker,
+		ServiceAccount:              dc.Spec.ServiceAccount,
+		NodeSelector:                dc.Spec.NodeSelector,
+		DseWorkloads:                dc.Spec.DseWorkloads,
+		PodTemplateSpec:             dc.Spec.PodTemplateSpec,
+		Networking:                  dc.Spec.Networking,
+		DisableSystemLoggerSidecar:  dc.Spec.DisableSystemLoggerSidecar,
+		SystemLoggerImage:           dc.Spec.SystemLoggerImage,
+		Tolerations:                 dc.Spec.Tolerations,
+	}
+}
+
 func DatacenterReady(dc *cassdcapi.CassandraDatacenter) bool {
 	return dc.GetConditionStatus(cassdcapi.DatacenterReady) == corev1.ConditionTrue && dc.Status.CassandraOperatorProgress == cassdcapi.ProgressReady
 }
@@ -31,18 +83,71 @@ func DatacenterStopping(dc *cassdcapi.CassandraDatacenter) bool {
 	return dc.GetConditionStatus(cassdcapi.DatacenterStopped) == corev1.ConditionTrue && dc.Status.CassandraOperatorProgress == cassdcapi.ProgressUpdating
 }
 
+// ComputeSystemReplication returns the replication of the system keyspaces that is set when
+// the nodes bootstrap. The nodes only support a single replication factor for all the
+// datacenters, it is the lowest of the replication factors of the datacenters.
 func ComputeSystemReplication(kluster *api.K8ssandraCluster) SystemReplication {
-	rf := 3.0
-	for _, dc := range kluster.Spec.Cassandra.Datacenters {
-		rf = math.Min(rf, float64(dc.Size))
-	}
-
+	replication := ComputeSystemKeyspacesReplication(kluster.Spec.Cassandra, nil)
+	rf := kluster.Spec.Cassandra.SystemReplication.GetReplicationFactor()
 	dcNames := make([]string, 0, len(kluster.Spec.Cassandra.Datacenters))
 	for _, dc := range kluster.Spec.Cassandra.Datacenters {
 		dcNames = append(dcNames, dc.Meta.Name)
+		if replication[dc.Meta.Name] < rf {
+			rf = replication[dc.Meta.Name]
+		}
+	}
+
+	return SystemReplication{Datacenters: dcNames, ReplicationFactor: rf}
+}
+
+// SystemKeyspaces returns the system keyspaces whose replication is managed by the
+// operator, followed by the additional keyspaces of the system replication policy of
+// template.
+func SystemKeyspaces(template *api.CassandraClusterTemplate) []string {
+	keyspaces := []string{"system_traces", "system_distributed", "system_auth"}
+	if template.SystemReplication != nil {
+		for _, keyspace := range template.SystemReplication.AdditionalKeyspaces {
+			if !utils.SliceContains(keyspaces, keyspace) {
+				keyspaces = append(keyspaces, keyspace)
+			}
+		}
 	}
+	return keyspaces
+}
 
-	return SystemReplication{Datacenters: dcNames, ReplicationFactor: int(rf)}
+// ComputeSystemKeyspacesReplication returns the desired replication of the system keyspaces
+// according to the system replication policy of template. current is the replication of
+// the keyspace; when the replication factor does not grow with the size of the datacenters,
+// the datacenters of current keep a lower replication factor.
+func ComputeSystemKeyspacesReplication(template *api.CassandraClusterTemplate, current map[string]int) map[string]int {
+	policy := template.SystemReplication
+	replication := ComputeReplication(policy.GetReplicationFactor(), template.Datacenters...)
+	for dcName, rf := range replication {
+		if policy != nil {
+			if override, found := policy.Datacenters[dcName]; found {
+				replication[dcName] = override
+				continue
+			}
+		}
+		if currentRf, found := current[dcName]; found && currentRf < rf && !policy.GrowsWithDatacenterSize() {
+			replication[dcName] = currentRf
+		}
+	}
+	return replication
+}
+
+// RaisedReplication returns the sorted names of the datacenters of current whose replication
+// factor is higher in desired. The datacenters that are not in current are not included,
+// they get their data by being rebuilt.
+func RaisedReplication(current, desired map[string]int) []string {
+	raised := make([]string, 0)
+	for dcName, rf := range current {
+		if desiredRf, found := desired[dcName]; found && desiredRf > rf {
+			raised = append(raised, dcName)
+		}
+	}
+	sort.Strings(raised)
+	return raised
 }
 
 func ComputeReplication(maxReplicationPerDc int, datacenters ...api.CassandraDatacenterTemplate) map[string]int {
@@ -75,3 +180,25 @@ func CompareReplications(actualReplication map[string]string, desiredReplication
 	}
 	return true
 }
+
+// ParseReplication converts the replication settings returned by the management API
+// into a map of datacenter names to replication factors. The second return value is
+// false if the keyspace does not use NetworkTopologyStrategy or if a replication factor
+// cannot be parsed.
+func ParseReplication(replication map[string]string) (map[string]int, bool) {
+	if replication["class"] != networkTopology {
+		return nil, false
+	}
+	parsed := make(map[string]int, len(replication)-1)
+	for key, value := range replication {
+		if key == "class" {
+			continue
+		}
+		rf, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, false
+		}
+		parsed[key] = rf
+	}
+	return parsed, true
+}
@@ -3,9 +3,14 @@ package cassandra
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestComputeSystemReplication(t *testing.T) {
@@ -132,6 +137,61 @@ func TestComputeReplication(t *testing.T) {
 	}
 }
 
+func TestSystemKeyspaces(t *testing.T) {
+	template := &api.CassandraClusterTemplate{}
+	assert.Equal(t, []string{"system_traces", "system_distributed", "system_auth"}, SystemKeyspaces(template))
+
+	template.SystemReplication = &api.SystemReplicationPolicy{
+		AdditionalKeyspaces: []string{"dse_security", "system_auth", "dse_leases"},
+	}
+	assert.Equal(t, []string{"system_traces", "system_distributed", "system_auth", "dse_security", "dse_leases"}, SystemKeyspaces(template))
+}
+
+func TestComputeSystemKeyspacesReplication(t *testing.T) {
+	rf := func(i int) *int { return &i }
+	grow := false
+	dcs := []api.CassandraDatacenterTemplate{
+		{Meta: api.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+		{Meta: api.EmbeddedObjectMeta{Name: "dc2"}, Size: 5},
+	}
+	tests := []struct {
+		name     string
+		policy   *api.SystemReplicationPolicy
+		current  map[string]int
+		expected map[string]int
+	}{
+		{"no policy", nil, nil, map[string]int{"dc1": 3, "dc2": 3}},
+		{"no policy, growing dc", nil, map[string]int{"dc1": 1}, map[string]int{"dc1": 3, "dc2": 3}},
+		{"replication factor", &api.SystemReplicationPolicy{ReplicationFactor: rf(5)}, nil, map[string]int{"dc1": 3, "dc2": 5}},
+		{"override", &api.SystemReplicationPolicy{Datacenters: map[string]int{"dc2": 2}}, nil, map[string]int{"dc1": 3, "dc2": 2}},
+		{
+			"not growing with datacenter size",
+			&api.SystemReplicationPolicy{GrowWithDatacenterSize: &grow},
+			map[string]int{"dc1": 1, "dc2": 3},
+			map[string]int{"dc1": 1, "dc2": 3},
+		},
+		{
+			"override is applied when not growing",
+			&api.SystemReplicationPolicy{GrowWithDatacenterSize: &grow, Datacenters: map[string]int{"dc1": 3}},
+			map[string]int{"dc1": 1},
+			map[string]int{"dc1": 3, "dc2": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &api.CassandraClusterTemplate{Datacenters: dcs, SystemReplication: tt.policy}
+			assert.Equal(t, tt.expected, ComputeSystemKeyspacesReplication(template, tt.current))
+		})
+	}
+}
+
+func TestRaisedReplication(t *testing.T) {
+	current := map[string]int{"dc1": 1, "dc2": 3, "dc3": 1}
+	desired := map[string]int{"dc1": 3, "dc2": 3, "dc3": 2, "dc4": 3}
+	assert.Equal(t, []string{"dc1", "dc3"}, RaisedReplication(current, desired))
+	assert.Empty(t, RaisedReplication(desired, desired))
+}
+
 func TestCompareReplications(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -155,3 +215,111 @@ func TestCompareReplications(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReplication(t *testing.T) {
+	tests := []struct {
+		name           string
+		replication    map[string]string
+		expected       map[string]int
+		expectedParsed bool
+	}{
+		{"nil", nil, nil, false},
+		{"simple strategy", map[string]string{"class": "org.apache.cassandra.locator.SimpleStrategy", "replication_factor": "1"}, nil, false},
+		{"invalid rf", map[string]string{"class": networkTopology, "dc1": "not a number"}, nil, false},
+		{"no dcs", map[string]string{"class": networkTopology}, map[string]int{}, true},
+		{"many dcs", map[string]string{"class": networkTopology, "dc1": "3", "dc2": "1"}, map[string]int{"dc1": 3, "dc2": 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, parsed := ParseReplication(tt.replication)
+			assert.Equal(t, tt.expectedParsed, parsed)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDatacenterRestartedAfter(t *testing.T) {
+	requested := time.Date(2021, 12, 1, 10, 0, 0, 0, time.UTC)
+	before := metav1.NewTime(requested.Add(-time.Minute))
+	started := metav1.NewTime(requested.Add(time.Second))
+	completed := metav1.NewTime(requested.Add(time.Minute))
+
+	newDc := func(restartRequested bool, lastRollingRestart metav1.Time, restartCondition *cassdcapi.DatacenterCondition) *cassdcapi.CassandraDatacenter {
+		dc := &cassdcapi.CassandraDatacenter{}
+		dc.Spec.RollingRestartRequested = restartRequested
+		dc.Status.LastRollingRestart = lastRollingRestart
+		if restartCondition != nil {
+			dc.SetCondition(*restartCondition)
+		}
+		return dc
+	}
+	restartCondition := func(status corev1.ConditionStatus, transitionTime metav1.Time) *cassdcapi.DatacenterCondition {
+		return &cassdcapi.DatacenterCondition{
+			Type:               cassdcapi.DatacenterRollingRestart,
+			Status:             status,
+			LastTransitionTime: transitionTime,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		dc       *cassdcapi.CassandraDatacenter
+		expected bool
+	}{
+		{"restart not processed yet", newDc(true, before, restartCondition(corev1.ConditionFalse, before)), false},
+		{"previous restart", newDc(false, before, restartCondition(corev1.ConditionFalse, before)), false},
+		{"restart in progress", newDc(false, started, restartCondition(corev1.ConditionTrue, started)), false},
+		{"no restart condition", newDc(false, started, nil), false},
+		{"restart completed", newDc(false, started, restartCondition(corev1.ConditionFalse, completed)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DatacenterRestartedAfter(requested, tt.dc))
+		})
+	}
+}
+
+func TestPodTemplateChanged(t *testing.T) {
+	newDc := func(mutate func(dc *cassdcapi.CassandraDatacenter)) *cassdcapi.CassandraDatacenter {
+		dc := &cassdcapi.CassandraDatacenter{
+			Spec: cassdcapi.CassandraDatacenterSpec{
+				Size:          3,
+				ServerVersion: "4.0.1",
+				Config:        []byte(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":16}}`),
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+		}
+		mutate(dc)
+		return dc
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(dc *cassdcapi.CassandraDatacenter)
+		expected bool
+	}{
+		{"unchanged", func(dc *cassdcapi.CassandraDatacenter) {}, false},
+		{"config", func(dc *cassdcapi.CassandraDatacenter) {
+			dc.Spec.Config = []byte(`{"cassandra-yaml":{"compaction_throughput_mb_per_sec":64}}`)
+		}, false},
+		{"size", func(dc *cassdcapi.CassandraDatacenter) { dc.Spec.Size = 6 }, false},
+		{"same resources", func(dc *cassdcapi.CassandraDatacenter) {
+			dc.Spec.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1024Mi")
+		}, false},
+		{"resources", func(dc *cassdcapi.CassandraDatacenter) {
+			dc.Spec.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("2Gi")
+		}, true},
+		{"server version", func(dc *cassdcapi.CassandraDatacenter) { dc.Spec.ServerVersion = "4.0.3" }, true},
+		{"tolerations", func(dc *cassdcapi.CassandraDatacenter) {
+			dc.Spec.Tolerations = []corev1.Toleration{{Key: "cassandra", Operator: corev1.TolerationOpExists}}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := newDc(func(dc *cassdcapi.CassandraDatacenter) {})
+			assert.Equal(t, tt.expected, PodTemplateChanged(actual, newDc(tt.mutate)))
+		})
+	}
+}
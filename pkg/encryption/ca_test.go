@@ -0,0 +1,68 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA("test-ca", 24*time.Hour)
+	require.NoError(t, err)
+
+	certificatePEM, keyPEM, err := ca.Issue("dc1", []string{"dc1-service.ns"}, time.Hour)
+	require.NoError(t, err)
+	certificate, err := ParseCertificate(certificatePEM)
+	require.NoError(t, err)
+	assert.Equal(t, "dc1", certificate.Subject.CommonName)
+	assert.Equal(t, []string{"dc1-service.ns"}, certificate.DNSNames)
+	assert.ElementsMatch(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, certificate.ExtKeyUsage)
+	assert.NoError(t, certificate.CheckSignatureFrom(ca.Certificate))
+	assert.NotNil(t, decodePEM(keyPEM, "PRIVATE KEY"))
+
+	assert.False(t, NeedsRenewal(certificatePEM, 30*time.Minute))
+	assert.True(t, NeedsRenewal(certificatePEM, 2*time.Hour))
+	assert.True(t, NeedsRenewal([]byte("invalid"), 0))
+
+	// The certificates do not outlive the CA
+	certificatePEM, _, err = ca.Issue("dc1", nil, 48*time.Hour)
+	require.NoError(t, err)
+	certificate, err = ParseCertificate(certificatePEM)
+	require.NoError(t, err)
+	assert.Equal(t, ca.Certificate.NotAfter, certificate.NotAfter)
+}
+
+func TestParseCA(t *testing.T) {
+	ca, err := NewCA("test-ca", time.Hour)
+	require.NoError(t, err)
+	keyPEM, err := ca.KeyPEM()
+	require.NoError(t, err)
+
+	parsed, err := ParseCA(ca.CertificatePEM(), keyPEM)
+	require.NoError(t, err)
+	assert.True(t, parsed.Certificate.Equal(ca.Certificate))
+	_, _, err = parsed.Issue("dc1", nil, time.Hour)
+	assert.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecKeyDer, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	_, err = ParseCA(ca.CertificatePEM(), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecKeyDer}))
+	assert.NoError(t, err, "SEC1 keys should be supported")
+
+	certificatePEM, _, err := ca.Issue("dc1", nil, time.Hour)
+	require.NoError(t, err)
+	_, err = ParseCA(certificatePEM, keyPEM)
+	assert.Error(t, err, "a certificate that is not a CA should be rejected")
+
+	_, err = ParseCA(ca.CertificatePEM(), []byte("invalid"))
+	assert.Error(t, err)
+}
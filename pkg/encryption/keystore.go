@@ -0,0 +1,78 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pavel-v-chernykh/keystore-go"
+)
+
+const (
+	keystoreAlias = "certificate"
+	caAliasPrefix = "ca"
+)
+
+// NewJKSStores creates a JKS keystore that holds the PEM-encoded certificate and PKCS8
+// private key, and a JKS truststore that holds the PEM-encoded CA certificates. Both are
+// protected by password.
+func NewJKSStores(certificatePEM, keyPEM, caPEM []byte, password []byte) ([]byte, []byte, error) {
+	certificates := decodePEM(certificatePEM, "CERTIFICATE")
+	if len(certificates) == 0 {
+		return nil, nil, errors.New("no certificate found")
+	}
+	keys := decodePEM(keyPEM, "PRIVATE KEY")
+	if len(keys) != 1 {
+		return nil, nil, errors.New("expected one PKCS8 private key")
+	}
+	cas := decodePEM(caPEM, "CERTIFICATE")
+	if len(cas) == 0 {
+		return nil, nil, errors.New("no CA certificate found")
+	}
+
+	now := time.Now()
+	chain := make([]keystore.Certificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		chain = append(chain, keystore.Certificate{Type: "X509", Content: certificate})
+	}
+	keyStore := keystore.KeyStore{
+		keystoreAlias: &keystore.PrivateKeyEntry{
+			Entry:     keystore.Entry{CreationDate: now},
+			PrivKey:   keys[0],
+			CertChain: chain,
+		},
+	}
+	trustStore := keystore.KeyStore{}
+	for i, ca := range cas {
+		trustStore[fmt.Sprintf("%s%d", caAliasPrefix, i)] = &keystore.TrustedCertificateEntry{
+			Entry:       keystore.Entry{CreationDate: now},
+			Certificate: keystore.Certificate{Type: "X509", Content: ca},
+		}
+	}
+
+	var keyStoreBytes, trustStoreBytes bytes.Buffer
+	if err := keystore.Encode(&keyStoreBytes, keyStore, password); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode keystore: %v", err)
+	}
+	if err := keystore.Encode(&trustStoreBytes, trustStore, password); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode truststore: %v", err)
+	}
+	return keyStoreBytes.Bytes(), trustStoreBytes.Bytes(), nil
+}
+
+// decodePEM returns the DER content of the blocks of type blockType in data.
+func decodePEM(data []byte, blockType string) [][]byte {
+	var blocks [][]byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return blocks
+		}
+		if block.Type == blockType {
+			blocks = append(blocks, block.Bytes)
+		}
+	}
+}
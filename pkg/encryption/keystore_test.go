@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pavel-v-chernykh/keystore-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJKSStores(t *testing.T) {
+	ca, err := NewCA("test-ca", time.Hour)
+	require.NoError(t, err)
+	certificatePEM, keyPEM, err := ca.Issue("dc1", nil, time.Hour)
+	require.NoError(t, err)
+
+	keystoreBytes, truststoreBytes, err := NewJKSStores(certificatePEM, keyPEM, ca.CertificatePEM(), []byte("changeit"))
+	require.NoError(t, err)
+
+	keyStore, err := keystore.Decode(bytes.NewReader(keystoreBytes), []byte("changeit"))
+	require.NoError(t, err)
+	require.Contains(t, keyStore, keystoreAlias)
+	entry, ok := keyStore[keystoreAlias].(*keystore.PrivateKeyEntry)
+	require.True(t, ok)
+	assert.Equal(t, decodePEM(keyPEM, "PRIVATE KEY")[0], entry.PrivKey)
+	require.Len(t, entry.CertChain, 1)
+	assert.Equal(t, decodePEM(certificatePEM, "CERTIFICATE")[0], entry.CertChain[0].Content)
+
+	trustStore, err := keystore.Decode(bytes.NewReader(truststoreBytes), []byte("changeit"))
+	require.NoError(t, err)
+	require.Len(t, trustStore, 1)
+	caEntry, ok := trustStore["ca0"].(*keystore.TrustedCertificateEntry)
+	require.True(t, ok)
+	assert.Equal(t, ca.Certificate.Raw, caEntry.Certificate.Content)
+
+	_, err = keystore.Decode(bytes.NewReader(keystoreBytes), []byte("wrong"))
+	assert.Error(t, err)
+
+	_, _, err = NewJKSStores(certificatePEM, nil, ca.CertificatePEM(), []byte("changeit"))
+	assert.Error(t, err)
+}
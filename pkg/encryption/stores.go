@@ -0,0 +1,130 @@
+package encryption
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StoreType is the format of a keystore or of a truststore.
+type StoreType string
+
+const (
+	StoreTypeJKS    = StoreType("JKS")
+	StoreTypePKCS12 = StoreType("PKCS12")
+
+	// PasswordKey is the key of the password of the keystore and of the truststore in a
+	// stores Secret.
+	PasswordKey = "keystore-password"
+
+	// CertificateKey, PrivateKeyKey and CAKey are the keys of the PEM-encoded certificate,
+	// private key and CA certificates in the Secrets created by cert-manager. The stores
+	// Secrets and the Secret of the internal CA use the same keys.
+	CertificateKey = "tls.crt"
+	PrivateKeyKey  = "tls.key"
+	CAKey          = "ca.crt"
+
+	// MountPath is where the stores are mounted in the containers that use them.
+	MountPath = "/etc/encryption"
+
+	storesVolumeName = "encryption-stores"
+)
+
+// Stores references the keystore and the truststore used to encrypt the connections to
+// Cassandra.
+// +kubebuilder:object:generate=true
+type Stores struct {
+	// SecretRef references the Secret that holds the keystore, the truststore and their
+	// password. The stores are in the keystore.jks and truststore.jks keys, or in the
+	// keystore.p12 and truststore.p12 keys with the PKCS12 type, and the password is in the
+	// keystore-password key.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// Type is the format of the stores.
+	// +kubebuilder:validation:Enum=JKS;PKCS12
+	// +kubebuilder:default=JKS
+	// +optional
+	Type StoreType `json:"type,omitempty"`
+}
+
+// GetType returns the format of the stores, which defaults to JKS.
+func (s *Stores) GetType() StoreType {
+	if s.Type == "" {
+		return StoreTypeJKS
+	}
+	return s.Type
+}
+
+// KeystoreKey returns the key of the keystore in the Secret.
+func (s *Stores) KeystoreKey() string {
+	return "keystore." + fileExtension(s.GetType())
+}
+
+// TruststoreKey returns the key of the truststore in the Secret.
+func (s *Stores) TruststoreKey() string {
+	return "truststore." + fileExtension(s.GetType())
+}
+
+// KeystorePath returns the path of the keystore in the containers that mount the stores.
+func (s *Stores) KeystorePath() string {
+	return path.Join(MountPath, s.KeystoreKey())
+}
+
+// TruststorePath returns the path of the truststore in the containers that mount the
+// stores.
+func (s *Stores) TruststorePath() string {
+	return path.Join(MountPath, s.TruststoreKey())
+}
+
+// Volume returns the volume of the stores.
+func (s *Stores) Volume() corev1.Volume {
+	return corev1.Volume{
+		Name: storesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: s.SecretRef.Name,
+				Items: []corev1.KeyToPath{
+					{Key: s.KeystoreKey(), Path: s.KeystoreKey()},
+					{Key: s.TruststoreKey(), Path: s.TruststoreKey()},
+				},
+			},
+		},
+	}
+}
+
+// VolumeMount returns the mount of the volume of the stores in MountPath.
+func (s *Stores) VolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{Name: storesVolumeName, MountPath: MountPath, ReadOnly: true}
+}
+
+// PasswordEnvVar returns an environment variable named name that holds the password of
+// the stores.
+func (s *Stores) PasswordEnvVar(name string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: s.SecretRef,
+				Key:                  PasswordKey,
+			},
+		},
+	}
+}
+
+// JavaOptions returns the system properties that make the JVM use the stores for its TLS
+// connections. The passwords are references to the environment variable passwordEnvVar,
+// which Kubernetes expands if it is declared before the variable that holds the options.
+func (s *Stores) JavaOptions(passwordEnvVar string) string {
+	return fmt.Sprintf(
+		"-Djavax.net.ssl.keyStore=%s -Djavax.net.ssl.keyStorePassword=$(%s) -Djavax.net.ssl.keyStoreType=%s "+
+			"-Djavax.net.ssl.trustStore=%s -Djavax.net.ssl.trustStorePassword=$(%s) -Djavax.net.ssl.trustStoreType=%s",
+		s.KeystorePath(), passwordEnvVar, s.GetType(), s.TruststorePath(), passwordEnvVar, s.GetType())
+}
+
+func fileExtension(storeType StoreType) string {
+	if storeType == StoreTypePKCS12 {
+		return "p12"
+	}
+	return "jks"
+}
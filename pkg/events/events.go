@@ -0,0 +1,76 @@
+// Package events defines the reasons of the Kubernetes Events recorded by the controllers.
+package events
+
+// Reasons of the events recorded on K8ssandraClusters.
+const (
+	CreatedDatacenter              = "CreatedDatacenter"
+	CreateDatacenterFailed         = "CreateDatacenterFailed"
+	UpdatedDatacenter              = "UpdatedDatacenter"
+	UpdateDatacenterFailed         = "UpdateDatacenterFailed"
+	DatacenterUnreachable          = "DatacenterUnreachable"
+	DecommissionStarted            = "DecommissionStarted"
+	DecommissionCompleted          = "DecommissionCompleted"
+	RebuildStarted                 = "RebuildStarted"
+	RebuildCompleted               = "RebuildCompleted"
+	RebuildFailed                  = "RebuildFailed"
+	RepairStarted                  = "RepairStarted"
+	RepairCompleted                = "RepairCompleted"
+	RepairFailed                   = "RepairFailed"
+	RollingRestartStarted          = "RollingRestartStarted"
+	RollingRestartCompleted        = "RollingRestartCompleted"
+	UpgradeStarted                 = "UpgradeStarted"
+	UpgradingDatacenter            = "UpgradingDatacenter"
+	UpgradeCompleted               = "UpgradeCompleted"
+	UpgradeBlocked                 = "UpgradeBlocked"
+	UnsupportedCassandraProperties = "UnsupportedCassandraProperties"
+	ConfigFilesFailed              = "ConfigFilesFailed"
+	LiveSettingsApplied            = "LiveSettingsApplied"
+	LiveSettingsFailed             = "LiveSettingsFailed"
+	EncryptionStoresIssued         = "EncryptionStoresIssued"
+	EncryptionStoresFailed         = "EncryptionStoresFailed"
+	AuthenticationBlocked          = "AuthenticationBlocked"
+	LDAPGroupsSynced               = "LDAPGroupsSynced"
+	LDAPGroupSyncFailed            = "LDAPGroupSyncFailed"
+	ScaledDownDeployment           = "ScaledDownDeployment"
+	ScaledUpDeployment             = "ScaledUpDeployment"
+	ClusterReady                   = "ClusterReady"
+	ClusterNotReady                = "ClusterNotReady"
+	ClusterDegraded                = "ClusterDegraded"
+	ClusterRecovered               = "ClusterRecovered"
+)
+
+// Reasons of the events recorded on ReplicatedSecrets. They are mirrored on the
+// K8ssandraCluster that owns the ReplicatedSecret, if any.
+const (
+	SecretReplicated        = "SecretReplicated"
+	SecretReplicationFailed = "SecretReplicationFailed"
+)
+
+// Reasons of the events recorded on CassandraKeyspaces.
+const (
+	KeyspaceCreated            = "KeyspaceCreated"
+	KeyspaceReplicationUpdated = "KeyspaceReplicationUpdated"
+	KeyspaceDropped            = "KeyspaceDropped"
+	KeyspaceReconcileFailed    = "KeyspaceReconcileFailed"
+)
+
+// Reasons of the events recorded on CassandraRoles.
+const (
+	RoleCreated         = "RoleCreated"
+	RoleUpdated         = "RoleUpdated"
+	RoleDropped         = "RoleDropped"
+	RoleReconcileFailed = "RoleReconcileFailed"
+)
+
+// Reasons of the events recorded on Stargates and Reapers.
+const (
+	CreatedDeployment        = "CreatedDeployment"
+	UpdatedDeployment        = "UpdatedDeployment"
+	ReconcileDeploymentError = "ReconcileDeploymentError"
+	CreatedService           = "CreatedService"
+	UpdatedService           = "UpdatedService"
+	ReconcileServiceError    = "ReconcileServiceError"
+	BecameReady              = "Ready"
+	RegisteredCluster        = "RegisteredCluster"
+	RegisterClusterFailed    = "RegisterClusterFailed"
+)
@@ -0,0 +1,85 @@
+// Package ldap reads the members of the LDAP groups that are mapped to Cassandra roles.
+package ldap
+
+import (
+	"fmt"
+	"sort"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// Client is a connection to an LDAP server that is bound with the credentials of a
+// service account.
+type Client struct {
+	conn *goldap.Conn
+}
+
+// Dial connects to the LDAP server at url, e.g., ldaps://ldap.example.com:636, and binds
+// as bindDN.
+func Dial(url, bindDN, password string) (*Client, error) {
+	conn, err := goldap.DialURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", url, err)
+	}
+	if err = conn.Bind(bindDN, password); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to bind to %s as %s: %v", url, bindDN, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// GroupMembers returns the sorted values of usernameAttribute of the members of the group
+// groupDN, which are read from its member and uniqueMember attributes. The members that
+// do not exist or that do not have usernameAttribute are skipped.
+func (c *Client) GroupMembers(groupDN, usernameAttribute string) ([]string, error) {
+	group, err := c.lookup(groupDN, "member", "uniqueMember")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group %s: %v", groupDN, err)
+	}
+	if group == nil {
+		return nil, fmt.Errorf("group %s not found", groupDN)
+	}
+
+	memberDNs := append(group.GetAttributeValues("member"), group.GetAttributeValues("uniqueMember")...)
+	usernames := make(map[string]bool, len(memberDNs))
+	for _, memberDN := range memberDNs {
+		member, err := c.lookup(memberDN, usernameAttribute)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read member %s of group %s: %v", memberDN, groupDN, err)
+		}
+		if member == nil {
+			continue
+		}
+		if username := member.GetAttributeValue(usernameAttribute); username != "" {
+			usernames[username] = true
+		}
+	}
+
+	members := make([]string, 0, len(usernames))
+	for username := range usernames {
+		members = append(members, username)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+// lookup returns the attributes of the entry dn, or nil if it does not exist.
+func (c *Client) lookup(dn string, attributes ...string) (*goldap.Entry, error) {
+	request := goldap.NewSearchRequest(dn, goldap.ScopeBaseObject, goldap.NeverDerefAliases,
+		1, 0, false, "(objectClass=*)", attributes, nil)
+	response, err := c.conn.Search(request)
+	if err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(response.Entries) == 0 {
+		return nil, nil
+	}
+	return response.Entries[0], nil
+}
@@ -0,0 +1,53 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/k8ssandra/k8ssandra-operator/pkg/ldap/ldaptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bindDN   = "cn=admin,dc=example,dc=com"
+	password = "secret"
+)
+
+func TestGroupMembers(t *testing.T) {
+	server := ldaptest.NewServer(t, bindDN, password,
+		ldaptest.Entry{DN: "uid=alice,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"alice"}, "cn": {"Alice"}}},
+		ldaptest.Entry{DN: "uid=bob,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"bob"}}},
+		ldaptest.Entry{DN: "uid=carol,ou=people,dc=example,dc=com", Attributes: map[string][]string{"cn": {"Carol"}}},
+		ldaptest.Entry{DN: "cn=dba,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"member": {
+				"uid=bob,ou=people,dc=example,dc=com",
+				"uid=carol,ou=people,dc=example,dc=com",
+				"uid=deleted,ou=people,dc=example,dc=com",
+			},
+			"uniqueMember": {"uid=alice,ou=people,dc=example,dc=com"},
+		}},
+	)
+
+	client, err := Dial(server.URL(), bindDN, password)
+	require.NoError(t, err)
+	defer client.Close()
+
+	members, err := client.GroupMembers("cn=dba,ou=groups,dc=example,dc=com", "uid")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, members)
+
+	members, err = client.GroupMembers("cn=dba,ou=groups,dc=example,dc=com", "cn")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Carol"}, members)
+
+	_, err = client.GroupMembers("cn=missing,ou=groups,dc=example,dc=com", "uid")
+	assert.Error(t, err)
+}
+
+func TestDialInvalidCredentials(t *testing.T) {
+	server := ldaptest.NewServer(t, bindDN, password)
+
+	_, err := Dial(server.URL(), bindDN, "wrong")
+	assert.Error(t, err)
+	assert.Equal(t, 0, server.Binds())
+}
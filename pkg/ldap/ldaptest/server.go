@@ -0,0 +1,219 @@
+// Package ldaptest provides an in-process LDAP server for tests. It only implements the
+// operations that the operator uses: simple binds, and searches of a single entry, i.e.,
+// with the base object scope.
+package ldaptest
+
+import (
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+const (
+	applicationBindRequest       = 0
+	applicationBindResponse      = 1
+	applicationUnbindRequest     = 2
+	applicationSearchRequest     = 3
+	applicationSearchResultEntry = 4
+	applicationSearchResultDone  = 5
+
+	resultSuccess            = 0
+	resultNoSuchObject       = 32
+	resultInvalidCredentials = 49
+	resultInsufficientAccess = 50
+	resultUnwillingToPerform = 53
+
+	scopeBaseObject = 0
+)
+
+// Entry is an entry of the directory, whose attributes can have several values.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Server is an LDAP server that listens on the loopback interface.
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	bindDN   string
+	password string
+	entries  map[string]*Entry
+	binds    int
+}
+
+// NewServer starts a server that accepts binds as bindDN with password and serves
+// entries. It is stopped when t completes.
+func NewServer(t *testing.T, bindDN, password string, entries ...Entry) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the LDAP server: %v", err)
+	}
+	s := &Server{
+		listener: listener,
+		bindDN:   bindDN,
+		password: password,
+		entries:  make(map[string]*Entry),
+	}
+	for _, entry := range entries {
+		s.AddEntry(entry)
+	}
+	go s.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return s
+}
+
+// URL returns the URL that the server listens on.
+func (s *Server) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// AddEntry adds entry to the directory, or replaces the entry with the same DN.
+func (s *Server) AddEntry(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[normalizeDN(entry.DN)] = &entry
+}
+
+// RemoveEntry removes the entry dn from the directory.
+func (s *Server) RemoveEntry(dn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, normalizeDN(dn))
+}
+
+// Binds returns the number of successful binds.
+func (s *Server) Binds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.binds
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	bound := false
+	for {
+		request, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(request.Children) < 2 {
+			return
+		}
+		messageID, _ := request.Children[0].Value.(int64)
+		op := request.Children[1]
+		var responses []*ber.Packet
+		switch op.Tag {
+		case applicationBindRequest:
+			code := s.bind(op)
+			bound = code == resultSuccess
+			responses = append(responses, result(applicationBindResponse, code))
+		case applicationUnbindRequest:
+			return
+		case applicationSearchRequest:
+			if !bound {
+				responses = append(responses, result(applicationSearchResultDone, resultInsufficientAccess))
+			} else {
+				responses = s.search(op)
+			}
+		default:
+			responses = append(responses, result(applicationSearchResultDone, resultUnwillingToPerform))
+		}
+		for _, response := range responses {
+			if err := write(conn, messageID, response); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet) int64 {
+	// version, name, simple authentication
+	if len(op.Children) < 3 {
+		return resultInvalidCredentials
+	}
+	name, _ := op.Children[1].Value.(string)
+	password := op.Children[2].Data.String()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if normalizeDN(name) != normalizeDN(s.bindDN) || password != s.password {
+		return resultInvalidCredentials
+	}
+	s.binds++
+	return resultSuccess
+}
+
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	// base object, scope, aliases, size limit, time limit, types only, filter, attributes
+	if len(op.Children) < 8 {
+		return []*ber.Packet{result(applicationSearchResultDone, resultUnwillingToPerform)}
+	}
+	if scope, _ := op.Children[1].Value.(int64); scope != scopeBaseObject {
+		return []*ber.Packet{result(applicationSearchResultDone, resultUnwillingToPerform)}
+	}
+	base, _ := op.Children[0].Value.(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, found := s.entries[normalizeDN(base)]
+	if !found {
+		return []*ber.Packet{result(applicationSearchResultDone, resultNoSuchObject)}
+	}
+
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, applicationSearchResultEntry, nil, "Search Result Entry")
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+	attributes := ber.NewSequence("Attributes")
+	for _, requested := range op.Children[7].Children {
+		name, _ := requested.Value.(string)
+		for attribute, values := range entry.Attributes {
+			if !strings.EqualFold(attribute, name) {
+				continue
+			}
+			attributeValues := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				attributeValues.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			partialAttribute := ber.NewSequence("Attribute")
+			partialAttribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute, "Type"))
+			partialAttribute.AppendChild(attributeValues)
+			attributes.AppendChild(partialAttribute)
+		}
+	}
+	response.AppendChild(attributes)
+	return []*ber.Packet{response, result(applicationSearchResultDone, resultSuccess)}
+}
+
+func result(application ber.Tag, code int64) *ber.Packet {
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, application, nil, "Result")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return response
+}
+
+func write(w io.Writer, messageID int64, response *ber.Packet) error {
+	message := ber.NewSequence("LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	message.AppendChild(response)
+	_, err := w.Write(message.Bytes())
+	return err
+}
+
+func normalizeDN(dn string) string {
+	return strings.ToLower(strings.ReplaceAll(dn, " ", ""))
+}
@@ -4,7 +4,11 @@ package mocks
 
 import (
 	httphelper "github.com/k8ssandra/cass-operator/pkg/httphelper"
+	cassandra "github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+
 	mock "github.com/stretchr/testify/mock"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 // ManagementApiFacade is an autogenerated mock type for the ManagementApiFacade type
@@ -26,6 +30,62 @@ func (_m *ManagementApiFacade) AlterKeyspace(keyspaceName string, replicationSet
 	return r0
 }
 
+// CleanupKeyspace provides a mock function with given fields: pod, keyspace, tables
+func (_m *ManagementApiFacade) CleanupKeyspace(pod *v1.Pod, keyspace string, tables []string) (string, error) {
+	ret := _m.Called(pod, keyspace, tables)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, []string) string); ok {
+		r0 = rf(pod, keyspace, tables)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string, []string) error); ok {
+		r1 = rf(pod, keyspace, tables)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClearSnapshots provides a mock function with given fields: pod, snapshotName, keyspaces
+func (_m *ManagementApiFacade) ClearSnapshots(pod *v1.Pod, snapshotName string, keyspaces []string) error {
+	ret := _m.Called(pod, snapshotName, keyspaces)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, []string) error); ok {
+		r0 = rf(pod, snapshotName, keyspaces)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompactTables provides a mock function with given fields: pod, keyspace, tables
+func (_m *ManagementApiFacade) CompactTables(pod *v1.Pod, keyspace string, tables []string) (string, error) {
+	ret := _m.Called(pod, keyspace, tables)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, []string) string); ok {
+		r0 = rf(pod, keyspace, tables)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string, []string) error); ok {
+		r1 = rf(pod, keyspace, tables)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateKeyspaceIfNotExists provides a mock function with given fields: keyspaceName, replication
 func (_m *ManagementApiFacade) CreateKeyspaceIfNotExists(keyspaceName string, replication map[string]int) error {
 	ret := _m.Called(keyspaceName, replication)
@@ -54,6 +114,34 @@ func (_m *ManagementApiFacade) CreateTable(definition *httphelper.TableDefinitio
 	return r0
 }
 
+// DecommissionNode provides a mock function with given fields: pod
+func (_m *ManagementApiFacade) DecommissionNode(pod *v1.Pod) error {
+	ret := _m.Called(pod)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.Pod) error); ok {
+		r0 = rf(pod)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DrainNode provides a mock function with given fields: pod
+func (_m *ManagementApiFacade) DrainNode(pod *v1.Pod) error {
+	ret := _m.Called(pod)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.Pod) error); ok {
+		r0 = rf(pod)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // EnsureKeyspaceReplication provides a mock function with given fields: keyspaceName, replication
 func (_m *ManagementApiFacade) EnsureKeyspaceReplication(keyspaceName string, replication map[string]int) error {
 	ret := _m.Called(keyspaceName, replication)
@@ -68,6 +156,117 @@ func (_m *ManagementApiFacade) EnsureKeyspaceReplication(keyspaceName string, re
 	return r0
 }
 
+// FlushTables provides a mock function with given fields: pod, keyspace, tables
+func (_m *ManagementApiFacade) FlushTables(pod *v1.Pod, keyspace string, tables []string) (string, error) {
+	ret := _m.Called(pod, keyspace, tables)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, []string) string); ok {
+		r0 = rf(pod, keyspace, tables)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string, []string) error); ok {
+		r1 = rf(pod, keyspace, tables)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GarbageCollectTables provides a mock function with given fields: pod, keyspace, tables
+func (_m *ManagementApiFacade) GarbageCollectTables(pod *v1.Pod, keyspace string, tables []string) (string, error) {
+	ret := _m.Called(pod, keyspace, tables)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, []string) string); ok {
+		r0 = rf(pod, keyspace, tables)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string, []string) error); ok {
+		r1 = rf(pod, keyspace, tables)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDatacenterPods provides a mock function with given fields:
+func (_m *ManagementApiFacade) GetDatacenterPods() ([]v1.Pod, error) {
+	ret := _m.Called()
+
+	var r0 []v1.Pod
+	if rf, ok := ret.Get(0).(func() []v1.Pod); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Pod)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointStates provides a mock function with given fields:
+func (_m *ManagementApiFacade) GetEndpointStates() ([]httphelper.EndpointState, error) {
+	ret := _m.Called()
+
+	var r0 []httphelper.EndpointState
+	if rf, ok := ret.Get(0).(func() []httphelper.EndpointState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]httphelper.EndpointState)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJobDetails provides a mock function with given fields: pod, jobId
+func (_m *ManagementApiFacade) GetJobDetails(pod *v1.Pod, jobId string) (*httphelper.JobDetails, error) {
+	ret := _m.Called(pod, jobId)
+
+	var r0 *httphelper.JobDetails
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string) *httphelper.JobDetails); ok {
+		r0 = rf(pod, jobId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*httphelper.JobDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string) error); ok {
+		r1 = rf(pod, jobId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetKeyspaceReplication provides a mock function with given fields: keyspaceName
 func (_m *ManagementApiFacade) GetKeyspaceReplication(keyspaceName string) (map[string]string, error) {
 	ret := _m.Called(keyspaceName)
@@ -91,6 +290,75 @@ func (_m *ManagementApiFacade) GetKeyspaceReplication(keyspaceName string) (map[
 	return r0, r1
 }
 
+// GetRingStatus provides a mock function with given fields:
+func (_m *ManagementApiFacade) GetRingStatus() ([]cassandra.NodeStatus, error) {
+	ret := _m.Called()
+
+	var r0 []cassandra.NodeStatus
+	if rf, ok := ret.Get(0).(func() []cassandra.NodeStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cassandra.NodeStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchemaVersions provides a mock function with given fields:
+func (_m *ManagementApiFacade) GetSchemaVersions() (map[string][]string, error) {
+	ret := _m.Called()
+
+	var r0 map[string][]string
+	if rf, ok := ret.Get(0).(func() map[string][]string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTableDefinition provides a mock function with given fields: keyspaceName, tableName
+func (_m *ManagementApiFacade) GetTableDefinition(keyspaceName string, tableName string) (*httphelper.TableDefinition, error) {
+	ret := _m.Called(keyspaceName, tableName)
+
+	var r0 *httphelper.TableDefinition
+	if rf, ok := ret.Get(0).(func(string, string) *httphelper.TableDefinition); ok {
+		r0 = rf(keyspaceName, tableName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*httphelper.TableDefinition)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(keyspaceName, tableName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListKeyspaces provides a mock function with given fields: keyspaceName
 func (_m *ManagementApiFacade) ListKeyspaces(keyspaceName string) ([]string, error) {
 	ret := _m.Called(keyspaceName)
@@ -136,3 +404,94 @@ func (_m *ManagementApiFacade) ListTables(keyspaceName string) ([]string, error)
 
 	return r0, r1
 }
+
+// RebuildNode provides a mock function with given fields: pod, sourceDatacenter
+func (_m *ManagementApiFacade) RebuildNode(pod *v1.Pod, sourceDatacenter string) (string, error) {
+	ret := _m.Called(pod, sourceDatacenter)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string) string); ok {
+		r0 = rf(pod, sourceDatacenter)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string) error); ok {
+		r1 = rf(pod, sourceDatacenter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RepairKeyspace provides a mock function with given fields: pod, keyspace
+func (_m *ManagementApiFacade) RepairKeyspace(pod *v1.Pod, keyspace string) (string, error) {
+	ret := _m.Called(pod, keyspace)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string) string); ok {
+		r0 = rf(pod, keyspace)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod, string) error); ok {
+		r1 = rf(pod, keyspace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLiveSetting provides a mock function with given fields: pod, setting, value
+func (_m *ManagementApiFacade) SetLiveSetting(pod *v1.Pod, setting string, value string) error {
+	ret := _m.Called(pod, setting, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, string) error); ok {
+		r0 = rf(pod, setting, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TakeSnapshot provides a mock function with given fields: pod, snapshotName, keyspaces
+func (_m *ManagementApiFacade) TakeSnapshot(pod *v1.Pod, snapshotName string, keyspaces []string) error {
+	ret := _m.Called(pod, snapshotName, keyspaces)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.Pod, string, []string) error); ok {
+		r0 = rf(pod, snapshotName, keyspaces)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpgradeSSTables provides a mock function with given fields: pod
+func (_m *ManagementApiFacade) UpgradeSSTables(pod *v1.Pod) (string, error) {
+	ret := _m.Called(pod)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*v1.Pod) string); ok {
+		r0 = rf(pod)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*v1.Pod) error); ok {
+		r1 = rf(pod)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -6,8 +6,8 @@ import (
 	"github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	api "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
-	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +23,10 @@ const (
 	// apis/reaper/v1alpha1/reaper_types.go accordingly.
 )
 
+// storesPasswordEnvVar holds the password of the encryption stores. It is referenced by
+// JAVA_OPTS.
+const storesPasswordEnvVar = "ENCRYPTION_STORES_PASSWORD"
+
 var (
 	defaultImage = images.Image{
 		Registry:   images.DefaultRegistry,
@@ -132,6 +136,18 @@ func NewDeployment(reaper *api.Reaper, dc *cassdcapi.CassandraDatacenter, authVa
 		}
 	}
 
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	if stores := reaper.Spec.ClientEncryptionStores; stores != nil {
+		envVars = append(envVars,
+			corev1.EnvVar{Name: "REAPER_CASS_NATIVE_PROTOCOL_SSL_ENCRYPTION_ENABLED", Value: "true"},
+			stores.PasswordEnvVar(storesPasswordEnvVar),
+			corev1.EnvVar{Name: "JAVA_OPTS", Value: stores.JavaOptions(storesPasswordEnvVar)},
+		)
+		volumes = append(volumes, stores.Volume())
+		volumeMounts = append(volumeMounts, stores.VolumeMount())
+	}
+
 	initImage := reaper.Spec.InitContainerImage.ApplyDefaults(defaultImage)
 	mainImage := reaper.Spec.ContainerImage.ApplyDefaults(defaultImage)
 
@@ -157,6 +173,7 @@ func NewDeployment(reaper *api.Reaper, dc *cassdcapi.CassandraDatacenter, authVa
 							SecurityContext: reaper.Spec.InitContainerSecurityContext,
 							Env:             envVars,
 							Args:            []string{"schema-migration"},
+							VolumeMounts:    volumeMounts,
 						},
 					},
 					Containers: []corev1.Container{
@@ -180,8 +197,10 @@ func NewDeployment(reaper *api.Reaper, dc *cassdcapi.CassandraDatacenter, authVa
 							ReadinessProbe: readinessProbe,
 							LivenessProbe:  livenessProbe,
 							Env:            envVars,
+							VolumeMounts:   volumeMounts,
 						},
 					},
+					Volumes:            volumes,
 					ServiceAccountName: reaper.Spec.ServiceAccountName,
 					Tolerations:        reaper.Spec.Tolerations,
 					SecurityContext:    reaper.Spec.PodSecurityContext,
@@ -191,6 +210,10 @@ func NewDeployment(reaper *api.Reaper, dc *cassdcapi.CassandraDatacenter, authVa
 		},
 	}
 	addAuthEnvVars(deployment, authVars)
+	if hash, found := reaper.Annotations[v1alpha1.EncryptionStoresHashAnnotation]; found {
+		// Restart Reaper when the stores are renewed
+		deployment.Spec.Template.Annotations = map[string]string{v1alpha1.EncryptionStoresHashAnnotation: hash}
+	}
 	annotations.AddHashAnnotation(deployment)
 	return deployment
 }
@@ -233,7 +256,7 @@ func getAdaptiveIncremental(reaper *api.Reaper, dc *cassdcapi.CassandraDatacente
 	case "INCREMENTAL":
 		incremental = true
 	case "AUTO":
-		if cassandra.IsCassandra3(dc.Spec.ServerVersion) {
+		if version.Cassandra3_11.Contains(dc.Spec.ServerVersion) {
 			adaptive = true
 		} else {
 			incremental = true
@@ -4,6 +4,7 @@ import (
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -207,6 +208,32 @@ func TestNewDeployment(t *testing.T) {
 	assert.Equal(t, probe, container.ReadinessProbe)
 }
 
+func TestGetAdaptiveIncremental(t *testing.T) {
+	tests := []struct {
+		repairType            string
+		serverVersion         string
+		adaptive, incremental bool
+	}{
+		{"AUTO", "3.11.11", true, false},
+		{"AUTO", "4.0.1", false, true},
+		{"AUTO", "4.1.0", false, true},
+		{"ADAPTIVE", "4.1.0", true, false},
+		{"INCREMENTAL", "3.11.11", false, true},
+		{"REGULAR", "4.0.1", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repairType+" "+tt.serverVersion, func(t *testing.T) {
+			reaper := newTestReaper()
+			reaper.Spec.AutoScheduling.RepairType = tt.repairType
+			dc := newTestDatacenter()
+			dc.Spec.ServerVersion = tt.serverVersion
+			adaptive, incremental := getAdaptiveIncremental(reaper, dc)
+			assert.Equal(t, tt.adaptive, adaptive)
+			assert.Equal(t, tt.incremental, incremental)
+		})
+	}
+}
+
 func TestReadinessProbe(t *testing.T) {
 	reaper := newTestReaper()
 	reaper.Spec.ReadinessProbe = &corev1.Probe{
@@ -402,6 +429,25 @@ func TestPodSecurityContext(t *testing.T) {
 	assert.EqualValues(t, podSecurityContext, podSpec.SecurityContext, "podSecurityContext expected at pod level")
 }
 
+func TestClientEncryptionStores(t *testing.T) {
+	reaper := newTestReaper()
+	stores := &encryption.Stores{SecretRef: corev1.LocalObjectReference{Name: "test-dc1-keystore"}}
+	reaper.Spec.ClientEncryptionStores = stores
+	reaper.Annotations = map[string]string{k8ssandraapi.EncryptionStoresHashAnnotation: "hash"}
+
+	deployment := NewDeployment(reaper, newTestDatacenter())
+	podSpec := deployment.Spec.Template.Spec
+
+	assert.Equal(t, "hash", deployment.Spec.Template.Annotations[k8ssandraapi.EncryptionStoresHashAnnotation])
+	assert.Contains(t, podSpec.Volumes, stores.Volume())
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		assert.Contains(t, container.VolumeMounts, stores.VolumeMount())
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "REAPER_CASS_NATIVE_PROTOCOL_SSL_ENCRYPTION_ENABLED", Value: "true"})
+		assert.Contains(t, container.Env, stores.PasswordEnvVar(storesPasswordEnvVar))
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "JAVA_OPTS", Value: stores.JavaOptions(storesPasswordEnvVar)})
+	}
+}
+
 func newTestReaper() *reaperapi.Reaper {
 	namespace := "service-test"
 	reaperName := "test-reaper"
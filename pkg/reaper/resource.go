@@ -6,6 +6,7 @@ import (
 	api "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
 	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -19,11 +20,15 @@ func ResourceName(klusterName, dcName string) string {
 	return klusterName + "-" + dcName + "-reaper"
 }
 
+// NewReaper returns the Reaper of dc. Reaper is given CQL credentials unless the
+// authentication of kc is disabled. encryptionStores are the stores that Reaper uses to
+// connect to the nodes when client encryption is enabled, or nil.
 func NewReaper(
 	reaperKey types.NamespacedName,
 	kc *k8ssandraapi.K8ssandraCluster,
 	dc *cassdcapi.CassandraDatacenter,
 	reaperTemplate *reaperapi.ReaperClusterTemplate,
+	encryptionStores *cassandra.EncryptionStores,
 ) *reaperapi.Reaper {
 	labels := createResourceLabels(kc)
 	desiredReaper := &reaperapi.Reaper{
@@ -42,12 +47,19 @@ func NewReaper(
 			DatacenterAvailability: computeReaperDcAvailability(kc),
 		},
 	}
-	if desiredReaper.Spec.CassandraUserSecretRef == "" {
+	if !kc.Spec.Cassandra.Auth.IsEnabled() {
+		// Reaper does not authenticate its CQL connections
+		desiredReaper.Spec.CassandraUserSecretRef = ""
+	} else if desiredReaper.Spec.CassandraUserSecretRef == "" {
 		desiredReaper.Spec.CassandraUserSecretRef = DefaultUserSecretName(kc.Name)
 	}
 	if desiredReaper.Spec.JmxUserSecretRef == "" {
 		desiredReaper.Spec.JmxUserSecretRef = DefaultJmxUserSecretName(kc.Name)
 	}
+	if encryptionStores != nil {
+		desiredReaper.Spec.ClientEncryptionStores = encryptionStores.Stores.DeepCopy()
+		desiredReaper.Annotations[k8ssandraapi.EncryptionStoresHashAnnotation] = encryptionStores.Hash
+	}
 	annotations.AddHashAnnotation(desiredReaper)
 	return desiredReaper
 }
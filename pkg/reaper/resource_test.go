@@ -0,0 +1,43 @@
+package reaper
+
+import (
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewReaperAuth(t *testing.T) {
+	enabled := true
+	disabled := false
+	tests := []struct {
+		name     string
+		auth     *api.Auth
+		template *reaperapi.ReaperClusterTemplate
+		expected string
+	}{
+		{"default", nil, &reaperapi.ReaperClusterTemplate{}, "cluster1-reaper"},
+		{"enabled", &api.Auth{Enabled: &enabled}, &reaperapi.ReaperClusterTemplate{CassandraUserSecretRef: "reaper-cql"}, "reaper-cql"},
+		{"disabled", &api.Auth{Enabled: &disabled}, &reaperapi.ReaperClusterTemplate{CassandraUserSecretRef: "reaper-cql"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &api.K8ssandraCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"},
+				Spec: api.K8ssandraClusterSpec{
+					Cassandra: &api.CassandraClusterTemplate{Auth: tt.auth},
+				},
+			}
+			dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "dc1"}}
+			reaperKey := types.NamespacedName{Namespace: "ns1", Name: ResourceName(kc.Name, dc.Name)}
+
+			reaper := NewReaper(reaperKey, kc, dc, tt.template, nil)
+			assert.Equal(t, tt.expected, reaper.Spec.CassandraUserSecretRef)
+			assert.Equal(t, "cluster1-reaper-jmx", reaper.Spec.JmxUserSecretRef)
+		})
+	}
+}
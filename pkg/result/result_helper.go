@@ -1,8 +1,10 @@
 package result
 
 import (
-	ctrl "sigs.k8s.io/controller-runtime"
 	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 // Copyright DataStax, Inc.
@@ -68,3 +70,39 @@ func RequeueSoon(after time.Duration) ReconcileResult {
 func Error(e error) ReconcileResult {
 	return errorOut{err: e}
 }
+
+// Aggregate combines the results of independent reconciliation steps. Errors take
+// precedence and are aggregated into a single error. Otherwise the result requeues after
+// the shortest of the requested delays, if any. Failing that, Aggregate returns Done if any
+// of the results is Done, and Continue otherwise.
+func Aggregate(results ...ReconcileResult) ReconcileResult {
+	var errs []error
+	requeue := false
+	var requeueAfter time.Duration
+	isDone := false
+
+	for _, result := range results {
+		switch r := result.(type) {
+		case errorOut:
+			errs = append(errs, r.err)
+		case callBackSoon:
+			if !requeue || r.after < requeueAfter {
+				requeueAfter = r.after
+			}
+			requeue = true
+		case done:
+			isDone = true
+		}
+	}
+
+	if len(errs) > 0 {
+		return Error(utilerrors.NewAggregate(errs))
+	}
+	if requeue {
+		return RequeueSoon(requeueAfter)
+	}
+	if isDone {
+		return Done()
+	}
+	return Continue()
+}
@@ -0,0 +1,42 @@
+package result
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestAggregate(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+
+	t.Run("no results", func(t *testing.T) {
+		assert.False(t, Aggregate().Completed())
+	})
+	t.Run("all continue", func(t *testing.T) {
+		assert.False(t, Aggregate(Continue(), Continue()).Completed())
+	})
+	t.Run("done", func(t *testing.T) {
+		result := Aggregate(Continue(), Done())
+		assert.True(t, result.Completed())
+		res, err := result.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+	})
+	t.Run("shortest requeue", func(t *testing.T) {
+		result := Aggregate(RequeueSoon(10*time.Second), Continue(), RequeueSoon(5*time.Second), Done())
+		res, err := result.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, res)
+	})
+	t.Run("errors", func(t *testing.T) {
+		result := Aggregate(Error(err1), RequeueSoon(time.Second), Error(err2))
+		_, err := result.Output()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), err1.Error())
+		assert.Contains(t, err.Error(), err2.Error())
+	})
+}
@@ -45,6 +45,11 @@ func generateRandomString(charset string, length int) ([]byte, error) {
 	return result, nil
 }
 
+// GeneratePassword returns a random password made of letters, digits and dashes.
+func GeneratePassword() ([]byte, error) {
+	return generateRandomString(passwordCharacters, 20)
+}
+
 // DefaultSuperuserSecretName follows the convention from k8ssandra Helm charts
 func DefaultSuperuserSecretName(clusterName string) string {
 	cleanedClusterName := strings.ReplaceAll(strings.ReplaceAll(clusterName, "_", ""), "-", "")
@@ -56,8 +61,15 @@ func DefaultSuperuserSecretName(clusterName string) string {
 // ReconcileSecret creates a new secret with proper "managed-by" annotations, or ensure the existing secret has such
 // annotations.
 func ReconcileSecret(ctx context.Context, c client.Client, secretName string, kcKey client.ObjectKey) error {
+	_, err := ReconcileUserSecret(ctx, c, secretName, secretName, kcKey)
+	return err
+}
+
+// ReconcileUserSecret is like ReconcileSecret, with username as the username of the
+// generated secret. The secret is returned.
+func ReconcileUserSecret(ctx context.Context, c client.Client, secretName, username string, kcKey client.ObjectKey) (*corev1.Secret, error) {
 	if secretName == "" {
-		return fmt.Errorf("secretName is required")
+		return nil, fmt.Errorf("secretName is required")
 	}
 	currentSec := &corev1.Secret{}
 	err := c.Get(ctx, types.NamespacedName{Name: secretName, Namespace: kcKey.Namespace}, currentSec)
@@ -65,7 +77,7 @@ func ReconcileSecret(ctx context.Context, c client.Client, secretName string, kc
 		if errors.IsNotFound(err) {
 			password, err := generateRandomString(passwordCharacters, 20)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			sec := &corev1.Secret{
@@ -74,22 +86,40 @@ func ReconcileSecret(ctx context.Context, c client.Client, secretName string, kc
 				// Immutable feature is only available from 1.21 and up (beta in 1.19 and up)
 				// Immutable:  true,
 				Data: map[string][]byte{
-					"username": []byte(secretName),
+					"username": []byte(username),
 					"password": password,
 				},
 			}
 
-			return c.Create(ctx, sec)
+			return sec, c.Create(ctx, sec)
 		} else {
-			return err
+			return nil, err
 		}
 	}
 
-	// It exists or was created: ensure it has proper annotations
-	if !labels.IsManagedBy(currentSec, kcKey) {
-		labels.SetManagedBy(currentSec, kcKey)
-		annotations.AddAnnotation(currentSec, OrphanResourceAnnotation, "true")
-		return c.Update(ctx, currentSec)
+	// It exists: ensure it has proper annotations
+	return currentSec, adoptSecret(ctx, c, currentSec, kcKey)
+}
+
+// AdoptSecret adds the "managed-by" labels to an existing secret that was provided by the
+// user, so that it is replicated along with the secrets generated by the operator. The
+// secret is returned.
+func AdoptSecret(ctx context.Context, c client.Client, secretName string, kcKey client.ObjectKey) (*corev1.Secret, error) {
+	sec := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: secretName, Namespace: kcKey.Namespace}, sec); err != nil {
+		return nil, err
+	}
+	if err := adoptSecret(ctx, c, sec, kcKey); err != nil {
+		return nil, err
+	}
+	return sec, nil
+}
+
+func adoptSecret(ctx context.Context, c client.Client, sec *corev1.Secret, kcKey client.ObjectKey) error {
+	if !labels.IsManagedBy(sec, kcKey) {
+		labels.SetManagedBy(sec, kcKey)
+		annotations.AddAnnotation(sec, OrphanResourceAnnotation, "true")
+		return c.Update(ctx, sec)
 	}
 	return nil
 }
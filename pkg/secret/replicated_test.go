@@ -1,11 +1,17 @@
 package secret
 
 import (
+	"context"
 	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	replicationapi "github.com/k8ssandra/k8ssandra-operator/apis/replication/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -91,3 +97,24 @@ func TestRequiresUpdate(t *testing.T) {
 }
 
 // controllers/secret_controller_test.go tests VerifyReplicatedSecret
+
+func TestAdoptSecret(t *testing.T) {
+	kcKey := client.ObjectKey{Namespace: "namespace", Name: "name"}
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ldap-bind"},
+		Data:       map[string][]byte{"username": []byte("cn=admin"), "password": []byte("secret")},
+	}
+	c := fake.NewClientBuilder().WithObjects(userSecret).Build()
+
+	sec, err := AdoptSecret(context.Background(), c, "ldap-bind", kcKey)
+	require.NoError(t, err)
+	assert.Equal(t, userSecret.Data, sec.Data)
+
+	actual := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "namespace", Name: "ldap-bind"}, actual))
+	assert.True(t, labels.IsManagedBy(actual, kcKey))
+	assert.Equal(t, "true", actual.Annotations[OrphanResourceAnnotation])
+
+	_, err = AdoptSecret(context.Background(), c, "missing", kcKey)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -1,8 +1,6 @@
 package stargate
 
 import (
-	"fmt"
-	"github.com/go-logr/logr"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 )
@@ -22,23 +20,17 @@ var authTableDefinition = &httphelper.TableDefinition{
 	},
 }
 
-// ReconcileAuthTable ensures that the Stargate auth schema contains the appropriate tables. Note that we don't do much
-// here and currently, we only check if the token table exists, without checking if the table definition matches the
-// expected one. If the auth schema becomes more complex in the future, we'd need to find a more robust solution, maybe
-// à la Reaper.
-func ReconcileAuthTable(managementApi cassandra.ManagementApiFacade, logger logr.Logger) error {
-	if tables, err := managementApi.ListTables(AuthKeyspace); err != nil {
-		return err
-	} else if len(tables) == 0 {
-		logger.Info(fmt.Sprintf("table %s does not exist in keyspace %v, creating it", AuthTable, AuthKeyspace))
-		if err := managementApi.CreateTable(authTableDefinition); err != nil {
-			return err
-		} else {
-			logger.Info(fmt.Sprintf("Table %s successfully created", AuthTable))
-			return nil
-		}
-	} else {
-		logger.Info(fmt.Sprintf("table %s already exists in keyspace %v", AuthTable, AuthKeyspace))
-		return nil
-	}
+// AuthSchema is the schema of the Stargate auth keyspace. New changes to the schema must be
+// added as new migrations; the existing ones must not be modified, they are not applied
+// again on the clusters where they were already applied.
+var AuthSchema = &cassandra.Schema{
+	Keyspace: AuthKeyspace,
+	Migrations: []cassandra.Migration{
+		{
+			Version:     1,
+			Description: "Create the token table",
+			Tables:      []*httphelper.TableDefinition{authTableDefinition},
+		},
+	},
+	Tables: []*httphelper.TableDefinition{authTableDefinition},
 }
@@ -2,64 +2,162 @@ package stargate
 
 import (
 	"errors"
+	"testing"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"testing"
 )
 
-func TestReconcileAuthTable(t *testing.T) {
+func TestMigrateAuthSchema(t *testing.T) {
 	dummyError := errors.New("failure")
+	agreement := map[string][]string{"version-1": {"host-1", "host-2"}}
+	migrationsTable := func(def *httphelper.TableDefinition) bool {
+		return def.KeyspaceName == AuthKeyspace && def.TableName == cassandra.MigrationsTable
+	}
+	tokenTable := func(def *httphelper.TableDefinition) bool {
+		return def.KeyspaceName == AuthKeyspace && def.TableName == AuthTable
+	}
+
 	tests := []struct {
-		name          string
-		managementApi func() cassandra.ManagementApiFacade
-		err           error
+		name      string
+		setup     func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient)
+		err       error
+		errString string
 	}{
 		{
-			"list tables failed",
-			func() cassandra.ManagementApiFacade {
-				m := new(mocks.ManagementApiFacade)
-				m.On("ListTables", AuthKeyspace).Return(nil, dummyError)
-				return m
+			name: "list tables failed",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return(nil, dummyError)
+			},
+			err: dummyError,
+		},
+		{
+			name: "new cluster",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return([]string{}, nil)
+				managementApi.On("CreateTable", mock.MatchedBy(migrationsTable)).Return(nil).Once()
+				managementApi.On("CreateTable", mock.MatchedBy(tokenTable)).Return(nil).Once()
+				cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{}, nil)
+				cqlClient.On("RecordSchemaMigration", AuthKeyspace, 1, "Create the token table").Return(nil).Once()
+				managementApi.On("GetTableDefinition", AuthKeyspace, AuthTable).Return(authTableDefinition, nil)
+			},
+		},
+		{
+			name: "table created before the migrations",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return([]string{AuthTable}, nil)
+				managementApi.On("CreateTable", mock.MatchedBy(migrationsTable)).Return(nil).Once()
+				cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{}, nil)
+				cqlClient.On("RecordSchemaMigration", AuthKeyspace, 1, "Create the token table").Return(nil).Once()
+				managementApi.On("GetTableDefinition", AuthKeyspace, AuthTable).Return(authTableDefinition, nil)
+			},
+		},
+		{
+			name: "migrations already applied",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return([]string{cassandra.MigrationsTable, AuthTable}, nil)
+				cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{1}, nil)
+				managementApi.On("GetTableDefinition", AuthKeyspace, AuthTable).Return(authTableDefinition, nil)
 			},
-			dummyError,
 		},
 		{
-			"table exists",
-			func() cassandra.ManagementApiFacade {
-				m := new(mocks.ManagementApiFacade)
-				m.On("ListTables", AuthKeyspace).Return([]string{AuthTable}, nil)
-				return m
+			name: "table creation failed",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return([]string{cassandra.MigrationsTable}, nil)
+				managementApi.On("CreateTable", mock.MatchedBy(tokenTable)).Return(dummyError)
+				cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{}, nil)
 			},
-			nil,
+			errString: "failed to apply migration 1 of keyspace data_endpoint_auth: failure",
 		},
 		{
-			"table creation OK",
-			func() cassandra.ManagementApiFacade {
-				m := new(mocks.ManagementApiFacade)
-				m.On("ListTables", AuthKeyspace).Return([]string{}, nil)
-				m.On("CreateTable", mock.Anything).Return(nil)
-				return m
+			name: "missing regular column",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return([]string{cassandra.MigrationsTable, AuthTable}, nil)
+				cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{1}, nil)
+				managementApi.On("GetTableDefinition", AuthKeyspace, AuthTable).Return(httphelper.NewTableDefinition(AuthKeyspace, AuthTable,
+					httphelper.NewPartitionKeyColumn("auth_token", "uuid", 0),
+					httphelper.NewRegularColumn("username", "text"),
+				), nil)
+				cqlClient.On("ExecuteSchemaChange", `ALTER TABLE "data_endpoint_auth"."token" ADD "created_timestamp" int`).Return(nil).Once()
 			},
-			nil,
 		},
 		{
-			"table creation failed",
-			func() cassandra.ManagementApiFacade {
-				m := new(mocks.ManagementApiFacade)
-				m.On("ListTables", AuthKeyspace).Return([]string{}, nil)
-				m.On("CreateTable", mock.Anything).Return(dummyError)
-				return m
+			name: "column type mismatch",
+			setup: func(managementApi *mocks.ManagementApiFacade, cqlClient *mocks.CqlClient) {
+				managementApi.On("GetSchemaVersions").Return(agreement, nil)
+				managementApi.On("ListTables", AuthKeyspace).Return([]string{cassandra.MigrationsTable, AuthTable}, nil)
+				cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{1}, nil)
+				managementApi.On("GetTableDefinition", AuthKeyspace, AuthTable).Return(httphelper.NewTableDefinition(AuthKeyspace, AuthTable,
+					httphelper.NewPartitionKeyColumn("auth_token", "text", 0),
+					httphelper.NewRegularColumn("username", "text"),
+					httphelper.NewRegularColumn("created_timestamp", "int"),
+				), nil)
 			},
-			dummyError,
+			errString: "the schema of keyspace data_endpoint_auth does not match its migrations: " +
+				"table token: column auth_token is text PARTITION_KEY 0, expected uuid PARTITION_KEY 0",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ReconcileAuthTable(tt.managementApi(), log.NullLogger{})
-			assert.Equal(t, tt.err, err)
+			managementApi := new(mocks.ManagementApiFacade)
+			cqlClient := new(mocks.CqlClient)
+			tt.setup(managementApi, cqlClient)
+
+			err := cassandra.MigrateSchema(managementApi, cqlClient, AuthSchema, log.NullLogger{})
+			switch {
+			case tt.err != nil:
+				assert.Equal(t, tt.err, err)
+			case tt.errString != "":
+				assert.EqualError(t, err, tt.errString)
+			default:
+				require.NoError(t, err)
+				managementApi.AssertExpectations(t)
+				cqlClient.AssertExpectations(t)
+			}
 		})
 	}
 }
+
+func TestMigrateAuthSchemaDisagreement(t *testing.T) {
+	agreement := map[string][]string{"version-1": {"host-1", "host-2"}}
+	disagreement := map[string][]string{"version-1": {"host-1"}, "version-2": {"host-2"}}
+
+	t.Run("before the migrations", func(t *testing.T) {
+		managementApi := new(mocks.ManagementApiFacade)
+		cqlClient := new(mocks.CqlClient)
+		managementApi.On("GetSchemaVersions").Return(disagreement, nil)
+
+		err := cassandra.MigrateSchema(managementApi, cqlClient, AuthSchema, log.NullLogger{})
+		require.IsType(t, &cassandra.SchemaDisagreementError{}, err)
+		managementApi.AssertNumberOfCalls(t, "GetSchemaVersions", 1)
+		managementApi.AssertNotCalled(t, "ListTables", AuthKeyspace)
+	})
+
+	t.Run("after a migration", func(t *testing.T) {
+		managementApi := new(mocks.ManagementApiFacade)
+		cqlClient := new(mocks.CqlClient)
+		managementApi.On("GetSchemaVersions").Return(agreement, nil).Once()
+		managementApi.On("GetSchemaVersions").Return(disagreement, nil).Once()
+		managementApi.On("ListTables", AuthKeyspace).Return([]string{cassandra.MigrationsTable}, nil)
+		managementApi.On("CreateTable", mock.Anything).Return(nil).Once()
+		cqlClient.On("ListSchemaMigrations", AuthKeyspace).Return([]int{}, nil)
+		cqlClient.On("RecordSchemaMigration", AuthKeyspace, 1, "Create the token table").Return(nil).Once()
+
+		err := cassandra.MigrateSchema(managementApi, cqlClient, AuthSchema, log.NullLogger{})
+		require.IsType(t, &cassandra.SchemaDisagreementError{}, err)
+		managementApi.AssertExpectations(t)
+		cqlClient.AssertExpectations(t)
+		managementApi.AssertNotCalled(t, "GetTableDefinition", AuthKeyspace, AuthTable)
+	})
+}
@@ -3,8 +3,10 @@ package stargate
 import (
 	"fmt"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/encryption"
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
-	"strings"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/version"
+	"strconv"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	coreapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
@@ -60,7 +62,7 @@ var (
 // resources.
 func NewDeployments(stargate *api.Stargate, dc *cassdcapi.CassandraDatacenter) map[string]appsv1.Deployment {
 
-	clusterVersion := computeClusterVersion(dc)
+	clusterVersion := computeClusterVersion(stargate, dc)
 	seedService := computeSeedServiceUrl(dc)
 
 	racks := dc.GetRacks()
@@ -83,8 +85,8 @@ func NewDeployments(stargate *api.Stargate, dc *cassdcapi.CassandraDatacenter) m
 		livenessProbe := computeLivenessProbe(template)
 		readinessProbe := computeReadinessProbe(template)
 		jvmOptions := computeJvmOptions(template)
-		volumes := computeVolumes(template)
-		volumeMounts := computeVolumeMounts(template)
+		volumes := computeVolumes(template, stargate.Spec.EncryptionStores)
+		volumeMounts := computeVolumeMounts(template, stargate.Spec.EncryptionStores)
 		serviceAccountName := computeServiceAccount(template)
 		nodeSelector := computeNodeSelector(template, dc)
 		tolerations := computeTolerations(template, dc)
@@ -171,7 +173,7 @@ func NewDeployments(stargate *api.Stargate, dc *cassdcapi.CassandraDatacenter) m
 								{Name: "SEED", Value: seedService},
 								{Name: "DATACENTER_NAME", Value: dc.Name},
 								{Name: "RACK_NAME", Value: rack.Name},
-								{Name: "ENABLE_AUTH", Value: "true"},
+								{Name: "ENABLE_AUTH", Value: strconv.FormatBool(stargate.Spec.IsAuthEnabled())},
 								// Watching bundles is unnecessary in a k8s deployment. See
 								// https://github.com/stargate/stargate/issues/1286 for
 								// details.
@@ -201,6 +203,10 @@ func NewDeployments(stargate *api.Stargate, dc *cassdcapi.CassandraDatacenter) m
 			deployment.Spec.Template.Labels[coreapi.K8ssandraClusterNameLabel] = klusterName
 			deployment.Spec.Template.Labels[coreapi.K8ssandraClusterNamespaceLabel] = klusterNamespace
 		}
+		if hash, found := stargate.Annotations[coreapi.EncryptionStoresHashAnnotation]; found {
+			// Restart Stargate when the stores are renewed
+			deployment.Spec.Template.Annotations = map[string]string{coreapi.EncryptionStoresHashAnnotation: hash}
+		}
 		annotations.AddHashAnnotation(&deployment)
 		deployments[deploymentName] = deployment
 	}
@@ -218,9 +224,12 @@ func computeSeedServiceUrl(dc *cassdcapi.CassandraDatacenter) string {
 	return dc.Spec.ClusterName + "-seed-service." + dc.Namespace + ".svc." + clusterDomain
 }
 
-func computeClusterVersion(dc *cassdcapi.CassandraDatacenter) ClusterVersion {
-	cassandraVersion := dc.Spec.ServerVersion
-	if strings.HasPrefix(cassandraVersion, "3") {
+func computeClusterVersion(stargate *api.Stargate, dc *cassdcapi.CassandraDatacenter) ClusterVersion {
+	cassandraVersion := stargate.Spec.ServerVersion
+	if cassandraVersion == "" {
+		cassandraVersion = dc.Spec.ServerVersion
+	}
+	if version.Cassandra3_11.Contains(cassandraVersion) {
 		return ClusterVersion3
 	} else {
 		return ClusterVersion4
@@ -319,28 +328,36 @@ func computeHeapSize(template *api.StargateTemplate) resource.Quantity {
 	return resource.MustParse("256Mi")
 }
 
-func computeVolumes(template *api.StargateTemplate) []corev1.Volume {
+func computeVolumes(template *api.StargateTemplate, stores *encryption.Stores) []corev1.Volume {
+	var volumes []corev1.Volume
 	if template.CassandraConfigMapRef != nil {
-		return []corev1.Volume{{
+		volumes = append(volumes, corev1.Volume{
 			Name: "cassandra-config",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: *template.CassandraConfigMapRef,
 				},
 			},
-		}}
+		})
 	}
-	return nil
+	if stores != nil {
+		volumes = append(volumes, stores.Volume())
+	}
+	return volumes
 }
 
-func computeVolumeMounts(template *api.StargateTemplate) []corev1.VolumeMount {
+func computeVolumeMounts(template *api.StargateTemplate, stores *encryption.Stores) []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
 	if template.CassandraConfigMapRef != nil {
-		return []corev1.VolumeMount{{
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      "cassandra-config",
 			MountPath: cassandraConfigDir,
-		}}
+		})
 	}
-	return nil
+	if stores != nil {
+		volumeMounts = append(volumeMounts, stores.VolumeMount())
+	}
+	return volumeMounts
 }
 
 func computeServiceAccount(template *api.StargateTemplate) string {
@@ -2,6 +2,7 @@ package stargate
 
 import (
 	"github.com/k8ssandra/k8ssandra-operator/pkg/images"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -53,6 +54,7 @@ func TestNewDeployments(t *testing.T) {
 	t.Run("Many racks few replicas", testNewDeploymentsManyRacksFewReplicas)
 	t.Run("CassandraConfigMap", testNewDeploymentsCassandraConfigMap)
 	t.Run("Custom images", testImages)
+	t.Run("Auth", testNewDeploymentsAuth)
 }
 
 func testNewDeploymentsDefaultRackSingleReplica(t *testing.T) {
@@ -512,6 +514,24 @@ func testNewDeploymentsCassandraConfigMap(t *testing.T) {
 	assert.Equal(t, expected, *volume, "cassandra-config volume does not match expected value")
 }
 
+func testNewDeploymentsAuth(t *testing.T) {
+	for _, auth := range []*bool{nil, boolPtr(true), boolPtr(false)} {
+		stargate := stargate.DeepCopy()
+		stargate.Spec.Auth = auth
+
+		deployments := NewDeployments(stargate, dc)
+		require.Len(t, deployments, 1)
+		deployment := deployments["cluster1-dc1-default-stargate-deployment"]
+
+		container := findContainer(&deployment, deployment.Name)
+		require.NotNil(t, container, "failed to find stargate container")
+
+		envVar := findEnvVar(container, "ENABLE_AUTH")
+		require.NotNil(t, envVar, "failed to find ENABLE_AUTH env var")
+		assert.Equal(t, strconv.FormatBool(auth == nil || *auth), envVar.Value)
+	}
+}
+
 func testImages(t *testing.T) {
 	// Note: a nil image is normally not possible due to the kubebuilder marker on the CRD spec
 	t.Run("nil image 3", func(t *testing.T) {
@@ -536,6 +556,16 @@ func testImages(t *testing.T) {
 		assert.Equal(t, corev1.PullIfNotPresent, deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy)
 		assert.Empty(t, deployment.Spec.Template.Spec.ImagePullSecrets)
 	})
+	t.Run("nil image 4.1", func(t *testing.T) {
+		stargate := stargate.DeepCopy()
+		stargate.Spec.ContainerImage = nil
+		dc := dc.DeepCopy()
+		dc.Spec.ServerVersion = "4.1.0"
+		deployments := NewDeployments(stargate, dc)
+		require.Len(t, deployments, 1)
+		deployment := deployments["cluster1-dc1-default-stargate-deployment"]
+		assert.Equal(t, defaultImage4.String(), deployment.Spec.Template.Spec.Containers[0].Image)
+	})
 	t.Run("default image 3", func(t *testing.T) {
 		stargate := stargate.DeepCopy()
 		stargate.Spec.ContainerImage = &images.Image{
@@ -598,6 +628,17 @@ func testImages(t *testing.T) {
 		assert.Contains(t, deployment.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: "my-secret"})
 		assert.Len(t, deployment.Spec.Template.Spec.ImagePullSecrets, 1)
 	})
+	t.Run("cluster being upgraded", func(t *testing.T) {
+		stargate := stargate.DeepCopy()
+		stargate.Spec.ContainerImage = nil
+		stargate.Spec.ServerVersion = "3.11.11"
+		dc := dc.DeepCopy()
+		dc.Spec.ServerVersion = "4.0.1"
+		deployments := NewDeployments(stargate, dc)
+		require.Len(t, deployments, 1)
+		deployment := deployments["cluster1-dc1-default-stargate-deployment"]
+		assert.Equal(t, defaultImage3.String(), deployment.Spec.Template.Spec.Containers[0].Image)
+	})
 }
 
 func findContainer(deployment *appsv1.Deployment, name string) *corev1.Container {
@@ -642,3 +683,7 @@ func affinityForRack(dc *cassdcapi.CassandraDatacenter, rackName string) *corev1
 		PodAntiAffinity: computePodAntiAffinity(false, dc, rackName),
 	}
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
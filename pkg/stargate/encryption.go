@@ -0,0 +1,36 @@
+package stargate
+
+import (
+	"fmt"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// CassandraYamlFile is the key of the cassandra.yaml file in the ConfigMap referenced by
+// CassandraConfigMapRef.
+const CassandraYamlFile = "cassandra.yaml"
+
+// EncryptionConfigMapName returns the name of the ConfigMap that holds the cassandra.yaml
+// of the Stargate resource named stargateName when internode encryption is enabled.
+func EncryptionConfigMapName(stargateName string) string {
+	return stargateName + "-cassandra-config"
+}
+
+// AddServerEncryptionOptions returns cassandraYaml, the content of the cassandra.yaml
+// file of Stargate, with its server_encryption_options set to options.
+func AddServerEncryptionOptions(cassandraYaml string, options *api.ServerEncryptionOptions) (string, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(cassandraYaml), &config); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %v", CassandraYamlFile, err)
+	}
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	config["server_encryption_options"] = options
+	rendered, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
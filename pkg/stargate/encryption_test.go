@@ -0,0 +1,37 @@
+package stargate
+
+import (
+	"testing"
+
+	api "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAddServerEncryptionOptions(t *testing.T) {
+	internodeEncryption := "all"
+	keystore := "/etc/encryption/keystore.jks"
+	options := &api.ServerEncryptionOptions{
+		InternodeEncryption: &internodeEncryption,
+		EncryptionOptions:   api.EncryptionOptions{Keystore: &keystore},
+	}
+
+	for _, cassandraYaml := range []string{"", "num_tokens: 8\n"} {
+		rendered, err := AddServerEncryptionOptions(cassandraYaml, options)
+		require.NoError(t, err)
+
+		var config map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(rendered), &config))
+		assert.Equal(t, map[string]interface{}{
+			"internode_encryption": "all",
+			"keystore":             keystore,
+		}, config["server_encryption_options"])
+		if cassandraYaml != "" {
+			assert.Equal(t, float64(8), config["num_tokens"])
+		}
+	}
+
+	_, err := AddServerEncryptionOptions("not: [yaml", options)
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"reflect"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -8,3 +10,16 @@ import (
 func GetKey(obj metav1.Object) client.ObjectKey {
 	return client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
 }
+
+// MergeFields sets each field of the struct pointed to by dst that has its zero value to the
+// value of the same field of the struct pointed to by src. src and dst must point to structs
+// of the same type.
+func MergeFields(src, dst interface{}) {
+	srcValue := reflect.ValueOf(src).Elem()
+	dstValue := reflect.ValueOf(dst).Elem()
+	for i := 0; i < dstValue.NumField(); i++ {
+		if dstValue.Field(i).IsZero() {
+			dstValue.Field(i).Set(srcValue.Field(i))
+		}
+	}
+}
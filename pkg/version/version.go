@@ -0,0 +1,65 @@
+// Package version models the Cassandra versions that the operator deploys. The logic that
+// depends on the version, e.g., the defaults of the config or the Stargate images, should
+// be expressed with the ranges of this package rather than by matching version strings.
+package version
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Range is a range of Cassandra versions, from Min inclusive to Max exclusive. Both bounds
+// are generic versions, e.g., 4.0 or 4.0.1.
+type Range struct {
+	Min string
+	Max string
+}
+
+var (
+	// Cassandra3_11 is the range of the 3.11.x releases.
+	Cassandra3_11 = Range{Min: "3.11", Max: "4.0"}
+
+	// Cassandra4_0 is the range of the 4.0.x releases.
+	Cassandra4_0 = Range{Min: "4.0", Max: "4.1"}
+
+	// Cassandra4_1 is the range of the 4.1.x releases.
+	Cassandra4_1 = Range{Min: "4.1", Max: "4.2"}
+
+	// Supported is the range of the versions that the operator can deploy.
+	Supported = Range{Min: "3.11", Max: "4.2"}
+)
+
+// Contains returns true if v is a valid version within r.
+func (r Range) Contains(v string) bool {
+	parsed, err := version.ParseGeneric(v)
+	if err != nil {
+		return false
+	}
+	return parsed.AtLeast(version.MustParseGeneric(r.Min)) && parsed.LessThan(version.MustParseGeneric(r.Max))
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf(">=%s <%s", r.Min, r.Max)
+}
+
+// AtLeast returns true if v is a valid version that is equal to or newer than min, e.g.,
+// AtLeast("4.0.1", "4.0") is true.
+func AtLeast(v, min string) bool {
+	parsed, err := version.ParseGeneric(v)
+	if err != nil {
+		return false
+	}
+	return parsed.AtLeast(version.MustParseGeneric(min))
+}
+
+// Validate returns an error if v is not a valid version of the Supported range.
+func Validate(v string) error {
+	if _, err := version.ParseGeneric(v); err != nil {
+		return fmt.Errorf("invalid Cassandra version %q", v)
+	}
+	if !Supported.Contains(v) {
+		return fmt.Errorf("unsupported Cassandra version %s, the supported versions are %s", v, Supported)
+	}
+	return nil
+}
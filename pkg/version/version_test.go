@@ -0,0 +1,52 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        Range
+		version  string
+		expected bool
+	}{
+		{"3.11 min", Cassandra3_11, "3.11.0", true},
+		{"3.11 patch", Cassandra3_11, "3.11.11", true},
+		{"3.11 excludes 4.0", Cassandra3_11, "4.0.0", false},
+		{"3.0 not 3.11", Cassandra3_11, "3.0.25", false},
+		{"4.0", Cassandra4_0, "4.0.1", true},
+		{"4.0 excludes 4.1", Cassandra4_0, "4.1.0", false},
+		{"4.1", Cassandra4_1, "4.1.2", true},
+		{"4.1 excludes 4.2", Cassandra4_1, "4.2.0", false},
+		{"supported 3.11", Supported, "3.11.11", true},
+		{"supported 4.1", Supported, "4.1.0", true},
+		{"unsupported 3.0", Supported, "3.0.25", false},
+		{"unsupported 4.2", Supported, "4.2.0", false},
+		{"empty", Supported, "", false},
+		{"invalid", Supported, "latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.r.Contains(tt.version))
+		})
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	assert.True(t, AtLeast("4.0.1", "4.0"))
+	assert.True(t, AtLeast("4.1.0", "4.0"))
+	assert.False(t, AtLeast("3.11.11", "4.0"))
+	assert.False(t, AtLeast("", "4.0"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("3.11.11"))
+	assert.NoError(t, Validate("4.0.1"))
+	assert.NoError(t, Validate("4.1.0"))
+	assert.EqualError(t, Validate("3.0.25"), "unsupported Cassandra version 3.0.25, the supported versions are >=3.11 <4.2")
+	assert.EqualError(t, Validate("5.0.0"), "unsupported Cassandra version 5.0.0, the supported versions are >=3.11 <4.2")
+	assert.EqualError(t, Validate("latest"), `invalid Cassandra version "latest"`)
+}
@@ -167,6 +167,23 @@ func (f *Framework) PatchDatacenterStatus(ctx context.Context, key ClusterKey, u
 	return remoteClient.Status().Patch(ctx, dc, patch)
 }
 
+// PatchDatacenter fetches the datacenter specified by key, applies changes via updateFn,
+// and then performs a patch operation. Use PatchDatacenterStatus to update the status.
+func (f *Framework) PatchDatacenter(ctx context.Context, key ClusterKey, updateFn func(dc *cassdcapi.CassandraDatacenter)) error {
+	dc := &cassdcapi.CassandraDatacenter{}
+	err := f.Get(ctx, key, dc)
+
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFromWithOptions(dc.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	updateFn(dc)
+
+	remoteClient := f.remoteClients[key.K8sContext]
+	return remoteClient.Patch(ctx, dc, patch)
+}
+
 func (f *Framework) PatchStargateStatus(ctx context.Context, key ClusterKey, updateFn func(sg *stargateapi.Stargate)) error {
 	sg := &stargateapi.Stargate{}
 	err := f.Get(ctx, key, sg)